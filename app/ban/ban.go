@@ -0,0 +1,204 @@
+package ban
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/task"
+	"github.com/xtls/xray-core/features/ban"
+)
+
+// Ban is a banned IP.
+type Ban struct {
+	IP       string
+	Failures int
+	Expire   time.Time
+}
+
+// Manager bans source IPs with too many failed authentications in inbounds.
+type Manager struct {
+	maxFailures int
+	findTime    time.Duration
+	banTime     time.Duration
+
+	access   sync.Mutex
+	failures map[string][]time.Time
+	bans     map[string]*Ban
+	cleanup  *task.Periodic
+
+	// now is replaced in tests.
+	now func() time.Time
+}
+
+// New creates a Manager.
+func New(ctx context.Context, config *Config) (*Manager, error) {
+	m := &Manager{
+		maxFailures: 5,
+		findTime:    10 * time.Minute,
+		banTime:     time.Hour,
+		failures:    make(map[string][]time.Time),
+		bans:        make(map[string]*Ban),
+		now:         time.Now,
+	}
+	if config.MaxFailures > 0 {
+		m.maxFailures = int(config.MaxFailures)
+	}
+	if config.FindTime > 0 {
+		m.findTime = time.Duration(config.FindTime)
+	}
+	if config.BanTime > 0 {
+		m.banTime = time.Duration(config.BanTime)
+	}
+	m.cleanup = &task.Periodic{
+		Interval: time.Minute,
+		Execute: func() error {
+			m.expire()
+			return nil
+		},
+	}
+	return m, nil
+}
+
+// Type implements common.HasType.
+func (*Manager) Type() interface{} {
+	return ban.ManagerType()
+}
+
+// Start implements common.Runnable.
+func (m *Manager) Start() error {
+	return m.cleanup.Start()
+}
+
+// Close implements common.Closable.
+func (m *Manager) Close() error {
+	return m.cleanup.Close()
+}
+
+// RecordFailure implements ban.Manager.
+func (m *Manager) RecordFailure(ctx context.Context, ip net.Address) {
+	// Connections through a local reverse proxy all come from loopback, which must not be banned.
+	if !ip.Family().IsIP() || ip.IP().IsLoopback() {
+		return
+	}
+	key := ip.String()
+	now := m.now()
+
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	if _, found := m.bans[key]; found {
+		return
+	}
+	failures := append(m.recentFailures(key, now), now)
+	if len(failures) < m.maxFailures {
+		m.failures[key] = failures
+		return
+	}
+	delete(m.failures, key)
+	m.bans[key] = &Ban{
+		IP:       key,
+		Failures: len(failures),
+		Expire:   now.Add(m.banTime),
+	}
+	errors.LogWarning(ctx, "banned ", key, " for ", m.banTime, " after ", len(failures), " failed authentications")
+}
+
+// recentFailures returns the failures of the IP within findTime. Must be called with m.access locked.
+func (m *Manager) recentFailures(key string, now time.Time) []time.Time {
+	failures := m.failures[key]
+	i := 0
+	for i < len(failures) && now.Sub(failures[i]) > m.findTime {
+		i++
+	}
+	return failures[i:]
+}
+
+// IsBanned implements ban.Manager.
+func (m *Manager) IsBanned(ip net.Address) bool {
+	if !ip.Family().IsIP() {
+		return false
+	}
+	key := ip.String()
+
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	b, found := m.bans[key]
+	if !found {
+		return false
+	}
+	if m.now().After(b.Expire) {
+		delete(m.bans, key)
+		errors.LogInfo(context.Background(), "ban of ", key, " expired")
+		return false
+	}
+	return true
+}
+
+// expire removes expired bans and failures.
+func (m *Manager) expire() {
+	now := m.now()
+
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	for key, b := range m.bans {
+		if now.After(b.Expire) {
+			delete(m.bans, key)
+			errors.LogInfo(context.Background(), "ban of ", key, " expired")
+		}
+	}
+	for key := range m.failures {
+		if failures := m.recentFailures(key, now); len(failures) > 0 {
+			m.failures[key] = failures
+		} else {
+			delete(m.failures, key)
+		}
+	}
+}
+
+// Bans returns the current bans, ordered by their expire time.
+func (m *Manager) Bans() []Ban {
+	m.expire()
+
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	bans := make([]Ban, 0, len(m.bans))
+	for _, b := range m.bans {
+		bans = append(bans, *b)
+	}
+	slices.SortFunc(bans, func(a, b Ban) int {
+		return a.Expire.Compare(b.Expire)
+	})
+	return bans
+}
+
+// Unban lifts the ban of the IP, and clears its failures. It returns false if the IP is not banned.
+func (m *Manager) Unban(ip string) bool {
+	if addr := net.ParseAddress(ip); addr.Family().IsIP() {
+		ip = addr.String()
+	}
+
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	delete(m.failures, ip)
+	if _, found := m.bans[ip]; !found {
+		return false
+	}
+	delete(m.bans, ip)
+	errors.LogInfo(context.Background(), "ban of ", ip, " lifted")
+	return true
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return New(ctx, config.(*Config))
+	}))
+}
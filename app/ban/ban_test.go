@@ -0,0 +1,66 @@
+package ban
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+)
+
+func TestManager(t *testing.T) {
+	m, err := New(context.Background(), &Config{
+		MaxFailures: 3,
+		FindTime:    int64(time.Minute),
+		BanTime:     int64(time.Hour),
+	})
+	common.Must(err)
+	now := time.Unix(1700000000, 0)
+	m.now = func() time.Time { return now }
+
+	ip := net.ParseAddress("203.0.113.1")
+	m.RecordFailure(context.Background(), ip)
+	m.RecordFailure(context.Background(), ip)
+	if m.IsBanned(ip) {
+		t.Fatal("expect not banned below the limit")
+	}
+
+	// Failures out of findTime are forgotten.
+	now = now.Add(2 * time.Minute)
+	m.RecordFailure(context.Background(), ip)
+	if m.IsBanned(ip) {
+		t.Fatal("expect not banned with old failures")
+	}
+	m.RecordFailure(context.Background(), ip)
+	m.RecordFailure(context.Background(), ip)
+	if !m.IsBanned(ip) {
+		t.Fatal("expect banned")
+	}
+	if bans := m.Bans(); len(bans) != 1 || bans[0].IP != "203.0.113.1" || bans[0].Failures != 3 {
+		t.Error("unexpected bans ", bans)
+	}
+
+	now = now.Add(time.Hour + time.Second)
+	if m.IsBanned(ip) {
+		t.Error("expect ban expired")
+	}
+
+	for i := 0; i < 3; i++ {
+		m.RecordFailure(context.Background(), ip)
+	}
+	if !m.Unban("203.0.113.1") || m.IsBanned(ip) {
+		t.Error("expect ban lifted")
+	}
+	if m.Unban("203.0.113.1") {
+		t.Error("expect no ban to lift")
+	}
+
+	loopback := net.ParseAddress("127.0.0.1")
+	for i := 0; i < 5; i++ {
+		m.RecordFailure(context.Background(), loopback)
+	}
+	if m.IsBanned(loopback) {
+		t.Error("expect loopback never banned")
+	}
+}
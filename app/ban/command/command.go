@@ -0,0 +1,62 @@
+package command
+
+import (
+	"context"
+
+	"github.com/xtls/xray-core/app/ban"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/core"
+	featureban "github.com/xtls/xray-core/features/ban"
+	"google.golang.org/grpc"
+)
+
+type service struct {
+	UnimplementedBanServiceServer
+	v *core.Instance
+}
+
+func (s *service) manager() (*ban.Manager, error) {
+	m, ok := s.v.GetFeature(featureban.ManagerType()).(*ban.Manager)
+	if !ok {
+		return nil, errors.New("ban is not enabled")
+	}
+	return m, nil
+}
+
+func (s *service) ListBans(ctx context.Context, request *ListBansRequest) (*ListBansResponse, error) {
+	m, err := s.manager()
+	if err != nil {
+		return nil, err
+	}
+	resp := &ListBansResponse{}
+	for _, b := range m.Bans() {
+		resp.Bans = append(resp.Bans, &Ban{
+			Ip:         b.IP,
+			Failures:   uint32(b.Failures),
+			ExpireTime: b.Expire.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+func (s *service) Unban(ctx context.Context, request *UnbanRequest) (*UnbanResponse, error) {
+	m, err := s.manager()
+	if err != nil {
+		return nil, err
+	}
+	if !m.Unban(request.Ip) {
+		return nil, errors.New(request.Ip, " is not banned")
+	}
+	return &UnbanResponse{}, nil
+}
+
+func (s *service) Register(server *grpc.Server) {
+	RegisterBanServiceServer(server, s)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		return &service{v: core.MustFromContext(ctx)}, nil
+	}))
+}
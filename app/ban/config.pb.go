@@ -0,0 +1,147 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.5
+// source: app/ban/config.proto
+
+package ban
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Config is the settings for banning source IPs with too many failed
+// authentications in inbounds.
+type Config struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of failed authentications within find_time to ban an IP. Default 5.
+	MaxFailures uint32 `protobuf:"varint,1,opt,name=max_failures,json=maxFailures,proto3" json:"max_failures,omitempty"`
+	// Duration in which failed authentications are counted, in nanoseconds.
+	// Default 10 minutes.
+	FindTime int64 `protobuf:"varint,2,opt,name=find_time,json=findTime,proto3" json:"find_time,omitempty"`
+	// Duration of bans, in nanoseconds. Default 1 hour.
+	BanTime       int64 `protobuf:"varint,3,opt,name=ban_time,json=banTime,proto3" json:"ban_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	mi := &file_app_ban_config_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ban_config_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_app_ban_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Config) GetMaxFailures() uint32 {
+	if x != nil {
+		return x.MaxFailures
+	}
+	return 0
+}
+
+func (x *Config) GetFindTime() int64 {
+	if x != nil {
+		return x.FindTime
+	}
+	return 0
+}
+
+func (x *Config) GetBanTime() int64 {
+	if x != nil {
+		return x.BanTime
+	}
+	return 0
+}
+
+var File_app_ban_config_proto protoreflect.FileDescriptor
+
+const file_app_ban_config_proto_rawDesc = "" +
+	"\n" +
+	"\x14app/ban/config.proto\x12\fxray.app.ban\"c\n" +
+	"\x06Config\x12!\n" +
+	"\fmax_failures\x18\x01 \x01(\rR\vmaxFailures\x12\x1b\n" +
+	"\tfind_time\x18\x02 \x01(\x03R\bfindTime\x12\x19\n" +
+	"\bban_time\x18\x03 \x01(\x03R\abanTimeBF\n" +
+	"\x10com.xray.app.banP\x01Z!github.com/xtls/xray-core/app/ban\xaa\x02\fXray.App.Banb\x06proto3"
+
+var (
+	file_app_ban_config_proto_rawDescOnce sync.Once
+	file_app_ban_config_proto_rawDescData []byte
+)
+
+func file_app_ban_config_proto_rawDescGZIP() []byte {
+	file_app_ban_config_proto_rawDescOnce.Do(func() {
+		file_app_ban_config_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_app_ban_config_proto_rawDesc), len(file_app_ban_config_proto_rawDesc)))
+	})
+	return file_app_ban_config_proto_rawDescData
+}
+
+var file_app_ban_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_app_ban_config_proto_goTypes = []any{
+	(*Config)(nil), // 0: xray.app.ban.Config
+}
+var file_app_ban_config_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_app_ban_config_proto_init() }
+func file_app_ban_config_proto_init() {
+	if File_app_ban_config_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_ban_config_proto_rawDesc), len(file_app_ban_config_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_app_ban_config_proto_goTypes,
+		DependencyIndexes: file_app_ban_config_proto_depIdxs,
+		MessageInfos:      file_app_ban_config_proto_msgTypes,
+	}.Build()
+	File_app_ban_config_proto = out.File
+	file_app_ban_config_proto_goTypes = nil
+	file_app_ban_config_proto_depIdxs = nil
+}
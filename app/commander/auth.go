@@ -0,0 +1,214 @@
+package commander
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"strings"
+
+	"github.com/xtls/xray-core/common/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// methodScopes are the scopes required by the methods, by their full names. Methods not listed require Scope_Full.
+var methodScopes = map[string]Scope{
+	"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo":      Scope_Read,
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": Scope_Read,
+
+	"/xray.core.app.observatory.command.ObservatoryService/GetOutboundStatus":   Scope_Read,
+	"/xray.app.stats.command.StatsService/GetStats":                             Scope_Read,
+	"/xray.app.stats.command.StatsService/GetStatsOnline":                       Scope_Read,
+	"/xray.app.stats.command.StatsService/QueryStats":                           Scope_Read,
+	"/xray.app.stats.command.StatsService/GetSysStats":                          Scope_Read,
+	"/xray.app.stats.command.StatsService/GetStatsOnlineIpList":                 Scope_Read,
+	"/xray.app.stats.command.StatsService/GetAllOnlineUsers":                    Scope_Read,
+	"/xray.app.policy.command.PolicyService/GetRateLimit":                       Scope_Read,
+	"/xray.app.router.command.RoutingService/SubscribeRoutingStats":             Scope_Read,
+	"/xray.app.router.command.RoutingService/TestRoute":                         Scope_Read,
+	"/xray.app.router.command.RoutingService/GetBalancerInfo":                   Scope_Read,
+	"/xray.app.router.command.RoutingService/ListRule":                          Scope_Read,
+	"/xray.app.dispatcher.command.ConnectionService/SubscribeConnectionRecords": Scope_Read,
+	"/xray.app.dispatcher.command.ConnectionService/ListConnections":            Scope_Read,
+	"/xray.app.dns.fakedns.command.FakeDNSService/LookupFakeDNS":                Scope_Read,
+	"/xray.app.proxyman.command.HandlerService/ListInbounds":                    Scope_Read,
+	"/xray.app.proxyman.command.HandlerService/GetInboundUsers":                 Scope_Read,
+	"/xray.app.proxyman.command.HandlerService/GetInboundUsersCount":            Scope_Read,
+	"/xray.app.proxyman.command.HandlerService/ListOutbounds":                   Scope_Read,
+	"/xray.app.ban.command.BanService/ListBans":                                 Scope_Read,
+
+	"/xray.app.proxyman.command.HandlerService/AlterInbound":              Scope_Users,
+	"/xray.app.policy.command.PolicyService/SetRateLimit":                 Scope_Users,
+	"/xray.app.policy.command.PolicyService/ClearRateLimit":               Scope_Users,
+	"/xray.app.dispatcher.command.ConnectionService/CloseConnection":      Scope_Users,
+	"/xray.app.dispatcher.command.ConnectionService/CloseUserConnections": Scope_Users,
+	"/xray.app.ban.command.BanService/Unban":                              Scope_Users,
+}
+
+// requiredScope returns the scope required by the full method name like "/package.Service/Method".
+func requiredScope(fullMethod string) Scope {
+	if scope, found := methodScopes[fullMethod]; found {
+		return scope
+	}
+	return Scope_Full
+}
+
+// authenticator checks the credentials of requests.
+type authenticator struct {
+	tokens []*Credential
+	names  map[string]Scope
+	// mtls is whether client certificates are verified.
+	mtls bool
+}
+
+func newAuthenticator(config *Config) (*authenticator, error) {
+	a := &authenticator{
+		names: make(map[string]Scope),
+		mtls:  config.ClientCaFile != "",
+	}
+	for _, c := range config.Credentials {
+		if c.Token == "" && c.ClientName == "" {
+			return nil, errors.New("API credential has neither token nor client name")
+		}
+		if c.Token != "" {
+			a.tokens = append(a.tokens, c)
+		}
+		if c.ClientName != "" {
+			if !a.mtls {
+				return nil, errors.New("client name ", c.ClientName, " of API credential requires client CA")
+			}
+			a.names[c.ClientName] = c.Scope
+		}
+	}
+	return a, nil
+}
+
+// enabled returns whether requests need to be authenticated.
+func (a *authenticator) enabled() bool {
+	return len(a.tokens) > 0 || a.mtls
+}
+
+// scope returns the highest scope granted to the request, and false if it has no valid credential.
+func (a *authenticator) scope(ctx context.Context) (Scope, bool) {
+	scope, ok := Scope_Read, false
+	grant := func(s Scope) {
+		if !ok || s > scope {
+			scope = s
+		}
+		ok = true
+	}
+
+	if md, found := metadata.FromIncomingContext(ctx); found {
+		for _, v := range md.Get("authorization") {
+			token, found := strings.CutPrefix(v, "Bearer ")
+			if !found {
+				continue
+			}
+			for _, c := range a.tokens {
+				if subtle.ConstantTimeCompare([]byte(token), []byte(c.Token)) == 1 {
+					grant(c.Scope)
+				}
+			}
+		}
+	}
+
+	if p, found := peer.FromContext(ctx); found && a.mtls {
+		if info, isTLS := p.AuthInfo.(credentials.TLSInfo); isTLS && len(info.State.VerifiedChains) > 0 {
+			name := info.State.VerifiedChains[0][0].Subject.CommonName
+			if len(a.names) == 0 {
+				// The CA alone decides who may call the services.
+				grant(Scope_Full)
+			} else if s, found := a.names[name]; found {
+				grant(s)
+			}
+		}
+	}
+	return scope, ok
+}
+
+func (a *authenticator) authorize(ctx context.Context, fullMethod string) error {
+	// The gateway authorizes its requests before calling the server.
+	if p, ok := peer.FromContext(ctx); ok {
+		if _, ok := p.AuthInfo.(gatewayAuthInfo); ok {
+			return nil
+		}
+	}
+	scope, ok := a.scope(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "invalid or missing credential")
+	}
+	if required := requiredScope(fullMethod); scope < required {
+		return status.Error(codes.PermissionDenied, "credential of scope "+scope.String()+" can't call "+fullMethod)
+	}
+	return nil
+}
+
+func (a *authenticator) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *authenticator) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// newTLSConfig returns the TLS config for serving the API, or nil if TLS is not enabled.
+func newTLSConfig(config *Config) (*tls.Config, error) {
+	if config.CertificateFile == "" && config.KeyFile == "" {
+		if config.ClientCaFile != "" {
+			return nil, errors.New("client CA of API requires certificate and key")
+		}
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(config.CertificateFile, config.KeyFile)
+	if err != nil {
+		return nil, errors.New("failed to load certificate of API").Base(err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if config.ClientCaFile != "" {
+		pem, err := os.ReadFile(config.ClientCaFile)
+		if err != nil {
+			return nil, errors.New("failed to read client CA of API").Base(err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificate in client CA of API")
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// serverOptions returns the options of the grpc server for authentication and TLS.
+func (c *Commander) serverOptions() []grpc.ServerOption {
+	var options []grpc.ServerOption
+	if c.auth.enabled() {
+		options = append(options,
+			grpc.UnaryInterceptor(c.auth.unaryInterceptor),
+			grpc.StreamInterceptor(c.auth.streamInterceptor))
+	}
+	creds := insecure.NewCredentials()
+	if c.tlsConfig != nil {
+		creds = credentials.NewTLS(c.tlsConfig)
+	}
+	if len(c.gatewayListen) > 0 {
+		creds = gatewayCredentials{creds}
+	}
+	return append(options, grpc.Creds(creds))
+}
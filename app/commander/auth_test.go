@@ -0,0 +1,74 @@
+package commander
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func withToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func withClient(name string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: name}}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}},
+	})
+}
+
+func TestAuthenticator(t *testing.T) {
+	a, err := newAuthenticator(&Config{
+		Credentials: []*Credential{
+			{Token: "reader"},
+			{Token: "manager", Scope: Scope_Users},
+			{ClientName: "admin", Scope: Scope_Full},
+		},
+		ClientCaFile: "ca.pem",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		ctx    context.Context
+		method string
+		code   codes.Code
+	}{
+		{context.Background(), "/xray.app.stats.command.StatsService/QueryStats", codes.Unauthenticated},
+		{withToken("wrong"), "/xray.app.stats.command.StatsService/QueryStats", codes.Unauthenticated},
+		{withToken("reader"), "/xray.app.stats.command.StatsService/QueryStats", codes.OK},
+		{withToken("reader"), "/xray.app.proxyman.command.HandlerService/AlterInbound", codes.PermissionDenied},
+		{withToken("manager"), "/xray.app.proxyman.command.HandlerService/AlterInbound", codes.OK},
+		{withToken("manager"), "/xray.app.proxyman.command.HandlerService/AddInbound", codes.PermissionDenied},
+		{withClient("admin"), "/xray.app.proxyman.command.HandlerService/AddInbound", codes.OK},
+		{withClient("guest"), "/xray.app.stats.command.StatsService/QueryStats", codes.Unauthenticated},
+		{withToken("reader"), "/unknown.Service/Method", codes.PermissionDenied},
+		{withToken("reader"), "/unknown.Service/GetStats", codes.PermissionDenied},
+	}
+	for _, c := range cases {
+		if code := status.Code(a.authorize(c.ctx, c.method)); code != c.code {
+			t.Error("expect ", c.code, " for ", c.method, ", but got ", code)
+		}
+	}
+}
+
+func TestAuthenticatorConfig(t *testing.T) {
+	if a, err := newAuthenticator(&Config{}); err != nil || a.enabled() {
+		t.Error("expect no authentication without credentials")
+	}
+	if _, err := newAuthenticator(&Config{Credentials: []*Credential{{}}}); err == nil {
+		t.Error("expect error for empty credential")
+	}
+	if _, err := newAuthenticator(&Config{Credentials: []*Credential{{ClientName: "admin"}}}); err == nil {
+		t.Error("expect error for client name without client CA")
+	}
+}
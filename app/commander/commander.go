@@ -2,6 +2,7 @@ package commander
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"sync"
 
@@ -16,18 +17,33 @@ import (
 // Commander is a Xray feature that provides gRPC methods to external clients.
 type Commander struct {
 	sync.Mutex
-	server   *grpc.Server
-	services []Service
-	ohm      outbound.Manager
-	tag      string
-	listen   string
+	server        *grpc.Server
+	services      []Service
+	ohm           outbound.Manager
+	tag           string
+	listen        string
+	gatewayListen string
+
+	auth      *authenticator
+	tlsConfig *tls.Config
+	gateway   *gateway
 }
 
 // NewCommander creates a new Commander based on the given config.
 func NewCommander(ctx context.Context, config *Config) (*Commander, error) {
 	c := &Commander{
-		tag:    config.Tag,
-		listen: config.Listen,
+		tag:           config.Tag,
+		listen:        config.Listen,
+		gatewayListen: config.GatewayListen,
+	}
+
+	auth, err := newAuthenticator(config)
+	if err != nil {
+		return nil, err
+	}
+	c.auth = auth
+	if c.tlsConfig, err = newTLSConfig(config); err != nil {
+		return nil, err
 	}
 
 	common.Must(core.RequireFeatures(ctx, func(om outbound.Manager) {
@@ -61,10 +77,23 @@ func (c *Commander) Type() interface{} {
 // Start implements common.Runnable.
 func (c *Commander) Start() error {
 	c.Lock()
-	c.server = grpc.NewServer()
+	c.server = grpc.NewServer(c.serverOptions()...)
 	for _, service := range c.services {
 		service.Register(c.server)
 	}
+	if len(c.gatewayListen) > 0 {
+		g, err := newGateway(c.server, c.auth)
+		if err != nil {
+			c.Unlock()
+			return err
+		}
+		if err := g.start(c.gatewayListen, c.tlsConfig); err != nil {
+			g.close()
+			c.Unlock()
+			return err
+		}
+		c.gateway = g
+	}
 	c.Unlock()
 
 	var listen = func(listener net.Listener) {
@@ -110,6 +139,10 @@ func (c *Commander) Close() error {
 		c.server.Stop()
 		c.server = nil
 	}
+	if c.gateway != nil {
+		c.gateway.close()
+		c.gateway = nil
+	}
 
 	return nil
 }
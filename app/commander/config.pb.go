@@ -22,6 +22,123 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Scope is the permission of a credential. Each scope includes the ones
+// before it.
+type Scope int32
+
+const (
+	// Reading stats, status and settings.
+	Scope_Read Scope = 0
+	// Managing users, their limits and connections.
+	Scope_Users Scope = 1
+	// Managing handlers, rules and everything else.
+	Scope_Full Scope = 2
+)
+
+// Enum value maps for Scope.
+var (
+	Scope_name = map[int32]string{
+		0: "Read",
+		1: "Users",
+		2: "Full",
+	}
+	Scope_value = map[string]int32{
+		"Read":  0,
+		"Users": 1,
+		"Full":  2,
+	}
+)
+
+func (x Scope) Enum() *Scope {
+	p := new(Scope)
+	*p = x
+	return p
+}
+
+func (x Scope) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Scope) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_commander_config_proto_enumTypes[0].Descriptor()
+}
+
+func (Scope) Type() protoreflect.EnumType {
+	return &file_app_commander_config_proto_enumTypes[0]
+}
+
+func (x Scope) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Scope.Descriptor instead.
+func (Scope) EnumDescriptor() ([]byte, []int) {
+	return file_app_commander_config_proto_rawDescGZIP(), []int{0}
+}
+
+// Credential authenticates clients of the services.
+type Credential struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Bearer token in the authorization metadata of requests.
+	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// Common name of client certificates verified by client_ca_file.
+	ClientName    string `protobuf:"bytes,2,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	Scope         Scope  `protobuf:"varint,3,opt,name=scope,proto3,enum=xray.app.commander.Scope" json:"scope,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Credential) Reset() {
+	*x = Credential{}
+	mi := &file_app_commander_config_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Credential) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Credential) ProtoMessage() {}
+
+func (x *Credential) ProtoReflect() protoreflect.Message {
+	mi := &file_app_commander_config_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Credential.ProtoReflect.Descriptor instead.
+func (*Credential) Descriptor() ([]byte, []int) {
+	return file_app_commander_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Credential) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *Credential) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *Credential) GetScope() Scope {
+	if x != nil {
+		return x.Scope
+	}
+	return Scope_Read
+}
+
 // Config is the settings for Commander.
 type Config struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -31,14 +148,26 @@ type Config struct {
 	Listen string `protobuf:"bytes,3,opt,name=listen,proto3" json:"listen,omitempty"`
 	// Services that supported by this server. All services must implement Service
 	// interface.
-	Service       []*serial.TypedMessage `protobuf:"bytes,2,rep,name=service,proto3" json:"service,omitempty"`
+	Service []*serial.TypedMessage `protobuf:"bytes,2,rep,name=service,proto3" json:"service,omitempty"`
+	// Credentials allowed to call the services. Requests are not authenticated
+	// if there is neither a credential nor client_ca_file.
+	Credentials []*Credential `protobuf:"bytes,4,rep,name=credentials,proto3" json:"credentials,omitempty"`
+	// Certificate and key in PEM files, for serving with TLS.
+	CertificateFile string `protobuf:"bytes,5,opt,name=certificate_file,json=certificateFile,proto3" json:"certificate_file,omitempty"`
+	KeyFile         string `protobuf:"bytes,6,opt,name=key_file,json=keyFile,proto3" json:"key_file,omitempty"`
+	// CA in a PEM file to verify client certificates. Client certificates are
+	// required if it is set.
+	ClientCaFile string `protobuf:"bytes,7,opt,name=client_ca_file,json=clientCaFile,proto3" json:"client_ca_file,omitempty"`
+	// Network address of the HTTP gateway, which serves the services as JSON
+	// over HTTP. The gateway is disabled if it is empty.
+	GatewayListen string `protobuf:"bytes,8,opt,name=gateway_listen,json=gatewayListen,proto3" json:"gateway_listen,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Config) Reset() {
 	*x = Config{}
-	mi := &file_app_commander_config_proto_msgTypes[0]
+	mi := &file_app_commander_config_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -50,7 +179,7 @@ func (x *Config) String() string {
 func (*Config) ProtoMessage() {}
 
 func (x *Config) ProtoReflect() protoreflect.Message {
-	mi := &file_app_commander_config_proto_msgTypes[0]
+	mi := &file_app_commander_config_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -63,7 +192,7 @@ func (x *Config) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Config.ProtoReflect.Descriptor instead.
 func (*Config) Descriptor() ([]byte, []int) {
-	return file_app_commander_config_proto_rawDescGZIP(), []int{0}
+	return file_app_commander_config_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *Config) GetTag() string {
@@ -87,6 +216,41 @@ func (x *Config) GetService() []*serial.TypedMessage {
 	return nil
 }
 
+func (x *Config) GetCredentials() []*Credential {
+	if x != nil {
+		return x.Credentials
+	}
+	return nil
+}
+
+func (x *Config) GetCertificateFile() string {
+	if x != nil {
+		return x.CertificateFile
+	}
+	return ""
+}
+
+func (x *Config) GetKeyFile() string {
+	if x != nil {
+		return x.KeyFile
+	}
+	return ""
+}
+
+func (x *Config) GetClientCaFile() string {
+	if x != nil {
+		return x.ClientCaFile
+	}
+	return ""
+}
+
+func (x *Config) GetGatewayListen() string {
+	if x != nil {
+		return x.GatewayListen
+	}
+	return ""
+}
+
 // ReflectionConfig is the placeholder config for ReflectionService.
 type ReflectionConfig struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -96,7 +260,7 @@ type ReflectionConfig struct {
 
 func (x *ReflectionConfig) Reset() {
 	*x = ReflectionConfig{}
-	mi := &file_app_commander_config_proto_msgTypes[1]
+	mi := &file_app_commander_config_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -108,7 +272,7 @@ func (x *ReflectionConfig) String() string {
 func (*ReflectionConfig) ProtoMessage() {}
 
 func (x *ReflectionConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_app_commander_config_proto_msgTypes[1]
+	mi := &file_app_commander_config_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -121,19 +285,34 @@ func (x *ReflectionConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReflectionConfig.ProtoReflect.Descriptor instead.
 func (*ReflectionConfig) Descriptor() ([]byte, []int) {
-	return file_app_commander_config_proto_rawDescGZIP(), []int{1}
+	return file_app_commander_config_proto_rawDescGZIP(), []int{2}
 }
 
 var File_app_commander_config_proto protoreflect.FileDescriptor
 
 const file_app_commander_config_proto_rawDesc = "" +
 	"\n" +
-	"\x1aapp/commander/config.proto\x12\x12xray.app.commander\x1a!common/serial/typed_message.proto\"n\n" +
+	"\x1aapp/commander/config.proto\x12\x12xray.app.commander\x1a!common/serial/typed_message.proto\"t\n" +
+	"\n" +
+	"Credential\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1f\n" +
+	"\vclient_name\x18\x02 \x01(\tR\n" +
+	"clientName\x12/\n" +
+	"\x05scope\x18\x03 \x01(\x0e2\x19.xray.app.commander.ScopeR\x05scope\"\xc3\x02\n" +
 	"\x06Config\x12\x10\n" +
 	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x16\n" +
 	"\x06listen\x18\x03 \x01(\tR\x06listen\x12:\n" +
-	"\aservice\x18\x02 \x03(\v2 .xray.common.serial.TypedMessageR\aservice\"\x12\n" +
-	"\x10ReflectionConfigBX\n" +
+	"\aservice\x18\x02 \x03(\v2 .xray.common.serial.TypedMessageR\aservice\x12@\n" +
+	"\vcredentials\x18\x04 \x03(\v2\x1e.xray.app.commander.CredentialR\vcredentials\x12)\n" +
+	"\x10certificate_file\x18\x05 \x01(\tR\x0fcertificateFile\x12\x19\n" +
+	"\bkey_file\x18\x06 \x01(\tR\akeyFile\x12$\n" +
+	"\x0eclient_ca_file\x18\a \x01(\tR\fclientCaFile\x12%\n" +
+	"\x0egateway_listen\x18\b \x01(\tR\rgatewayListen\"\x12\n" +
+	"\x10ReflectionConfig*&\n" +
+	"\x05Scope\x12\b\n" +
+	"\x04Read\x10\x00\x12\t\n" +
+	"\x05Users\x10\x01\x12\b\n" +
+	"\x04Full\x10\x02BX\n" +
 	"\x16com.xray.app.commanderP\x01Z'github.com/xtls/xray-core/app/commander\xaa\x02\x12Xray.App.Commanderb\x06proto3"
 
 var (
@@ -148,19 +327,24 @@ func file_app_commander_config_proto_rawDescGZIP() []byte {
 	return file_app_commander_config_proto_rawDescData
 }
 
-var file_app_commander_config_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_app_commander_config_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_app_commander_config_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_app_commander_config_proto_goTypes = []any{
-	(*Config)(nil),              // 0: xray.app.commander.Config
-	(*ReflectionConfig)(nil),    // 1: xray.app.commander.ReflectionConfig
-	(*serial.TypedMessage)(nil), // 2: xray.common.serial.TypedMessage
+	(Scope)(0),                  // 0: xray.app.commander.Scope
+	(*Credential)(nil),          // 1: xray.app.commander.Credential
+	(*Config)(nil),              // 2: xray.app.commander.Config
+	(*ReflectionConfig)(nil),    // 3: xray.app.commander.ReflectionConfig
+	(*serial.TypedMessage)(nil), // 4: xray.common.serial.TypedMessage
 }
 var file_app_commander_config_proto_depIdxs = []int32{
-	2, // 0: xray.app.commander.Config.service:type_name -> xray.common.serial.TypedMessage
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0, // 0: xray.app.commander.Credential.scope:type_name -> xray.app.commander.Scope
+	4, // 1: xray.app.commander.Config.service:type_name -> xray.common.serial.TypedMessage
+	1, // 2: xray.app.commander.Config.credentials:type_name -> xray.app.commander.Credential
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_app_commander_config_proto_init() }
@@ -173,13 +357,14 @@ func file_app_commander_config_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_commander_config_proto_rawDesc), len(file_app_commander_config_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      1,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_app_commander_config_proto_goTypes,
 		DependencyIndexes: file_app_commander_config_proto_depIdxs,
+		EnumInfos:         file_app_commander_config_proto_enumTypes,
 		MessageInfos:      file_app_commander_config_proto_msgTypes,
 	}.Build()
 	File_app_commander_config_proto = out.File
@@ -0,0 +1,377 @@
+package commander
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/signal/done"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// maxGatewayRequestSize is the limit of the size of request bodies of the gateway.
+const maxGatewayRequestSize = 4 * 1024 * 1024
+
+// gatewayMethod is a method served by the gateway.
+type gatewayMethod struct {
+	input  protoreflect.MessageType
+	output protoreflect.MessageType
+	stream bool
+}
+
+// gateway serves the services as JSON over HTTP. The path of a method is its full gRPC
+// name like "/xray.app.stats.command.StatsService/QueryStats", the request message is the
+// body, and the response message is returned in JSON. Server streaming methods return the
+// messages as server-sent events, and can also be called with GET.
+//
+// The gateway calls the gRPC server of the API through in-memory connections, which the
+// server accepts without handshake or authentication, as the gateway authenticates the
+// requests itself.
+type gateway struct {
+	auth     *authenticator
+	listener *OutboundListener
+	conn     *grpc.ClientConn
+	methods  map[string]*gatewayMethod
+	http     *http.Server
+	// addr is the address the gateway listens on.
+	addr *net.TCPAddr
+}
+
+func newGateway(server *grpc.Server, auth *authenticator) (*gateway, error) {
+	g := &gateway{
+		auth: auth,
+		listener: &OutboundListener{
+			buffer: make(chan net.Conn, 4),
+			done:   done.New(),
+		},
+		methods: make(map[string]*gatewayMethod),
+	}
+
+	for name, info := range server.GetServiceInfo() {
+		d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			// Services registered under legacy names have no descriptors.
+			continue
+		}
+		sd, ok := d.(protoreflect.ServiceDescriptor)
+		if !ok {
+			continue
+		}
+		for _, m := range info.Methods {
+			md := sd.Methods().ByName(protoreflect.Name(m.Name))
+			if md == nil || m.IsClientStream {
+				continue
+			}
+			input, err := protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName())
+			if err != nil {
+				continue
+			}
+			output, err := protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+			if err != nil {
+				continue
+			}
+			g.methods["/"+name+"/"+m.Name] = &gatewayMethod{
+				input:  input,
+				output: output,
+				stream: m.IsServerStream,
+			}
+		}
+	}
+
+	conn, err := grpc.NewClient("passthrough:///gateway",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			client, server := net.Pipe()
+			g.listener.add(&gatewayConn{server})
+			return client, nil
+		}))
+	if err != nil {
+		return nil, err
+	}
+	g.conn = conn
+	go server.Serve(g.listener)
+	return g, nil
+}
+
+// start serves the gateway on the address, with TLS if tlsConfig is not nil.
+func (g *gateway) start(address string, tlsConfig *tls.Config) error {
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.New("API gateway failed to listen on ", address).Base(err)
+	}
+	g.addr = l.Addr().(*net.TCPAddr)
+	if tlsConfig != nil {
+		l = tls.NewListener(l, tlsConfig)
+	}
+	g.http = &http.Server{
+		Handler:           g,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errors.LogInfo(context.Background(), "API gateway listening on ", l.Addr())
+	go func() {
+		if err := g.http.Serve(l); err != nil && err != http.ErrServerClosed {
+			errors.LogErrorInner(context.Background(), err, "failed to serve API gateway")
+		}
+	}()
+	return nil
+}
+
+func (g *gateway) close() {
+	if g.http != nil {
+		g.http.Close()
+	}
+	g.conn.Close()
+	g.listener.Close()
+}
+
+// ServeHTTP implements http.Handler.
+func (g *gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method, found := g.methods[r.URL.Path]
+	if !found {
+		writeGatewayError(w, status.Error(codes.Unimplemented, "unknown method "+r.URL.Path))
+		return
+	}
+	if r.Method != http.MethodPost && (r.Method != http.MethodGet || !method.stream) {
+		if method.stream {
+			w.Header().Set("Allow", "GET, POST")
+		} else {
+			w.Header().Set("Allow", "POST")
+		}
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Method == http.MethodPost {
+		if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+			http.Error(w, "content type must be application/json", http.StatusUnsupportedMediaType)
+			return
+		}
+	}
+	// Browsers send requests of other sites to local addresses too, and may resolve
+	// names of other sites to them, so only requests to the gateway itself are served.
+	if !g.isOwnHost(r.Host, r.TLS != nil) {
+		http.Error(w, "host not allowed", http.StatusForbidden)
+		return
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || !g.isOwnHost(u.Host, u.Scheme == "https") {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+	}
+
+	if g.auth.enabled() {
+		ctx := metadata.NewIncomingContext(r.Context(), metadata.Pairs("authorization", r.Header.Get("Authorization")))
+		if r.TLS != nil {
+			ctx = peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{State: *r.TLS}})
+		}
+		if err := g.auth.authorize(ctx, r.URL.Path); err != nil {
+			writeGatewayError(w, err)
+			return
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxGatewayRequestSize))
+	if err != nil {
+		writeGatewayError(w, status.Error(codes.InvalidArgument, err.Error()))
+		return
+	}
+	request := method.input.New().Interface()
+	if body = bytes.TrimSpace(body); len(body) > 0 {
+		if err := protojson.Unmarshal(body, request); err != nil {
+			writeGatewayError(w, status.Error(codes.InvalidArgument, "invalid request: "+err.Error()))
+			return
+		}
+	}
+
+	if !method.stream {
+		response := method.output.New().Interface()
+		if err := g.conn.Invoke(r.Context(), r.URL.Path, request, response); err != nil {
+			writeGatewayError(w, err)
+			return
+		}
+		b, err := protojson.Marshal(response)
+		if err != nil {
+			writeGatewayError(w, status.Error(codes.Internal, err.Error()))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(b)
+		return
+	}
+
+	stream, err := g.conn.NewStream(r.Context(), &grpc.StreamDesc{ServerStreams: true}, r.URL.Path)
+	if err == nil {
+		err = stream.SendMsg(request)
+	}
+	if err == nil {
+		err = stream.CloseSend()
+	}
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+	for {
+		response := method.output.New().Interface()
+		err := stream.RecvMsg(response)
+		if err == io.EOF {
+			return
+		}
+		var b []byte
+		if err == nil {
+			b, err = protojson.Marshal(response)
+		}
+		if err != nil {
+			// The status is already sent, so the error is sent as an event.
+			fmt.Fprintf(w, "event: error\ndata: %s\n\n", gatewayErrorJSON(err))
+			return
+		}
+		fmt.Fprintf(w, "data: %s\n\n", b)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// isOwnHost returns whether the host of a request, or of its origin, is the address the gateway listens on.
+func (g *gateway) isOwnHost(host string, secure bool) bool {
+	name, port, err := net.SplitHostPort(host)
+	if err != nil {
+		name, port = strings.Trim(host, "[]"), "80"
+		if secure {
+			port = "443"
+		}
+	}
+	if port != strconv.Itoa(g.addr.Port) {
+		return false
+	}
+	// Names other than localhost may be resolved to the gateway by anyone, so only addresses are accepted.
+	if strings.EqualFold(name, "localhost") {
+		return g.addr.IP.IsLoopback() || g.addr.IP.IsUnspecified()
+	}
+	ip := net.ParseIP(name)
+	if ip == nil {
+		return false
+	}
+	if g.addr.IP.IsUnspecified() {
+		return ip.IsLoopback() || isLocalIP(ip)
+	}
+	return ip.Equal(g.addr.IP)
+}
+
+// isLocalIP returns whether the IP is an address of a local interface.
+func isLocalIP(ip net.IP) bool {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return false
+	}
+	for _, addr := range addrs {
+		if n, ok := addr.(*net.IPNet); ok && n.IP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// gatewayConn is an in-memory connection of the gateway to the gRPC server.
+type gatewayConn struct {
+	net.Conn
+}
+
+// gatewayAuthInfo is the credentials.AuthInfo of the connections of the gateway.
+type gatewayAuthInfo struct {
+	credentials.CommonAuthInfo
+}
+
+// AuthType implements credentials.AuthInfo.
+func (gatewayAuthInfo) AuthType() string {
+	return "gateway"
+}
+
+// gatewayCredentials are the transport credentials of the gRPC server, which accept the
+// connections of the gateway as they are, and the others with the wrapped credentials.
+type gatewayCredentials struct {
+	credentials.TransportCredentials
+}
+
+// ServerHandshake implements credentials.TransportCredentials.
+func (c gatewayCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	if _, ok := conn.(*gatewayConn); ok {
+		return conn, gatewayAuthInfo{credentials.CommonAuthInfo{SecurityLevel: credentials.PrivacyAndIntegrity}}, nil
+	}
+	return c.TransportCredentials.ServerHandshake(conn)
+}
+
+// Clone implements credentials.TransportCredentials.
+func (c gatewayCredentials) Clone() credentials.TransportCredentials {
+	return gatewayCredentials{c.TransportCredentials.Clone()}
+}
+
+// gatewayErrorJSON returns the gRPC status of err in JSON.
+func gatewayErrorJSON(err error) []byte {
+	b, _ := protojson.Marshal(status.Convert(err).Proto())
+	return b
+}
+
+// writeGatewayError writes the gRPC status of err, with the corresponding HTTP status.
+func writeGatewayError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus(status.Code(err)))
+	w.Write(gatewayErrorJSON(err))
+}
+
+// httpStatus maps gRPC codes to HTTP status codes.
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,170 @@
+package commander
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	routercommand "github.com/xtls/xray-core/app/router/command"
+	"github.com/xtls/xray-core/app/stats"
+	statscommand "github.com/xtls/xray-core/app/stats/command"
+	"github.com/xtls/xray-core/common"
+	"google.golang.org/grpc"
+)
+
+func TestGateway(t *testing.T) {
+	manager, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+	counter, err := manager.RegisterCounter("user>>>alice>>>traffic>>>uplink")
+	common.Must(err)
+	counter.Set(42)
+
+	channel := stats.NewChannel(&stats.ChannelConfig{SubscriberLimit: 1, BufferSize: 16})
+	common.Must(channel.Start())
+	defer channel.Close()
+
+	auth, err := newAuthenticator(&Config{Credentials: []*Credential{{Token: "secret"}}})
+	common.Must(err)
+	c := &Commander{auth: auth, gatewayListen: "127.0.0.1:0"}
+	server := grpc.NewServer(c.serverOptions()...)
+	defer server.Stop()
+	statscommand.RegisterStatsServiceServer(server, statscommand.NewStatsServer(manager))
+	routercommand.RegisterRoutingServiceServer(server, routercommand.NewRoutingServer(nil, channel))
+	g, err := newGateway(server, auth)
+	common.Must(err)
+	defer g.close()
+	common.Must(g.start(c.gatewayListen, nil))
+
+	do := func(method string, path string, body string, header http.Header) *http.Response {
+		req, err := http.NewRequest(method, "http://"+g.addr.String()+path, strings.NewReader(body))
+		common.Must(err)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		if host := header.Get("Host"); host != "" {
+			req.Host = host
+		}
+		resp, err := http.DefaultClient.Do(req)
+		common.Must(err)
+		return resp
+	}
+	call := func(path string, body string, token string) *http.Response {
+		header := http.Header{"Content-Type": {"application/json"}}
+		if token != "" {
+			header.Set("Authorization", "Bearer "+token)
+		}
+		return do(http.MethodPost, path, body, header)
+	}
+
+	resp := call("/xray.app.stats.command.StatsService/GetStats", `{"name": "user>>>alice>>>traffic>>>uplink"}`, "")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Error("expect unauthorized without token, but got ", resp.Status)
+	}
+
+	resp = call("/xray.app.stats.command.StatsService/GetStats", `{"name": "user>>>alice>>>traffic>>>uplink"}`, "secret")
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(b), `"value":"42"`) {
+		t.Error("unexpected response ", resp.Status, " ", string(b))
+	}
+
+	resp = call("/xray.app.stats.command.StatsService/GetStats", `{"name": "unknown"}`, "secret")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Error("expect not found for unknown counter, but got ", resp.Status)
+	}
+
+	resp = call("/xray.app.stats.command.StatsService/Unknown", `{}`, "secret")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Error("expect not implemented for unknown method, but got ", resp.Status)
+	}
+
+	for _, c := range []struct {
+		method string
+		header http.Header
+		status int
+	}{
+		{http.MethodPost, http.Header{"Authorization": {"Bearer secret"}}, http.StatusUnsupportedMediaType},
+		{http.MethodPost, http.Header{"Authorization": {"Bearer secret"}, "Content-Type": {"text/plain"}}, http.StatusUnsupportedMediaType},
+		{http.MethodGet, http.Header{"Authorization": {"Bearer secret"}}, http.StatusMethodNotAllowed},
+		{http.MethodPost, http.Header{"Authorization": {"Bearer secret"}, "Content-Type": {"application/json"}, "Host": {"example.com"}}, http.StatusForbidden},
+		{http.MethodPost, http.Header{"Authorization": {"Bearer secret"}, "Content-Type": {"application/json"}, "Origin": {"http://example.com"}}, http.StatusForbidden},
+		{http.MethodPost, http.Header{"Authorization": {"Bearer secret"}, "Content-Type": {"application/json; charset=utf-8"}, "Origin": {"http://" + g.addr.String()}}, http.StatusOK},
+	} {
+		resp := do(c.method, "/xray.app.stats.command.StatsService/GetStats", `{"name": "user>>>alice>>>traffic>>>uplink"}`, c.header)
+		resp.Body.Close()
+		if resp.StatusCode != c.status {
+			t.Error("expect ", c.status, " for ", c.method, " ", c.header, ", but got ", resp.Status)
+		}
+	}
+
+	resp = do(http.MethodGet, "/xray.app.router.command.RoutingService/SubscribeRoutingStats", ``, http.Header{"Authorization": {"Bearer secret"}})
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatal("expect server-sent events, but got ", resp.Header.Get("Content-Type"))
+	}
+	go func() {
+		for len(channel.Subscribers()) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		channel.Publish(context.Background(), routercommand.AsRoutingRoute(&routercommand.RoutingContext{InboundTag: "in", OutboundTag: "out"}))
+	}()
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	common.Must(err)
+	if !strings.HasPrefix(line, "data: ") || !strings.Contains(line, `"OutboundTag":"out"`) {
+		t.Error("unexpected event ", line)
+	}
+}
+
+func TestGatewayHostOfUnspecifiedListen(t *testing.T) {
+	manager, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+	_, err = manager.RegisterCounter("c")
+	common.Must(err)
+
+	auth, err := newAuthenticator(&Config{})
+	common.Must(err)
+	c := &Commander{auth: auth, gatewayListen: "0.0.0.0:0"}
+	server := grpc.NewServer(c.serverOptions()...)
+	defer server.Stop()
+	statscommand.RegisterStatsServiceServer(server, statscommand.NewStatsServer(manager))
+	g, err := newGateway(server, auth)
+	common.Must(err)
+	defer g.close()
+	common.Must(g.start(c.gatewayListen, nil))
+	port := strconv.Itoa(g.addr.Port)
+
+	for _, c := range []struct {
+		host   string
+		origin string
+		status int
+	}{
+		{"127.0.0.1:" + port, "", http.StatusOK},
+		{"localhost:" + port, "http://localhost:" + port, http.StatusOK},
+		{"evil.example:" + port, "", http.StatusForbidden},
+		{"127.0.0.1:" + port, "http://evil.example:" + port, http.StatusForbidden},
+		{"192.0.2.1:" + port, "", http.StatusForbidden},
+	} {
+		req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:"+port+"/xray.app.stats.command.StatsService/GetStats", strings.NewReader(`{"name": "c"}`))
+		common.Must(err)
+		req.Host = c.host
+		req.Header.Set("Content-Type", "application/json")
+		if c.origin != "" {
+			req.Header.Set("Origin", c.origin)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		common.Must(err)
+		resp.Body.Close()
+		if resp.StatusCode != c.status {
+			t.Error("expect ", c.status, " for host ", c.host, " and origin ", c.origin, ", but got ", resp.Status)
+		}
+	}
+}
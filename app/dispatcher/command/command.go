@@ -0,0 +1,160 @@
+package command
+
+import (
+	"context"
+	"sort"
+
+	"github.com/xtls/xray-core/app/dispatcher"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/features/stats"
+	grpc "google.golang.org/grpc"
+)
+
+// ConnectionManager is the dispatcher which keeps active connections.
+type ConnectionManager interface {
+	EnableConnectionTracking()
+	ListConnections() []*dispatcher.ConnectionRecord
+	CloseConnection(id uint64) bool
+	CloseUserConnections(email string) int
+}
+
+// connectionServer is an implementation of ConnectionService.
+type connectionServer struct {
+	stats       stats.Manager
+	connections ConnectionManager
+}
+
+func NewConnectionServer(sm stats.Manager, cm ConnectionManager) ConnectionServiceServer {
+	if cm != nil {
+		cm.EnableConnectionTracking()
+	}
+	return &connectionServer{
+		stats:       sm,
+		connections: cm,
+	}
+}
+
+// toProtoRecord converts a connection record to its protobuf message.
+func toProtoRecord(r *dispatcher.ConnectionRecord) *ConnectionRecord {
+	record := &ConnectionRecord{
+		Id:          r.ID,
+		InboundTag:  r.InboundTag,
+		OutboundTag: r.OutboundTag,
+		RuleTag:     r.RuleTag,
+		Email:       r.Email,
+		Domain:      r.Domain,
+		Protocol:    r.Protocol,
+		StartTime:   r.Start.UnixMilli(),
+		DurationMs:  r.Duration.Milliseconds(),
+		Uplink:      r.Uplink,
+		Downlink:    r.Downlink,
+		Reason:      r.Reason,
+		Closed:      r.Closed,
+	}
+	if r.Source.IsValid() {
+		record.Source = r.Source.String()
+	}
+	if r.Destination.IsValid() {
+		record.Destination = r.Destination.String()
+	}
+	return record
+}
+
+func (s *connectionServer) SubscribeConnectionRecords(request *SubscribeConnectionRecordsRequest, stream ConnectionService_SubscribeConnectionRecordsServer) error {
+	channel := s.stats.GetChannel(dispatcher.ConnectionRecordChannel)
+	if channel == nil {
+		return errors.New("connection records are not available")
+	}
+	subscriber, err := stats.SubscribeRunnableChannel(channel)
+	if err != nil {
+		return err
+	}
+	defer stats.UnsubscribeClosableChannel(channel, subscriber)
+	for {
+		select {
+		case value, ok := <-subscriber:
+			if !ok {
+				return errors.New("upstream closed the subscriber channel")
+			}
+			record, ok := value.(*dispatcher.ConnectionRecord)
+			if !ok {
+				return errors.New("upstream sent malformed connection record")
+			}
+			if !record.Closed && !request.Opened {
+				continue
+			}
+			if err := stream.Send(toProtoRecord(record)); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+func (s *connectionServer) ListConnections(ctx context.Context, request *ListConnectionsRequest) (*ListConnectionsResponse, error) {
+	if s.connections == nil {
+		return nil, errors.New("dispatcher does not track connections")
+	}
+	response := &ListConnectionsResponse{}
+	for _, record := range s.connections.ListConnections() {
+		if request.Email != "" && record.Email != request.Email {
+			continue
+		}
+		response.Connections = append(response.Connections, toProtoRecord(record))
+	}
+	sort.Slice(response.Connections, func(i, j int) bool {
+		return response.Connections[i].Id < response.Connections[j].Id
+	})
+	return response, nil
+}
+
+func (s *connectionServer) CloseConnection(ctx context.Context, request *CloseConnectionRequest) (*CloseConnectionResponse, error) {
+	if s.connections == nil {
+		return nil, errors.New("dispatcher does not track connections")
+	}
+	if !s.connections.CloseConnection(request.Id) {
+		return nil, errors.New("connection not found: ", request.Id)
+	}
+	return &CloseConnectionResponse{}, nil
+}
+
+func (s *connectionServer) CloseUserConnections(ctx context.Context, request *CloseUserConnectionsRequest) (*CloseUserConnectionsResponse, error) {
+	if s.connections == nil {
+		return nil, errors.New("dispatcher does not track connections")
+	}
+	if request.Email == "" {
+		return nil, errors.New("email is not specified")
+	}
+	return &CloseUserConnectionsResponse{
+		Count: uint32(s.connections.CloseUserConnections(request.Email)),
+	}, nil
+}
+
+func (s *connectionServer) mustEmbedUnimplementedConnectionServiceServer() {}
+
+type service struct {
+	statsManager stats.Manager
+	dispatcher   routing.Dispatcher
+}
+
+func (s *service) Register(server *grpc.Server) {
+	cm, _ := s.dispatcher.(ConnectionManager)
+	RegisterConnectionServiceServer(server, NewConnectionServer(s.statsManager, cm))
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		s := new(service)
+
+		core.RequireFeatures(ctx, func(sm stats.Manager, d routing.Dispatcher) {
+			s.statsManager = sm
+			s.dispatcher = d
+		})
+
+		return s, nil
+	}))
+}
@@ -0,0 +1,634 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.5
+// source: app/dispatcher/command/command.proto
+
+package command
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Config struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_app_dispatcher_command_command_proto_rawDescGZIP(), []int{0}
+}
+
+// ConnectionRecord is the summary of an active or closed connection.
+type ConnectionRecord struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	InboundTag  string                 `protobuf:"bytes,1,opt,name=inbound_tag,json=inboundTag,proto3" json:"inbound_tag,omitempty"`
+	OutboundTag string                 `protobuf:"bytes,2,opt,name=outbound_tag,json=outboundTag,proto3" json:"outbound_tag,omitempty"`
+	RuleTag     string                 `protobuf:"bytes,3,opt,name=rule_tag,json=ruleTag,proto3" json:"rule_tag,omitempty"`
+	Email       string                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	// Source and destination, like "tcp:1.2.3.4:443".
+	Source      string `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`
+	Destination string `protobuf:"bytes,6,opt,name=destination,proto3" json:"destination,omitempty"`
+	// Sniffed domain and protocol.
+	Domain   string `protobuf:"bytes,7,opt,name=domain,proto3" json:"domain,omitempty"`
+	Protocol string `protobuf:"bytes,8,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	// Unix time in milliseconds.
+	StartTime int64 `protobuf:"varint,9,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// Duration and traffic so far, if not closed.
+	DurationMs int64  `protobuf:"varint,10,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	Uplink     uint64 `protobuf:"varint,11,opt,name=uplink,proto3" json:"uplink,omitempty"`
+	Downlink   uint64 `protobuf:"varint,12,opt,name=downlink,proto3" json:"downlink,omitempty"`
+	// Error closing the connection, empty if closed normally.
+	Reason        string `protobuf:"bytes,13,opt,name=reason,proto3" json:"reason,omitempty"`
+	Id            uint64 `protobuf:"varint,14,opt,name=id,proto3" json:"id,omitempty"`
+	Closed        bool   `protobuf:"varint,15,opt,name=closed,proto3" json:"closed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConnectionRecord) Reset() {
+	*x = ConnectionRecord{}
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConnectionRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectionRecord) ProtoMessage() {}
+
+func (x *ConnectionRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectionRecord.ProtoReflect.Descriptor instead.
+func (*ConnectionRecord) Descriptor() ([]byte, []int) {
+	return file_app_dispatcher_command_command_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ConnectionRecord) GetInboundTag() string {
+	if x != nil {
+		return x.InboundTag
+	}
+	return ""
+}
+
+func (x *ConnectionRecord) GetOutboundTag() string {
+	if x != nil {
+		return x.OutboundTag
+	}
+	return ""
+}
+
+func (x *ConnectionRecord) GetRuleTag() string {
+	if x != nil {
+		return x.RuleTag
+	}
+	return ""
+}
+
+func (x *ConnectionRecord) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *ConnectionRecord) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ConnectionRecord) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *ConnectionRecord) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *ConnectionRecord) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *ConnectionRecord) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *ConnectionRecord) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *ConnectionRecord) GetUplink() uint64 {
+	if x != nil {
+		return x.Uplink
+	}
+	return 0
+}
+
+func (x *ConnectionRecord) GetDownlink() uint64 {
+	if x != nil {
+		return x.Downlink
+	}
+	return 0
+}
+
+func (x *ConnectionRecord) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ConnectionRecord) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ConnectionRecord) GetClosed() bool {
+	if x != nil {
+		return x.Closed
+	}
+	return false
+}
+
+type SubscribeConnectionRecordsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Also send records when connections are opened, not only when closed.
+	Opened        bool `protobuf:"varint,1,opt,name=opened,proto3" json:"opened,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeConnectionRecordsRequest) Reset() {
+	*x = SubscribeConnectionRecordsRequest{}
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeConnectionRecordsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeConnectionRecordsRequest) ProtoMessage() {}
+
+func (x *SubscribeConnectionRecordsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeConnectionRecordsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeConnectionRecordsRequest) Descriptor() ([]byte, []int) {
+	return file_app_dispatcher_command_command_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SubscribeConnectionRecordsRequest) GetOpened() bool {
+	if x != nil {
+		return x.Opened
+	}
+	return false
+}
+
+type ListConnectionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only list connections of the user, if not empty.
+	Email         string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListConnectionsRequest) Reset() {
+	*x = ListConnectionsRequest{}
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConnectionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConnectionsRequest) ProtoMessage() {}
+
+func (x *ListConnectionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConnectionsRequest.ProtoReflect.Descriptor instead.
+func (*ListConnectionsRequest) Descriptor() ([]byte, []int) {
+	return file_app_dispatcher_command_command_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListConnectionsRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type ListConnectionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Connections   []*ConnectionRecord    `protobuf:"bytes,1,rep,name=connections,proto3" json:"connections,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListConnectionsResponse) Reset() {
+	*x = ListConnectionsResponse{}
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConnectionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConnectionsResponse) ProtoMessage() {}
+
+func (x *ListConnectionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConnectionsResponse.ProtoReflect.Descriptor instead.
+func (*ListConnectionsResponse) Descriptor() ([]byte, []int) {
+	return file_app_dispatcher_command_command_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListConnectionsResponse) GetConnections() []*ConnectionRecord {
+	if x != nil {
+		return x.Connections
+	}
+	return nil
+}
+
+type CloseConnectionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseConnectionRequest) Reset() {
+	*x = CloseConnectionRequest{}
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseConnectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseConnectionRequest) ProtoMessage() {}
+
+func (x *CloseConnectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseConnectionRequest.ProtoReflect.Descriptor instead.
+func (*CloseConnectionRequest) Descriptor() ([]byte, []int) {
+	return file_app_dispatcher_command_command_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CloseConnectionRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CloseConnectionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseConnectionResponse) Reset() {
+	*x = CloseConnectionResponse{}
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseConnectionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseConnectionResponse) ProtoMessage() {}
+
+func (x *CloseConnectionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseConnectionResponse.ProtoReflect.Descriptor instead.
+func (*CloseConnectionResponse) Descriptor() ([]byte, []int) {
+	return file_app_dispatcher_command_command_proto_rawDescGZIP(), []int{6}
+}
+
+type CloseUserConnectionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseUserConnectionsRequest) Reset() {
+	*x = CloseUserConnectionsRequest{}
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseUserConnectionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseUserConnectionsRequest) ProtoMessage() {}
+
+func (x *CloseUserConnectionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseUserConnectionsRequest.ProtoReflect.Descriptor instead.
+func (*CloseUserConnectionsRequest) Descriptor() ([]byte, []int) {
+	return file_app_dispatcher_command_command_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CloseUserConnectionsRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type CloseUserConnectionsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of closed connections.
+	Count         uint32 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseUserConnectionsResponse) Reset() {
+	*x = CloseUserConnectionsResponse{}
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseUserConnectionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseUserConnectionsResponse) ProtoMessage() {}
+
+func (x *CloseUserConnectionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dispatcher_command_command_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseUserConnectionsResponse.ProtoReflect.Descriptor instead.
+func (*CloseUserConnectionsResponse) Descriptor() ([]byte, []int) {
+	return file_app_dispatcher_command_command_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CloseUserConnectionsResponse) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+var File_app_dispatcher_command_command_proto protoreflect.FileDescriptor
+
+const file_app_dispatcher_command_command_proto_rawDesc = "" +
+	"\n" +
+	"$app/dispatcher/command/command.proto\x12\x1bxray.app.dispatcher.command\"\b\n" +
+	"\x06Config\"\xa9\x03\n" +
+	"\x10ConnectionRecord\x12\x1f\n" +
+	"\vinbound_tag\x18\x01 \x01(\tR\n" +
+	"inboundTag\x12!\n" +
+	"\foutbound_tag\x18\x02 \x01(\tR\voutboundTag\x12\x19\n" +
+	"\brule_tag\x18\x03 \x01(\tR\aruleTag\x12\x14\n" +
+	"\x05email\x18\x04 \x01(\tR\x05email\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06source\x12 \n" +
+	"\vdestination\x18\x06 \x01(\tR\vdestination\x12\x16\n" +
+	"\x06domain\x18\a \x01(\tR\x06domain\x12\x1a\n" +
+	"\bprotocol\x18\b \x01(\tR\bprotocol\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\t \x01(\x03R\tstartTime\x12\x1f\n" +
+	"\vduration_ms\x18\n" +
+	" \x01(\x03R\n" +
+	"durationMs\x12\x16\n" +
+	"\x06uplink\x18\v \x01(\x04R\x06uplink\x12\x1a\n" +
+	"\bdownlink\x18\f \x01(\x04R\bdownlink\x12\x16\n" +
+	"\x06reason\x18\r \x01(\tR\x06reason\x12\x0e\n" +
+	"\x02id\x18\x0e \x01(\x04R\x02id\x12\x16\n" +
+	"\x06closed\x18\x0f \x01(\bR\x06closed\";\n" +
+	"!SubscribeConnectionRecordsRequest\x12\x16\n" +
+	"\x06opened\x18\x01 \x01(\bR\x06opened\".\n" +
+	"\x16ListConnectionsRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"j\n" +
+	"\x17ListConnectionsResponse\x12O\n" +
+	"\vconnections\x18\x01 \x03(\v2-.xray.app.dispatcher.command.ConnectionRecordR\vconnections\"(\n" +
+	"\x16CloseConnectionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\x19\n" +
+	"\x17CloseConnectionResponse\"3\n" +
+	"\x1bCloseUserConnectionsRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"4\n" +
+	"\x1cCloseUserConnectionsResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\rR\x05count2\xb5\x04\n" +
+	"\x11ConnectionService\x12\x8f\x01\n" +
+	"\x1aSubscribeConnectionRecords\x12>.xray.app.dispatcher.command.SubscribeConnectionRecordsRequest\x1a-.xray.app.dispatcher.command.ConnectionRecord\"\x000\x01\x12~\n" +
+	"\x0fListConnections\x123.xray.app.dispatcher.command.ListConnectionsRequest\x1a4.xray.app.dispatcher.command.ListConnectionsResponse\"\x00\x12~\n" +
+	"\x0fCloseConnection\x123.xray.app.dispatcher.command.CloseConnectionRequest\x1a4.xray.app.dispatcher.command.CloseConnectionResponse\"\x00\x12\x8d\x01\n" +
+	"\x14CloseUserConnections\x128.xray.app.dispatcher.command.CloseUserConnectionsRequest\x1a9.xray.app.dispatcher.command.CloseUserConnectionsResponse\"\x00Bs\n" +
+	"\x1fcom.xray.app.dispatcher.commandP\x01Z0github.com/xtls/xray-core/app/dispatcher/command\xaa\x02\x1bXray.App.Dispatcher.Commandb\x06proto3"
+
+var (
+	file_app_dispatcher_command_command_proto_rawDescOnce sync.Once
+	file_app_dispatcher_command_command_proto_rawDescData []byte
+)
+
+func file_app_dispatcher_command_command_proto_rawDescGZIP() []byte {
+	file_app_dispatcher_command_command_proto_rawDescOnce.Do(func() {
+		file_app_dispatcher_command_command_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_app_dispatcher_command_command_proto_rawDesc), len(file_app_dispatcher_command_command_proto_rawDesc)))
+	})
+	return file_app_dispatcher_command_command_proto_rawDescData
+}
+
+var file_app_dispatcher_command_command_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_app_dispatcher_command_command_proto_goTypes = []any{
+	(*Config)(nil),                            // 0: xray.app.dispatcher.command.Config
+	(*ConnectionRecord)(nil),                  // 1: xray.app.dispatcher.command.ConnectionRecord
+	(*SubscribeConnectionRecordsRequest)(nil), // 2: xray.app.dispatcher.command.SubscribeConnectionRecordsRequest
+	(*ListConnectionsRequest)(nil),            // 3: xray.app.dispatcher.command.ListConnectionsRequest
+	(*ListConnectionsResponse)(nil),           // 4: xray.app.dispatcher.command.ListConnectionsResponse
+	(*CloseConnectionRequest)(nil),            // 5: xray.app.dispatcher.command.CloseConnectionRequest
+	(*CloseConnectionResponse)(nil),           // 6: xray.app.dispatcher.command.CloseConnectionResponse
+	(*CloseUserConnectionsRequest)(nil),       // 7: xray.app.dispatcher.command.CloseUserConnectionsRequest
+	(*CloseUserConnectionsResponse)(nil),      // 8: xray.app.dispatcher.command.CloseUserConnectionsResponse
+}
+var file_app_dispatcher_command_command_proto_depIdxs = []int32{
+	1, // 0: xray.app.dispatcher.command.ListConnectionsResponse.connections:type_name -> xray.app.dispatcher.command.ConnectionRecord
+	2, // 1: xray.app.dispatcher.command.ConnectionService.SubscribeConnectionRecords:input_type -> xray.app.dispatcher.command.SubscribeConnectionRecordsRequest
+	3, // 2: xray.app.dispatcher.command.ConnectionService.ListConnections:input_type -> xray.app.dispatcher.command.ListConnectionsRequest
+	5, // 3: xray.app.dispatcher.command.ConnectionService.CloseConnection:input_type -> xray.app.dispatcher.command.CloseConnectionRequest
+	7, // 4: xray.app.dispatcher.command.ConnectionService.CloseUserConnections:input_type -> xray.app.dispatcher.command.CloseUserConnectionsRequest
+	1, // 5: xray.app.dispatcher.command.ConnectionService.SubscribeConnectionRecords:output_type -> xray.app.dispatcher.command.ConnectionRecord
+	4, // 6: xray.app.dispatcher.command.ConnectionService.ListConnections:output_type -> xray.app.dispatcher.command.ListConnectionsResponse
+	6, // 7: xray.app.dispatcher.command.ConnectionService.CloseConnection:output_type -> xray.app.dispatcher.command.CloseConnectionResponse
+	8, // 8: xray.app.dispatcher.command.ConnectionService.CloseUserConnections:output_type -> xray.app.dispatcher.command.CloseUserConnectionsResponse
+	5, // [5:9] is the sub-list for method output_type
+	1, // [1:5] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_app_dispatcher_command_command_proto_init() }
+func file_app_dispatcher_command_command_proto_init() {
+	if File_app_dispatcher_command_command_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_dispatcher_command_command_proto_rawDesc), len(file_app_dispatcher_command_command_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_app_dispatcher_command_command_proto_goTypes,
+		DependencyIndexes: file_app_dispatcher_command_command_proto_depIdxs,
+		MessageInfos:      file_app_dispatcher_command_command_proto_msgTypes,
+	}.Build()
+	File_app_dispatcher_command_command_proto = out.File
+	file_app_dispatcher_command_command_proto_goTypes = nil
+	file_app_dispatcher_command_command_proto_depIdxs = nil
+}
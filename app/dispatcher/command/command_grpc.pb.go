@@ -0,0 +1,239 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             v6.33.5
+// source: app/dispatcher/command/command.proto
+
+package command
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ConnectionService_SubscribeConnectionRecords_FullMethodName = "/xray.app.dispatcher.command.ConnectionService/SubscribeConnectionRecords"
+	ConnectionService_ListConnections_FullMethodName            = "/xray.app.dispatcher.command.ConnectionService/ListConnections"
+	ConnectionService_CloseConnection_FullMethodName            = "/xray.app.dispatcher.command.ConnectionService/CloseConnection"
+	ConnectionService_CloseUserConnections_FullMethodName       = "/xray.app.dispatcher.command.ConnectionService/CloseUserConnections"
+)
+
+// ConnectionServiceClient is the client API for ConnectionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ConnectionServiceClient interface {
+	SubscribeConnectionRecords(ctx context.Context, in *SubscribeConnectionRecordsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ConnectionRecord], error)
+	ListConnections(ctx context.Context, in *ListConnectionsRequest, opts ...grpc.CallOption) (*ListConnectionsResponse, error)
+	CloseConnection(ctx context.Context, in *CloseConnectionRequest, opts ...grpc.CallOption) (*CloseConnectionResponse, error)
+	CloseUserConnections(ctx context.Context, in *CloseUserConnectionsRequest, opts ...grpc.CallOption) (*CloseUserConnectionsResponse, error)
+}
+
+type connectionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewConnectionServiceClient(cc grpc.ClientConnInterface) ConnectionServiceClient {
+	return &connectionServiceClient{cc}
+}
+
+func (c *connectionServiceClient) SubscribeConnectionRecords(ctx context.Context, in *SubscribeConnectionRecordsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ConnectionRecord], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ConnectionService_ServiceDesc.Streams[0], ConnectionService_SubscribeConnectionRecords_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeConnectionRecordsRequest, ConnectionRecord]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ConnectionService_SubscribeConnectionRecordsClient = grpc.ServerStreamingClient[ConnectionRecord]
+
+func (c *connectionServiceClient) ListConnections(ctx context.Context, in *ListConnectionsRequest, opts ...grpc.CallOption) (*ListConnectionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListConnectionsResponse)
+	err := c.cc.Invoke(ctx, ConnectionService_ListConnections_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connectionServiceClient) CloseConnection(ctx context.Context, in *CloseConnectionRequest, opts ...grpc.CallOption) (*CloseConnectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CloseConnectionResponse)
+	err := c.cc.Invoke(ctx, ConnectionService_CloseConnection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connectionServiceClient) CloseUserConnections(ctx context.Context, in *CloseUserConnectionsRequest, opts ...grpc.CallOption) (*CloseUserConnectionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CloseUserConnectionsResponse)
+	err := c.cc.Invoke(ctx, ConnectionService_CloseUserConnections_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConnectionServiceServer is the server API for ConnectionService service.
+// All implementations must embed UnimplementedConnectionServiceServer
+// for forward compatibility.
+type ConnectionServiceServer interface {
+	SubscribeConnectionRecords(*SubscribeConnectionRecordsRequest, grpc.ServerStreamingServer[ConnectionRecord]) error
+	ListConnections(context.Context, *ListConnectionsRequest) (*ListConnectionsResponse, error)
+	CloseConnection(context.Context, *CloseConnectionRequest) (*CloseConnectionResponse, error)
+	CloseUserConnections(context.Context, *CloseUserConnectionsRequest) (*CloseUserConnectionsResponse, error)
+	mustEmbedUnimplementedConnectionServiceServer()
+}
+
+// UnimplementedConnectionServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedConnectionServiceServer struct{}
+
+func (UnimplementedConnectionServiceServer) SubscribeConnectionRecords(*SubscribeConnectionRecordsRequest, grpc.ServerStreamingServer[ConnectionRecord]) error {
+	return status.Error(codes.Unimplemented, "method SubscribeConnectionRecords not implemented")
+}
+func (UnimplementedConnectionServiceServer) ListConnections(context.Context, *ListConnectionsRequest) (*ListConnectionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListConnections not implemented")
+}
+func (UnimplementedConnectionServiceServer) CloseConnection(context.Context, *CloseConnectionRequest) (*CloseConnectionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CloseConnection not implemented")
+}
+func (UnimplementedConnectionServiceServer) CloseUserConnections(context.Context, *CloseUserConnectionsRequest) (*CloseUserConnectionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CloseUserConnections not implemented")
+}
+func (UnimplementedConnectionServiceServer) mustEmbedUnimplementedConnectionServiceServer() {}
+func (UnimplementedConnectionServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeConnectionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ConnectionServiceServer will
+// result in compilation errors.
+type UnsafeConnectionServiceServer interface {
+	mustEmbedUnimplementedConnectionServiceServer()
+}
+
+func RegisterConnectionServiceServer(s grpc.ServiceRegistrar, srv ConnectionServiceServer) {
+	// If the following call panics, it indicates UnimplementedConnectionServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ConnectionService_ServiceDesc, srv)
+}
+
+func _ConnectionService_SubscribeConnectionRecords_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeConnectionRecordsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ConnectionServiceServer).SubscribeConnectionRecords(m, &grpc.GenericServerStream[SubscribeConnectionRecordsRequest, ConnectionRecord]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ConnectionService_SubscribeConnectionRecordsServer = grpc.ServerStreamingServer[ConnectionRecord]
+
+func _ConnectionService_ListConnections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConnectionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectionServiceServer).ListConnections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConnectionService_ListConnections_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectionServiceServer).ListConnections(ctx, req.(*ListConnectionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConnectionService_CloseConnection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseConnectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectionServiceServer).CloseConnection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConnectionService_CloseConnection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectionServiceServer).CloseConnection(ctx, req.(*CloseConnectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConnectionService_CloseUserConnections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseUserConnectionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectionServiceServer).CloseUserConnections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConnectionService_CloseUserConnections_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectionServiceServer).CloseUserConnections(ctx, req.(*CloseUserConnectionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ConnectionService_ServiceDesc is the grpc.ServiceDesc for ConnectionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ConnectionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "xray.app.dispatcher.command.ConnectionService",
+	HandlerType: (*ConnectionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListConnections",
+			Handler:    _ConnectionService_ListConnections_Handler,
+		},
+		{
+			MethodName: "CloseConnection",
+			Handler:    _ConnectionService_CloseConnection_Handler,
+		},
+		{
+			MethodName: "CloseUserConnections",
+			Handler:    _ConnectionService_CloseUserConnections_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeConnectionRecords",
+			Handler:       _ConnectionService_SubscribeConnectionRecords_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "app/dispatcher/command/command.proto",
+}
@@ -58,10 +58,14 @@ func (*SessionConfig) Descriptor() ([]byte, []int) {
 }
 
 type Config struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Settings      *SessionConfig         `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Settings *SessionConfig         `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
+	// Write a record to the access log, and publish it to the stats channel
+	// "dispatcher>>>connections", when a connection is closed. Traffic copied
+	// by splice is not counted.
+	ConnectionRecords bool `protobuf:"varint,2,opt,name=connection_records,json=connectionRecords,proto3" json:"connection_records,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *Config) Reset() {
@@ -101,14 +105,22 @@ func (x *Config) GetSettings() *SessionConfig {
 	return nil
 }
 
+func (x *Config) GetConnectionRecords() bool {
+	if x != nil {
+		return x.ConnectionRecords
+	}
+	return false
+}
+
 var File_app_dispatcher_config_proto protoreflect.FileDescriptor
 
 const file_app_dispatcher_config_proto_rawDesc = "" +
 	"\n" +
 	"\x1bapp/dispatcher/config.proto\x12\x13xray.app.dispatcher\"\x15\n" +
-	"\rSessionConfigJ\x04\b\x01\x10\x02\"H\n" +
+	"\rSessionConfigJ\x04\b\x01\x10\x02\"w\n" +
 	"\x06Config\x12>\n" +
-	"\bsettings\x18\x01 \x01(\v2\".xray.app.dispatcher.SessionConfigR\bsettingsB[\n" +
+	"\bsettings\x18\x01 \x01(\v2\".xray.app.dispatcher.SessionConfigR\bsettings\x12-\n" +
+	"\x12connection_records\x18\x02 \x01(\bR\x11connectionRecordsB[\n" +
 	"\x17com.xray.app.dispatcherP\x01Z(github.com/xtls/xray-core/app/dispatcher\xaa\x02\x13Xray.App.Dispatcherb\x06proto3"
 
 var (
@@ -0,0 +1,354 @@
+package dispatcher
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/log"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/features/stats"
+	"github.com/xtls/xray-core/transport"
+	"github.com/xtls/xray-core/transport/pipe"
+)
+
+// ConnectionRecordChannel is the name of the stats channel where a *ConnectionRecord is published
+// when a connection is opened or closed.
+const ConnectionRecordChannel = "dispatcher>>>connections"
+
+// ConnectionRecord is the summary of a connection, published when it is routed and when it is closed.
+// A connection that fails to be routed is only published when it is closed.
+type ConnectionRecord struct {
+	ID          uint64
+	InboundTag  string
+	OutboundTag string
+	RuleTag     string
+	Email       string
+	Source      net.Destination
+	Destination net.Destination
+	// Domain and Protocol are the results of sniffing.
+	Domain   string
+	Protocol string
+	Start    time.Time
+	// Duration and traffic are so far, if the connection is not closed yet.
+	Duration time.Duration
+	Uplink   uint64
+	Downlink uint64
+	Closed   bool
+	// Reason is the error which closed the connection, or empty if it is closed normally.
+	Reason string
+}
+
+// byteCounter is a stats.Counter of the traffic of a single connection.
+type byteCounter struct {
+	value atomic.Int64
+}
+
+func (c *byteCounter) Value() int64 {
+	return c.value.Load()
+}
+
+func (c *byteCounter) Set(v int64) int64 {
+	return c.value.Swap(v)
+}
+
+func (c *byteCounter) Add(delta int64) int64 {
+	return c.value.Add(delta) - delta
+}
+
+type connectionKey struct{}
+
+// connection tracks a dispatched connection.
+type connection struct {
+	uplink   byteCounter
+	downlink byteCounter
+	// ctx is the context without the connection, to pass errors to the original tracker.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	access    sync.Mutex
+	record    ConnectionRecord
+	interrupt func()
+	// inbound is the inbound session of the connection, whose connection is closed to stop spliced traffic.
+	inbound *session.Inbound
+}
+
+// SubmitError implements session.TrackedRequestErrorFeedback.
+func (c *connection) SubmitError(err error) {
+	c.access.Lock()
+	if c.record.Reason == "" {
+		c.record.Reason = err.Error()
+	}
+	c.access.Unlock()
+	session.SubmitOutboundErrorToOriginator(c.ctx, err)
+}
+
+// setRoute records the outbound that routedDispatch picks for the connection.
+func (c *connection) setRoute(outboundTag string, ruleTag string, domain string, protocol string) {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	c.record.OutboundTag = outboundTag
+	c.record.RuleTag = ruleTag
+	c.record.Domain = domain
+	c.record.Protocol = protocol
+}
+
+// snapshot returns the record of the connection at present.
+func (c *connection) snapshot() *ConnectionRecord {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	record := c.record
+	record.Duration = time.Since(record.Start)
+	record.Uplink = uint64(c.uplink.Value())
+	record.Downlink = uint64(c.downlink.Value())
+	return &record
+}
+
+// close closes the connection by force.
+func (c *connection) close(reason string) {
+	c.access.Lock()
+	if c.record.Reason == "" {
+		c.record.Reason = reason
+	}
+	interrupt := c.interrupt
+	inbound := c.inbound
+	c.access.Unlock()
+
+	c.cancel()
+	if interrupt != nil {
+		interrupt()
+	}
+	// Spliced traffic bypasses the links, so the inbound connection is closed as well. Connections that can't
+	// be spliced may share their inbound connection with others, e.g. with mux, so only their links are closed.
+	if inbound != nil && inbound.CanSpliceCopy != 3 && inbound.Conn != nil {
+		inbound.Conn.Close()
+	}
+}
+
+func connectionFromContext(ctx context.Context) *connection {
+	if c, ok := ctx.Value(connectionKey{}).(*connection); ok {
+		return c
+	}
+	return nil
+}
+
+// connectionTracker keeps the active connections of DefaultDispatcher.
+type connectionTracker struct {
+	sync.Mutex
+	enabled bool
+	lastID  uint64
+	active  map[uint64]*connection
+	channel stats.Channel
+}
+
+// EnableConnectionTracking makes the dispatcher keep active connections and publish their records,
+// even if connection records are not enabled in config.
+func (d *DefaultDispatcher) EnableConnectionTracking() {
+	d.connections.Lock()
+	defer d.connections.Unlock()
+
+	if d.connections.enabled {
+		return
+	}
+	d.connections.enabled = true
+	d.connections.active = make(map[uint64]*connection)
+	// The channel is not available without stats.
+	d.connections.channel, _ = stats.GetOrRegisterChannel(d.stats, ConnectionRecordChannel)
+}
+
+// trackConnection starts tracking the connection of ctx, if enabled.
+func (d *DefaultDispatcher) trackConnection(ctx context.Context, destination net.Destination) (context.Context, *connection) {
+	d.connections.Lock()
+	enabled := d.connections.enabled
+	d.connections.Unlock()
+	if !enabled {
+		return ctx, nil
+	}
+
+	c := &connection{
+		ctx: ctx,
+		record: ConnectionRecord{
+			Destination: destination,
+			Start:       time.Now(),
+		},
+	}
+	ctx, c.cancel = context.WithCancel(ctx)
+	ctx = session.TrackedConnectionError(ctx, c)
+	return context.WithValue(ctx, connectionKey{}, c), c
+}
+
+// addConnection adds the connection to the active ones. It is called after the inbound user
+// is known, which may be set by WrapLink. The connection is published once it is routed.
+func (d *DefaultDispatcher) addConnection(ctx context.Context, c *connection) {
+	c.access.Lock()
+	if inbound := session.InboundFromContext(ctx); inbound != nil {
+		c.inbound = inbound
+		c.record.InboundTag = inbound.Tag
+		c.record.Source = inbound.Source
+		if inbound.User != nil {
+			c.record.Email = inbound.User.Email
+		}
+	}
+	c.access.Unlock()
+
+	d.connections.Lock()
+	d.connections.lastID++
+	c.record.ID = d.connections.lastID
+	d.connections.active[c.record.ID] = c
+	d.connections.Unlock()
+}
+
+// watchPipeLink tracks the connection of the links returned by getLink, and returns the function
+// to call after routedDispatch returns, which finishes the connection once it is closed.
+func (d *DefaultDispatcher) watchPipeLink(ctx context.Context, c *connection, inbound *transport.Link, outbound *transport.Link) func() {
+	downlink := inbound.Reader.(*pipe.Reader)
+	uplink := outbound.Reader.(*pipe.Reader)
+	inbound.Writer = &SizeStatWriter{
+		Counter: &c.uplink,
+		Writer:  inbound.Writer,
+	}
+	outbound.Writer = &SizeStatWriter{
+		Counter: &c.downlink,
+		Writer:  outbound.Writer,
+	}
+	c.interrupt = func() {
+		uplink.Interrupt()
+		downlink.Interrupt()
+	}
+	d.addConnection(ctx, c)
+
+	return func() {
+		// Both pipes are closed when the outbound finishes, even if routedDispatch returns
+		// earlier, e.g. with mux.
+		<-uplink.Done()
+		<-downlink.Done()
+		d.finishConnection(ctx, c)
+	}
+}
+
+// watchLink tracks the connection of the link passed to DispatchLink.
+func (d *DefaultDispatcher) watchLink(ctx context.Context, c *connection, link *transport.Link) {
+	link.Reader = &SizeStatReader{
+		Counter: &c.uplink,
+		Reader:  link.Reader.(buf.TimeoutReader),
+	}
+	link.Writer = &SizeStatWriter{
+		Counter: &c.downlink,
+		Writer:  link.Writer,
+	}
+	reader, writer := link.Reader, link.Writer
+	c.interrupt = func() {
+		common.Interrupt(reader)
+		common.Interrupt(writer)
+	}
+	d.addConnection(ctx, c)
+}
+
+// sniffedDomain returns the domain that the destination is overridden to by sniffing.
+func sniffedDomain(ob *session.Outbound, destination net.Destination) string {
+	if destination.Address.Family().IsDomain() && destination.Address != ob.OriginalTarget.Address {
+		return destination.Address.Domain()
+	}
+	return ""
+}
+
+// finishConnection removes a closed connection from the active ones, and writes its record.
+func (d *DefaultDispatcher) finishConnection(ctx context.Context, c *connection) {
+	d.connections.Lock()
+	delete(d.connections.active, c.record.ID)
+	d.connections.Unlock()
+	c.cancel()
+
+	record := c.snapshot()
+	record.Closed = true
+
+	if d.connectionRecords {
+		msg := &log.AccessMessage{
+			From:        record.Source,
+			To:          record.Destination,
+			Status:      log.AccessClosed,
+			Reason:      record.Reason,
+			Email:       record.Email,
+			InboundTag:  record.InboundTag,
+			OutboundTag: record.OutboundTag,
+			Domain:      record.Domain,
+			Protocol:    record.Protocol,
+			RuleTag:     record.RuleTag,
+			Uplink:      record.Uplink,
+			Downlink:    record.Downlink,
+			Duration:    record.Duration,
+		}
+		if accessMessage := log.AccessMessageFromContext(ctx); accessMessage != nil {
+			msg.From = accessMessage.From
+			msg.Detour = accessMessage.Detour
+		}
+		log.Record(msg)
+	}
+
+	d.publishConnection(record)
+}
+
+func (d *DefaultDispatcher) publishConnection(record *ConnectionRecord) {
+	d.connections.Lock()
+	channel := d.connections.channel
+	d.connections.Unlock()
+	if channel == nil || len(channel.Subscribers()) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+	channel.Publish(ctx, record)
+	// The record may be delivered after Publish returns.
+	time.AfterFunc(4*time.Second, cancel)
+}
+
+// ListConnections returns the records of the active connections.
+func (d *DefaultDispatcher) ListConnections() []*ConnectionRecord {
+	d.connections.Lock()
+	active := make([]*connection, 0, len(d.connections.active))
+	for _, c := range d.connections.active {
+		active = append(active, c)
+	}
+	d.connections.Unlock()
+
+	records := make([]*ConnectionRecord, 0, len(active))
+	for _, c := range active {
+		records = append(records, c.snapshot())
+	}
+	return records
+}
+
+// CloseConnection closes the active connection of the given ID. It returns false if there is no such connection.
+func (d *DefaultDispatcher) CloseConnection(id uint64) bool {
+	d.connections.Lock()
+	c, found := d.connections.active[id]
+	d.connections.Unlock()
+
+	if found {
+		c.close("closed by API")
+	}
+	return found
+}
+
+// CloseUserConnections closes all the active connections of the user, and returns how many are closed.
+func (d *DefaultDispatcher) CloseUserConnections(email string) int {
+	var closing []*connection
+	d.connections.Lock()
+	for _, c := range d.connections.active {
+		// Email is not changed after the connection is added.
+		if c.record.Email == email {
+			closing = append(closing, c)
+		}
+	}
+	d.connections.Unlock()
+
+	for _, c := range closing {
+		c.close("closed by API")
+	}
+	return len(closing)
+}
@@ -0,0 +1,216 @@
+package dispatcher_test
+
+import (
+	"context"
+	gonet "net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/xtls/xray-core/app/dispatcher"
+	"github.com/xtls/xray-core/app/stats"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/log"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/features/policy"
+	feature_stats "github.com/xtls/xray-core/features/stats"
+	"github.com/xtls/xray-core/testing/mocks"
+	"github.com/xtls/xray-core/transport"
+)
+
+// pongHandler answers "pong" once the request is fully read.
+type pongHandler struct{}
+
+func (pongHandler) Start() error { return nil }
+func (pongHandler) Close() error { return nil }
+func (pongHandler) Tag() string  { return "direct" }
+
+func (pongHandler) SenderSettings() *serial.TypedMessage { return nil }
+func (pongHandler) ProxySettings() *serial.TypedMessage  { return nil }
+
+func (pongHandler) Dispatch(ctx context.Context, link *transport.Link) {
+	buf.Copy(link.Reader, buf.Discard)
+	b := buf.New()
+	b.WriteString("pong")
+	common.Must(link.Writer.WriteMultiBuffer(buf.MultiBuffer{b}))
+	common.Close(link.Writer)
+	common.Interrupt(link.Reader)
+}
+
+type accessLogger chan *log.AccessMessage
+
+func (l accessLogger) Handle(msg log.Message) {
+	if msg, ok := msg.(*log.AccessMessage); ok && msg.Status == log.AccessClosed {
+		l <- msg
+	}
+}
+
+func TestConnectionRecord(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	om := mocks.NewOutboundManager(mockCtl)
+	om.EXPECT().GetDefaultHandler().Return(pongHandler{}).AnyTimes()
+
+	sm, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+	d := new(DefaultDispatcher)
+	common.Must(d.Init(&Config{ConnectionRecords: true}, om, nil, policy.DefaultManager{}, sm))
+
+	channel := sm.GetChannel(ConnectionRecordChannel)
+	if channel == nil {
+		t.Fatal("connection record channel is not registered")
+	}
+	records, err := feature_stats.SubscribeRunnableChannel(channel)
+	common.Must(err)
+	defer feature_stats.UnsubscribeClosableChannel(channel, records)
+
+	logs := make(accessLogger, 1)
+	log.RegisterHandler(logs)
+
+	ctx := session.ContextWithInbound(context.Background(), &session.Inbound{
+		Tag:    "in",
+		Source: net.TCPDestination(net.ParseAddress("1.2.3.4"), 5678),
+	})
+	link, err := d.Dispatch(ctx, net.TCPDestination(net.DomainAddress("example.com"), 80))
+	common.Must(err)
+
+	b := buf.New()
+	b.WriteString("ping!")
+	common.Must(link.Writer.WriteMultiBuffer(buf.MultiBuffer{b}))
+	common.Close(link.Writer)
+	mb, err := buf.ReadAllToBytes(&buf.BufferedReader{Reader: link.Reader})
+	common.Must(err)
+	if string(mb) != "pong" {
+		t.Error("unexpected response: ", string(mb))
+	}
+
+	select {
+	case value := <-records:
+		record := value.(*ConnectionRecord)
+		if record.Closed {
+			t.Fatal("expect the record of the opened connection first")
+		}
+		if record.OutboundTag != "direct" {
+			t.Error("expect the record of the opened connection to be routed, got outbound ", record.OutboundTag)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("no connection record")
+	}
+
+	select {
+	case value := <-records:
+		record := value.(*ConnectionRecord)
+		if !record.Closed {
+			t.Error("expect the connection to be closed")
+		}
+		if record.Uplink != 5 || record.Downlink != 4 {
+			t.Error("unexpected traffic: ", record.Uplink, " ", record.Downlink)
+		}
+		if record.InboundTag != "in" || record.OutboundTag != "direct" || record.Reason != "" {
+			t.Error("unexpected record: ", record)
+		}
+		if record.Destination.NetAddr() != "example.com:80" {
+			t.Error("unexpected destination: ", record.Destination)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("no connection record")
+	}
+
+	select {
+	case msg := <-logs:
+		if msg.Uplink != 5 || msg.Downlink != 4 || msg.OutboundTag != "direct" {
+			t.Error("unexpected access log: ", msg)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("no access log")
+	}
+}
+
+// holdHandler keeps the connection open until it is closed.
+type holdHandler struct {
+	pongHandler
+}
+
+func (holdHandler) Dispatch(ctx context.Context, link *transport.Link) {
+	buf.Copy(link.Reader, buf.Discard)
+	common.Close(link.Writer)
+}
+
+func TestCloseUserConnections(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	om := mocks.NewOutboundManager(mockCtl)
+	om.EXPECT().GetDefaultHandler().Return(holdHandler{}).AnyTimes()
+
+	sm, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+	d := new(DefaultDispatcher)
+	common.Must(d.Init(&Config{}, om, nil, policy.DefaultManager{}, sm))
+	d.EnableConnectionTracking()
+
+	// The connection of bob may be spliced, which bypasses the links.
+	bobConn, bobClient := gonet.Pipe()
+	defer bobClient.Close()
+
+	var links []*transport.Link
+	for _, email := range []string{"alice", "bob"} {
+		inbound := &session.Inbound{
+			Tag:  "in",
+			User: &protocol.MemoryUser{Email: email},
+		}
+		if email == "bob" {
+			inbound.Conn = bobConn
+			inbound.CanSpliceCopy = 1
+		}
+		ctx := session.ContextWithInbound(context.Background(), inbound)
+		link, err := d.Dispatch(ctx, net.TCPDestination(net.DomainAddress("example.com"), 80))
+		common.Must(err)
+		links = append(links, link)
+	}
+
+	if n := len(d.ListConnections()); n != 2 {
+		t.Fatal("expect 2 connections, but got ", n)
+	}
+	if n := d.CloseUserConnections("alice"); n != 1 {
+		t.Error("expect 1 connection of alice, but got ", n)
+	}
+
+	// The inbound sees the end of the downlink once the connection is closed.
+	done := make(chan struct{})
+	go func() {
+		buf.Copy(links[0].Reader, buf.Discard)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("connection is not closed")
+	}
+
+	var remaining []*ConnectionRecord
+	for i := 0; i < 50; i++ {
+		if remaining = d.ListConnections(); len(remaining) == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond * 100)
+	}
+	if len(remaining) != 1 || remaining[0].Email != "bob" {
+		t.Fatal("unexpected connections: ", remaining)
+	}
+	if !d.CloseConnection(remaining[0].ID) {
+		t.Error("failed to close connection ", remaining[0].ID)
+	}
+	if d.CloseConnection(remaining[0].ID + 1) {
+		t.Error("closed a connection that does not exist")
+	}
+	if _, err := bobClient.Read(make([]byte, 1)); err == nil {
+		t.Error("expect the inbound connection of a spliceable connection to be closed")
+	}
+	common.Interrupt(links[1].Writer)
+}
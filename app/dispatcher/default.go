@@ -99,6 +99,10 @@ type DefaultDispatcher struct {
 	policy policy.Manager
 	stats  stats.Manager
 	fdns   dns.FakeDNSEngine
+
+	connectionRecords bool
+	connections       connectionTracker
+	sweeper           *quotaSweeper
 }
 
 func init() {
@@ -122,6 +126,11 @@ func (d *DefaultDispatcher) Init(config *Config, om outbound.Manager, router rou
 	d.router = router
 	d.policy = pm
 	d.stats = sm
+	d.sweeper = newQuotaSweeper()
+	if config.ConnectionRecords {
+		d.connectionRecords = true
+		d.EnableConnectionTracking()
+	}
 	return nil
 }
 
@@ -131,12 +140,20 @@ func (*DefaultDispatcher) Type() interface{} {
 }
 
 // Start implements common.Runnable.
-func (*DefaultDispatcher) Start() error {
+func (d *DefaultDispatcher) Start() error {
+	if d.sweeper != nil {
+		return d.sweeper.task.Start()
+	}
 	return nil
 }
 
 // Close implements common.Closable.
-func (*DefaultDispatcher) Close() error { return nil }
+func (d *DefaultDispatcher) Close() error {
+	if d.sweeper != nil {
+		return d.sweeper.task.Close()
+	}
+	return nil
+}
 
 func (d *DefaultDispatcher) getLink(ctx context.Context) (*transport.Link, *transport.Link) {
 	opt := pipe.OptionsFromContext(ctx)
@@ -156,6 +173,7 @@ func (d *DefaultDispatcher) getLink(ctx context.Context) (*transport.Link, *tran
 	sessionInbound := session.InboundFromContext(ctx)
 	var user *protocol.MemoryUser
 	if sessionInbound != nil {
+		identifyClient(sessionInbound)
 		user = sessionInbound.User
 	}
 
@@ -193,13 +211,71 @@ func (d *DefaultDispatcher) getLink(ctx context.Context) (*transport.Link, *tran
 		}
 	}
 
+	if user != nil {
+		if rl, ok := d.policy.(policy.UserRateLimiter); ok {
+			uplink, downlink, release := rl.LimitersForUser(user)
+			// The context of the connection is canceled when it is closed.
+			context.AfterFunc(ctx, release)
+			if uplink != nil {
+				inboundLink.Writer = &RateLimitWriter{
+					Limiter: uplink,
+					Writer:  inboundLink.Writer,
+					Context: ctx,
+				}
+			}
+			if downlink != nil {
+				outboundLink.Writer = &RateLimitWriter{
+					Limiter: downlink,
+					Writer:  outboundLink.Writer,
+					Context: ctx,
+				}
+			}
+			if isLimited(uplink) || isLimited(downlink) {
+				// Spliced traffic bypasses the limiters, so limits set after the connection is spliced don't apply to it.
+				sessionInbound.CanSpliceCopy = 3
+			}
+		}
+		if user.HasQuota() {
+			inboundLink.Writer = &QuotaWriter{
+				User:   user,
+				Writer: inboundLink.Writer,
+			}
+			outboundLink.Writer = &QuotaWriter{
+				User:   user,
+				Writer: outboundLink.Writer,
+			}
+			sessionInbound.CanSpliceCopy = 3
+			d.sweeper.watch(ctx, user, func() bool {
+				return isClosed(uplinkReader.Done()) && isClosed(downlinkReader.Done())
+			}, func() {
+				uplinkReader.Interrupt()
+				downlinkReader.Interrupt()
+			})
+		}
+	}
+
 	return inboundLink, outboundLink
 }
 
+// identifyClient names the user of the inbound after its verified TLS client certificate,
+// if the inbound protocol doesn't name it, so that user rules and stats apply to the client.
+func identifyClient(inbound *session.Inbound) {
+	if inbound.ClientCertSubject == "" || (inbound.User != nil && inbound.User.Email != "") {
+		return
+	}
+	user := &protocol.MemoryUser{}
+	if inbound.User != nil {
+		*user = *inbound.User
+	}
+	user.Email = inbound.ClientCertSubject
+	inbound.User = user
+}
+
 func WrapLink(ctx context.Context, policyManager policy.Manager, statsManager stats.Manager, link *transport.Link) *transport.Link {
 	sessionInbound := session.InboundFromContext(ctx)
 	var user *protocol.MemoryUser
 	if sessionInbound != nil {
+		identifyClient(sessionInbound)
 		user = sessionInbound.User
 	}
 
@@ -234,6 +310,48 @@ func WrapLink(ctx context.Context, policyManager policy.Manager, statsManager st
 		}
 	}
 
+	if user != nil {
+		if rl, ok := policyManager.(policy.UserRateLimiter); ok {
+			uplink, downlink, release := rl.LimitersForUser(user)
+			// The context of the connection is canceled when it is closed.
+			context.AfterFunc(ctx, release)
+			if uplink != nil {
+				link.Reader = &RateLimitReader{
+					Limiter: uplink,
+					Reader:  link.Reader.(buf.TimeoutReader),
+					Context: ctx,
+				}
+			}
+			if downlink != nil {
+				link.Writer = &RateLimitWriter{
+					Limiter: downlink,
+					Writer:  link.Writer,
+					Context: ctx,
+				}
+			}
+			if isLimited(uplink) || isLimited(downlink) {
+				// Spliced traffic bypasses the limiters, so limits set after the connection is spliced don't apply to it.
+				sessionInbound.CanSpliceCopy = 3
+			}
+		}
+		if user.HasQuota() {
+			link.Reader = &QuotaReader{
+				User:   user,
+				Reader: link.Reader.(buf.TimeoutReader),
+			}
+			link.Writer = &QuotaWriter{
+				User:   user,
+				Writer: link.Writer,
+			}
+			sessionInbound.CanSpliceCopy = 3
+			reader, writer := link.Reader, link.Writer
+			quotaSweeperFromContext(ctx).watch(ctx, user, nil, func() {
+				common.Interrupt(reader)
+				common.Interrupt(writer)
+			})
+		}
+	}
+
 	return link
 }
 
@@ -301,10 +419,18 @@ func (d *DefaultDispatcher) Dispatch(ctx context.Context, destination net.Destin
 		ctx = session.ContextWithContent(ctx, content)
 	}
 
+	ctx, conn := d.trackConnection(ctx, destination)
 	sniffingRequest := content.SniffingRequest
 	inbound, outbound := d.getLink(ctx)
+	finish := func() {}
+	if conn != nil {
+		finish = d.watchPipeLink(ctx, conn, inbound, outbound)
+	}
 	if !sniffingRequest.Enabled {
-		go d.routedDispatch(ctx, outbound, destination)
+		go func() {
+			d.routedDispatch(ctx, outbound, destination)
+			finish()
+		}()
 	} else {
 		go func() {
 			cReader := &cachedReader{
@@ -314,6 +440,7 @@ func (d *DefaultDispatcher) Dispatch(ctx context.Context, destination net.Destin
 			result, err := sniffer(ctx, cReader, sniffingRequest.MetadataOnly, destination.Network)
 			if err == nil {
 				content.Protocol = result.Protocol()
+				content.ClientHello = clientHelloOf(result)
 			}
 			if err == nil && d.shouldOverride(ctx, result, sniffingRequest, destination) {
 				domain := result.Domain()
@@ -334,6 +461,7 @@ func (d *DefaultDispatcher) Dispatch(ctx context.Context, destination net.Destin
 				}
 			}
 			d.routedDispatch(ctx, outbound, destination)
+			finish()
 		}()
 	}
 	return inbound, nil
@@ -357,7 +485,11 @@ func (d *DefaultDispatcher) DispatchLink(ctx context.Context, destination net.De
 		content = new(session.Content)
 		ctx = session.ContextWithContent(ctx, content)
 	}
+	ctx, conn := d.trackConnection(ctx, destination)
 	outbound = WrapLink(ctx, d.policy, d.stats, outbound)
+	if conn != nil {
+		d.watchLink(ctx, conn, outbound)
+	}
 	sniffingRequest := content.SniffingRequest
 	if !sniffingRequest.Enabled {
 		d.routedDispatch(ctx, outbound, destination)
@@ -369,6 +501,7 @@ func (d *DefaultDispatcher) DispatchLink(ctx context.Context, destination net.De
 		result, err := sniffer(ctx, cReader, sniffingRequest.MetadataOnly, destination.Network)
 		if err == nil {
 			content.Protocol = result.Protocol()
+			content.ClientHello = clientHelloOf(result)
 		}
 		if err == nil && d.shouldOverride(ctx, result, sniffingRequest, destination) {
 			domain := result.Domain()
@@ -390,6 +523,9 @@ func (d *DefaultDispatcher) DispatchLink(ctx context.Context, destination net.De
 		}
 		d.routedDispatch(ctx, outbound, destination)
 	}
+	if conn != nil {
+		d.finishConnection(ctx, conn)
+	}
 
 	return nil
 }
@@ -459,6 +595,7 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.
 	routingLink := routing_session.AsRoutingContext(ctx)
 	inTag := routingLink.GetInboundTag()
 	isPickRoute := 0
+	ruleTag := ""
 	if forcedOutboundTag := session.GetForcedOutboundTagFromContext(ctx); forcedOutboundTag != "" {
 		ctx = session.SetForcedOutboundTagToContext(ctx, "")
 		if h := d.ohm.GetHandler(forcedOutboundTag); h != nil {
@@ -476,6 +613,7 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.
 			outTag := route.GetOutboundTag()
 			if h := d.ohm.GetHandler(outTag); h != nil {
 				isPickRoute = 2
+				ruleTag = route.GetRuleTag()
 				if route.GetRuleTag() == "" {
 					errors.LogInfo(ctx, "taking detour [", outTag, "] for [", destination, "]")
 				} else {
@@ -517,8 +655,22 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.
 				accessMessage.Detour = inTag + " >> " + tag
 			}
 		}
+		accessMessage.InboundTag = inTag
+		accessMessage.OutboundTag = handler.Tag()
+		if content := session.ContentFromContext(ctx); content != nil {
+			accessMessage.Protocol = content.Protocol
+		}
+		accessMessage.Domain = sniffedDomain(ob, destination)
 		log.Record(accessMessage)
 	}
+	if conn := connectionFromContext(ctx); conn != nil {
+		protocol := ""
+		if content := session.ContentFromContext(ctx); content != nil {
+			protocol = content.Protocol
+		}
+		conn.setRoute(handler.Tag(), ruleTag, sniffedDomain(ob, destination), protocol)
+		d.publishConnection(conn.snapshot())
+	}
 
 	handler.Dispatch(ctx, link)
 }
@@ -0,0 +1,152 @@
+package dispatcher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/common/task"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/routing"
+)
+
+// QuotaWriter is a buf.Writer that counts the traffic of a user, and fails once the user
+// is expired or has used up its quota.
+type QuotaWriter struct {
+	User   *protocol.MemoryUser
+	Writer buf.Writer
+}
+
+func (w *QuotaWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	if err := w.User.CheckQuota(); err != nil {
+		buf.ReleaseMulti(mb)
+		return err
+	}
+	w.User.AddTraffic(uint64(mb.Len()))
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+func (w *QuotaWriter) Close() error {
+	return common.Close(w.Writer)
+}
+
+func (w *QuotaWriter) Interrupt() {
+	common.Interrupt(w.Writer)
+}
+
+// QuotaReader is a buf.TimeoutReader that counts the traffic of a user, and fails once the user
+// is expired or has used up its quota.
+type QuotaReader struct {
+	User   *protocol.MemoryUser
+	Reader buf.TimeoutReader
+}
+
+func (r *QuotaReader) check(mb buf.MultiBuffer, err error) (buf.MultiBuffer, error) {
+	if qerr := r.User.CheckQuota(); qerr != nil {
+		buf.ReleaseMulti(mb)
+		return nil, qerr
+	}
+	r.User.AddTraffic(uint64(mb.Len()))
+	return mb, err
+}
+
+func (r *QuotaReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	return r.check(r.Reader.ReadMultiBuffer())
+}
+
+func (r *QuotaReader) ReadMultiBufferTimeout(timeout time.Duration) (buf.MultiBuffer, error) {
+	return r.check(r.Reader.ReadMultiBufferTimeout(timeout))
+}
+
+func (r *QuotaReader) Interrupt() {
+	common.Interrupt(r.Reader)
+}
+
+// quotaConn is a connection of a user with quota.
+type quotaConn struct {
+	user *protocol.MemoryUser
+	// done returns whether the connection is finished.
+	done      func() bool
+	interrupt func()
+}
+
+// quotaSweeper interrupts the connections of users who are expired or have used up their quotas.
+// QuotaReader and QuotaWriter only stop a connection when it transfers data, so idle connections
+// are stopped by the sweeper.
+type quotaSweeper struct {
+	sync.Mutex
+	conns map[*quotaConn]struct{}
+	task  *task.Periodic
+}
+
+func newQuotaSweeper() *quotaSweeper {
+	s := &quotaSweeper{
+		conns: make(map[*quotaConn]struct{}),
+	}
+	s.task = &task.Periodic{
+		Interval: time.Second,
+		Execute:  s.sweep,
+	}
+	return s
+}
+
+// quotaSweeperFromContext returns the sweeper of the dispatcher of the instance in ctx, or nil if there is none.
+func quotaSweeperFromContext(ctx context.Context) *quotaSweeper {
+	if v := core.FromContext(ctx); v != nil {
+		if d, ok := v.GetFeature(routing.DispatcherType()).(*DefaultDispatcher); ok {
+			return d.sweeper
+		}
+	}
+	return nil
+}
+
+// watch adds a connection of the user to the sweeper. The connection is removed once ctx is done or
+// done returns true, and interrupt is called if the user expires or uses up its quota before that.
+// A nil sweeper watches nothing.
+func (s *quotaSweeper) watch(ctx context.Context, user *protocol.MemoryUser, done func() bool, interrupt func()) {
+	if s == nil {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.conns[&quotaConn{
+		user: user,
+		done: func() bool {
+			return ctx.Err() != nil || (done != nil && done())
+		},
+		interrupt: interrupt,
+	}] = struct{}{}
+}
+
+func isClosed(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *quotaSweeper) sweep() error {
+	var exhausted []*quotaConn
+	s.Lock()
+	for c := range s.conns {
+		if c.done() {
+			delete(s.conns, c)
+		} else if c.user.CheckQuota() != nil {
+			delete(s.conns, c)
+			exhausted = append(exhausted, c)
+		}
+	}
+	s.Unlock()
+
+	for _, c := range exhausted {
+		c.interrupt()
+	}
+	return nil
+}
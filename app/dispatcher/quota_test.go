@@ -0,0 +1,58 @@
+package dispatcher_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/xtls/xray-core/app/dispatcher"
+	"github.com/xtls/xray-core/app/proxyman"
+	_ "github.com/xtls/xray-core/app/proxyman/outbound"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/policy"
+	"github.com/xtls/xray-core/transport"
+	"github.com/xtls/xray-core/transport/pipe"
+)
+
+const xrayKey core.XrayKey = 1
+
+func TestQuotaStopsIdleConnection(t *testing.T) {
+	user := &protocol.MemoryUser{
+		Email:      "alice",
+		ExpireTime: time.Now().Add(100 * time.Millisecond),
+	}
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+	})
+	common.Must(err)
+	common.Must(v.Start())
+	defer v.Close()
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), xrayKey, v))
+	defer cancel()
+	ctx = session.ContextWithInbound(ctx, &session.Inbound{User: user})
+
+	reader, writer := pipe.New()
+	link := WrapLink(ctx, policy.DefaultManager{}, nil, &transport.Link{Reader: reader, Writer: writer})
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := link.Reader.ReadMultiBuffer()
+		errCh <- err
+	}()
+	select {
+	case err := <-errCh:
+		if err == nil {
+			t.Error("expect error reading from the connection of an expired user")
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("idle connection of an expired user is not stopped")
+	}
+}
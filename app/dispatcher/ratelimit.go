@@ -0,0 +1,83 @@
+package dispatcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"golang.org/x/time/rate"
+)
+
+// isLimited returns whether the limiter currently limits its throughput.
+func isLimited(limiter *rate.Limiter) bool {
+	return limiter != nil && limiter.Limit() != rate.Inf
+}
+
+// waitN blocks until the limiter allows n bytes, or ctx is done.
+func waitN(ctx context.Context, limiter *rate.Limiter, n int) error {
+	for n > 0 {
+		// WaitN refuses to wait for more than burst at once.
+		chunk := n
+		if burst := limiter.Burst(); limiter.Limit() != rate.Inf && chunk > burst {
+			chunk = max(burst, 1)
+		}
+		if err := limiter.WaitN(ctx, chunk); err != nil {
+			return err
+		}
+		n -= chunk
+	}
+	return nil
+}
+
+// RateLimitWriter is a buf.Writer that limits its throughput with a token bucket.
+type RateLimitWriter struct {
+	Limiter *rate.Limiter
+	Writer  buf.Writer
+	Context context.Context
+}
+
+func (w *RateLimitWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	if err := waitN(w.Context, w.Limiter, int(mb.Len())); err != nil {
+		buf.ReleaseMulti(mb)
+		return err
+	}
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+func (w *RateLimitWriter) Close() error {
+	return common.Close(w.Writer)
+}
+
+func (w *RateLimitWriter) Interrupt() {
+	common.Interrupt(w.Writer)
+}
+
+// RateLimitReader is a buf.TimeoutReader that limits its throughput with a token bucket.
+type RateLimitReader struct {
+	Limiter *rate.Limiter
+	Reader  buf.TimeoutReader
+	Context context.Context
+}
+
+func (r *RateLimitReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	mb, err := r.Reader.ReadMultiBuffer()
+	if werr := waitN(r.Context, r.Limiter, int(mb.Len())); werr != nil {
+		buf.ReleaseMulti(mb)
+		return nil, werr
+	}
+	return mb, err
+}
+
+func (r *RateLimitReader) ReadMultiBufferTimeout(timeout time.Duration) (buf.MultiBuffer, error) {
+	mb, err := r.Reader.ReadMultiBufferTimeout(timeout)
+	if werr := waitN(r.Context, r.Limiter, int(mb.Len())); werr != nil {
+		buf.ReleaseMulti(mb)
+		return nil, werr
+	}
+	return mb, err
+}
+
+func (r *RateLimitReader) Interrupt() {
+	common.Interrupt(r.Reader)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/xtls/xray-core/common/protocol/http"
 	"github.com/xtls/xray-core/common/protocol/quic"
 	"github.com/xtls/xray-core/common/protocol/tls"
+	"github.com/xtls/xray-core/common/session"
 )
 
 type SniffResult interface {
@@ -18,6 +19,31 @@ type SniffResult interface {
 	Domain() string
 }
 
+// ClientHelloResult is a SniffResult of TLS ClientHello, from TLS and QUIC traffic.
+type ClientHelloResult interface {
+	ALPN() []string
+	TLSVersion() string
+	JA3() string
+	JA4() string
+}
+
+// clientHelloOf returns the details of the TLS ClientHello in the result, if any.
+func clientHelloOf(result SniffResult) *session.ClientHello {
+	if c, ok := result.(*compositeResult); ok {
+		result = c.protocolResult
+	}
+	h, ok := result.(ClientHelloResult)
+	if !ok {
+		return nil
+	}
+	return &session.ClientHello{
+		ALPN:    h.ALPN(),
+		Version: h.TLSVersion(),
+		JA3:     h.JA3(),
+		JA4:     h.JA4(),
+	}
+}
+
 type protocolSniffer func(context.Context, []byte) (SniffResult, error)
 
 type protocolSnifferWithMetadata struct {
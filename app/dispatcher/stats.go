@@ -1,6 +1,8 @@
 package dispatcher
 
 import (
+	"time"
+
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/buf"
 	"github.com/xtls/xray-core/features/stats"
@@ -23,3 +25,25 @@ func (w *SizeStatWriter) Close() error {
 func (w *SizeStatWriter) Interrupt() {
 	common.Interrupt(w.Writer)
 }
+
+// SizeStatReader is a buf.TimeoutReader that counts the bytes read.
+type SizeStatReader struct {
+	Counter stats.Counter
+	Reader  buf.TimeoutReader
+}
+
+func (r *SizeStatReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	mb, err := r.Reader.ReadMultiBuffer()
+	r.Counter.Add(int64(mb.Len()))
+	return mb, err
+}
+
+func (r *SizeStatReader) ReadMultiBufferTimeout(timeout time.Duration) (buf.MultiBuffer, error) {
+	mb, err := r.Reader.ReadMultiBufferTimeout(timeout)
+	r.Counter.Add(int64(mb.Len()))
+	return mb, err
+}
+
+func (r *SizeStatReader) Interrupt() {
+	common.Interrupt(r.Reader)
+}
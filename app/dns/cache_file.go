@@ -0,0 +1,185 @@
+package dns
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/platform/filesystem"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// cacheSnapshot is the content of the cache file, records by domain by the name of name servers.
+type cacheSnapshot map[string]map[string]*cachedRecord
+
+type cachedRecord struct {
+	A    *cachedIPs `json:"a,omitempty"`
+	AAAA *cachedIPs `json:"aaaa,omitempty"`
+}
+
+type cachedIPs struct {
+	IP     []net.IP         `json:"ip,omitempty"`
+	Expire time.Time        `json:"expire"`
+	RCode  dnsmessage.RCode `json:"rcode,omitempty"`
+}
+
+func toCachedIPs(r *IPRecord) *cachedIPs {
+	if r == nil {
+		return nil
+	}
+	return &cachedIPs{IP: r.IP, Expire: r.Expire, RCode: r.RCode}
+}
+
+func (r *cachedIPs) toIPRecord() *IPRecord {
+	if r == nil {
+		return nil
+	}
+	return &IPRecord{
+		IP:        r.IP,
+		Expire:    r.Expire,
+		RCode:     r.RCode,
+		RawHeader: &dnsmessage.Header{RCode: r.RCode},
+	}
+}
+
+// servable returns whether the record can still be answered from the cache, maybe as a stale one.
+func (c *CacheController) servable(r *IPRecord, now time.Time) bool {
+	if r == nil {
+		return false
+	}
+	if c.serveStale {
+		if c.serveExpiredTTL == 0 {
+			return true
+		}
+		now = now.Add(time.Duration(c.serveExpiredTTL) * time.Second)
+	}
+	return r.Expire.After(now)
+}
+
+// snapshot returns the servable records in the cache.
+func (c *CacheController) snapshot() map[string]*cachedRecord {
+	c.RLock()
+	defer c.RUnlock()
+
+	now := time.Now()
+	records := make(map[string]*cachedRecord, len(c.ips))
+	add := func(ips map[string]*record) {
+		for domain, rec := range ips {
+			if _, found := records[domain]; found {
+				continue
+			}
+			r := &cachedRecord{}
+			if c.servable(rec.A, now) {
+				r.A = toCachedIPs(rec.A)
+			}
+			if c.servable(rec.AAAA, now) {
+				r.AAAA = toCachedIPs(rec.AAAA)
+			}
+			if r.A != nil || r.AAAA != nil {
+				records[domain] = r
+			}
+		}
+	}
+	add(c.ips)
+	add(c.dirtyips)
+	return records
+}
+
+// restore adds the servable records of a snapshot which are not in the cache yet.
+func (c *CacheController) restore(records map[string]*cachedRecord) {
+	if c.disableCache || len(records) == 0 {
+		return
+	}
+
+	c.Lock()
+	now := time.Now()
+	restored := 0
+	for domain, r := range records {
+		if _, found := c.ips[domain]; found {
+			continue
+		}
+		rec := &record{A: r.A.toIPRecord(), AAAA: r.AAAA.toIPRecord()}
+		if !c.servable(rec.A, now) {
+			rec.A = nil
+		}
+		if !c.servable(rec.AAAA, now) {
+			rec.AAAA = nil
+		}
+		if rec.A != nil || rec.AAAA != nil {
+			c.ips[domain] = rec
+			restored++
+		}
+	}
+	c.Unlock()
+
+	errors.LogDebug(context.Background(), c.name, " restored ", restored, " records from cache file")
+	if restored > 0 && (!c.serveStale || c.serveExpiredTTL != 0) {
+		c.cacheCleanup.Start()
+	}
+}
+
+// cacheControllers returns the caches of the name servers.
+func (s *DNS) cacheControllers() []*CacheController {
+	s.RLock()
+	defer s.RUnlock()
+
+	var caches []*CacheController
+	for _, client := range s.clients {
+		if ns, ok := client.server.(CachedNameserver); ok {
+			caches = append(caches, ns.getCacheController())
+		}
+	}
+	return caches
+}
+
+// saveCache writes the caches of the name servers to the cache file.
+func (s *DNS) saveCache() error {
+	snapshot := make(cacheSnapshot)
+	for _, cache := range s.cacheControllers() {
+		if cache.disableCache {
+			continue
+		}
+		records := snapshot[cache.name]
+		if records == nil {
+			snapshot[cache.name] = cache.snapshot()
+			continue
+		}
+		// Name servers of the same address share the file, records of the first one win.
+		for domain, r := range cache.snapshot() {
+			if _, found := records[domain]; !found {
+				records[domain] = r
+			}
+		}
+	}
+
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	s.RLock()
+	path := s.cacheFile
+	s.RUnlock()
+	return filesystem.WriteFileAtomic(path, b)
+}
+
+// loadCache restores the caches of the name servers from the cache file.
+func (s *DNS) loadCache() error {
+	b, err := os.ReadFile(s.cacheFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var snapshot cacheSnapshot
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return errors.New("invalid cache file").Base(err)
+	}
+	for _, cache := range s.cacheControllers() {
+		cache.restore(snapshot[cache.name])
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package dns
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/app/dispatcher"
+	"github.com/xtls/xray-core/app/policy"
+	"github.com/xtls/xray-core/app/proxyman"
+	_ "github.com/xtls/xray-core/app/proxyman/outbound"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/core"
+	feature_dns "github.com/xtls/xray-core/features/dns"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func newCacheFileDNS(path string, serveStale bool) *DNS {
+	server := NewClassicNameServer(net.UDPDestination(net.ParseAddress("8.8.8.8"), 53), nil, false, serveStale, 0, nil)
+	return &DNS{
+		clients:   []*Client{{server: server}},
+		cacheFile: path,
+	}
+}
+
+func TestCacheFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dns.cache")
+
+	d := newCacheFileDNS(path, false)
+	cache := d.clients[0].server.(CachedNameserver).getCacheController()
+	now := time.Now()
+	cache.ips["example.com."] = &record{
+		A:    &IPRecord{IP: []net.IP{net.ParseIP("1.2.3.4")}, Expire: now.Add(time.Hour)},
+		AAAA: &IPRecord{RCode: dnsmessage.RCodeSuccess, Expire: now.Add(-time.Minute)},
+	}
+	cache.ips["expired.com."] = &record{
+		A: &IPRecord{IP: []net.IP{net.ParseIP("5.6.7.8")}, Expire: now.Add(-time.Minute)},
+	}
+	cache.ips["nxdomain.com."] = &record{
+		A: &IPRecord{RCode: dnsmessage.RCodeNameError, Expire: now.Add(time.Hour)},
+	}
+	common.Must(d.saveCache())
+
+	restored := newCacheFileDNS(path, false)
+	common.Must(restored.loadCache())
+	cache = restored.clients[0].server.(CachedNameserver).getCacheController()
+	rec := cache.findRecords("example.com.")
+	if rec == nil || rec.A == nil {
+		t.Fatal("expected record of example.com. to be restored")
+	}
+	if ips, ttl, err := rec.A.getIPs(); err != nil || len(ips) != 1 || !ips[0].Equal(net.ParseIP("1.2.3.4")) || ttl <= 0 {
+		t.Error("unexpected restored record: ", ips, " ", ttl, " ", err)
+	}
+	if rec.AAAA != nil {
+		t.Error("expected expired AAAA record not to be restored")
+	}
+	if cache.findRecords("expired.com.") != nil {
+		t.Error("expected expired record not to be restored")
+	}
+	if rec := cache.findRecords("nxdomain.com."); rec == nil || rec.A == nil || rec.A.RCode != dnsmessage.RCodeNameError {
+		t.Error("expected NXDOMAIN record to be restored")
+	}
+
+	// Expired records are served as stale ones, and refreshed in the background.
+	stale := newCacheFileDNS(path, true)
+	stale.clients[0].server.(CachedNameserver).getCacheController().ips["expired.com."] = &record{
+		A: &IPRecord{IP: []net.IP{net.ParseIP("5.6.7.8")}, Expire: now.Add(-time.Minute)},
+	}
+	common.Must(stale.saveCache())
+	stale = newCacheFileDNS(path, true)
+	common.Must(stale.loadCache())
+	cache = stale.clients[0].server.(CachedNameserver).getCacheController()
+	if rec := cache.findRecords("expired.com."); rec == nil || rec.A == nil {
+		t.Error("expected stale record to be restored")
+	}
+}
+
+func TestCacheFileNotExist(t *testing.T) {
+	d := newCacheFileDNS(filepath.Join(t.TempDir(), "dns.cache"), false)
+	common.Must(d.loadCache())
+}
+
+func TestCacheFileReload(t *testing.T) {
+	dir := t.TempDir()
+	newConfig := func(path string) *Config {
+		return &Config{
+			NameServer: []*NameServer{{
+				Address: &net.Endpoint{
+					Network: net.Network_UDP,
+					Address: &net.IPOrDomain{Address: &net.IPOrDomain_Ip{Ip: []byte{127, 0, 0, 1}}},
+					Port:    53,
+				},
+			}},
+			CacheFile: path,
+		}
+	}
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(newConfig(filepath.Join(dir, "old.cache"))),
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&policy.Config{}),
+		},
+	})
+	common.Must(err)
+	d := v.GetFeature(feature_dns.ClientType()).(*DNS)
+	common.Must(d.Start())
+	d.cacheControllers()[0].ips["example.com."] = &record{
+		A: &IPRecord{IP: []net.IP{net.ParseIP("1.2.3.4")}, Expire: time.Now().Add(time.Hour)},
+	}
+
+	commit, _, err := d.PrepareReload(newConfig(filepath.Join(dir, "new.cache")))
+	common.Must(err)
+	commit()
+	if b, err := os.ReadFile(filepath.Join(dir, "old.cache")); err != nil || !strings.Contains(string(b), "example.com.") {
+		t.Error("expected the cache to be saved to the previous cache file, got ", string(b), err)
+	}
+	if d.cacheControllers()[0].findRecords("example.com.") == nil {
+		t.Error("expected the cache to be kept on reload")
+	}
+
+	common.Must(d.Close())
+	if b, err := os.ReadFile(filepath.Join(dir, "new.cache")); err != nil || !strings.Contains(string(b), "example.com.") {
+		t.Error("expected the cache to be saved to the new cache file, got ", string(b), err)
+	}
+}
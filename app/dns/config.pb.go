@@ -146,8 +146,10 @@ type NameServer struct {
 	UnexpectedGeoip   []*router.GeoIP              `protobuf:"bytes,13,rep,name=unexpected_geoip,json=unexpectedGeoip,proto3" json:"unexpected_geoip,omitempty"`
 	ActUnprior        bool                         `protobuf:"varint,14,opt,name=actUnprior,proto3" json:"actUnprior,omitempty"`
 	PolicyID          uint32                       `protobuf:"varint,17,opt,name=policyID,proto3" json:"policyID,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	// Names of rule sets in Config, which are prioritized like prioritized_domain.
+	DomainRuleSet []string `protobuf:"bytes,18,rep,name=domain_rule_set,json=domainRuleSet,proto3" json:"domain_rule_set,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *NameServer) Reset() {
@@ -299,6 +301,13 @@ func (x *NameServer) GetPolicyID() uint32 {
 	return 0
 }
 
+func (x *NameServer) GetDomainRuleSet() []string {
+	if x != nil {
+		return x.DomainRuleSet
+	}
+	return nil
+}
+
 type Config struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// NameServer list used by this DNS client.
@@ -318,8 +327,14 @@ type Config struct {
 	DisableFallback        bool          `protobuf:"varint,10,opt,name=disableFallback,proto3" json:"disableFallback,omitempty"`
 	DisableFallbackIfMatch bool          `protobuf:"varint,11,opt,name=disableFallbackIfMatch,proto3" json:"disableFallbackIfMatch,omitempty"`
 	EnableParallelQuery    bool          `protobuf:"varint,14,opt,name=enableParallelQuery,proto3" json:"enableParallelQuery,omitempty"`
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
+	// Rule sets of domains referenced by name servers.
+	RuleSet []*router.RuleSet `protobuf:"bytes,15,rep,name=rule_set,json=ruleSet,proto3" json:"rule_set,omitempty"`
+	// CacheFile is the file the DNS cache is saved to, and restored from on start.
+	CacheFile string `protobuf:"bytes,16,opt,name=cache_file,json=cacheFile,proto3" json:"cache_file,omitempty"`
+	// Interval in nanoseconds to save the DNS cache, default 10 minutes.
+	CacheSaveInterval int64 `protobuf:"varint,17,opt,name=cache_save_interval,json=cacheSaveInterval,proto3" json:"cache_save_interval,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *Config) Reset() {
@@ -429,6 +444,27 @@ func (x *Config) GetEnableParallelQuery() bool {
 	return false
 }
 
+func (x *Config) GetRuleSet() []*router.RuleSet {
+	if x != nil {
+		return x.RuleSet
+	}
+	return nil
+}
+
+func (x *Config) GetCacheFile() string {
+	if x != nil {
+		return x.CacheFile
+	}
+	return ""
+}
+
+func (x *Config) GetCacheSaveInterval() int64 {
+	if x != nil {
+		return x.CacheSaveInterval
+	}
+	return 0
+}
+
 type NameServer_PriorityDomain struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Type          DomainMatchingType     `protobuf:"varint,1,opt,name=type,proto3,enum=xray.app.dns.DomainMatchingType" json:"type,omitempty"`
@@ -607,7 +643,7 @@ var File_app_dns_config_proto protoreflect.FileDescriptor
 
 const file_app_dns_config_proto_rawDesc = "" +
 	"\n" +
-	"\x14app/dns/config.proto\x12\fxray.app.dns\x1a\x1ccommon/net/destination.proto\x1a\x17app/router/config.proto\"\xdf\a\n" +
+	"\x14app/dns/config.proto\x12\fxray.app.dns\x1a\x1ccommon/net/destination.proto\x1a\x17app/router/config.proto\"\x87\b\n" +
 	"\n" +
 	"NameServer\x123\n" +
 	"\aaddress\x18\x01 \x01(\v2\x19.xray.common.net.EndpointR\aaddress\x12\x1b\n" +
@@ -633,7 +669,8 @@ const file_app_dns_config_proto_rawDesc = "" +
 	"\n" +
 	"actUnprior\x18\x0e \x01(\bR\n" +
 	"actUnprior\x12\x1a\n" +
-	"\bpolicyID\x18\x11 \x01(\rR\bpolicyID\x1a^\n" +
+	"\bpolicyID\x18\x11 \x01(\rR\bpolicyID\x12&\n" +
+	"\x0fdomain_rule_set\x18\x12 \x03(\tR\rdomainRuleSet\x1a^\n" +
 	"\x0ePriorityDomain\x124\n" +
 	"\x04type\x18\x01 \x01(\x0e2 .xray.app.dns.DomainMatchingTypeR\x04type\x12\x16\n" +
 	"\x06domain\x18\x02 \x01(\tR\x06domain\x1a6\n" +
@@ -642,7 +679,7 @@ const file_app_dns_config_proto_rawDesc = "" +
 	"\x04size\x18\x02 \x01(\rR\x04sizeB\x0f\n" +
 	"\r_disableCacheB\r\n" +
 	"\v_serveStaleB\x12\n" +
-	"\x10_serveExpiredTTL\"\x98\x05\n" +
+	"\x10_serveExpiredTTL\"\x9c\x06\n" +
 	"\x06Config\x129\n" +
 	"\vname_server\x18\x05 \x03(\v2\x18.xray.app.dns.NameServerR\n" +
 	"nameServer\x12\x1b\n" +
@@ -658,7 +695,11 @@ const file_app_dns_config_proto_rawDesc = "" +
 	"\x0fdisableFallback\x18\n" +
 	" \x01(\bR\x0fdisableFallback\x126\n" +
 	"\x16disableFallbackIfMatch\x18\v \x01(\bR\x16disableFallbackIfMatch\x120\n" +
-	"\x13enableParallelQuery\x18\x0e \x01(\bR\x13enableParallelQuery\x1a\x92\x01\n" +
+	"\x13enableParallelQuery\x18\x0e \x01(\bR\x13enableParallelQuery\x123\n" +
+	"\brule_set\x18\x0f \x03(\v2\x18.xray.app.router.RuleSetR\aruleSet\x12\x1d\n" +
+	"\n" +
+	"cache_file\x18\x10 \x01(\tR\tcacheFile\x12.\n" +
+	"\x13cache_save_interval\x18\x11 \x01(\x03R\x11cacheSaveInterval\x1a\x92\x01\n" +
 	"\vHostMapping\x124\n" +
 	"\x04type\x18\x01 \x01(\x0e2 .xray.app.dns.DomainMatchingTypeR\x04type\x12\x16\n" +
 	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x0e\n" +
@@ -701,6 +742,7 @@ var file_app_dns_config_proto_goTypes = []any{
 	(*Config_HostMapping)(nil),        // 6: xray.app.dns.Config.HostMapping
 	(*net.Endpoint)(nil),              // 7: xray.common.net.Endpoint
 	(*router.GeoIP)(nil),              // 8: xray.app.router.GeoIP
+	(*router.RuleSet)(nil),            // 9: xray.app.router.RuleSet
 }
 var file_app_dns_config_proto_depIdxs = []int32{
 	7,  // 0: xray.app.dns.NameServer.address:type_name -> xray.common.net.Endpoint
@@ -712,13 +754,14 @@ var file_app_dns_config_proto_depIdxs = []int32{
 	2,  // 6: xray.app.dns.Config.name_server:type_name -> xray.app.dns.NameServer
 	6,  // 7: xray.app.dns.Config.static_hosts:type_name -> xray.app.dns.Config.HostMapping
 	1,  // 8: xray.app.dns.Config.query_strategy:type_name -> xray.app.dns.QueryStrategy
-	0,  // 9: xray.app.dns.NameServer.PriorityDomain.type:type_name -> xray.app.dns.DomainMatchingType
-	0,  // 10: xray.app.dns.Config.HostMapping.type:type_name -> xray.app.dns.DomainMatchingType
-	11, // [11:11] is the sub-list for method output_type
-	11, // [11:11] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	9,  // 9: xray.app.dns.Config.rule_set:type_name -> xray.app.router.RuleSet
+	0,  // 10: xray.app.dns.NameServer.PriorityDomain.type:type_name -> xray.app.dns.DomainMatchingType
+	0,  // 11: xray.app.dns.Config.HostMapping.type:type_name -> xray.app.dns.DomainMatchingType
+	12, // [12:12] is the sub-list for method output_type
+	12, // [12:12] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_app_dns_config_proto_init() }
@@ -19,12 +19,14 @@ import (
 	"github.com/xtls/xray-core/common/platform"
 	"github.com/xtls/xray-core/common/session"
 	"github.com/xtls/xray-core/common/strmatcher"
+	"github.com/xtls/xray-core/common/task"
 	"github.com/xtls/xray-core/features/dns"
 )
 
 // DNS is a DNS rely server.
 type DNS struct {
-	sync.Mutex
+	// RWMutex protects the fields replaced on reload.
+	sync.RWMutex
 	disableFallback        bool
 	disableFallbackIfMatch bool
 	enableParallelQuery    bool
@@ -34,7 +36,11 @@ type DNS struct {
 	ctx                    context.Context
 	domainMatcher          strmatcher.IndexMatcher
 	matcherInfos           []*DomainMatcherInfo
+	ruleSets               map[string]*router.RuleSetMatcher
 	checkSystem            bool
+	cacheFile              string
+	cacheSaveInterval      time.Duration
+	cacheSaver             *task.Periodic
 }
 
 // DomainMatcherInfo contains information attached to index returned by Server.domainMatcher
@@ -121,7 +127,12 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 	}
 
 	for _, ns := range config.NameServer {
-		domainRuleCount += len(ns.PrioritizedDomain)
+		domainRuleCount += len(ns.PrioritizedDomain) + len(ns.DomainRuleSet)
+	}
+
+	ruleSets, err := router.NewRuleSetMatchers(config.RuleSet)
+	if err != nil {
+		return nil, err
 	}
 
 	// MatcherInfos is ensured to cover the maximum index domainMatcher could return, where matcher's index starts from 1
@@ -165,11 +176,15 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 		}
 		clientIPOption := ResolveIpOptionOverride(ns.QueryStrategy, ipOption)
 		if !clientIPOption.IPv4Enable && !clientIPOption.IPv6Enable {
+			closeClients(clients)
+			router.CloseRuleSetMatchers(ruleSets)
 			return nil, errors.New("no QueryStrategy available for ", ns.Address)
 		}
 
-		client, err := NewClient(ctx, ns, myClientIP, disableCache, serveStale, serveExpiredTTL, tag, clientIPOption, &matcherInfos, updateDomain)
+		client, err := NewClient(ctx, ns, myClientIP, disableCache, serveStale, serveExpiredTTL, tag, clientIPOption, &matcherInfos, updateDomain, ruleSets)
 		if err != nil {
+			closeClients(clients)
+			router.CloseRuleSetMatchers(ruleSets)
 			return nil, errors.New("failed to create client").Base(err)
 		}
 		clients = append(clients, client)
@@ -187,10 +202,13 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 		ctx:                    ctx,
 		domainMatcher:          domainMatcher,
 		matcherInfos:           matcherInfos,
+		ruleSets:               ruleSets,
 		disableFallback:        config.DisableFallback,
 		disableFallbackIfMatch: config.DisableFallbackIfMatch,
 		enableParallelQuery:    config.EnableParallelQuery,
 		checkSystem:            checkSystem,
+		cacheFile:              config.CacheFile,
+		cacheSaveInterval:      time.Duration(config.CacheSaveInterval),
 	}, nil
 }
 
@@ -199,13 +217,128 @@ func (*DNS) Type() interface{} {
 	return dns.ClientType()
 }
 
+// PrepareReload implements features.Reloadable.
+func (s *DNS) PrepareReload(config interface{}) (func(), func(), error) {
+	c, ok := config.(*Config)
+	if !ok {
+		return nil, nil, errors.New("Reload: config type error")
+	}
+	n, err := New(s.ctx, c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	commit := func() {
+		if s.cacheSaver != nil {
+			s.cacheSaver.Close()
+			s.cacheSaver = nil
+			if n.cacheFile != s.cacheFile {
+				if err := s.saveCache(); err != nil {
+					errors.LogWarningInner(s.ctx, err, "failed to save DNS cache to ", s.cacheFile)
+				}
+			}
+		}
+		if s.cacheFile != "" || n.cacheFile != "" {
+			// Keep the cached records of name servers which are still there.
+			caches := make(map[string]*CacheController)
+			for _, cache := range s.cacheControllers() {
+				caches[cache.name] = cache
+			}
+			for _, cache := range n.cacheControllers() {
+				if old, found := caches[cache.name]; found {
+					cache.restore(old.snapshot())
+				}
+			}
+		}
+		if n.cacheFile != "" && n.cacheFile != s.cacheFile {
+			if err := n.loadCache(); err != nil {
+				errors.LogWarningInner(s.ctx, err, "failed to load DNS cache from ", n.cacheFile)
+			}
+		}
+
+		s.Lock()
+		oldClients, oldRuleSets := s.clients, s.ruleSets
+		s.hosts = n.hosts
+		s.ipOption = n.ipOption
+		s.clients = n.clients
+		s.domainMatcher = n.domainMatcher
+		s.matcherInfos = n.matcherInfos
+		s.ruleSets = n.ruleSets
+		s.disableFallback = n.disableFallback
+		s.disableFallbackIfMatch = n.disableFallbackIfMatch
+		s.enableParallelQuery = n.enableParallelQuery
+		s.checkSystem = n.checkSystem
+		s.cacheFile = n.cacheFile
+		s.cacheSaveInterval = n.cacheSaveInterval
+		s.Unlock()
+
+		closeClients(oldClients)
+		router.CloseRuleSetMatchers(oldRuleSets)
+		if s.cacheFile != "" {
+			if err := s.startCacheSaver(); err != nil {
+				errors.LogWarningInner(s.ctx, err, "failed to save DNS cache to ", s.cacheFile)
+			}
+		}
+	}
+	abort := func() {
+		closeClients(n.clients)
+		router.CloseRuleSetMatchers(n.ruleSets)
+	}
+	return commit, abort, nil
+}
+
+// closeClients closes the name servers of clients which hold connections.
+func closeClients(clients []*Client) {
+	for _, client := range clients {
+		if err := common.Close(client.server); err != nil {
+			errors.LogInfoInner(context.Background(), err, "failed to close DNS client ", client.Name())
+		}
+	}
+}
+
 // Start implements common.Runnable.
 func (s *DNS) Start() error {
-	return nil
+	if s.cacheFile == "" {
+		return nil
+	}
+	if err := s.loadCache(); err != nil {
+		errors.LogWarningInner(s.ctx, err, "failed to load DNS cache from ", s.cacheFile)
+	}
+	return s.startCacheSaver()
+}
+
+// startCacheSaver saves the caches to the cache file periodically.
+func (s *DNS) startCacheSaver() error {
+	path, interval := s.cacheFile, s.cacheSaveInterval
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	s.cacheSaver = &task.Periodic{
+		Interval: interval,
+		Execute: func() error {
+			if err := s.saveCache(); err != nil {
+				errors.LogWarningInner(s.ctx, err, "failed to save DNS cache to ", path)
+			}
+			return nil
+		},
+	}
+	return s.cacheSaver.Start()
 }
 
 // Close implements common.Closable.
 func (s *DNS) Close() error {
+	if s.cacheSaver != nil {
+		s.cacheSaver.Close()
+		if err := s.saveCache(); err != nil {
+			errors.LogWarningInner(s.ctx, err, "failed to save DNS cache to ", s.cacheFile)
+		}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	closeClients(s.clients)
+	router.CloseRuleSetMatchers(s.ruleSets)
 	return nil
 }
 
@@ -215,6 +348,10 @@ func (s *DNS) IsOwnLink(ctx context.Context) bool {
 	if inbound == nil {
 		return false
 	}
+
+	s.RLock()
+	defer s.RUnlock()
+
 	for _, client := range s.clients {
 		if client.tag == inbound.Tag {
 			return true
@@ -231,13 +368,17 @@ func (s *DNS) LookupIP(domain string, option dns.IPOption) ([]net.IP, uint32, er
 		return nil, 0, errors.New("empty domain name")
 	}
 
-	if s.checkSystem {
+	s.RLock()
+	checkSystem, ipOption, hosts, enableParallelQuery := s.checkSystem, s.ipOption, s.hosts, s.enableParallelQuery
+	s.RUnlock()
+
+	if checkSystem {
 		supportIPv4, supportIPv6 := checkRoutes()
 		option.IPv4Enable = option.IPv4Enable && supportIPv4
 		option.IPv6Enable = option.IPv6Enable && supportIPv6
 	} else {
-		option.IPv4Enable = option.IPv4Enable && s.ipOption.IPv4Enable
-		option.IPv6Enable = option.IPv6Enable && s.ipOption.IPv6Enable
+		option.IPv4Enable = option.IPv4Enable && ipOption.IPv4Enable
+		option.IPv6Enable = option.IPv6Enable && ipOption.IPv6Enable
 	}
 
 	if !option.IPv4Enable && !option.IPv6Enable {
@@ -245,7 +386,7 @@ func (s *DNS) LookupIP(domain string, option dns.IPOption) ([]net.IP, uint32, er
 	}
 
 	// Static host lookup
-	switch addrs, err := s.hosts.Lookup(domain, option); {
+	switch addrs, err := hosts.Lookup(domain, option); {
 	case err != nil:
 		if go_errors.Is(err, dns.ErrEmptyResponse) {
 			return nil, 0, dns.ErrEmptyResponse
@@ -268,7 +409,7 @@ func (s *DNS) LookupIP(domain string, option dns.IPOption) ([]net.IP, uint32, er
 	}
 
 	// Name servers lookup
-	if s.enableParallelQuery {
+	if enableParallelQuery {
 		return s.parallelQuery(domain, option)
 	} else {
 		return s.serialQuery(domain, option)
@@ -276,6 +417,9 @@ func (s *DNS) LookupIP(domain string, option dns.IPOption) ([]net.IP, uint32, er
 }
 
 func (s *DNS) sortClients(domain string) []*Client {
+	s.RLock()
+	defer s.RUnlock()
+
 	clients := make([]*Client, 0, len(s.clients))
 	clientUsed := make([]bool, len(s.clients))
 	clientNames := make([]string, 0, len(s.clients))
@@ -0,0 +1,69 @@
+package fakedns
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/platform/filesystem"
+)
+
+// saveInterval is the interval to save changed mappings to the cache file.
+const saveInterval = time.Minute
+
+// cacheFile is the content of the cache file of a pool.
+type cacheFile struct {
+	IPPool string `json:"ipPool"`
+	// Mappings from the least recently used one.
+	Mappings []cachedMapping `json:"mappings"`
+}
+
+type cachedMapping struct {
+	Domain string `json:"domain"`
+	IP     string `json:"ip"`
+}
+
+// save writes the mappings to the cache file.
+func (fkdns *Holder) save() error {
+	c := cacheFile{IPPool: fkdns.config.IpPool}
+	for _, m := range fkdns.Lookup("", nil) {
+		c.Mappings = append(c.Mappings, cachedMapping{Domain: m.Domain, IP: m.IP.String()})
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return filesystem.WriteFileAtomic(fkdns.config.CacheFile, b)
+}
+
+// load restores the mappings in the cache file which are still in the pool.
+func (fkdns *Holder) load() error {
+	b, err := os.ReadFile(fkdns.config.CacheFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var c cacheFile
+	if err := json.Unmarshal(b, &c); err != nil {
+		return errors.New("invalid cache file").Base(err)
+	}
+
+	fkdns.mu.Lock()
+	defer fkdns.mu.Unlock()
+
+	for _, m := range c.Mappings {
+		ip := net.ParseAddress(m.IP)
+		if m.Domain == "" || !ip.Family().IsIP() || !fkdns.ipRange.Contains(ip.IP()) {
+			continue
+		}
+		if _, found := fkdns.domainToIP.PeekKeyFromValue(ip); found {
+			continue
+		}
+		fkdns.domainToIP.Put(m.Domain, ip)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package command
+
+import (
+	"context"
+
+	"github.com/xtls/xray-core/app/dns/fakedns"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/dns"
+	"google.golang.org/grpc"
+)
+
+// fakeDNS is implemented by the fake DNS engines of fakedns.
+type fakeDNS interface {
+	Lookup(domain string, ip net.Address) []fakedns.Mapping
+	Flush(domain string) int
+}
+
+type service struct {
+	UnimplementedFakeDNSServiceServer
+	v *core.Instance
+}
+
+func (s *service) fakeDNS() (fakeDNS, error) {
+	f, ok := s.v.GetFeature((*dns.FakeDNSEngine)(nil)).(fakeDNS)
+	if !ok {
+		return nil, errors.New("fake DNS is not enabled")
+	}
+	return f, nil
+}
+
+func (s *service) LookupFakeDNS(ctx context.Context, request *LookupFakeDNSRequest) (*LookupFakeDNSResponse, error) {
+	f, err := s.fakeDNS()
+	if err != nil {
+		return nil, err
+	}
+	var ip net.Address
+	if request.Ip != "" {
+		ip = net.ParseAddress(request.Ip)
+		if !ip.Family().IsIP() {
+			return nil, errors.New("invalid IP: ", request.Ip)
+		}
+	}
+	resp := &LookupFakeDNSResponse{}
+	for _, m := range f.Lookup(request.Domain, ip) {
+		resp.Mappings = append(resp.Mappings, &FakeDNSMapping{
+			Domain: m.Domain,
+			Ip:     m.IP.String(),
+		})
+	}
+	return resp, nil
+}
+
+func (s *service) FlushFakeDNS(ctx context.Context, request *FlushFakeDNSRequest) (*FlushFakeDNSResponse, error) {
+	f, err := s.fakeDNS()
+	if err != nil {
+		return nil, err
+	}
+	return &FlushFakeDNSResponse{Count: uint32(f.Flush(request.Domain))}, nil
+}
+
+func (s *service) Register(server *grpc.Server) {
+	RegisterFakeDNSServiceServer(server, s)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		return &service{v: core.MustFromContext(ctx)}, nil
+	}))
+}
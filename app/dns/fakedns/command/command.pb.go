@@ -0,0 +1,378 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.5
+// source: app/dns/fakedns/command/command.proto
+
+package command
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Config struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	mi := &file_app_dns_fakedns_command_command_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_fakedns_command_command_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_app_dns_fakedns_command_command_proto_rawDescGZIP(), []int{0}
+}
+
+type FakeDNSMapping struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Ip            string                 `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FakeDNSMapping) Reset() {
+	*x = FakeDNSMapping{}
+	mi := &file_app_dns_fakedns_command_command_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FakeDNSMapping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FakeDNSMapping) ProtoMessage() {}
+
+func (x *FakeDNSMapping) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_fakedns_command_command_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FakeDNSMapping.ProtoReflect.Descriptor instead.
+func (*FakeDNSMapping) Descriptor() ([]byte, []int) {
+	return file_app_dns_fakedns_command_command_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FakeDNSMapping) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *FakeDNSMapping) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+type LookupFakeDNSRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Look up the mapping of the domain or the fake IP, or list all mappings if both are empty.
+	Domain        string `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Ip            string `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupFakeDNSRequest) Reset() {
+	*x = LookupFakeDNSRequest{}
+	mi := &file_app_dns_fakedns_command_command_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupFakeDNSRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupFakeDNSRequest) ProtoMessage() {}
+
+func (x *LookupFakeDNSRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_fakedns_command_command_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupFakeDNSRequest.ProtoReflect.Descriptor instead.
+func (*LookupFakeDNSRequest) Descriptor() ([]byte, []int) {
+	return file_app_dns_fakedns_command_command_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LookupFakeDNSRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *LookupFakeDNSRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+type LookupFakeDNSResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mappings      []*FakeDNSMapping      `protobuf:"bytes,1,rep,name=mappings,proto3" json:"mappings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupFakeDNSResponse) Reset() {
+	*x = LookupFakeDNSResponse{}
+	mi := &file_app_dns_fakedns_command_command_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupFakeDNSResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupFakeDNSResponse) ProtoMessage() {}
+
+func (x *LookupFakeDNSResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_fakedns_command_command_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupFakeDNSResponse.ProtoReflect.Descriptor instead.
+func (*LookupFakeDNSResponse) Descriptor() ([]byte, []int) {
+	return file_app_dns_fakedns_command_command_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LookupFakeDNSResponse) GetMappings() []*FakeDNSMapping {
+	if x != nil {
+		return x.Mappings
+	}
+	return nil
+}
+
+type FlushFakeDNSRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Remove the mapping of the domain, or all mappings if empty.
+	Domain        string `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlushFakeDNSRequest) Reset() {
+	*x = FlushFakeDNSRequest{}
+	mi := &file_app_dns_fakedns_command_command_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlushFakeDNSRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlushFakeDNSRequest) ProtoMessage() {}
+
+func (x *FlushFakeDNSRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_fakedns_command_command_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlushFakeDNSRequest.ProtoReflect.Descriptor instead.
+func (*FlushFakeDNSRequest) Descriptor() ([]byte, []int) {
+	return file_app_dns_fakedns_command_command_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FlushFakeDNSRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+type FlushFakeDNSResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of removed mappings.
+	Count         uint32 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlushFakeDNSResponse) Reset() {
+	*x = FlushFakeDNSResponse{}
+	mi := &file_app_dns_fakedns_command_command_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlushFakeDNSResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlushFakeDNSResponse) ProtoMessage() {}
+
+func (x *FlushFakeDNSResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_dns_fakedns_command_command_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlushFakeDNSResponse.ProtoReflect.Descriptor instead.
+func (*FlushFakeDNSResponse) Descriptor() ([]byte, []int) {
+	return file_app_dns_fakedns_command_command_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FlushFakeDNSResponse) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+var File_app_dns_fakedns_command_command_proto protoreflect.FileDescriptor
+
+const file_app_dns_fakedns_command_command_proto_rawDesc = "" +
+	"\n" +
+	"%app/dns/fakedns/command/command.proto\x12\x1cxray.app.dns.fakedns.command\"\b\n" +
+	"\x06Config\"8\n" +
+	"\x0eFakeDNSMapping\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x0e\n" +
+	"\x02ip\x18\x02 \x01(\tR\x02ip\">\n" +
+	"\x14LookupFakeDNSRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x0e\n" +
+	"\x02ip\x18\x02 \x01(\tR\x02ip\"a\n" +
+	"\x15LookupFakeDNSResponse\x12H\n" +
+	"\bmappings\x18\x01 \x03(\v2,.xray.app.dns.fakedns.command.FakeDNSMappingR\bmappings\"-\n" +
+	"\x13FlushFakeDNSRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\",\n" +
+	"\x14FlushFakeDNSResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\rR\x05count2\x85\x02\n" +
+	"\x0eFakeDNSService\x12z\n" +
+	"\rLookupFakeDNS\x122.xray.app.dns.fakedns.command.LookupFakeDNSRequest\x1a3.xray.app.dns.fakedns.command.LookupFakeDNSResponse\"\x00\x12w\n" +
+	"\fFlushFakeDNS\x121.xray.app.dns.fakedns.command.FlushFakeDNSRequest\x1a2.xray.app.dns.fakedns.command.FlushFakeDNSResponse\"\x00Bv\n" +
+	" com.xray.app.dns.fakedns.commandP\x01Z1github.com/xtls/xray-core/app/dns/fakedns/command\xaa\x02\x1cXray.App.Dns.Fakedns.Commandb\x06proto3"
+
+var (
+	file_app_dns_fakedns_command_command_proto_rawDescOnce sync.Once
+	file_app_dns_fakedns_command_command_proto_rawDescData []byte
+)
+
+func file_app_dns_fakedns_command_command_proto_rawDescGZIP() []byte {
+	file_app_dns_fakedns_command_command_proto_rawDescOnce.Do(func() {
+		file_app_dns_fakedns_command_command_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_app_dns_fakedns_command_command_proto_rawDesc), len(file_app_dns_fakedns_command_command_proto_rawDesc)))
+	})
+	return file_app_dns_fakedns_command_command_proto_rawDescData
+}
+
+var file_app_dns_fakedns_command_command_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_app_dns_fakedns_command_command_proto_goTypes = []any{
+	(*Config)(nil),                // 0: xray.app.dns.fakedns.command.Config
+	(*FakeDNSMapping)(nil),        // 1: xray.app.dns.fakedns.command.FakeDNSMapping
+	(*LookupFakeDNSRequest)(nil),  // 2: xray.app.dns.fakedns.command.LookupFakeDNSRequest
+	(*LookupFakeDNSResponse)(nil), // 3: xray.app.dns.fakedns.command.LookupFakeDNSResponse
+	(*FlushFakeDNSRequest)(nil),   // 4: xray.app.dns.fakedns.command.FlushFakeDNSRequest
+	(*FlushFakeDNSResponse)(nil),  // 5: xray.app.dns.fakedns.command.FlushFakeDNSResponse
+}
+var file_app_dns_fakedns_command_command_proto_depIdxs = []int32{
+	1, // 0: xray.app.dns.fakedns.command.LookupFakeDNSResponse.mappings:type_name -> xray.app.dns.fakedns.command.FakeDNSMapping
+	2, // 1: xray.app.dns.fakedns.command.FakeDNSService.LookupFakeDNS:input_type -> xray.app.dns.fakedns.command.LookupFakeDNSRequest
+	4, // 2: xray.app.dns.fakedns.command.FakeDNSService.FlushFakeDNS:input_type -> xray.app.dns.fakedns.command.FlushFakeDNSRequest
+	3, // 3: xray.app.dns.fakedns.command.FakeDNSService.LookupFakeDNS:output_type -> xray.app.dns.fakedns.command.LookupFakeDNSResponse
+	5, // 4: xray.app.dns.fakedns.command.FakeDNSService.FlushFakeDNS:output_type -> xray.app.dns.fakedns.command.FlushFakeDNSResponse
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_app_dns_fakedns_command_command_proto_init() }
+func file_app_dns_fakedns_command_command_proto_init() {
+	if File_app_dns_fakedns_command_command_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_dns_fakedns_command_command_proto_rawDesc), len(file_app_dns_fakedns_command_command_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_app_dns_fakedns_command_command_proto_goTypes,
+		DependencyIndexes: file_app_dns_fakedns_command_command_proto_depIdxs,
+		MessageInfos:      file_app_dns_fakedns_command_command_proto_msgTypes,
+	}.Build()
+	File_app_dns_fakedns_command_command_proto = out.File
+	file_app_dns_fakedns_command_command_proto_goTypes = nil
+	file_app_dns_fakedns_command_command_proto_depIdxs = nil
+}
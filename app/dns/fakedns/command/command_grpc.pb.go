@@ -0,0 +1,159 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             v6.33.5
+// source: app/dns/fakedns/command/command.proto
+
+package command
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FakeDNSService_LookupFakeDNS_FullMethodName = "/xray.app.dns.fakedns.command.FakeDNSService/LookupFakeDNS"
+	FakeDNSService_FlushFakeDNS_FullMethodName  = "/xray.app.dns.fakedns.command.FakeDNSService/FlushFakeDNS"
+)
+
+// FakeDNSServiceClient is the client API for FakeDNSService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FakeDNSServiceClient interface {
+	LookupFakeDNS(ctx context.Context, in *LookupFakeDNSRequest, opts ...grpc.CallOption) (*LookupFakeDNSResponse, error)
+	FlushFakeDNS(ctx context.Context, in *FlushFakeDNSRequest, opts ...grpc.CallOption) (*FlushFakeDNSResponse, error)
+}
+
+type fakeDNSServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFakeDNSServiceClient(cc grpc.ClientConnInterface) FakeDNSServiceClient {
+	return &fakeDNSServiceClient{cc}
+}
+
+func (c *fakeDNSServiceClient) LookupFakeDNS(ctx context.Context, in *LookupFakeDNSRequest, opts ...grpc.CallOption) (*LookupFakeDNSResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LookupFakeDNSResponse)
+	err := c.cc.Invoke(ctx, FakeDNSService_LookupFakeDNS_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fakeDNSServiceClient) FlushFakeDNS(ctx context.Context, in *FlushFakeDNSRequest, opts ...grpc.CallOption) (*FlushFakeDNSResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FlushFakeDNSResponse)
+	err := c.cc.Invoke(ctx, FakeDNSService_FlushFakeDNS_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FakeDNSServiceServer is the server API for FakeDNSService service.
+// All implementations must embed UnimplementedFakeDNSServiceServer
+// for forward compatibility.
+type FakeDNSServiceServer interface {
+	LookupFakeDNS(context.Context, *LookupFakeDNSRequest) (*LookupFakeDNSResponse, error)
+	FlushFakeDNS(context.Context, *FlushFakeDNSRequest) (*FlushFakeDNSResponse, error)
+	mustEmbedUnimplementedFakeDNSServiceServer()
+}
+
+// UnimplementedFakeDNSServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFakeDNSServiceServer struct{}
+
+func (UnimplementedFakeDNSServiceServer) LookupFakeDNS(context.Context, *LookupFakeDNSRequest) (*LookupFakeDNSResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method LookupFakeDNS not implemented")
+}
+func (UnimplementedFakeDNSServiceServer) FlushFakeDNS(context.Context, *FlushFakeDNSRequest) (*FlushFakeDNSResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method FlushFakeDNS not implemented")
+}
+func (UnimplementedFakeDNSServiceServer) mustEmbedUnimplementedFakeDNSServiceServer() {}
+func (UnimplementedFakeDNSServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeFakeDNSServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FakeDNSServiceServer will
+// result in compilation errors.
+type UnsafeFakeDNSServiceServer interface {
+	mustEmbedUnimplementedFakeDNSServiceServer()
+}
+
+func RegisterFakeDNSServiceServer(s grpc.ServiceRegistrar, srv FakeDNSServiceServer) {
+	// If the following call panics, it indicates UnimplementedFakeDNSServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FakeDNSService_ServiceDesc, srv)
+}
+
+func _FakeDNSService_LookupFakeDNS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupFakeDNSRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FakeDNSServiceServer).LookupFakeDNS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FakeDNSService_LookupFakeDNS_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FakeDNSServiceServer).LookupFakeDNS(ctx, req.(*LookupFakeDNSRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FakeDNSService_FlushFakeDNS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FlushFakeDNSRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FakeDNSServiceServer).FlushFakeDNS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FakeDNSService_FlushFakeDNS_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FakeDNSServiceServer).FlushFakeDNS(ctx, req.(*FlushFakeDNSRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FakeDNSService_ServiceDesc is the grpc.ServiceDesc for FakeDNSService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FakeDNSService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "xray.app.dns.fakedns.command.FakeDNSService",
+	HandlerType: (*FakeDNSServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "LookupFakeDNS",
+			Handler:    _FakeDNSService_LookupFakeDNS_Handler,
+		},
+		{
+			MethodName: "FlushFakeDNS",
+			Handler:    _FakeDNSService_FlushFakeDNS_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "app/dns/fakedns/command/command.proto",
+}
@@ -5,12 +5,14 @@ import (
 	"math"
 	"math/big"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/cache"
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/task"
 	"github.com/xtls/xray-core/features/dns"
 )
 
@@ -20,6 +22,15 @@ type Holder struct {
 	mu         *sync.Mutex
 
 	config *FakeDnsPool
+
+	changed atomic.Bool
+	saver   *task.Periodic
+}
+
+// Mapping is a domain and its fake IP.
+type Mapping struct {
+	Domain string
+	IP     net.Address
 }
 
 func (fkdns *Holder) IsIPInIPPool(ip net.Address) bool {
@@ -42,13 +53,39 @@ func (*Holder) Type() interface{} {
 }
 
 func (fkdns *Holder) Start() error {
-	if fkdns.config != nil && fkdns.config.IpPool != "" && fkdns.config.LruSize != 0 {
-		return fkdns.initializeFromConfig()
+	if fkdns.config == nil || fkdns.config.IpPool == "" || fkdns.config.LruSize == 0 {
+		return errors.New("invalid fakeDNS setting")
+	}
+	if err := fkdns.initializeFromConfig(); err != nil {
+		return err
+	}
+	if fkdns.config.CacheFile == "" {
+		return nil
+	}
+	if err := fkdns.load(); err != nil {
+		errors.LogWarningInner(context.Background(), err, "failed to load fake DNS mappings from ", fkdns.config.CacheFile)
+	}
+	fkdns.saver = &task.Periodic{
+		Interval: saveInterval,
+		Execute: func() error {
+			if fkdns.changed.Swap(false) {
+				if err := fkdns.save(); err != nil {
+					errors.LogWarningInner(context.Background(), err, "failed to save fake DNS mappings to ", fkdns.config.CacheFile)
+				}
+			}
+			return nil
+		},
 	}
-	return errors.New("invalid fakeDNS setting")
+	return fkdns.saver.Start()
 }
 
 func (fkdns *Holder) Close() error {
+	if fkdns.saver != nil {
+		fkdns.saver.Close()
+		if err := fkdns.save(); err != nil {
+			errors.LogWarningInner(context.Background(), err, "failed to save fake DNS mappings to ", fkdns.config.CacheFile)
+		}
+	}
 	fkdns.domainToIP = nil
 	fkdns.ipRange = nil
 	fkdns.mu = nil
@@ -70,7 +107,7 @@ func NewFakeDNSHolder() (*Holder, error) {
 }
 
 func NewFakeDNSHolderConfigOnly(conf *FakeDnsPool) (*Holder, error) {
-	return &Holder{nil, nil, nil, conf}, nil
+	return &Holder{config: conf}, nil
 }
 
 func (fkdns *Holder) initializeFromConfig() error {
@@ -126,6 +163,7 @@ func (fkdns *Holder) GetFakeIPForDomain(domain string) []net.Address {
 		}
 	}
 	fkdns.domainToIP.Put(domain, ip)
+	fkdns.changed.Store(true)
 	return []net.Address{ip}
 }
 
@@ -141,6 +179,45 @@ func (fkdns *Holder) GetDomainFromFakeDNS(ip net.Address) string {
 	return ""
 }
 
+// Lookup returns the mapping of the domain or the IP, or all mappings if both are empty,
+// from the least recently used one.
+func (fkdns *Holder) Lookup(domain string, ip net.Address) []Mapping {
+	var mappings []Mapping
+	fkdns.domainToIP.Range(func(key, value interface{}) bool {
+		m := Mapping{Domain: key.(string), IP: value.(net.Address)}
+		if (domain == "" || m.Domain == domain) && (ip == nil || m.IP == ip) {
+			mappings = append(mappings, m)
+		}
+		return true
+	})
+	return mappings
+}
+
+// Flush removes the mapping of the domain, or all mappings if the domain is empty,
+// and returns the number of removed ones.
+func (fkdns *Holder) Flush(domain string) int {
+	fkdns.mu.Lock()
+	defer fkdns.mu.Unlock()
+
+	count := 0
+	if domain != "" {
+		if fkdns.domainToIP.Delete(domain) {
+			count++
+		}
+	} else {
+		fkdns.domainToIP.Range(func(key, value interface{}) bool {
+			if fkdns.domainToIP.Delete(key) {
+				count++
+			}
+			return true
+		})
+	}
+	if count > 0 {
+		fkdns.changed.Store(true)
+	}
+	return count
+}
+
 type HolderMulti struct {
 	holders []*Holder
 
@@ -184,6 +261,24 @@ func (h *HolderMulti) GetDomainFromFakeDNS(ip net.Address) string {
 	return ""
 }
 
+// Lookup returns the mappings of the domain or the IP in all pools, or all mappings if both are empty.
+func (h *HolderMulti) Lookup(domain string, ip net.Address) []Mapping {
+	var mappings []Mapping
+	for _, v := range h.holders {
+		mappings = append(mappings, v.Lookup(domain, ip)...)
+	}
+	return mappings
+}
+
+// Flush removes the mappings of the domain in all pools, or all mappings if the domain is empty.
+func (h *HolderMulti) Flush(domain string) int {
+	count := 0
+	for _, v := range h.holders {
+		count += v.Flush(domain)
+	}
+	return count
+}
+
 func (h *HolderMulti) Type() interface{} {
 	return (*dns.FakeDNSEngine)(nil)
 }
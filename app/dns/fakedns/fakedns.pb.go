@@ -23,8 +23,9 @@ const (
 
 type FakeDnsPool struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	IpPool        string                 `protobuf:"bytes,1,opt,name=ip_pool,json=ipPool,proto3" json:"ip_pool,omitempty"` //CIDR of IP pool used as fake DNS IP
-	LruSize       int64                  `protobuf:"varint,2,opt,name=lruSize,proto3" json:"lruSize,omitempty"`            //Size of Pool for remembering relationship between domain name and IP address
+	IpPool        string                 `protobuf:"bytes,1,opt,name=ip_pool,json=ipPool,proto3" json:"ip_pool,omitempty"`          //CIDR of IP pool used as fake DNS IP
+	LruSize       int64                  `protobuf:"varint,2,opt,name=lruSize,proto3" json:"lruSize,omitempty"`                     //Size of Pool for remembering relationship between domain name and IP address
+	CacheFile     string                 `protobuf:"bytes,3,opt,name=cache_file,json=cacheFile,proto3" json:"cache_file,omitempty"` //File to save the relationship to, and restore it from on start
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -73,6 +74,13 @@ func (x *FakeDnsPool) GetLruSize() int64 {
 	return 0
 }
 
+func (x *FakeDnsPool) GetCacheFile() string {
+	if x != nil {
+		return x.CacheFile
+	}
+	return ""
+}
+
 type FakeDnsPoolMulti struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Pools         []*FakeDnsPool         `protobuf:"bytes,1,rep,name=pools,proto3" json:"pools,omitempty"`
@@ -121,10 +129,12 @@ var File_app_dns_fakedns_fakedns_proto protoreflect.FileDescriptor
 
 const file_app_dns_fakedns_fakedns_proto_rawDesc = "" +
 	"\n" +
-	"\x1dapp/dns/fakedns/fakedns.proto\x12\x14xray.app.dns.fakedns\"@\n" +
+	"\x1dapp/dns/fakedns/fakedns.proto\x12\x14xray.app.dns.fakedns\"_\n" +
 	"\vFakeDnsPool\x12\x17\n" +
 	"\aip_pool\x18\x01 \x01(\tR\x06ipPool\x12\x18\n" +
-	"\alruSize\x18\x02 \x01(\x03R\alruSize\"K\n" +
+	"\alruSize\x18\x02 \x01(\x03R\alruSize\x12\x1d\n" +
+	"\n" +
+	"cache_file\x18\x03 \x01(\tR\tcacheFile\"K\n" +
 	"\x10FakeDnsPoolMulti\x127\n" +
 	"\x05pools\x18\x01 \x03(\v2!.xray.app.dns.fakedns.FakeDnsPoolR\x05poolsB^\n" +
 	"\x18com.xray.app.dns.fakednsP\x01Z)github.com/xtls/xray-core/app/dns/fakedns\xaa\x02\x14Xray.App.Dns.Fakednsb\x06proto3"
@@ -1,6 +1,7 @@
 package fakedns
 
 import (
+	"path/filepath"
 	"strconv"
 	"testing"
 
@@ -203,3 +204,33 @@ func TestFakeDNSMulti(t *testing.T) {
 		})
 	})
 }
+
+func TestFakeDNSCacheFile(t *testing.T) {
+	config := &FakeDnsPool{
+		IpPool:    dns.FakeIPv4Pool,
+		LruSize:   256,
+		CacheFile: filepath.Join(t.TempDir(), "fakedns.json"),
+	}
+	fkdns, err := NewFakeDNSHolderConfigOnly(config)
+	common.Must(err)
+	common.Must(fkdns.Start())
+	addr := fkdns.GetFakeIPForDomain("example.com")
+	addr2 := fkdns.GetFakeIPForDomain("example.org")
+	common.Must(fkdns.Close())
+
+	fkdns, err = NewFakeDNSHolderConfigOnly(config)
+	common.Must(err)
+	common.Must(fkdns.Start())
+	defer fkdns.Close()
+
+	assert.Equal(t, "example.com", fkdns.GetDomainFromFakeDNS(addr[0]))
+	assert.Equal(t, "example.org", fkdns.GetDomainFromFakeDNS(addr2[0]))
+	assert.Equal(t, addr, fkdns.GetFakeIPForDomain("example.com"))
+
+	assert.Equal(t, []Mapping{{Domain: "example.org", IP: addr2[0]}}, fkdns.Lookup("", addr2[0]))
+	assert.Equal(t, 2, len(fkdns.Lookup("", nil)))
+	assert.Equal(t, 1, fkdns.Flush("example.com"))
+	assert.Equal(t, "", fkdns.GetDomainFromFakeDNS(addr[0]))
+	assert.Equal(t, 1, fkdns.Flush(""))
+	assert.Equal(t, 0, len(fkdns.Lookup("", nil)))
+}
@@ -31,6 +31,19 @@ func (w *mphMatcherWrapper) String() string {
 	return "mph-matcher"
 }
 
+// ruleSetMatcherWrapper is a strmatcher.Matcher of a rule set, which is reloaded when its file changes.
+type ruleSetMatcherWrapper struct {
+	m *router.RuleSetMatcher
+}
+
+func (w *ruleSetMatcherWrapper) Match(s string) bool {
+	return w.m.MatchDomain(s)
+}
+
+func (w *ruleSetMatcherWrapper) String() string {
+	return "ruleset:" + w.m.Name()
+}
+
 // Server is the interface for Name Server.
 type Server interface {
 	// Name of the Client.
@@ -83,6 +96,10 @@ func NewServer(ctx context.Context, dest net.Destination, dispatcher routing.Dis
 			return NewTCPNameServer(u, dispatcher, disableCache, serveStale, serveExpiredTTL, clientIP)
 		case strings.EqualFold(u.Scheme, "tcp+local"): // DNS-over-TCP Local mode
 			return NewTCPLocalNameServer(u, disableCache, serveStale, serveExpiredTTL, clientIP)
+		case strings.EqualFold(u.Scheme, "tls"): // DNS-over-TLS Remote mode
+			return NewTLSNameServer(u, dispatcher, disableCache, serveStale, serveExpiredTTL, clientIP)
+		case strings.EqualFold(u.Scheme, "tls+local"): // DNS-over-TLS Local mode
+			return NewTLSLocalNameServer(u, disableCache, serveStale, serveExpiredTTL, clientIP)
 		case strings.EqualFold(u.String(), "fakedns"):
 			var fd dns.FakeDNSEngine
 			err = core.RequireFeatures(ctx, func(fdns dns.FakeDNSEngine) {
@@ -113,6 +130,7 @@ func NewClient(
 	ipOption dns.IPOption,
 	matcherInfos *[]*DomainMatcherInfo,
 	updateDomainRule func(strmatcher.Matcher, int, []*DomainMatcherInfo),
+	ruleSets map[string]*router.RuleSetMatcher,
 ) (*Client, error) {
 	client := &Client{}
 
@@ -194,6 +212,19 @@ func NewClient(
 		ns.PrioritizedDomain = nil
 		runtime.GC()
 
+		for _, name := range ns.DomainRuleSet {
+			m, found := ruleSets[name]
+			if !found {
+				return errors.New("rule set ", name, " not found")
+			}
+			if m.Type() != router.RuleSet_Domain {
+				return errors.New("rule set ", name, " is not of domains")
+			}
+			w := &ruleSetMatcherWrapper{m: m}
+			updateDomainRule(w, len(rules), *matcherInfos)
+			rules = append(rules, w.String())
+		}
+
 		// Establish expected IPs
 		var expectedMatcher router.GeoIPMatcher
 		if len(ns.ExpectedGeoip) > 0 {
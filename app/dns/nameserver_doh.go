@@ -111,6 +111,12 @@ func NewDoHNameServer(url *url.URL, dispatcher routing.Dispatcher, h2c bool, dis
 	return s
 }
 
+// Close implements common.Closable.
+func (s *DoHNameServer) Close() error {
+	s.httpClient.CloseIdleConnections()
+	return nil
+}
+
 // Name implements Server.
 func (s *DoHNameServer) Name() string {
 	return s.cacheController.name
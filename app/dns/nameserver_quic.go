@@ -57,6 +57,18 @@ func NewQUICNameServer(url *url.URL, disableCache bool, serveStale bool, serveEx
 	return s, nil
 }
 
+// Close implements common.Closable.
+func (s *QUICNameServer) Close() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.connection != nil {
+		_ = s.connection.CloseWithError(0, "")
+		s.connection = nil
+	}
+	return nil
+}
+
 // Name implements Server.
 func (s *QUICNameServer) Name() string {
 	return s.cacheController.name
@@ -0,0 +1,387 @@
+package dns
+
+import (
+	"context"
+	gotls "crypto/tls"
+	"encoding/binary"
+	"io"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xtls/xray-core/common/crypto"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/net/cnc"
+	"github.com/xtls/xray-core/common/protocol/dns"
+	dns_feature "github.com/xtls/xray-core/features/dns"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/transport/internet"
+	"github.com/xtls/xray-core/transport/internet/tls"
+)
+
+// NextProtoDoT is the ALPN token of DNS-over-TLS.
+const NextProtoDoT = "dot"
+
+// TLSNameServer implemented DNS over TLS (RFC7858). Queries are pipelined over a single
+// connection, which is reused until the server closes it.
+type TLSNameServer struct {
+	sync.Mutex
+	cacheController *CacheController
+	destination     *net.Destination
+	reqID           uint32
+	dial            func(context.Context) (net.Conn, error)
+	tlsConfig       *gotls.Config
+	clientIP        net.IP
+	conn            *dotConn
+	// dialing is closed when the connection being opened is ready, or nil if no connection is being opened.
+	dialing chan struct{}
+	closed  bool
+}
+
+// NewTLSNameServer creates DNS over TLS server object for remote resolving.
+func NewTLSNameServer(
+	url *url.URL,
+	dispatcher routing.Dispatcher,
+	disableCache bool, serveStale bool, serveExpiredTTL uint32,
+	clientIP net.IP,
+) (*TLSNameServer, error) {
+	s, err := baseTLSNameServer(url, "DOT", disableCache, serveStale, serveExpiredTTL, clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	s.dial = func(ctx context.Context) (net.Conn, error) {
+		link, err := dispatcher.Dispatch(toDnsContext(ctx, s.destination.String()), *s.destination)
+		if err != nil {
+			return nil, err
+		}
+
+		return cnc.NewConnection(
+			cnc.ConnectionInputMulti(link.Writer),
+			cnc.ConnectionOutputMulti(link.Reader),
+		), nil
+	}
+
+	errors.LogInfo(context.Background(), "DNS: created DNS-over-TLS client initialized for ", url.String())
+	return s, nil
+}
+
+// NewTLSLocalNameServer creates DNS over TLS client object for local resolving
+func NewTLSLocalNameServer(url *url.URL, disableCache bool, serveStale bool, serveExpiredTTL uint32, clientIP net.IP) (*TLSNameServer, error) {
+	s, err := baseTLSNameServer(url, "DOTL", disableCache, serveStale, serveExpiredTTL, clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	s.dial = func(ctx context.Context) (net.Conn, error) {
+		return internet.DialSystem(ctx, *s.destination, nil)
+	}
+
+	errors.LogInfo(context.Background(), "DNS: created Local DNS-over-TLS client initialized for ", url.String())
+	return s, nil
+}
+
+func baseTLSNameServer(url *url.URL, prefix string, disableCache bool, serveStale bool, serveExpiredTTL uint32, clientIP net.IP) (*TLSNameServer, error) {
+	port := net.Port(853)
+	if url.Port() != "" {
+		var err error
+		if port, err = net.PortFromString(url.Port()); err != nil {
+			return nil, err
+		}
+	}
+	dest := net.TCPDestination(net.ParseAddress(url.Hostname()), port)
+
+	tlsConfig := tls.Config{
+		ServerName: url.Hostname(),
+	}
+
+	s := &TLSNameServer{
+		cacheController: NewCacheController(prefix+"//"+dest.NetAddr(), disableCache, serveStale, serveExpiredTTL),
+		destination:     &dest,
+		tlsConfig:       tlsConfig.GetTLSConfig(tls.WithNextProto(NextProtoDoT)),
+		clientIP:        clientIP,
+	}
+
+	return s, nil
+}
+
+// Name implements Server.
+func (s *TLSNameServer) Name() string {
+	return s.cacheController.name
+}
+
+// IsDisableCache implements Server.
+func (s *TLSNameServer) IsDisableCache() bool {
+	return s.cacheController.disableCache
+}
+
+func (s *TLSNameServer) newReqID() uint16 {
+	return uint16(atomic.AddUint32(&s.reqID, 1))
+}
+
+// getCacheController implements CachedNameserver.
+func (s *TLSNameServer) getCacheController() *CacheController {
+	return s.cacheController
+}
+
+// getConnection returns the current connection to the server, or opens a new one. The connection is
+// opened without holding the lock, and concurrent queries wait for it instead of opening their own.
+func (s *TLSNameServer) getConnection(ctx context.Context) (*dotConn, error) {
+	for {
+		s.Lock()
+		if s.closed {
+			s.Unlock()
+			return nil, errors.New("nameserver closed")
+		}
+		if s.conn != nil && !s.conn.isClosed() {
+			conn := s.conn
+			s.Unlock()
+			return conn, nil
+		}
+		if dialing := s.dialing; dialing != nil {
+			s.Unlock()
+			select {
+			case <-dialing:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		dialing := make(chan struct{})
+		s.dialing = dialing
+		s.Unlock()
+
+		conn, err := s.openConnection(ctx)
+
+		s.Lock()
+		s.dialing = nil
+		close(dialing)
+		if err == nil && s.closed {
+			conn.close()
+			err = errors.New("nameserver closed")
+		}
+		if err != nil {
+			s.Unlock()
+			return nil, err
+		}
+		s.conn = conn
+		s.Unlock()
+
+		go conn.readLoop(s.Name())
+		return conn, nil
+	}
+}
+
+// openConnection dials the server and completes the TLS handshake.
+func (s *TLSNameServer) openConnection(ctx context.Context) (*dotConn, error) {
+	// The connection outlives the query opening it.
+	rawConn, err := s.dial(context.WithoutCancel(ctx))
+	if err != nil {
+		return nil, errors.New("failed to dial nameserver").Base(err)
+	}
+	tlsConn := gotls.Client(rawConn, s.tlsConfig)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		rawConn.Close()
+		return nil, errors.New("failed to handshake with nameserver").Base(err)
+	}
+	return newDoTConn(tlsConn), nil
+}
+
+// Close implements common.Closable. It closes the connection to the server, and fails later queries.
+func (s *TLSNameServer) Close() error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.closed = true
+	if s.conn != nil {
+		s.conn.close()
+		s.conn = nil
+	}
+	return nil
+}
+
+// sendQuery implements CachedNameserver.
+func (s *TLSNameServer) sendQuery(ctx context.Context, noResponseErrCh chan<- error, fqdn string, option dns_feature.IPOption) {
+	errors.LogInfo(ctx, s.Name(), " querying DNS for: ", fqdn)
+
+	reqs := buildReqMsgs(fqdn, option, s.newReqID, genEDNS0Options(s.clientIP, int(crypto.RandBetween(100, 300))))
+
+	var deadline time.Time
+	if d, ok := ctx.Deadline(); ok {
+		deadline = d
+	} else {
+		deadline = time.Now().Add(time.Second * 5)
+	}
+
+	for _, req := range reqs {
+		go func(r *dnsRequest) {
+			dnsCtx, cancel := context.WithDeadline(ctx, deadline)
+			defer cancel()
+
+			resp, err := s.exchange(dnsCtx, r)
+			if err != nil {
+				errors.LogErrorInner(ctx, err, "failed to query DNS over TLS")
+				if noResponseErrCh != nil {
+					noResponseErrCh <- err
+				}
+				return
+			}
+
+			rec, err := parseResponse(resp)
+			if err != nil {
+				errors.LogErrorInner(ctx, err, "failed to parse DNS over TLS response")
+				if noResponseErrCh != nil {
+					noResponseErrCh <- err
+				}
+				return
+			}
+
+			s.cacheController.updateRecord(r, rec)
+		}(req)
+	}
+}
+
+func (s *TLSNameServer) exchange(ctx context.Context, r *dnsRequest) ([]byte, error) {
+	b, err := dns.PackMessage(r.msg)
+	if err != nil {
+		return nil, errors.New("failed to pack dns query").Base(err)
+	}
+	defer b.Release()
+
+	conn, err := s.getConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ch, err := conn.register(r.msg.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.unregister(r.msg.ID)
+
+	if err := conn.write(b.Bytes()); err != nil {
+		return nil, errors.New("failed to send query").Base(err)
+	}
+
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			return nil, errors.New("connection closed before response")
+		}
+		return resp, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// QueryIP implements Server.
+func (s *TLSNameServer) QueryIP(ctx context.Context, domain string, option dns_feature.IPOption) ([]net.IP, uint32, error) {
+	return queryIP(ctx, s, domain, option)
+}
+
+// dotConn is a connection to a DNS-over-TLS server, with the queries waiting for response.
+type dotConn struct {
+	conn      net.Conn
+	writeLock sync.Mutex
+
+	access  sync.Mutex
+	pending map[uint16]chan []byte
+	closed  bool
+}
+
+func newDoTConn(conn net.Conn) *dotConn {
+	return &dotConn{
+		conn:    conn,
+		pending: make(map[uint16]chan []byte),
+	}
+}
+
+func (c *dotConn) isClosed() bool {
+	c.access.Lock()
+	defer c.access.Unlock()
+	return c.closed
+}
+
+func (c *dotConn) register(id uint16) (<-chan []byte, error) {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	if c.closed {
+		return nil, errors.New("connection closed")
+	}
+	if _, found := c.pending[id]; found {
+		return nil, errors.New("duplicated query id ", id)
+	}
+	ch := make(chan []byte, 1)
+	c.pending[id] = ch
+	return ch, nil
+}
+
+func (c *dotConn) unregister(id uint16) {
+	c.access.Lock()
+	defer c.access.Unlock()
+	delete(c.pending, id)
+}
+
+func (c *dotConn) write(msg []byte) error {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	b := make([]byte, 2+len(msg))
+	binary.BigEndian.PutUint16(b, uint16(len(msg)))
+	copy(b[2:], msg)
+	_, err := c.conn.Write(b)
+	if err != nil {
+		c.close()
+	}
+	return err
+}
+
+func (c *dotConn) close() {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.conn.Close()
+	for id, ch := range c.pending {
+		close(ch)
+		delete(c.pending, id)
+	}
+}
+
+// readLoop reads responses from the connection, and delivers them to the queries by ID.
+func (c *dotConn) readLoop(name string) {
+	defer c.close()
+
+	var length [2]byte
+	for {
+		if _, err := io.ReadFull(c.conn, length[:]); err != nil {
+			if errors.Cause(err) != io.EOF {
+				errors.LogInfoInner(context.Background(), err, name, " connection closed")
+			}
+			return
+		}
+		resp := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(c.conn, resp); err != nil {
+			errors.LogInfoInner(context.Background(), err, name, " failed to read response")
+			return
+		}
+		if len(resp) < 2 {
+			continue
+		}
+
+		id := binary.BigEndian.Uint16(resp)
+		c.access.Lock()
+		ch, found := c.pending[id]
+		delete(c.pending, id)
+		c.access.Unlock()
+		if found {
+			ch <- resp
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package dns
+
+import (
+	"context"
+	gotls "crypto/tls"
+	"encoding/binary"
+	"io"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/protocol/tls/cert"
+	dns_feature "github.com/xtls/xray-core/features/dns"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// serveDoT answers A queries with 1.2.3.4 and AAAA queries with ::1. Queries are answered in
+// pairs, in reverse order of arrival.
+func serveDoT(t *testing.T, conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		if !answerDoT(t, conn) {
+			return
+		}
+	}
+}
+
+func answerDoT(t *testing.T, conn net.Conn) bool {
+	var queries []dnsmessage.Message
+	for len(queries) < 2 {
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return false
+		}
+		b := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, b); err != nil {
+			return false
+		}
+		var msg dnsmessage.Message
+		common.Must(msg.Unpack(b))
+		queries = append(queries, msg)
+	}
+
+	for i := len(queries) - 1; i >= 0; i-- {
+		q := queries[i]
+		resp := dnsmessage.Message{
+			Header:    dnsmessage.Header{ID: q.ID, Response: true, RCode: dnsmessage.RCodeSuccess},
+			Questions: q.Questions,
+		}
+		h := dnsmessage.ResourceHeader{Name: q.Questions[0].Name, Class: dnsmessage.ClassINET, TTL: 300}
+		if q.Questions[0].Type == dnsmessage.TypeA {
+			resp.Answers = append(resp.Answers, dnsmessage.Resource{Header: h, Body: &dnsmessage.AResource{A: [4]byte{1, 2, 3, 4}}})
+		} else {
+			resp.Answers = append(resp.Answers, dnsmessage.Resource{Header: h, Body: &dnsmessage.AAAAResource{AAAA: [16]byte{15: 1}}})
+		}
+		b, err := resp.Pack()
+		common.Must(err)
+		if _, err := conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(b))), b...)); err != nil {
+			t.Error(err)
+			return false
+		}
+	}
+	return true
+}
+
+func TestTLSNameServer(t *testing.T) {
+	c, _ := cert.MustGenerate(nil, cert.DNSNames("dns.example.com"))
+	certPEM, keyPEM := c.ToPEM()
+	certificate, err := gotls.X509KeyPair(certPEM, keyPEM)
+	common.Must(err)
+	listener, err := gotls.Listen("tcp", "127.0.0.1:0", &gotls.Config{
+		Certificates: []gotls.Certificate{certificate},
+		NextProtos:   []string{NextProtoDoT},
+	})
+	common.Must(err)
+	defer listener.Close()
+
+	var accepted atomic.Int32
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+			go serveDoT(t, conn)
+		}
+	}()
+
+	u, err := url.Parse("tls+local://dns.example.com")
+	common.Must(err)
+	s, err := NewTLSLocalNameServer(u, true, false, 0, net.IP(nil))
+	common.Must(err)
+	s.tlsConfig.InsecureSkipVerify = true
+	s.dial = func(ctx context.Context) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", listener.Addr().String())
+	}
+
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		ips, _, err := s.QueryIP(ctx, "example.com", dns_feature.IPOption{
+			IPv4Enable: true,
+			IPv6Enable: true,
+		})
+		cancel()
+		common.Must(err)
+		if len(ips) != 2 {
+			t.Error("expect 2 ips, but got ", ips)
+		}
+	}
+	if n := accepted.Load(); n != 1 {
+		t.Error("expect the connection to be reused, but got ", n, " connections")
+	}
+
+	conn := s.conn
+	common.Must(s.Close())
+	if !conn.isClosed() {
+		t.Error("expect the connection to be closed with the nameserver")
+	}
+	if _, _, err := s.QueryIP(context.Background(), "example.org", dns_feature.IPOption{IPv4Enable: true}); err == nil {
+		t.Error("expect error querying a closed nameserver")
+	}
+}
@@ -74,6 +74,52 @@ func (LogType) EnumDescriptor() ([]byte, []int) {
 	return file_app_log_config_proto_rawDescGZIP(), []int{0}
 }
 
+type LogFormat int32
+
+const (
+	LogFormat_Text LogFormat = 0
+	LogFormat_JSON LogFormat = 1
+)
+
+// Enum value maps for LogFormat.
+var (
+	LogFormat_name = map[int32]string{
+		0: "Text",
+		1: "JSON",
+	}
+	LogFormat_value = map[string]int32{
+		"Text": 0,
+		"JSON": 1,
+	}
+)
+
+func (x LogFormat) Enum() *LogFormat {
+	p := new(LogFormat)
+	*p = x
+	return p
+}
+
+func (x LogFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LogFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_log_config_proto_enumTypes[1].Descriptor()
+}
+
+func (LogFormat) Type() protoreflect.EnumType {
+	return &file_app_log_config_proto_enumTypes[1]
+}
+
+func (x LogFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LogFormat.Descriptor instead.
+func (LogFormat) EnumDescriptor() ([]byte, []int) {
+	return file_app_log_config_proto_rawDescGZIP(), []int{1}
+}
+
 type Config struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ErrorLogType  LogType                `protobuf:"varint,1,opt,name=error_log_type,json=errorLogType,proto3,enum=xray.app.log.LogType" json:"error_log_type,omitempty"`
@@ -83,6 +129,7 @@ type Config struct {
 	AccessLogPath string                 `protobuf:"bytes,5,opt,name=access_log_path,json=accessLogPath,proto3" json:"access_log_path,omitempty"`
 	EnableDnsLog  bool                   `protobuf:"varint,6,opt,name=enable_dns_log,json=enableDnsLog,proto3" json:"enable_dns_log,omitempty"`
 	MaskAddress   string                 `protobuf:"bytes,7,opt,name=mask_address,json=maskAddress,proto3" json:"mask_address,omitempty"`
+	Format        LogFormat              `protobuf:"varint,8,opt,name=format,proto3,enum=xray.app.log.LogFormat" json:"format,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -166,11 +213,18 @@ func (x *Config) GetMaskAddress() string {
 	return ""
 }
 
+func (x *Config) GetFormat() LogFormat {
+	if x != nil {
+		return x.Format
+	}
+	return LogFormat_Text
+}
+
 var File_app_log_config_proto protoreflect.FileDescriptor
 
 const file_app_log_config_proto_rawDesc = "" +
 	"\n" +
-	"\x14app/log/config.proto\x12\fxray.app.log\x1a\x14common/log/log.proto\"\xde\x02\n" +
+	"\x14app/log/config.proto\x12\fxray.app.log\x1a\x14common/log/log.proto\"\x8f\x03\n" +
 	"\x06Config\x12;\n" +
 	"\x0eerror_log_type\x18\x01 \x01(\x0e2\x15.xray.app.log.LogTypeR\ferrorLogType\x12A\n" +
 	"\x0ferror_log_level\x18\x02 \x01(\x0e2\x19.xray.common.log.SeverityR\rerrorLogLevel\x12$\n" +
@@ -178,12 +232,16 @@ const file_app_log_config_proto_rawDesc = "" +
 	"\x0faccess_log_type\x18\x04 \x01(\x0e2\x15.xray.app.log.LogTypeR\raccessLogType\x12&\n" +
 	"\x0faccess_log_path\x18\x05 \x01(\tR\raccessLogPath\x12$\n" +
 	"\x0eenable_dns_log\x18\x06 \x01(\bR\fenableDnsLog\x12!\n" +
-	"\fmask_address\x18\a \x01(\tR\vmaskAddress*5\n" +
+	"\fmask_address\x18\a \x01(\tR\vmaskAddress\x12/\n" +
+	"\x06format\x18\b \x01(\x0e2\x17.xray.app.log.LogFormatR\x06format*5\n" +
 	"\aLogType\x12\b\n" +
 	"\x04None\x10\x00\x12\v\n" +
 	"\aConsole\x10\x01\x12\b\n" +
 	"\x04File\x10\x02\x12\t\n" +
-	"\x05Event\x10\x03BF\n" +
+	"\x05Event\x10\x03*\x1f\n" +
+	"\tLogFormat\x12\b\n" +
+	"\x04Text\x10\x00\x12\b\n" +
+	"\x04JSON\x10\x01BF\n" +
 	"\x10com.xray.app.logP\x01Z!github.com/xtls/xray-core/app/log\xaa\x02\fXray.App.Logb\x06proto3"
 
 var (
@@ -198,22 +256,24 @@ func file_app_log_config_proto_rawDescGZIP() []byte {
 	return file_app_log_config_proto_rawDescData
 }
 
-var file_app_log_config_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_app_log_config_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
 var file_app_log_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
 var file_app_log_config_proto_goTypes = []any{
 	(LogType)(0),      // 0: xray.app.log.LogType
-	(*Config)(nil),    // 1: xray.app.log.Config
-	(log.Severity)(0), // 2: xray.common.log.Severity
+	(LogFormat)(0),    // 1: xray.app.log.LogFormat
+	(*Config)(nil),    // 2: xray.app.log.Config
+	(log.Severity)(0), // 3: xray.common.log.Severity
 }
 var file_app_log_config_proto_depIdxs = []int32{
 	0, // 0: xray.app.log.Config.error_log_type:type_name -> xray.app.log.LogType
-	2, // 1: xray.app.log.Config.error_log_level:type_name -> xray.common.log.Severity
+	3, // 1: xray.app.log.Config.error_log_level:type_name -> xray.common.log.Severity
 	0, // 2: xray.app.log.Config.access_log_type:type_name -> xray.app.log.LogType
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	1, // 3: xray.app.log.Config.format:type_name -> xray.app.log.LogFormat
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_app_log_config_proto_init() }
@@ -226,7 +286,7 @@ func file_app_log_config_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_log_config_proto_rawDesc), len(file_app_log_config_proto_rawDesc)),
-			NumEnums:      1,
+			NumEnums:      2,
 			NumMessages:   1,
 			NumExtensions: 0,
 			NumServices:   0,
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/errors"
@@ -53,7 +54,8 @@ func New(ctx context.Context, config *Config) (*Instance, error) {
 
 func (g *Instance) initAccessLogger() error {
 	handler, err := createHandler(g.config.AccessLogType, HandlerCreatorOptions{
-		Path: g.config.AccessLogPath,
+		Path:   g.config.AccessLogPath,
+		Format: g.config.Format,
 	})
 	if err != nil {
 		return err
@@ -64,7 +66,8 @@ func (g *Instance) initAccessLogger() error {
 
 func (g *Instance) initErrorLogger() error {
 	handler, err := createHandler(g.config.ErrorLogType, HandlerCreatorOptions{
-		Path: g.config.ErrorLogPath,
+		Path:   g.config.ErrorLogPath,
+		Format: g.config.Format,
 	})
 	if err != nil {
 		return err
@@ -103,6 +106,54 @@ func (g *Instance) Start() error {
 	return g.startInternal()
 }
 
+// PrepareReload implements features.Reloadable.
+func (g *Instance) PrepareReload(config interface{}) (func(), func(), error) {
+	c, ok := config.(*Config)
+	if !ok {
+		return nil, nil, errors.New("Reload: config type error")
+	}
+	m4, m6, err := ParseMaskAddress(c.MaskAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	accessLogger, err := createHandler(c.AccessLogType, HandlerCreatorOptions{
+		Path:   c.AccessLogPath,
+		Format: c.Format,
+	})
+	if err != nil {
+		return nil, nil, errors.New("failed to initialize access logger").Base(err)
+	}
+	errorLogger, err := createHandler(c.ErrorLogType, HandlerCreatorOptions{
+		Path:   c.ErrorLogPath,
+		Format: c.Format,
+	})
+	if err != nil {
+		common.Close(accessLogger)
+		return nil, nil, errors.New("failed to initialize error logger").Base(err)
+	}
+
+	commit := func() {
+		g.Lock()
+		oldAccessLogger, oldErrorLogger := g.accessLogger, g.errorLogger
+		g.config = c
+		g.dns = c.EnableDnsLog
+		g.mask4 = m4
+		g.mask6 = m6
+		g.accessLogger = accessLogger
+		g.errorLogger = errorLogger
+		g.active = true
+		g.Unlock()
+
+		common.Close(oldAccessLogger)
+		common.Close(oldErrorLogger)
+	}
+	abort := func() {
+		common.Close(accessLogger)
+		common.Close(errorLogger)
+	}
+	return commit, abort, nil
+}
+
 // Handle implements log.Handler.
 func (g *Instance) Handle(msg log.Message) {
 	g.RLock()
@@ -112,15 +163,25 @@ func (g *Instance) Handle(msg log.Message) {
 		return
 	}
 
-	var Msg log.Message
-	if g.config.MaskAddress != "" {
+	var Msg log.Message = msg
+	if g.config.Format == LogFormat_JSON {
+		jsonMsg := &log.JSONMessage{
+			Message: msg,
+			Time:    time.Now(),
+		}
+		if g.config.MaskAddress != "" {
+			mask4, mask6 := g.mask4, g.mask6
+			jsonMsg.Mask = func(s string) string {
+				return maskAddress(s, mask4, mask6)
+			}
+		}
+		Msg = jsonMsg
+	} else if g.config.MaskAddress != "" {
 		Msg = &MaskedMsgWrapper{
 			Message: msg,
 			Mask4:   g.mask4,
 			Mask6:   g.mask6,
 		}
-	} else {
-		Msg = msg
 	}
 
 	switch msg := msg.(type) {
@@ -213,19 +274,21 @@ var (
 )
 
 func (m *MaskedMsgWrapper) String() string {
-	str := m.Message.String()
+	return maskAddress(m.Message.String(), m.Mask4, m.Mask6)
+}
 
+func maskAddress(str string, mask4 int, mask6 int) string {
 	// Process ipv4
 	maskedMsg := ipv4Regex.ReplaceAllStringFunc(str, func(s string) string {
-		if m.Mask4 == 32 {
+		if mask4 == 32 {
 			return s
 		}
-		if m.Mask4 == 0 {
+		if mask4 == 0 {
 			return "[Masked IPv4]"
 		}
 
 		parts := strings.Split(s, ".")
-		for i := m.Mask4 / 8; i < 4; i++ {
+		for i := mask4 / 8; i < 4; i++ {
 			parts[i] = "*"
 		}
 		return strings.Join(parts, ".")
@@ -233,17 +296,17 @@ func (m *MaskedMsgWrapper) String() string {
 
 	// process ipv6
 	maskedMsg = ipv6Regex.ReplaceAllStringFunc(maskedMsg, func(s string) string {
-		if m.Mask6 == 128 {
+		if mask6 == 128 {
 			return s
 		}
-		if m.Mask6 == 0 {
+		if mask6 == 0 {
 			return "Masked IPv6"
 		}
 		ip := net.ParseIP(s)
 		if ip == nil {
 			return s
 		}
-		return ip.Mask(net.CIDRMask(m.Mask6, 128)).String() + "/" + strconv.Itoa(m.Mask6)
+		return ip.Mask(net.CIDRMask(mask6, 128)).String() + "/" + strconv.Itoa(mask6)
 	})
 
 	return maskedMsg
@@ -9,7 +9,8 @@ import (
 )
 
 type HandlerCreatorOptions struct {
-	Path string
+	Path   string
+	Format LogFormat
 }
 
 type HandlerCreator func(LogType, HandlerCreatorOptions) (log.Handler, error)
@@ -43,11 +44,18 @@ func createHandler(logType LogType, options HandlerCreatorOptions) (log.Handler,
 
 func init() {
 	common.Must(RegisterHandlerCreator(LogType_Console, func(lt LogType, options HandlerCreatorOptions) (log.Handler, error) {
+		if options.Format == LogFormat_JSON {
+			return log.NewLogger(log.CreatePlainStdoutLogWriter()), nil
+		}
 		return log.NewLogger(log.CreateStdoutLogWriter()), nil
 	}))
 
 	common.Must(RegisterHandlerCreator(LogType_File, func(lt LogType, options HandlerCreatorOptions) (log.Handler, error) {
-		creator, err := log.CreateFileLogWriter(options.Path)
+		createWriter := log.CreateFileLogWriter
+		if options.Format == LogFormat_JSON {
+			createWriter = log.CreatePlainFileLogWriter
+		}
+		creator, err := createWriter(options.Path)
 		if err != nil {
 			return nil, err
 		}
@@ -2,10 +2,13 @@ package log_test
 
 import (
 	"context"
+	"encoding/json"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
 	"github.com/xtls/xray-core/app/log"
 	"github.com/xtls/xray-core/common"
 	clog "github.com/xtls/xray-core/common/log"
@@ -87,3 +90,81 @@ func TestMaskAddress(t *testing.T) {
 		t.Fatal("expected '11:45:14:19::/64', but actually", maskedAddr.String())
 	}
 }
+
+func TestJSONLog(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	var loggedValue []string
+
+	mockHandler := mocks.NewLogHandler(mockCtl)
+	mockHandler.EXPECT().Handle(gomock.Any()).AnyTimes().DoAndReturn(func(msg clog.Message) {
+		loggedValue = append(loggedValue, msg.String())
+	})
+
+	log.RegisterHandlerCreator(log.LogType_Console, func(lt log.LogType, options log.HandlerCreatorOptions) (clog.Handler, error) {
+		if options.Format != log.LogFormat_JSON {
+			t.Error("unexpected log format: ", options.Format)
+		}
+		return mockHandler, nil
+	})
+
+	logger, err := log.New(context.Background(), &log.Config{
+		ErrorLogLevel: clog.Severity_Warning,
+		ErrorLogType:  log.LogType_Console,
+		AccessLogType: log.LogType_Console,
+		MaskAddress:   "half",
+		Format:        log.LogFormat_JSON,
+	})
+	common.Must(err)
+	common.Must(logger.Start())
+
+	clog.Record(&clog.AccessMessage{
+		From:        "tcp:11.45.1.4:1234",
+		To:          "tcp:www.example.com:443",
+		Status:      clog.AccessAccepted,
+		Email:       "love@example.com",
+		Detour:      "in -> out",
+		InboundTag:  "in",
+		OutboundTag: "out",
+		Domain:      "www.example.com",
+		Protocol:    "tls",
+	})
+	clog.Record(&clog.GeneralMessage{
+		Severity: clog.Severity_Warning,
+		Content:  "test",
+	})
+
+	if len(loggedValue) != 2 {
+		t.Fatal("expected 2 log messages, but actually ", loggedValue)
+	}
+
+	var access map[string]string
+	common.Must(json.Unmarshal([]byte(loggedValue[0]), &access))
+	delete(access, "time")
+	if diff := cmp.Diff(map[string]string{
+		"type":         "access",
+		"inbound_tag":  "in",
+		"outbound_tag": "out",
+		"source":       "tcp:11.45.*.*:1234",
+		"destination":  "tcp:www.example.com:443",
+		"domain":       "www.example.com",
+		"protocol":     "tls",
+		"email":        "love@example.com",
+		"status":       "accepted",
+		"detour":       "in -> out",
+	}, access); diff != "" {
+		t.Error(diff)
+	}
+
+	var general map[string]string
+	common.Must(json.Unmarshal([]byte(loggedValue[1]), &general))
+	if _, err := time.Parse(time.RFC3339Nano, general["time"]); err != nil {
+		t.Error(err)
+	}
+	if general["level"] != "warning" || general["message"] != "test" {
+		t.Error("unexpected error log: ", loggedValue[1])
+	}
+
+	common.Must(logger.Close())
+}
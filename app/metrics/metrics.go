@@ -6,6 +6,7 @@ import (
 	"net/http"
 	_ "net/http/pprof"
 	"strings"
+	"time"
 
 	"github.com/xtls/xray-core/app/observatory"
 	"github.com/xtls/xray-core/app/stats"
@@ -20,19 +21,23 @@ import (
 )
 
 type MetricsHandler struct {
+	ctx          context.Context
 	ohm          outbound.Manager
 	statsManager feature_stats.Manager
 	observatory  extension.Observatory
 	tag          string
 	listen       string
 	tcpListener  net.Listener
+	startTime    time.Time
 }
 
 // NewMetricsHandler creates a new MetricsHandler based on the given config.
 func NewMetricsHandler(ctx context.Context, config *Config) (*MetricsHandler, error) {
 	c := &MetricsHandler{
-		tag:    config.Tag,
-		listen: config.Listen,
+		ctx:       ctx,
+		tag:       config.Tag,
+		listen:    config.Listen,
+		startTime: time.Now(),
 	}
 	common.Must(core.RequireFeatures(ctx, func(om outbound.Manager, sm feature_stats.Manager) {
 		c.statsManager = sm
@@ -63,28 +68,41 @@ func NewMetricsHandler(ctx context.Context, config *Config) (*MetricsHandler, er
 		return resp
 	}))
 	expvar.Publish("observatory", expvar.Func(func() interface{} {
-		if c.observatory == nil {
-			common.Must(core.RequireFeatures(ctx, func(observatory extension.Observatory) error {
-				c.observatory = observatory
-				return nil
-			}))
-			if c.observatory == nil {
-				return nil
-			}
+		status, err := c.outboundStatus()
+		if err != nil {
+			return err
+		}
+		if status == nil {
+			return nil
 		}
 		resp := map[string]*observatory.OutboundStatus{}
-		if o, err := c.observatory.GetObservation(context.Background()); err != nil {
-			return err
-		} else {
-			for _, x := range o.(*observatory.ObservationResult).GetStatus() {
-				resp[x.OutboundTag] = x
-			}
+		for _, x := range status {
+			resp[x.OutboundTag] = x
 		}
 		return resp
 	}))
+	http.Handle("/metrics", c)
 	return c, nil
 }
 
+// outboundStatus returns the latest observation results, or nil if no observatory is configured.
+func (p *MetricsHandler) outboundStatus() ([]*observatory.OutboundStatus, error) {
+	if p.observatory == nil {
+		common.Must(core.RequireFeatures(p.ctx, func(observatory extension.Observatory) error {
+			p.observatory = observatory
+			return nil
+		}))
+		if p.observatory == nil {
+			return nil, nil
+		}
+	}
+	o, err := p.observatory.GetObservation(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return o.(*observatory.ObservationResult).GetStatus(), nil
+}
+
 func (p *MetricsHandler) Type() interface{} {
 	return (*MetricsHandler)(nil)
 }
@@ -0,0 +1,182 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xtls/xray-core/app/observatory"
+	"github.com/xtls/xray-core/app/stats"
+	feature_stats "github.com/xtls/xray-core/features/stats"
+)
+
+// prometheusContentType is the content type of the Prometheus text exposition format.
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type promSample struct {
+	labels []string
+	value  float64
+}
+
+type promFamily struct {
+	name    string
+	help    string
+	kind    string
+	samples []promSample
+}
+
+func (f *promFamily) add(value float64, labels ...string) {
+	f.samples = append(f.samples, promSample{labels: labels, value: value})
+}
+
+// promWriter renders metric families in Prometheus text exposition format.
+type promWriter struct {
+	families []*promFamily
+}
+
+func (w *promWriter) family(name, kind, help string) *promFamily {
+	f := &promFamily{name: name, kind: kind, help: help}
+	w.families = append(w.families, f)
+	return f
+}
+
+func (w *promWriter) writeTo(out io.Writer) error {
+	bw := bufio.NewWriter(out)
+	for _, f := range w.families {
+		if len(f.samples) == 0 {
+			continue
+		}
+		sort.SliceStable(f.samples, func(i, j int) bool {
+			return strings.Join(f.samples[i].labels, "\xff") < strings.Join(f.samples[j].labels, "\xff")
+		})
+		bw.WriteString("# HELP " + f.name + " " + f.help + "\n")
+		bw.WriteString("# TYPE " + f.name + " " + f.kind + "\n")
+		for _, s := range f.samples {
+			bw.WriteString(f.name)
+			if len(s.labels) > 0 {
+				bw.WriteByte('{')
+				for i := 0; i+1 < len(s.labels); i += 2 {
+					if i > 0 {
+						bw.WriteByte(',')
+					}
+					bw.WriteString(s.labels[i] + "=\"" + escapeLabelValue(s.labels[i+1]) + "\"")
+				}
+				bw.WriteByte('}')
+			}
+			bw.WriteString(" " + strconv.FormatFloat(s.value, 'g', -1, 64) + "\n")
+		}
+	}
+	return bw.Flush()
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeLabelValue(v string) string {
+	return labelValueReplacer.Replace(v)
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// collectStats adds traffic counters and online maps of the stats manager to w.
+func collectStats(w *promWriter, manager *stats.Manager) {
+	traffic := w.family("xray_traffic_bytes_total", "counter", "Traffic passed through inbounds and outbounds in bytes.")
+	userTraffic := w.family("xray_user_traffic_bytes_total", "counter", "Traffic of users in bytes.")
+	manager.VisitCounters(func(name string, counter feature_stats.Counter) bool {
+		nameSplit := strings.Split(name, ">>>")
+		if len(nameSplit) != 4 || nameSplit[2] != "traffic" {
+			return true
+		}
+		typeName, tagOrUser, direction := nameSplit[0], nameSplit[1], nameSplit[3]
+		switch typeName {
+		case "inbound", "outbound":
+			traffic.add(float64(counter.Value()), "type", typeName, "tag", tagOrUser, "direction", direction)
+		case "user":
+			userTraffic.add(float64(counter.Value()), "user", tagOrUser, "direction", direction)
+		}
+		return true
+	})
+
+	onlineIPs := w.family("xray_user_online_ips", "gauge", "Number of distinct source IPs a user is currently connected from.")
+	onlineUsers := w.family("xray_online_users", "gauge", "Number of users with at least one online IP.")
+	var online int
+	manager.VisitOnlineMaps(func(name string, om feature_stats.OnlineMap) bool {
+		nameSplit := strings.Split(name, ">>>")
+		if len(nameSplit) != 3 || nameSplit[0] != "user" || nameSplit[2] != "online" {
+			return true
+		}
+		count := om.Count()
+		onlineIPs.add(float64(count), "user", nameSplit[1])
+		if count > 0 {
+			online++
+		}
+		return true
+	})
+	onlineUsers.add(float64(online))
+}
+
+// collectObservatory adds the latest observation results to w.
+func collectObservatory(w *promWriter, status []*observatory.OutboundStatus) {
+	alive := w.family("xray_observatory_alive", "gauge", "Whether the outbound is considered alive by the observatory.")
+	delay := w.family("xray_observatory_delay_seconds", "gauge", "Delay of the last probe request.")
+	lastSeen := w.family("xray_observatory_last_seen_timestamp_seconds", "gauge", "Time the outbound was last known to be alive.")
+	lastTry := w.family("xray_observatory_last_try_timestamp_seconds", "gauge", "Time the outbound was last probed.")
+	pings := w.family("xray_observatory_health_ping_total", "gauge", "Number of health pings in the current sampling window.")
+	rtt := w.family("xray_observatory_health_ping_rtt_seconds", "gauge", "Round trip time statistics of health pings.")
+	for _, s := range status {
+		tag := s.OutboundTag
+		alive.add(boolToFloat(s.Alive), "outbound_tag", tag)
+		delay.add((time.Duration(s.Delay) * time.Millisecond).Seconds(), "outbound_tag", tag)
+		lastSeen.add(float64(s.LastSeenTime), "outbound_tag", tag)
+		lastTry.add(float64(s.LastTryTime), "outbound_tag", tag)
+		if hp := s.HealthPing; hp != nil {
+			pings.add(float64(hp.All), "outbound_tag", tag, "result", "all")
+			pings.add(float64(hp.Fail), "outbound_tag", tag, "result", "fail")
+			rtt.add(time.Duration(hp.Average).Seconds(), "outbound_tag", tag, "stat", "average")
+			rtt.add(time.Duration(hp.Deviation).Seconds(), "outbound_tag", tag, "stat", "deviation")
+			rtt.add(time.Duration(hp.Max).Seconds(), "outbound_tag", tag, "stat", "max")
+			rtt.add(time.Duration(hp.Min).Seconds(), "outbound_tag", tag, "stat", "min")
+		}
+	}
+}
+
+// collectRuntime adds the Go runtime statistics reported by StatsService.GetSysStats to w.
+func collectRuntime(w *promWriter, startTime time.Time) {
+	var rtm runtime.MemStats
+	runtime.ReadMemStats(&rtm)
+
+	w.family("xray_uptime_seconds", "gauge", "Time since the metrics handler was created.").add(time.Since(startTime).Seconds())
+	w.family("xray_goroutines", "gauge", "Number of goroutines that currently exist.").add(float64(runtime.NumGoroutine()))
+	w.family("xray_memstats_alloc_bytes", "gauge", "Bytes of allocated heap objects.").add(float64(rtm.Alloc))
+	w.family("xray_memstats_alloc_bytes_total", "counter", "Cumulative bytes allocated for heap objects.").add(float64(rtm.TotalAlloc))
+	w.family("xray_memstats_sys_bytes", "gauge", "Total bytes of memory obtained from the OS.").add(float64(rtm.Sys))
+	w.family("xray_memstats_mallocs_total", "counter", "Cumulative count of heap objects allocated.").add(float64(rtm.Mallocs))
+	w.family("xray_memstats_frees_total", "counter", "Cumulative count of heap objects freed.").add(float64(rtm.Frees))
+	w.family("xray_memstats_live_objects", "gauge", "Number of live heap objects.").add(float64(rtm.Mallocs - rtm.Frees))
+	w.family("xray_memstats_gc_total", "counter", "Number of completed GC cycles.").add(float64(rtm.NumGC))
+	w.family("xray_memstats_gc_pause_seconds_total", "counter", "Cumulative time spent in GC stop-the-world pauses.").add(time.Duration(rtm.PauseTotalNs).Seconds())
+}
+
+// ServeHTTP implements http.Handler. It serves all metrics in Prometheus text exposition format.
+func (p *MetricsHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	w := &promWriter{}
+	if manager, ok := p.statsManager.(*stats.Manager); ok {
+		collectStats(w, manager)
+	}
+	if status, err := p.outboundStatus(); err == nil {
+		collectObservatory(w, status)
+	}
+	collectRuntime(w, p.startTime)
+
+	rw.Header().Set("Content-Type", prometheusContentType)
+	w.writeTo(rw)
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/xtls/xray-core/app/observatory"
+	"github.com/xtls/xray-core/app/stats"
+	"github.com/xtls/xray-core/common"
+)
+
+func TestPrometheusExposition(t *testing.T) {
+	manager, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+
+	c, err := manager.RegisterCounter("inbound>>>socks-in>>>traffic>>>uplink")
+	common.Must(err)
+	c.Set(1024)
+	c, err = manager.RegisterCounter("user>>>a\"b@example.com>>>traffic>>>downlink")
+	common.Must(err)
+	c.Set(42)
+	om, err := manager.RegisterOnlineMap("user>>>love@example.com>>>online")
+	common.Must(err)
+	om.AddIP("10.0.0.1")
+	om.AddIP("10.0.0.2")
+
+	w := &promWriter{}
+	collectStats(w, manager)
+	collectObservatory(w, []*observatory.OutboundStatus{
+		{OutboundTag: "proxy", Alive: true, Delay: 250},
+	})
+	var buf bytes.Buffer
+	common.Must(w.writeTo(&buf))
+	out := buf.String()
+
+	for _, line := range []string{
+		"# TYPE xray_traffic_bytes_total counter",
+		`xray_traffic_bytes_total{type="inbound",tag="socks-in",direction="uplink"} 1024`,
+		`xray_user_traffic_bytes_total{user="a\"b@example.com",direction="downlink"} 42`,
+		`xray_user_online_ips{user="love@example.com"} 2`,
+		"xray_online_users 1",
+		`xray_observatory_alive{outbound_tag="proxy"} 1`,
+		`xray_observatory_delay_seconds{outbound_tag="proxy"} 0.25`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Error("missing line: ", line, "\n", out)
+		}
+	}
+	if strings.Contains(out, "xray_observatory_health_ping_total") {
+		t.Error("unexpected health ping metrics without measurement")
+	}
+}
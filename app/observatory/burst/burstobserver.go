@@ -98,6 +98,7 @@ func New(ctx context.Context, config *Config) (*Observer, error) {
 		return nil, errors.New("Cannot get depended features").Base(err)
 	}
 	hp := NewHealthPing(ctx, dispatcher, config.PingConfig)
+	hp.Probes = config.Probes
 	return &Observer{
 		config: config,
 		ctx:    ctx,
@@ -7,6 +7,7 @@
 package burst
 
 import (
+	observatory "github.com/xtls/xray-core/app/observatory"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -26,8 +27,11 @@ type Config struct {
 	// @Document The selectors for outbound under observation
 	SubjectSelector []string          `protobuf:"bytes,2,rep,name=subject_selector,json=subjectSelector,proto3" json:"subject_selector,omitempty"`
 	PingConfig      *HealthPingConfig `protobuf:"bytes,3,opt,name=ping_config,json=pingConfig,proto3" json:"ping_config,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	// @Document Probes of outbounds matching their selectors, instead of the
+	//HTTP request of ping_config
+	Probes        []*observatory.Probe `protobuf:"bytes,4,rep,name=probes,proto3" json:"probes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Config) Reset() {
@@ -74,6 +78,13 @@ func (x *Config) GetPingConfig() *HealthPingConfig {
 	return nil
 }
 
+func (x *Config) GetProbes() []*observatory.Probe {
+	if x != nil {
+		return x.Probes
+	}
+	return nil
+}
+
 type HealthPingConfig struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// destination url, need 204 for success return
@@ -169,11 +180,12 @@ var File_app_observatory_burst_config_proto protoreflect.FileDescriptor
 
 const file_app_observatory_burst_config_proto_rawDesc = "" +
 	"\n" +
-	"\"app/observatory/burst/config.proto\x12\x1fxray.core.app.observatory.burst\"\x87\x01\n" +
+	"\"app/observatory/burst/config.proto\x12\x1fxray.core.app.observatory.burst\x1a\x1capp/observatory/config.proto\"\xc1\x01\n" +
 	"\x06Config\x12)\n" +
 	"\x10subject_selector\x18\x02 \x03(\tR\x0fsubjectSelector\x12R\n" +
 	"\vping_config\x18\x03 \x01(\v21.xray.core.app.observatory.burst.HealthPingConfigR\n" +
-	"pingConfig\"\xd4\x01\n" +
+	"pingConfig\x128\n" +
+	"\x06probes\x18\x04 \x03(\v2 .xray.core.app.observatory.ProbeR\x06probes\"\xd4\x01\n" +
 	"\x10HealthPingConfig\x12 \n" +
 	"\vdestination\x18\x01 \x01(\tR\vdestination\x12\"\n" +
 	"\fconnectivity\x18\x02 \x01(\tR\fconnectivity\x12\x1a\n" +
@@ -199,16 +211,18 @@ func file_app_observatory_burst_config_proto_rawDescGZIP() []byte {
 
 var file_app_observatory_burst_config_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_app_observatory_burst_config_proto_goTypes = []any{
-	(*Config)(nil),           // 0: xray.core.app.observatory.burst.Config
-	(*HealthPingConfig)(nil), // 1: xray.core.app.observatory.burst.HealthPingConfig
+	(*Config)(nil),            // 0: xray.core.app.observatory.burst.Config
+	(*HealthPingConfig)(nil),  // 1: xray.core.app.observatory.burst.HealthPingConfig
+	(*observatory.Probe)(nil), // 2: xray.core.app.observatory.Probe
 }
 var file_app_observatory_burst_config_proto_depIdxs = []int32{
 	1, // 0: xray.core.app.observatory.burst.Config.ping_config:type_name -> xray.core.app.observatory.burst.HealthPingConfig
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	2, // 1: xray.core.app.observatory.burst.Config.probes:type_name -> xray.core.app.observatory.Probe
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_app_observatory_burst_config_proto_init() }
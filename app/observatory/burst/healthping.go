@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/xtls/xray-core/app/observatory"
 	"github.com/xtls/xray-core/common/dice"
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/features/routing"
@@ -32,6 +33,9 @@ type HealthPing struct {
 
 	Settings *HealthPingSettings
 	Results  map[string]*HealthPingRTTS
+
+	// Probes of outbounds matching their selectors, instead of the HTTP request of Settings
+	Probes []*observatory.Probe
 }
 
 // NewHealthPing creates a new HealthPing with settings
@@ -167,6 +171,16 @@ func (h *HealthPing) doCheck(tags []string, duration time.Duration, rounds int)
 			h.Settings.Timeout,
 			handler,
 		)
+		destination := h.Settings.Destination
+		measure := func() (time.Duration, error) {
+			return client.MeasureDelay(h.Settings.HttpMethod)
+		}
+		if probe := observatory.MatchProbe(h.Probes, handler); probe != nil {
+			destination = probe.Destination
+			measure = func() (time.Duration, error) {
+				return probe.Measure(h.ctx, h.dispatcher, handler, h.Settings.Timeout)
+			}
+		}
 		for i := 0; i < rounds; i++ {
 			delay := time.Duration(0)
 			if duration > 0 {
@@ -174,7 +188,7 @@ func (h *HealthPing) doCheck(tags []string, duration time.Duration, rounds int)
 			}
 			time.AfterFunc(delay, func() {
 				errors.LogDebug(h.ctx, "checking ", handler)
-				delay, err := client.MeasureDelay(h.Settings.HttpMethod)
+				delay, err := measure()
 				if err == nil {
 					ch <- &rtt{
 						handler: handler,
@@ -192,7 +206,7 @@ func (h *HealthPing) doCheck(tags []string, duration time.Duration, rounds int)
 				}
 				errors.LogWarning(h.ctx, fmt.Sprintf(
 					"error ping %s with %s: %s",
-					h.Settings.Destination,
+					destination,
 					handler,
 					err,
 				))
@@ -21,6 +21,58 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type Probe_Type int32
+
+const (
+	Probe_HTTP Probe_Type = 0
+	Probe_TCP  Probe_Type = 1
+	Probe_DNS  Probe_Type = 2
+	Probe_UDP  Probe_Type = 3
+)
+
+// Enum value maps for Probe_Type.
+var (
+	Probe_Type_name = map[int32]string{
+		0: "HTTP",
+		1: "TCP",
+		2: "DNS",
+		3: "UDP",
+	}
+	Probe_Type_value = map[string]int32{
+		"HTTP": 0,
+		"TCP":  1,
+		"DNS":  2,
+		"UDP":  3,
+	}
+)
+
+func (x Probe_Type) Enum() *Probe_Type {
+	p := new(Probe_Type)
+	*p = x
+	return p
+}
+
+func (x Probe_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Probe_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_observatory_config_proto_enumTypes[0].Descriptor()
+}
+
+func (Probe_Type) Type() protoreflect.EnumType {
+	return &file_app_observatory_config_proto_enumTypes[0]
+}
+
+func (x Probe_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Probe_Type.Descriptor instead.
+func (Probe_Type) EnumDescriptor() ([]byte, []int) {
+	return file_app_observatory_config_proto_rawDescGZIP(), []int{5, 0}
+}
+
 type ObservationResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Status        []*OutboundStatus      `protobuf:"bytes,1,rep,name=status,proto3" json:"status,omitempty"`
@@ -152,23 +204,23 @@ func (x *HealthPingMeasurementResult) GetMin() int64 {
 type OutboundStatus struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// @Document Whether this outbound is usable
-	// @Restriction ReadOnlyForUser
+	//@Restriction ReadOnlyForUser
 	Alive bool `protobuf:"varint,1,opt,name=alive,proto3" json:"alive,omitempty"`
 	// @Document The time for probe request to finish.
-	// @Type time.ms
-	// @Restriction ReadOnlyForUser
+	//@Type time.ms
+	//@Restriction ReadOnlyForUser
 	Delay int64 `protobuf:"varint,2,opt,name=delay,proto3" json:"delay,omitempty"`
 	// @Document The last error caused this outbound failed to relay probe request
-	// @Restriction NotMachineReadable
+	//@Restriction NotMachineReadable
 	LastErrorReason string `protobuf:"bytes,3,opt,name=last_error_reason,json=lastErrorReason,proto3" json:"last_error_reason,omitempty"`
 	// @Document The outbound tag for this Server
-	// @Type id.outboundTag
+	//@Type id.outboundTag
 	OutboundTag string `protobuf:"bytes,4,opt,name=outbound_tag,json=outboundTag,proto3" json:"outbound_tag,omitempty"`
 	// @Document The time this outbound is known to be alive
-	// @Type id.outboundTag
+	//@Type id.outboundTag
 	LastSeenTime int64 `protobuf:"varint,5,opt,name=last_seen_time,json=lastSeenTime,proto3" json:"last_seen_time,omitempty"`
 	// @Document The time this outbound is tried
-	// @Type id.outboundTag
+	//@Type id.outboundTag
 	LastTryTime   int64                        `protobuf:"varint,6,opt,name=last_try_time,json=lastTryTime,proto3" json:"last_try_time,omitempty"`
 	HealthPing    *HealthPingMeasurementResult `protobuf:"bytes,7,opt,name=health_ping,json=healthPing,proto3" json:"health_ping,omitempty"`
 	unknownFields protoimpl.UnknownFields
@@ -257,14 +309,14 @@ func (x *OutboundStatus) GetHealthPing() *HealthPingMeasurementResult {
 type ProbeResult struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// @Document Whether this outbound is usable
-	// @Restriction ReadOnlyForUser
+	//@Restriction ReadOnlyForUser
 	Alive bool `protobuf:"varint,1,opt,name=alive,proto3" json:"alive,omitempty"`
 	// @Document The time for probe request to finish.
-	// @Type time.ms
-	// @Restriction ReadOnlyForUser
+	//@Type time.ms
+	//@Restriction ReadOnlyForUser
 	Delay int64 `protobuf:"varint,2,opt,name=delay,proto3" json:"delay,omitempty"`
 	// @Document The error caused this outbound failed to relay probe request
-	// @Restriction NotMachineReadable
+	//@Restriction NotMachineReadable
 	LastErrorReason string `protobuf:"bytes,3,opt,name=last_error_reason,json=lastErrorReason,proto3" json:"last_error_reason,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
@@ -324,7 +376,7 @@ func (x *ProbeResult) GetLastErrorReason() string {
 type Intensity struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// @Document The time interval for a probe request in ms.
-	// @Type time.ms
+	//@Type time.ms
 	ProbeInterval uint32 `protobuf:"varint,1,opt,name=probe_interval,json=probeInterval,proto3" json:"probe_interval,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -367,6 +419,118 @@ func (x *Intensity) GetProbeInterval() uint32 {
 	return 0
 }
 
+type Probe struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// @Document The selectors for outbounds probed by this probe
+	SubjectSelector []string   `protobuf:"bytes,1,rep,name=subject_selector,json=subjectSelector,proto3" json:"subject_selector,omitempty"`
+	Type            Probe_Type `protobuf:"varint,2,opt,name=type,proto3,enum=xray.core.app.observatory.Probe_Type" json:"type,omitempty"`
+	// @Document URL of HTTP probes, host:port of the others. The port of DNS
+	//servers defaults to 53.
+	Destination string `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
+	// @Document Method of HTTP probes, default GET
+	HttpMethod string `protobuf:"bytes,4,opt,name=http_method,json=httpMethod,proto3" json:"http_method,omitempty"`
+	// @Document Expected status code of HTTP probes, any status if 0
+	ExpectedStatus uint32 `protobuf:"varint,5,opt,name=expected_status,json=expectedStatus,proto3" json:"expected_status,omitempty"`
+	// @Document Substring expected in the body of HTTP probes, or in the
+	//response of TCP and UDP probes.
+	Expected string `protobuf:"bytes,6,opt,name=expected,proto3" json:"expected,omitempty"`
+	// @Document Domain to query by DNS probes, default www.google.com
+	Domain string `protobuf:"bytes,7,opt,name=domain,proto3" json:"domain,omitempty"`
+	// @Document Data sent by TCP and UDP probes. As connections through
+	//outbounds are established lazily, TCP probes are alive once any data is
+	//received, so the payload should make the server respond. TCP probes
+	//require a payload, or an expected response from servers that speak first.
+	Payload       []byte `protobuf:"bytes,8,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Probe) Reset() {
+	*x = Probe{}
+	mi := &file_app_observatory_config_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Probe) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Probe) ProtoMessage() {}
+
+func (x *Probe) ProtoReflect() protoreflect.Message {
+	mi := &file_app_observatory_config_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Probe.ProtoReflect.Descriptor instead.
+func (*Probe) Descriptor() ([]byte, []int) {
+	return file_app_observatory_config_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Probe) GetSubjectSelector() []string {
+	if x != nil {
+		return x.SubjectSelector
+	}
+	return nil
+}
+
+func (x *Probe) GetType() Probe_Type {
+	if x != nil {
+		return x.Type
+	}
+	return Probe_HTTP
+}
+
+func (x *Probe) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *Probe) GetHttpMethod() string {
+	if x != nil {
+		return x.HttpMethod
+	}
+	return ""
+}
+
+func (x *Probe) GetExpectedStatus() uint32 {
+	if x != nil {
+		return x.ExpectedStatus
+	}
+	return 0
+}
+
+func (x *Probe) GetExpected() string {
+	if x != nil {
+		return x.Expected
+	}
+	return ""
+}
+
+func (x *Probe) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *Probe) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
 type Config struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// @Document The selectors for outbound under observation
@@ -374,13 +538,16 @@ type Config struct {
 	ProbeUrl          string   `protobuf:"bytes,3,opt,name=probe_url,json=probeUrl,proto3" json:"probe_url,omitempty"`
 	ProbeInterval     int64    `protobuf:"varint,4,opt,name=probe_interval,json=probeInterval,proto3" json:"probe_interval,omitempty"`
 	EnableConcurrency bool     `protobuf:"varint,5,opt,name=enable_concurrency,json=enableConcurrency,proto3" json:"enable_concurrency,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	// @Document Probes of outbounds matching their selectors, instead of HTTP
+	//GET to probe_url
+	Probes        []*Probe `protobuf:"bytes,6,rep,name=probes,proto3" json:"probes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Config) Reset() {
 	*x = Config{}
-	mi := &file_app_observatory_config_proto_msgTypes[5]
+	mi := &file_app_observatory_config_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -392,7 +559,7 @@ func (x *Config) String() string {
 func (*Config) ProtoMessage() {}
 
 func (x *Config) ProtoReflect() protoreflect.Message {
-	mi := &file_app_observatory_config_proto_msgTypes[5]
+	mi := &file_app_observatory_config_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -405,7 +572,7 @@ func (x *Config) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Config.ProtoReflect.Descriptor instead.
 func (*Config) Descriptor() ([]byte, []int) {
-	return file_app_observatory_config_proto_rawDescGZIP(), []int{5}
+	return file_app_observatory_config_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *Config) GetSubjectSelector() []string {
@@ -436,6 +603,13 @@ func (x *Config) GetEnableConcurrency() bool {
 	return false
 }
 
+func (x *Config) GetProbes() []*Probe {
+	if x != nil {
+		return x.Probes
+	}
+	return nil
+}
+
 var File_app_observatory_config_proto protoreflect.FileDescriptor
 
 const file_app_observatory_config_proto_rawDesc = "" +
@@ -464,12 +638,28 @@ const file_app_observatory_config_proto_rawDesc = "" +
 	"\x05delay\x18\x02 \x01(\x03R\x05delay\x12*\n" +
 	"\x11last_error_reason\x18\x03 \x01(\tR\x0flastErrorReason\"2\n" +
 	"\tIntensity\x12%\n" +
-	"\x0eprobe_interval\x18\x01 \x01(\rR\rprobeInterval\"\xa6\x01\n" +
+	"\x0eprobe_interval\x18\x01 \x01(\rR\rprobeInterval\"\xd4\x02\n" +
+	"\x05Probe\x12)\n" +
+	"\x10subject_selector\x18\x01 \x03(\tR\x0fsubjectSelector\x129\n" +
+	"\x04type\x18\x02 \x01(\x0e2%.xray.core.app.observatory.Probe.TypeR\x04type\x12 \n" +
+	"\vdestination\x18\x03 \x01(\tR\vdestination\x12\x1f\n" +
+	"\vhttp_method\x18\x04 \x01(\tR\n" +
+	"httpMethod\x12'\n" +
+	"\x0fexpected_status\x18\x05 \x01(\rR\x0eexpectedStatus\x12\x1a\n" +
+	"\bexpected\x18\x06 \x01(\tR\bexpected\x12\x16\n" +
+	"\x06domain\x18\a \x01(\tR\x06domain\x12\x18\n" +
+	"\apayload\x18\b \x01(\fR\apayload\"+\n" +
+	"\x04Type\x12\b\n" +
+	"\x04HTTP\x10\x00\x12\a\n" +
+	"\x03TCP\x10\x01\x12\a\n" +
+	"\x03DNS\x10\x02\x12\a\n" +
+	"\x03UDP\x10\x03\"\xe0\x01\n" +
 	"\x06Config\x12)\n" +
 	"\x10subject_selector\x18\x02 \x03(\tR\x0fsubjectSelector\x12\x1b\n" +
 	"\tprobe_url\x18\x03 \x01(\tR\bprobeUrl\x12%\n" +
 	"\x0eprobe_interval\x18\x04 \x01(\x03R\rprobeInterval\x12-\n" +
-	"\x12enable_concurrency\x18\x05 \x01(\bR\x11enableConcurrencyB^\n" +
+	"\x12enable_concurrency\x18\x05 \x01(\bR\x11enableConcurrency\x128\n" +
+	"\x06probes\x18\x06 \x03(\v2 .xray.core.app.observatory.ProbeR\x06probesB^\n" +
 	"\x18com.xray.app.observatoryP\x01Z)github.com/xtls/xray-core/app/observatory\xaa\x02\x14Xray.App.Observatoryb\x06proto3"
 
 var (
@@ -484,23 +674,28 @@ func file_app_observatory_config_proto_rawDescGZIP() []byte {
 	return file_app_observatory_config_proto_rawDescData
 }
 
-var file_app_observatory_config_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_app_observatory_config_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_app_observatory_config_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_app_observatory_config_proto_goTypes = []any{
-	(*ObservationResult)(nil),           // 0: xray.core.app.observatory.ObservationResult
-	(*HealthPingMeasurementResult)(nil), // 1: xray.core.app.observatory.HealthPingMeasurementResult
-	(*OutboundStatus)(nil),              // 2: xray.core.app.observatory.OutboundStatus
-	(*ProbeResult)(nil),                 // 3: xray.core.app.observatory.ProbeResult
-	(*Intensity)(nil),                   // 4: xray.core.app.observatory.Intensity
-	(*Config)(nil),                      // 5: xray.core.app.observatory.Config
+	(Probe_Type)(0),                     // 0: xray.core.app.observatory.Probe.Type
+	(*ObservationResult)(nil),           // 1: xray.core.app.observatory.ObservationResult
+	(*HealthPingMeasurementResult)(nil), // 2: xray.core.app.observatory.HealthPingMeasurementResult
+	(*OutboundStatus)(nil),              // 3: xray.core.app.observatory.OutboundStatus
+	(*ProbeResult)(nil),                 // 4: xray.core.app.observatory.ProbeResult
+	(*Intensity)(nil),                   // 5: xray.core.app.observatory.Intensity
+	(*Probe)(nil),                       // 6: xray.core.app.observatory.Probe
+	(*Config)(nil),                      // 7: xray.core.app.observatory.Config
 }
 var file_app_observatory_config_proto_depIdxs = []int32{
-	2, // 0: xray.core.app.observatory.ObservationResult.status:type_name -> xray.core.app.observatory.OutboundStatus
-	1, // 1: xray.core.app.observatory.OutboundStatus.health_ping:type_name -> xray.core.app.observatory.HealthPingMeasurementResult
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	3, // 0: xray.core.app.observatory.ObservationResult.status:type_name -> xray.core.app.observatory.OutboundStatus
+	2, // 1: xray.core.app.observatory.OutboundStatus.health_ping:type_name -> xray.core.app.observatory.HealthPingMeasurementResult
+	0, // 2: xray.core.app.observatory.Probe.type:type_name -> xray.core.app.observatory.Probe.Type
+	6, // 3: xray.core.app.observatory.Config.probes:type_name -> xray.core.app.observatory.Probe
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_app_observatory_config_proto_init() }
@@ -513,13 +708,14 @@ func file_app_observatory_config_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_observatory_config_proto_rawDesc), len(file_app_observatory_config_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      1,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_app_observatory_config_proto_goTypes,
 		DependencyIndexes: file_app_observatory_config_proto_depIdxs,
+		EnumInfos:         file_app_observatory_config_proto_enumTypes,
 		MessageInfos:      file_app_observatory_config_proto_msgTypes,
 	}.Build()
 	File_app_observatory_config_proto = out.File
@@ -119,6 +119,10 @@ func (o *Observer) updateStatus(outbounds []string) {
 }
 
 func (o *Observer) probe(outbound string) ProbeResult {
+	if p := MatchProbe(o.config.Probes, outbound); p != nil {
+		return o.probeWith(p, outbound)
+	}
+
 	errorCollectorForRequest := newErrorCollector()
 
 	httpTransport := http.Transport{
@@ -185,6 +189,20 @@ func (o *Observer) probe(outbound string) ProbeResult {
 	return ProbeResult{Alive: true, Delay: GETTime.Milliseconds()}
 }
 
+// probeWith probes the outbound with the probe configured for it.
+func (o *Observer) probeWith(p *Probe, outbound string) ProbeResult {
+	errorCollectorForRequest := newErrorCollector()
+	trackedCtx := session.TrackedConnectionError(o.ctx, errorCollectorForRequest)
+	delay, err := p.Measure(trackedCtx, o.dispatcher, outbound, time.Second*5)
+	if err != nil {
+		var errorMessage = "the outbound " + outbound + " is dead: " + p.Type.String() + " probe failed: " + err.Error()
+		errors.LogInfoInner(o.ctx, errorCollectorForRequest.UnderlyingError(), errorMessage)
+		return ProbeResult{Alive: false, LastErrorReason: errorMessage}
+	}
+	errors.LogInfo(o.ctx, "the outbound ", outbound, " is alive:", delay.Seconds())
+	return ProbeResult{Alive: true, Delay: delay.Milliseconds()}
+}
+
 func (o *Observer) updateStatusForResult(outbound string, result *ProbeResult) {
 	o.statusLock.Lock()
 	defer o.statusLock.Unlock()
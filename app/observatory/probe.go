@@ -0,0 +1,203 @@
+package observatory
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/xtls/xray-core/common/crypto"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/utils"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/transport/internet/tagged"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// maxProbeResponseSize is the limit of the response read by probes.
+const maxProbeResponseSize = 64 * 1024
+
+// MatchProbe returns the first probe with a selector matching the outbound, or nil.
+func MatchProbe(probes []*Probe, outbound string) *Probe {
+	for _, p := range probes {
+		for _, selector := range p.SubjectSelector {
+			if strings.HasPrefix(outbound, selector) {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+// Measure probes the outbound, and returns the delay if it is alive.
+func (p *Probe) Measure(ctx context.Context, dispatcher routing.Dispatcher, outbound string, timeout time.Duration) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	var err error
+	switch p.Type {
+	case Probe_HTTP:
+		err = p.probeHTTP(ctx, dispatcher, outbound)
+	case Probe_TCP:
+		err = p.probeStream(ctx, dispatcher, outbound, net.Network_TCP)
+	case Probe_UDP:
+		err = p.probeStream(ctx, dispatcher, outbound, net.Network_UDP)
+	case Probe_DNS:
+		err = p.probeDNS(ctx, dispatcher, outbound)
+	default:
+		err = errors.New("unknown probe type ", p.Type)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+func (p *Probe) probeHTTP(ctx context.Context, dispatcher routing.Dispatcher, outbound string) error {
+	transport := &http.Transport{
+		DisableKeepAlives: true,
+		DialContext: func(_ context.Context, network, addr string) (net.Conn, error) {
+			dest, err := net.ParseDestination(network + ":" + addr)
+			if err != nil {
+				return nil, errors.New("cannot understand address").Base(err)
+			}
+			return tagged.Dialer(ctx, dispatcher, dest, outbound)
+		},
+	}
+	client := &http.Client{
+		Transport: transport,
+		// don't follow redirect
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	method := p.HttpMethod
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.Destination, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", utils.ChromeUA)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if p.ExpectedStatus != 0 && resp.StatusCode != int(p.ExpectedStatus) {
+		return errors.New("unexpected status ", resp.Status)
+	}
+	if p.Expected == "" {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxProbeResponseSize))
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(body), p.Expected) {
+		return errors.New("expected content not found in response")
+	}
+	return nil
+}
+
+// probeStream sends the payload, and waits for the expected response.
+func (p *Probe) probeStream(ctx context.Context, dispatcher routing.Dispatcher, outbound string, network net.Network) error {
+	dest, err := net.ParseDestination(network.SystemString() + ":" + p.Destination)
+	if err != nil {
+		return errors.New("invalid probe destination ", p.Destination).Base(err)
+	}
+	payload := p.Payload
+	if network == net.Network_UDP && len(payload) == 0 {
+		payload = []byte("ping")
+	}
+	response, err := exchange(ctx, dispatcher, outbound, dest, payload)
+	if err != nil {
+		return err
+	}
+	if p.Expected != "" && !bytes.Contains(response, []byte(p.Expected)) {
+		return errors.New("expected content not found in response")
+	}
+	return nil
+}
+
+func (p *Probe) probeDNS(ctx context.Context, dispatcher routing.Dispatcher, outbound string) error {
+	address := p.Destination
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, "53")
+	}
+	dest, err := net.ParseDestination("udp:" + address)
+	if err != nil {
+		return errors.New("invalid probe destination ", p.Destination).Base(err)
+	}
+	domain := p.Domain
+	if domain == "" {
+		domain = "www.google.com"
+	}
+	name, err := dnsmessage.NewName(strings.TrimSuffix(domain, ".") + ".")
+	if err != nil {
+		return errors.New("invalid probe domain ", domain).Base(err)
+	}
+
+	id := uint16(crypto.RandBetween(0, 65536))
+	query, err := (&dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET}},
+	}).Pack()
+	if err != nil {
+		return err
+	}
+	response, err := exchange(ctx, dispatcher, outbound, dest, query)
+	if err != nil {
+		return err
+	}
+	var header dnsmessage.Header
+	var parser dnsmessage.Parser
+	if header, err = parser.Start(response); err != nil {
+		return errors.New("invalid DNS response").Base(err)
+	}
+	if header.ID != id {
+		return errors.New("unexpected DNS response ID")
+	}
+	if header.RCode != dnsmessage.RCodeSuccess {
+		return errors.New("DNS query failed: ", header.RCode)
+	}
+	return nil
+}
+
+// exchange dials the destination through the outbound, sends the payload if any,
+// and returns the first response.
+func exchange(ctx context.Context, dispatcher routing.Dispatcher, outbound string, dest net.Destination, payload []byte) ([]byte, error) {
+	conn, err := tagged.Dialer(ctx, dispatcher, dest, outbound)
+	if err != nil {
+		return nil, errors.New("cannot dial remote address ", dest).Base(err)
+	}
+	// Connections through outbounds have no deadline, so close it to stop reading on timeout.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer func() {
+		if stop() {
+			conn.Close()
+		}
+	}()
+
+	if len(payload) > 0 {
+		if _, err := conn.Write(payload); err != nil {
+			return nil, err
+		}
+	}
+	b := make([]byte, maxProbeResponseSize)
+	n, err := conn.Read(b)
+	if n == 0 {
+		if err == nil || err == io.EOF {
+			err = errors.New("no response")
+		}
+		return nil, err
+	}
+	return b[:n], nil
+}
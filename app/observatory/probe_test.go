@@ -0,0 +1,150 @@
+package observatory
+
+import (
+	"context"
+	"io"
+	gonet "net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/transport/internet/tagged"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dialDirectly makes the probes of the test dial directly instead of through outbounds.
+func dialDirectly(t *testing.T) {
+	dialer := tagged.Dialer
+	tagged.Dialer = func(ctx context.Context, dispatcher routing.Dispatcher, dest net.Destination, tag string) (net.Conn, error) {
+		return gonet.Dial(dest.Network.SystemString(), dest.NetAddr())
+	}
+	t.Cleanup(func() {
+		tagged.Dialer = dialer
+	})
+}
+
+func TestMatchProbe(t *testing.T) {
+	probes := []*Probe{
+		{SubjectSelector: []string{"dns-"}, Type: Probe_DNS},
+		{SubjectSelector: []string{"game-", "udp-"}, Type: Probe_UDP},
+	}
+	if p := MatchProbe(probes, "udp-1"); p != probes[1] {
+		t.Error("expected UDP probe, got ", p)
+	}
+	if p := MatchProbe(probes, "proxy"); p != nil {
+		t.Error("expected no probe, got ", p)
+	}
+}
+
+func TestProbeHTTP(t *testing.T) {
+	dialDirectly(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		io.WriteString(w, "hello xray")
+	}))
+	defer server.Close()
+
+	for _, c := range []struct {
+		probe *Probe
+		alive bool
+	}{
+		{&Probe{Destination: server.URL}, true},
+		{&Probe{Destination: server.URL, ExpectedStatus: 202, Expected: "xray"}, true},
+		{&Probe{Destination: server.URL, ExpectedStatus: 204}, false},
+		{&Probe{Destination: server.URL, Expected: "v2ray"}, false},
+	} {
+		_, err := c.probe.Measure(context.Background(), nil, "test", time.Second*5)
+		if (err == nil) != c.alive {
+			t.Error("probe ", c.probe, ": expected alive ", c.alive, ", got error ", err)
+		}
+	}
+}
+
+func TestProbeTCPAndUDP(t *testing.T) {
+	dialDirectly(t)
+
+	tcpListener, err := gonet.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer tcpListener.Close()
+	go func() {
+		for {
+			conn, err := tcpListener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	udpConn, err := gonet.ListenPacket("udp", "127.0.0.1:0")
+	common.Must(err)
+	defer udpConn.Close()
+	go func() {
+		b := make([]byte, 1500)
+		for {
+			n, addr, err := udpConn.ReadFrom(b)
+			if err != nil {
+				return
+			}
+			udpConn.WriteTo(b[:n], addr)
+		}
+	}()
+
+	for _, c := range []struct {
+		probe *Probe
+		alive bool
+	}{
+		{&Probe{Type: Probe_TCP, Destination: tcpListener.Addr().String(), Payload: []byte("ping"), Expected: "ping"}, true},
+		{&Probe{Type: Probe_TCP, Destination: tcpListener.Addr().String(), Payload: []byte("ping"), Expected: "pong"}, false},
+		// The echo server never speaks first.
+		{&Probe{Type: Probe_TCP, Destination: tcpListener.Addr().String()}, false},
+		{&Probe{Type: Probe_UDP, Destination: udpConn.LocalAddr().String(), Expected: "ping"}, true},
+	} {
+		_, err := c.probe.Measure(context.Background(), nil, "test", time.Millisecond*500)
+		if (err == nil) != c.alive {
+			t.Error("probe ", c.probe, ": expected alive ", c.alive, ", got error ", err)
+		}
+	}
+}
+
+func TestProbeDNS(t *testing.T) {
+	dialDirectly(t)
+
+	conn, err := gonet.ListenPacket("udp", "127.0.0.1:0")
+	common.Must(err)
+	defer conn.Close()
+	go func() {
+		b := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(b)
+			if err != nil {
+				return
+			}
+			var m dnsmessage.Message
+			if err := m.Unpack(b[:n]); err != nil {
+				continue
+			}
+			m.Header.Response = true
+			if m.Questions[0].Name.String() != "example.com." {
+				m.Header.RCode = dnsmessage.RCodeNameError
+			}
+			r, _ := m.Pack()
+			conn.WriteTo(r, addr)
+		}
+	}()
+
+	if _, err := (&Probe{Type: Probe_DNS, Destination: conn.LocalAddr().String(), Domain: "example.com"}).Measure(context.Background(), nil, "test", time.Second); err != nil {
+		t.Error("expected DNS probe to succeed: ", err)
+	}
+	if _, err := (&Probe{Type: Probe_DNS, Destination: conn.LocalAddr().String(), Domain: "example.org"}).Measure(context.Background(), nil, "test", time.Second); err == nil {
+		t.Error("expected DNS probe to fail")
+	}
+}
@@ -0,0 +1,92 @@
+package command
+
+import (
+	"context"
+
+	"github.com/xtls/xray-core/app/policy"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/core"
+	feature_policy "github.com/xtls/xray-core/features/policy"
+	grpc "google.golang.org/grpc"
+)
+
+// RateLimitManager is the part of the policy manager that changes rate limits at runtime.
+type RateLimitManager interface {
+	SetUserRateLimit(email string, limit feature_policy.RateLimit)
+	ClearUserRateLimit(email string)
+	UserRateLimit(email string, level uint32) (feature_policy.RateLimit, bool)
+	SetLevelRateLimit(level uint32, limit feature_policy.RateLimit)
+}
+
+// policyServer is an implementation of PolicyService.
+type policyServer struct {
+	manager RateLimitManager
+}
+
+func NewPolicyServer(manager RateLimitManager) PolicyServiceServer {
+	return &policyServer{
+		manager: manager,
+	}
+}
+
+func (s *policyServer) SetRateLimit(ctx context.Context, request *SetRateLimitRequest) (*SetRateLimitResponse, error) {
+	var limit feature_policy.RateLimit
+	if request.RateLimit != nil {
+		limit = request.RateLimit.ToCorePolicy()
+	}
+	if request.Email != "" {
+		s.manager.SetUserRateLimit(request.Email, limit)
+	} else {
+		s.manager.SetLevelRateLimit(request.Level, limit)
+	}
+	return &SetRateLimitResponse{}, nil
+}
+
+func (s *policyServer) ClearRateLimit(ctx context.Context, request *ClearRateLimitRequest) (*ClearRateLimitResponse, error) {
+	if request.Email == "" {
+		return nil, errors.New("email is required")
+	}
+	s.manager.ClearUserRateLimit(request.Email)
+	return &ClearRateLimitResponse{}, nil
+}
+
+func (s *policyServer) GetRateLimit(ctx context.Context, request *GetRateLimitRequest) (*GetRateLimitResponse, error) {
+	limit, overridden := s.manager.UserRateLimit(request.Email, request.Level)
+	return &GetRateLimitResponse{
+		RateLimit: &policy.Policy_RateLimit{
+			Uplink:        limit.Uplink,
+			Downlink:      limit.Downlink,
+			UplinkBurst:   limit.UplinkBurst,
+			DownlinkBurst: limit.DownlinkBurst,
+		},
+		Overridden: overridden,
+	}, nil
+}
+
+func (s *policyServer) mustEmbedUnimplementedPolicyServiceServer() {}
+
+type service struct {
+	policyManager feature_policy.Manager
+}
+
+func (s *service) Register(server *grpc.Server) {
+	manager, ok := s.policyManager.(RateLimitManager)
+	if !ok {
+		errors.LogWarning(context.Background(), "policy manager doesn't support changing rate limits, PolicyService is not registered")
+		return
+	}
+	RegisterPolicyServiceServer(server, NewPolicyServer(manager))
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		s := new(service)
+
+		core.RequireFeatures(ctx, func(pm feature_policy.Manager) {
+			s.policyManager = pm
+		})
+
+		return s, nil
+	}))
+}
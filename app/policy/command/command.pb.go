@@ -0,0 +1,436 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.5
+// source: app/policy/command/command.proto
+
+package command
+
+import (
+	policy "github.com/xtls/xray-core/app/policy"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Config struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	mi := &file_app_policy_command_command_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_app_policy_command_command_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_app_policy_command_command_proto_rawDescGZIP(), []int{0}
+}
+
+type SetRateLimitRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Email of the user to limit. If empty, the limit applies to all users of the level.
+	Email         string                   `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Level         uint32                   `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+	RateLimit     *policy.Policy_RateLimit `protobuf:"bytes,3,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRateLimitRequest) Reset() {
+	*x = SetRateLimitRequest{}
+	mi := &file_app_policy_command_command_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRateLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRateLimitRequest) ProtoMessage() {}
+
+func (x *SetRateLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_policy_command_command_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRateLimitRequest.ProtoReflect.Descriptor instead.
+func (*SetRateLimitRequest) Descriptor() ([]byte, []int) {
+	return file_app_policy_command_command_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SetRateLimitRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *SetRateLimitRequest) GetLevel() uint32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *SetRateLimitRequest) GetRateLimit() *policy.Policy_RateLimit {
+	if x != nil {
+		return x.RateLimit
+	}
+	return nil
+}
+
+type SetRateLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRateLimitResponse) Reset() {
+	*x = SetRateLimitResponse{}
+	mi := &file_app_policy_command_command_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRateLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRateLimitResponse) ProtoMessage() {}
+
+func (x *SetRateLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_policy_command_command_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRateLimitResponse.ProtoReflect.Descriptor instead.
+func (*SetRateLimitResponse) Descriptor() ([]byte, []int) {
+	return file_app_policy_command_command_proto_rawDescGZIP(), []int{2}
+}
+
+type ClearRateLimitRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Email of the user whose limit set by SetRateLimit is removed.
+	Email         string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearRateLimitRequest) Reset() {
+	*x = ClearRateLimitRequest{}
+	mi := &file_app_policy_command_command_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearRateLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearRateLimitRequest) ProtoMessage() {}
+
+func (x *ClearRateLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_policy_command_command_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearRateLimitRequest.ProtoReflect.Descriptor instead.
+func (*ClearRateLimitRequest) Descriptor() ([]byte, []int) {
+	return file_app_policy_command_command_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ClearRateLimitRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type ClearRateLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearRateLimitResponse) Reset() {
+	*x = ClearRateLimitResponse{}
+	mi := &file_app_policy_command_command_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearRateLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearRateLimitResponse) ProtoMessage() {}
+
+func (x *ClearRateLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_policy_command_command_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearRateLimitResponse.ProtoReflect.Descriptor instead.
+func (*ClearRateLimitResponse) Descriptor() ([]byte, []int) {
+	return file_app_policy_command_command_proto_rawDescGZIP(), []int{4}
+}
+
+type GetRateLimitRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Email string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	// Level of the user, used when the user has no live connections.
+	Level         uint32 `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRateLimitRequest) Reset() {
+	*x = GetRateLimitRequest{}
+	mi := &file_app_policy_command_command_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRateLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateLimitRequest) ProtoMessage() {}
+
+func (x *GetRateLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_policy_command_command_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateLimitRequest.ProtoReflect.Descriptor instead.
+func (*GetRateLimitRequest) Descriptor() ([]byte, []int) {
+	return file_app_policy_command_command_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetRateLimitRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *GetRateLimitRequest) GetLevel() uint32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+type GetRateLimitResponse struct {
+	state     protoimpl.MessageState   `protogen:"open.v1"`
+	RateLimit *policy.Policy_RateLimit `protobuf:"bytes,1,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+	// Whether the limit is set through SetRateLimit.
+	Overridden    bool `protobuf:"varint,2,opt,name=overridden,proto3" json:"overridden,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRateLimitResponse) Reset() {
+	*x = GetRateLimitResponse{}
+	mi := &file_app_policy_command_command_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRateLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateLimitResponse) ProtoMessage() {}
+
+func (x *GetRateLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_policy_command_command_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateLimitResponse.ProtoReflect.Descriptor instead.
+func (*GetRateLimitResponse) Descriptor() ([]byte, []int) {
+	return file_app_policy_command_command_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetRateLimitResponse) GetRateLimit() *policy.Policy_RateLimit {
+	if x != nil {
+		return x.RateLimit
+	}
+	return nil
+}
+
+func (x *GetRateLimitResponse) GetOverridden() bool {
+	if x != nil {
+		return x.Overridden
+	}
+	return false
+}
+
+var File_app_policy_command_command_proto protoreflect.FileDescriptor
+
+const file_app_policy_command_command_proto_rawDesc = "" +
+	"\n" +
+	" app/policy/command/command.proto\x12\x17xray.app.policy.command\x1a\x17app/policy/config.proto\"\b\n" +
+	"\x06Config\"\x83\x01\n" +
+	"\x13SetRateLimitRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\rR\x05level\x12@\n" +
+	"\n" +
+	"rate_limit\x18\x03 \x01(\v2!.xray.app.policy.Policy.RateLimitR\trateLimit\"\x16\n" +
+	"\x14SetRateLimitResponse\"-\n" +
+	"\x15ClearRateLimitRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"\x18\n" +
+	"\x16ClearRateLimitResponse\"A\n" +
+	"\x13GetRateLimitRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\rR\x05level\"x\n" +
+	"\x14GetRateLimitResponse\x12@\n" +
+	"\n" +
+	"rate_limit\x18\x01 \x01(\v2!.xray.app.policy.Policy.RateLimitR\trateLimit\x12\x1e\n" +
+	"\n" +
+	"overridden\x18\x02 \x01(\bR\n" +
+	"overridden2\xe2\x02\n" +
+	"\rPolicyService\x12m\n" +
+	"\fSetRateLimit\x12,.xray.app.policy.command.SetRateLimitRequest\x1a-.xray.app.policy.command.SetRateLimitResponse\"\x00\x12s\n" +
+	"\x0eClearRateLimit\x12..xray.app.policy.command.ClearRateLimitRequest\x1a/.xray.app.policy.command.ClearRateLimitResponse\"\x00\x12m\n" +
+	"\fGetRateLimit\x12,.xray.app.policy.command.GetRateLimitRequest\x1a-.xray.app.policy.command.GetRateLimitResponse\"\x00Bg\n" +
+	"\x1bcom.xray.app.policy.commandP\x01Z,github.com/xtls/xray-core/app/policy/command\xaa\x02\x17Xray.App.Policy.Commandb\x06proto3"
+
+var (
+	file_app_policy_command_command_proto_rawDescOnce sync.Once
+	file_app_policy_command_command_proto_rawDescData []byte
+)
+
+func file_app_policy_command_command_proto_rawDescGZIP() []byte {
+	file_app_policy_command_command_proto_rawDescOnce.Do(func() {
+		file_app_policy_command_command_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_app_policy_command_command_proto_rawDesc), len(file_app_policy_command_command_proto_rawDesc)))
+	})
+	return file_app_policy_command_command_proto_rawDescData
+}
+
+var file_app_policy_command_command_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_app_policy_command_command_proto_goTypes = []any{
+	(*Config)(nil),                  // 0: xray.app.policy.command.Config
+	(*SetRateLimitRequest)(nil),     // 1: xray.app.policy.command.SetRateLimitRequest
+	(*SetRateLimitResponse)(nil),    // 2: xray.app.policy.command.SetRateLimitResponse
+	(*ClearRateLimitRequest)(nil),   // 3: xray.app.policy.command.ClearRateLimitRequest
+	(*ClearRateLimitResponse)(nil),  // 4: xray.app.policy.command.ClearRateLimitResponse
+	(*GetRateLimitRequest)(nil),     // 5: xray.app.policy.command.GetRateLimitRequest
+	(*GetRateLimitResponse)(nil),    // 6: xray.app.policy.command.GetRateLimitResponse
+	(*policy.Policy_RateLimit)(nil), // 7: xray.app.policy.Policy.RateLimit
+}
+var file_app_policy_command_command_proto_depIdxs = []int32{
+	7, // 0: xray.app.policy.command.SetRateLimitRequest.rate_limit:type_name -> xray.app.policy.Policy.RateLimit
+	7, // 1: xray.app.policy.command.GetRateLimitResponse.rate_limit:type_name -> xray.app.policy.Policy.RateLimit
+	1, // 2: xray.app.policy.command.PolicyService.SetRateLimit:input_type -> xray.app.policy.command.SetRateLimitRequest
+	3, // 3: xray.app.policy.command.PolicyService.ClearRateLimit:input_type -> xray.app.policy.command.ClearRateLimitRequest
+	5, // 4: xray.app.policy.command.PolicyService.GetRateLimit:input_type -> xray.app.policy.command.GetRateLimitRequest
+	2, // 5: xray.app.policy.command.PolicyService.SetRateLimit:output_type -> xray.app.policy.command.SetRateLimitResponse
+	4, // 6: xray.app.policy.command.PolicyService.ClearRateLimit:output_type -> xray.app.policy.command.ClearRateLimitResponse
+	6, // 7: xray.app.policy.command.PolicyService.GetRateLimit:output_type -> xray.app.policy.command.GetRateLimitResponse
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_app_policy_command_command_proto_init() }
+func file_app_policy_command_command_proto_init() {
+	if File_app_policy_command_command_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_policy_command_command_proto_rawDesc), len(file_app_policy_command_command_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_app_policy_command_command_proto_goTypes,
+		DependencyIndexes: file_app_policy_command_command_proto_depIdxs,
+		MessageInfos:      file_app_policy_command_command_proto_msgTypes,
+	}.Build()
+	File_app_policy_command_command_proto = out.File
+	file_app_policy_command_command_proto_goTypes = nil
+	file_app_policy_command_command_proto_depIdxs = nil
+}
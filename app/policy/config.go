@@ -58,6 +58,20 @@ func (p *Policy) overrideWith(another *Policy) {
 			Connection: another.Buffer.Connection,
 		}
 	}
+	if another.RateLimit != nil {
+		p.RateLimit = &Policy_RateLimit{
+			Uplink:        another.RateLimit.Uplink,
+			Downlink:      another.RateLimit.Downlink,
+			UplinkBurst:   another.RateLimit.UplinkBurst,
+			DownlinkBurst: another.RateLimit.DownlinkBurst,
+		}
+	}
+	if another.ConnectionLimit != nil {
+		p.ConnectionLimit = &Policy_ConnectionLimit{
+			MaxConnections: another.ConnectionLimit.MaxConnections,
+			MaxIps:         another.ConnectionLimit.MaxIps,
+		}
+	}
 }
 
 // ToCorePolicy converts this Policy to policy.Session.
@@ -78,9 +92,28 @@ func (p *Policy) ToCorePolicy() policy.Session {
 	if p.Buffer != nil {
 		cp.Buffer.PerConnection = p.Buffer.Connection
 	}
+	if p.RateLimit != nil {
+		cp.RateLimit = p.RateLimit.ToCorePolicy()
+	}
+	if p.ConnectionLimit != nil {
+		cp.ConnectionLimit = policy.ConnectionLimit{
+			MaxConnections: p.ConnectionLimit.MaxConnections,
+			MaxIPs:         p.ConnectionLimit.MaxIps,
+		}
+	}
 	return cp
 }
 
+// ToCorePolicy converts this RateLimit to policy.RateLimit.
+func (r *Policy_RateLimit) ToCorePolicy() policy.RateLimit {
+	return policy.RateLimit{
+		Uplink:        r.Uplink,
+		Downlink:      r.Downlink,
+		UplinkBurst:   r.UplinkBurst,
+		DownlinkBurst: r.DownlinkBurst,
+	}
+}
+
 // ToCorePolicy converts this SystemPolicy to policy.System.
 func (p *SystemPolicy) ToCorePolicy() policy.System {
 	return policy.System{
@@ -66,12 +66,14 @@ func (x *Second) GetValue() uint32 {
 }
 
 type Policy struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Timeout       *Policy_Timeout        `protobuf:"bytes,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
-	Stats         *Policy_Stats          `protobuf:"bytes,2,opt,name=stats,proto3" json:"stats,omitempty"`
-	Buffer        *Policy_Buffer         `protobuf:"bytes,3,opt,name=buffer,proto3" json:"buffer,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState  `protogen:"open.v1"`
+	Timeout         *Policy_Timeout         `protobuf:"bytes,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Stats           *Policy_Stats           `protobuf:"bytes,2,opt,name=stats,proto3" json:"stats,omitempty"`
+	Buffer          *Policy_Buffer          `protobuf:"bytes,3,opt,name=buffer,proto3" json:"buffer,omitempty"`
+	RateLimit       *Policy_RateLimit       `protobuf:"bytes,4,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+	ConnectionLimit *Policy_ConnectionLimit `protobuf:"bytes,5,opt,name=connection_limit,json=connectionLimit,proto3" json:"connection_limit,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *Policy) Reset() {
@@ -125,6 +127,20 @@ func (x *Policy) GetBuffer() *Policy_Buffer {
 	return nil
 }
 
+func (x *Policy) GetRateLimit() *Policy_RateLimit {
+	if x != nil {
+		return x.RateLimit
+	}
+	return nil
+}
+
+func (x *Policy) GetConnectionLimit() *Policy_ConnectionLimit {
+	if x != nil {
+		return x.ConnectionLimit
+	}
+	return nil
+}
+
 type SystemPolicy struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Stats         *SystemPolicy_Stats    `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
@@ -395,6 +411,136 @@ func (x *Policy_Buffer) GetConnection() int32 {
 	return 0
 }
 
+// RateLimit limits the throughput of each user, shared by all connections of
+// the user.
+type Policy_RateLimit struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Rate limits in bytes per second. 0 for unlimited.
+	Uplink   uint64 `protobuf:"varint,1,opt,name=uplink,proto3" json:"uplink,omitempty"`
+	Downlink uint64 `protobuf:"varint,2,opt,name=downlink,proto3" json:"downlink,omitempty"`
+	// Maximum bytes that can be transferred at once. 0 for the same as the
+	// rate.
+	UplinkBurst   uint64 `protobuf:"varint,3,opt,name=uplink_burst,json=uplinkBurst,proto3" json:"uplink_burst,omitempty"`
+	DownlinkBurst uint64 `protobuf:"varint,4,opt,name=downlink_burst,json=downlinkBurst,proto3" json:"downlink_burst,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Policy_RateLimit) Reset() {
+	*x = Policy_RateLimit{}
+	mi := &file_app_policy_config_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Policy_RateLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Policy_RateLimit) ProtoMessage() {}
+
+func (x *Policy_RateLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_app_policy_config_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Policy_RateLimit.ProtoReflect.Descriptor instead.
+func (*Policy_RateLimit) Descriptor() ([]byte, []int) {
+	return file_app_policy_config_proto_rawDescGZIP(), []int{1, 3}
+}
+
+func (x *Policy_RateLimit) GetUplink() uint64 {
+	if x != nil {
+		return x.Uplink
+	}
+	return 0
+}
+
+func (x *Policy_RateLimit) GetDownlink() uint64 {
+	if x != nil {
+		return x.Downlink
+	}
+	return 0
+}
+
+func (x *Policy_RateLimit) GetUplinkBurst() uint64 {
+	if x != nil {
+		return x.UplinkBurst
+	}
+	return 0
+}
+
+func (x *Policy_RateLimit) GetDownlinkBurst() uint64 {
+	if x != nil {
+		return x.DownlinkBurst
+	}
+	return 0
+}
+
+// ConnectionLimit limits the simultaneous usage of each user, counted over
+// all inbounds.
+type Policy_ConnectionLimit struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Maximum number of simultaneous connections. 0 for unlimited.
+	MaxConnections uint32 `protobuf:"varint,1,opt,name=max_connections,json=maxConnections,proto3" json:"max_connections,omitempty"`
+	// Maximum number of distinct source IPs of the live connections of the
+	// user. 0 for unlimited.
+	MaxIps        uint32 `protobuf:"varint,2,opt,name=max_ips,json=maxIps,proto3" json:"max_ips,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Policy_ConnectionLimit) Reset() {
+	*x = Policy_ConnectionLimit{}
+	mi := &file_app_policy_config_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Policy_ConnectionLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Policy_ConnectionLimit) ProtoMessage() {}
+
+func (x *Policy_ConnectionLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_app_policy_config_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Policy_ConnectionLimit.ProtoReflect.Descriptor instead.
+func (*Policy_ConnectionLimit) Descriptor() ([]byte, []int) {
+	return file_app_policy_config_proto_rawDescGZIP(), []int{1, 4}
+}
+
+func (x *Policy_ConnectionLimit) GetMaxConnections() uint32 {
+	if x != nil {
+		return x.MaxConnections
+	}
+	return 0
+}
+
+func (x *Policy_ConnectionLimit) GetMaxIps() uint32 {
+	if x != nil {
+		return x.MaxIps
+	}
+	return 0
+}
+
 type SystemPolicy_Stats struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	InboundUplink    bool                   `protobuf:"varint,1,opt,name=inbound_uplink,json=inboundUplink,proto3" json:"inbound_uplink,omitempty"`
@@ -407,7 +553,7 @@ type SystemPolicy_Stats struct {
 
 func (x *SystemPolicy_Stats) Reset() {
 	*x = SystemPolicy_Stats{}
-	mi := &file_app_policy_config_proto_msgTypes[7]
+	mi := &file_app_policy_config_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -419,7 +565,7 @@ func (x *SystemPolicy_Stats) String() string {
 func (*SystemPolicy_Stats) ProtoMessage() {}
 
 func (x *SystemPolicy_Stats) ProtoReflect() protoreflect.Message {
-	mi := &file_app_policy_config_proto_msgTypes[7]
+	mi := &file_app_policy_config_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -469,11 +615,14 @@ const file_app_policy_config_proto_rawDesc = "" +
 	"\n" +
 	"\x17app/policy/config.proto\x12\x0fxray.app.policy\"\x1e\n" +
 	"\x06Second\x12\x14\n" +
-	"\x05value\x18\x01 \x01(\rR\x05value\"\xc7\x04\n" +
+	"\x05value\x18\x01 \x01(\rR\x05value\"\xbe\a\n" +
 	"\x06Policy\x129\n" +
 	"\atimeout\x18\x01 \x01(\v2\x1f.xray.app.policy.Policy.TimeoutR\atimeout\x123\n" +
 	"\x05stats\x18\x02 \x01(\v2\x1d.xray.app.policy.Policy.StatsR\x05stats\x126\n" +
-	"\x06buffer\x18\x03 \x01(\v2\x1e.xray.app.policy.Policy.BufferR\x06buffer\x1a\xfa\x01\n" +
+	"\x06buffer\x18\x03 \x01(\v2\x1e.xray.app.policy.Policy.BufferR\x06buffer\x12@\n" +
+	"\n" +
+	"rate_limit\x18\x04 \x01(\v2!.xray.app.policy.Policy.RateLimitR\trateLimit\x12R\n" +
+	"\x10connection_limit\x18\x05 \x01(\v2'.xray.app.policy.Policy.ConnectionLimitR\x0fconnectionLimit\x1a\xfa\x01\n" +
 	"\aTimeout\x125\n" +
 	"\thandshake\x18\x01 \x01(\v2\x17.xray.app.policy.SecondR\thandshake\x12@\n" +
 	"\x0fconnection_idle\x18\x02 \x01(\v2\x17.xray.app.policy.SecondR\x0econnectionIdle\x128\n" +
@@ -489,7 +638,15 @@ const file_app_policy_config_proto_rawDesc = "" +
 	"\x06Buffer\x12\x1e\n" +
 	"\n" +
 	"connection\x18\x01 \x01(\x05R\n" +
-	"connection\"\xfb\x01\n" +
+	"connection\x1a\x89\x01\n" +
+	"\tRateLimit\x12\x16\n" +
+	"\x06uplink\x18\x01 \x01(\x04R\x06uplink\x12\x1a\n" +
+	"\bdownlink\x18\x02 \x01(\x04R\bdownlink\x12!\n" +
+	"\fuplink_burst\x18\x03 \x01(\x04R\vuplinkBurst\x12%\n" +
+	"\x0edownlink_burst\x18\x04 \x01(\x04R\rdownlinkBurst\x1aS\n" +
+	"\x0fConnectionLimit\x12'\n" +
+	"\x0fmax_connections\x18\x01 \x01(\rR\x0emaxConnections\x12\x17\n" +
+	"\amax_ips\x18\x02 \x01(\rR\x06maxIps\"\xfb\x01\n" +
 	"\fSystemPolicy\x129\n" +
 	"\x05stats\x18\x01 \x01(\v2#.xray.app.policy.SystemPolicy.StatsR\x05stats\x1a\xaf\x01\n" +
 	"\x05Stats\x12%\n" +
@@ -518,35 +675,39 @@ func file_app_policy_config_proto_rawDescGZIP() []byte {
 	return file_app_policy_config_proto_rawDescData
 }
 
-var file_app_policy_config_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_app_policy_config_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_app_policy_config_proto_goTypes = []any{
-	(*Second)(nil),             // 0: xray.app.policy.Second
-	(*Policy)(nil),             // 1: xray.app.policy.Policy
-	(*SystemPolicy)(nil),       // 2: xray.app.policy.SystemPolicy
-	(*Config)(nil),             // 3: xray.app.policy.Config
-	(*Policy_Timeout)(nil),     // 4: xray.app.policy.Policy.Timeout
-	(*Policy_Stats)(nil),       // 5: xray.app.policy.Policy.Stats
-	(*Policy_Buffer)(nil),      // 6: xray.app.policy.Policy.Buffer
-	(*SystemPolicy_Stats)(nil), // 7: xray.app.policy.SystemPolicy.Stats
-	nil,                        // 8: xray.app.policy.Config.LevelEntry
+	(*Second)(nil),                 // 0: xray.app.policy.Second
+	(*Policy)(nil),                 // 1: xray.app.policy.Policy
+	(*SystemPolicy)(nil),           // 2: xray.app.policy.SystemPolicy
+	(*Config)(nil),                 // 3: xray.app.policy.Config
+	(*Policy_Timeout)(nil),         // 4: xray.app.policy.Policy.Timeout
+	(*Policy_Stats)(nil),           // 5: xray.app.policy.Policy.Stats
+	(*Policy_Buffer)(nil),          // 6: xray.app.policy.Policy.Buffer
+	(*Policy_RateLimit)(nil),       // 7: xray.app.policy.Policy.RateLimit
+	(*Policy_ConnectionLimit)(nil), // 8: xray.app.policy.Policy.ConnectionLimit
+	(*SystemPolicy_Stats)(nil),     // 9: xray.app.policy.SystemPolicy.Stats
+	nil,                            // 10: xray.app.policy.Config.LevelEntry
 }
 var file_app_policy_config_proto_depIdxs = []int32{
 	4,  // 0: xray.app.policy.Policy.timeout:type_name -> xray.app.policy.Policy.Timeout
 	5,  // 1: xray.app.policy.Policy.stats:type_name -> xray.app.policy.Policy.Stats
 	6,  // 2: xray.app.policy.Policy.buffer:type_name -> xray.app.policy.Policy.Buffer
-	7,  // 3: xray.app.policy.SystemPolicy.stats:type_name -> xray.app.policy.SystemPolicy.Stats
-	8,  // 4: xray.app.policy.Config.level:type_name -> xray.app.policy.Config.LevelEntry
-	2,  // 5: xray.app.policy.Config.system:type_name -> xray.app.policy.SystemPolicy
-	0,  // 6: xray.app.policy.Policy.Timeout.handshake:type_name -> xray.app.policy.Second
-	0,  // 7: xray.app.policy.Policy.Timeout.connection_idle:type_name -> xray.app.policy.Second
-	0,  // 8: xray.app.policy.Policy.Timeout.uplink_only:type_name -> xray.app.policy.Second
-	0,  // 9: xray.app.policy.Policy.Timeout.downlink_only:type_name -> xray.app.policy.Second
-	1,  // 10: xray.app.policy.Config.LevelEntry.value:type_name -> xray.app.policy.Policy
-	11, // [11:11] is the sub-list for method output_type
-	11, // [11:11] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	7,  // 3: xray.app.policy.Policy.rate_limit:type_name -> xray.app.policy.Policy.RateLimit
+	8,  // 4: xray.app.policy.Policy.connection_limit:type_name -> xray.app.policy.Policy.ConnectionLimit
+	9,  // 5: xray.app.policy.SystemPolicy.stats:type_name -> xray.app.policy.SystemPolicy.Stats
+	10, // 6: xray.app.policy.Config.level:type_name -> xray.app.policy.Config.LevelEntry
+	2,  // 7: xray.app.policy.Config.system:type_name -> xray.app.policy.SystemPolicy
+	0,  // 8: xray.app.policy.Policy.Timeout.handshake:type_name -> xray.app.policy.Second
+	0,  // 9: xray.app.policy.Policy.Timeout.connection_idle:type_name -> xray.app.policy.Second
+	0,  // 10: xray.app.policy.Policy.Timeout.uplink_only:type_name -> xray.app.policy.Second
+	0,  // 11: xray.app.policy.Policy.Timeout.downlink_only:type_name -> xray.app.policy.Second
+	1,  // 12: xray.app.policy.Config.LevelEntry.value:type_name -> xray.app.policy.Policy
+	13, // [13:13] is the sub-list for method output_type
+	13, // [13:13] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_app_policy_config_proto_init() }
@@ -560,7 +721,7 @@ func file_app_policy_config_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_policy_config_proto_rawDesc), len(file_app_policy_config_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
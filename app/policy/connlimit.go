@@ -0,0 +1,94 @@
+package policy
+
+import (
+	"sync"
+
+	"github.com/xtls/xray-core/app/stats"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/features/policy"
+	feature_stats "github.com/xtls/xray-core/features/stats"
+)
+
+// userConns are the live connections of a user.
+type userConns struct {
+	total int
+	// ips are the numbers of live connections from each source IP.
+	ips map[string]int
+	// online is the online map of the user, which is only fed for display.
+	online feature_stats.OnlineMap
+}
+
+// connLimiters keeps the live connections of all users with connection limits, keyed by email.
+type connLimiters struct {
+	sync.Mutex
+	users map[string]*userConns
+	// stats is the manager of the online maps of users, or nil if there is none.
+	stats feature_stats.Manager
+}
+
+func newConnLimiters() *connLimiters {
+	return &connLimiters{
+		users: make(map[string]*userConns),
+	}
+}
+
+// onlineMap returns the online map of the user, which is shared with the stats of the user if
+// there are stats. Must be called with the limiters locked.
+func (l *connLimiters) onlineMap(email string) feature_stats.OnlineMap {
+	if l.stats != nil {
+		if om, _ := feature_stats.GetOrRegisterOnlineMap(l.stats, "user>>>"+email+">>>online"); om != nil {
+			return om
+		}
+	}
+	return stats.NewOnlineMap()
+}
+
+// AcquireConnection implements policy.UserConnectionLimiter.
+func (m *Instance) AcquireConnection(user *protocol.MemoryUser, source net.Address) (func(), error) {
+	limit := m.ForLevel(user.Level).ConnectionLimit
+	// Users without email can't be told apart, so they are not limited.
+	if user.Email == "" || (limit.MaxConnections == 0 && limit.MaxIPs == 0) {
+		return func() {}, nil
+	}
+	ip := ""
+	if source != nil {
+		ip = source.String()
+	}
+
+	m.conns.Lock()
+	defer m.conns.Unlock()
+
+	u, found := m.conns.users[user.Email]
+	if !found {
+		u = &userConns{
+			ips:    make(map[string]int),
+			online: m.conns.onlineMap(user.Email),
+		}
+	}
+	if limit.MaxConnections > 0 && u.total >= int(limit.MaxConnections) {
+		return nil, policy.ErrTooManyConnections
+	}
+	if limit.MaxIPs > 0 && len(u.ips) >= int(limit.MaxIPs) && u.ips[ip] == 0 {
+		return nil, policy.ErrTooManyIPs
+	}
+	u.total++
+	u.ips[ip]++
+	u.online.AddIP(ip)
+	m.conns.users[user.Email] = u
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.conns.Lock()
+			defer m.conns.Unlock()
+
+			if u.ips[ip]--; u.ips[ip] == 0 {
+				delete(u.ips, ip)
+			}
+			if u.total--; u.total == 0 {
+				delete(m.conns.users, user.Email)
+			}
+		})
+	}, nil
+}
@@ -0,0 +1,102 @@
+package policy_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/xtls/xray-core/app/policy"
+	"github.com/xtls/xray-core/app/stats"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/policy"
+	feature_stats "github.com/xtls/xray-core/features/stats"
+)
+
+func TestUserConnectionLimit(t *testing.T) {
+	manager, err := New(context.Background(), &Config{
+		Level: map[uint32]*Policy{
+			1: {
+				ConnectionLimit: &Policy_ConnectionLimit{
+					MaxConnections: 3,
+					MaxIps:         2,
+				},
+			},
+		},
+	})
+	common.Must(err)
+
+	ip1 := net.ParseAddress("1.1.1.1")
+	ip2 := net.ParseAddress("2.2.2.2")
+	ip3 := net.ParseAddress("3.3.3.3")
+
+	for i := 0; i < 5; i++ {
+		if _, err := policy.AcquireUserConnection(manager, &protocol.MemoryUser{Email: "free"}, ip1, ip1, ""); err != nil {
+			t.Fatal("expect no limit for user of level 0, but got ", err)
+		}
+	}
+
+	alice := &protocol.MemoryUser{Email: "alice", Level: 1}
+	release1, err := manager.AcquireConnection(alice, ip1)
+	common.Must(err)
+	_, err = manager.AcquireConnection(alice, ip2)
+	common.Must(err)
+	if _, err := manager.AcquireConnection(alice, ip3); err != policy.ErrTooManyIPs {
+		t.Error("expect too many IPs, but got ", err)
+	}
+	_, err = manager.AcquireConnection(alice, ip1)
+	common.Must(err)
+	if _, err := manager.AcquireConnection(alice, ip1); err != policy.ErrTooManyConnections {
+		t.Error("expect too many connections, but got ", err)
+	}
+
+	release1()
+	release1()
+	if _, err := manager.AcquireConnection(alice, ip3); err != policy.ErrTooManyIPs {
+		t.Error("expect too many IPs while ip1 still has a connection, but got ", err)
+	}
+	if _, err := manager.AcquireConnection(alice, ip2); err != nil {
+		t.Error("expect connection from known IP to be accepted after release, but got ", err)
+	}
+}
+
+func TestUserConnectionLimitWithStats(t *testing.T) {
+	instance, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&stats.Config{}),
+			serial.ToTypedMessage(&Config{
+				Level: map[uint32]*Policy{
+					0: {ConnectionLimit: &Policy_ConnectionLimit{MaxIps: 1}},
+				},
+			}),
+		},
+	})
+	common.Must(err)
+	manager := instance.GetFeature(policy.ManagerType()).(policy.UserConnectionLimiter)
+
+	alice := &protocol.MemoryUser{Email: "alice"}
+	ip1 := net.ParseAddress("1.1.1.1")
+	ip2 := net.ParseAddress("2.2.2.2")
+	release1, err := manager.AcquireConnection(alice, ip1)
+	common.Must(err)
+	release2, err := manager.AcquireConnection(alice, ip1)
+	common.Must(err)
+	if _, err := manager.AcquireConnection(alice, ip2); err != policy.ErrTooManyIPs {
+		t.Error("expect too many IPs, but got ", err)
+	}
+
+	release1()
+	release2()
+	release, err := manager.AcquireConnection(alice, ip2)
+	if err != nil {
+		t.Fatal("expect connection from new IP accepted after all connections from the old one are closed, but got ", err)
+	}
+	release()
+
+	sm := instance.GetFeature(feature_stats.ManagerType()).(feature_stats.Manager)
+	if om := sm.GetOnlineMap("user>>>alice>>>online"); om == nil || om.Count() == 0 {
+		t.Error("expect IPs of the user in the online map")
+	}
+}
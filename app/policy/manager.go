@@ -2,28 +2,59 @@ package policy
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 
 	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/core"
 	"github.com/xtls/xray-core/features/policy"
+	"github.com/xtls/xray-core/features/stats"
 )
 
-// Instance is an instance of Policy manager.
-type Instance struct {
+// policies are the level and system policies of an Instance. They are never modified, but replaced as a whole.
+type policies struct {
 	levels map[uint32]*Policy
 	system *SystemPolicy
 }
 
+func newPolicies(config *Config) *policies {
+	p := &policies{
+		levels: make(map[uint32]*Policy),
+		system: config.System,
+	}
+	for lv, lp := range config.Level {
+		pp := defaultPolicy()
+		pp.overrideWith(lp)
+		p.levels[lv] = pp
+	}
+	return p
+}
+
+// Instance is an instance of Policy manager.
+type Instance struct {
+	// access serializes the updates of policies.
+	access   sync.Mutex
+	policies atomic.Pointer[policies]
+	limiters *rateLimiters
+	conns    *connLimiters
+}
+
 // New creates new Policy manager instance.
 func New(ctx context.Context, config *Config) (*Instance, error) {
 	m := &Instance{
-		levels: make(map[uint32]*Policy),
-		system: config.System,
+		limiters: newRateLimiters(),
+		conns:    newConnLimiters(),
 	}
-	if len(config.Level) > 0 {
-		for lv, p := range config.Level {
-			pp := defaultPolicy()
-			pp.overrideWith(p)
-			m.levels[lv] = pp
+	m.policies.Store(newPolicies(config))
+
+	if v := core.FromContext(ctx); v != nil {
+		if err := v.RequireFeatures(func(sm stats.Manager) {
+			m.conns.Lock()
+			m.conns.stats = sm
+			m.conns.Unlock()
+		}, true); err != nil {
+			return nil, err
 		}
 	}
 
@@ -37,7 +68,7 @@ func (*Instance) Type() interface{} {
 
 // ForLevel implements policy.Manager.
 func (m *Instance) ForLevel(level uint32) policy.Session {
-	if p, ok := m.levels[level]; ok {
+	if p, ok := m.policies.Load().levels[level]; ok {
 		return p.ToCorePolicy()
 	}
 	return policy.SessionDefault()
@@ -45,10 +76,28 @@ func (m *Instance) ForLevel(level uint32) policy.Session {
 
 // ForSystem implements policy.Manager.
 func (m *Instance) ForSystem() policy.System {
-	if m.system == nil {
+	system := m.policies.Load().system
+	if system == nil {
 		return policy.System{}
 	}
-	return m.system.ToCorePolicy()
+	return system.ToCorePolicy()
+}
+
+// PrepareReload implements features.Reloadable.
+func (m *Instance) PrepareReload(config interface{}) (func(), func(), error) {
+	c, ok := config.(*Config)
+	if !ok {
+		return nil, nil, errors.New("Reload: config type error")
+	}
+	p := newPolicies(c)
+	commit := func() {
+		m.access.Lock()
+		defer m.access.Unlock()
+
+		m.policies.Store(p)
+		m.refreshLimiters()
+	}
+	return commit, func() {}, nil
 }
 
 // Start implements common.Runnable.Start().
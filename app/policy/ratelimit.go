@@ -0,0 +1,189 @@
+package policy
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/features/policy"
+	"golang.org/x/time/rate"
+	"google.golang.org/protobuf/proto"
+)
+
+// userLimiter holds the token buckets shared by all connections of a user.
+type userLimiter struct {
+	level            uint32
+	uplinkOverride   uint64
+	downlinkOverride uint64
+	uplink           *rate.Limiter
+	downlink         *rate.Limiter
+	// conns is the number of live connections using the token buckets.
+	conns int
+}
+
+// rateLimiters keeps the token buckets of the users with live connections, keyed by email.
+type rateLimiters struct {
+	sync.Mutex
+	users map[string]*userLimiter
+	// overrides are rate limits set through the API, which take precedence over the config.
+	overrides map[string]policy.RateLimit
+}
+
+func newRateLimiters() *rateLimiters {
+	return &rateLimiters{
+		users:     make(map[string]*userLimiter),
+		overrides: make(map[string]policy.RateLimit),
+	}
+}
+
+func applyRateLimit(l *rate.Limiter, limit uint64, burst uint64) {
+	if limit == 0 {
+		l.SetLimit(rate.Inf)
+		return
+	}
+	if burst == 0 {
+		burst = limit
+	}
+	l.SetLimit(rate.Limit(limit))
+	l.SetBurst(int(burst))
+}
+
+// rateLimitFor returns the effective rate limit of the given user. Must be called with m.limiters locked.
+func (m *Instance) rateLimitFor(email string, u *userLimiter) policy.RateLimit {
+	if limit, found := m.limiters.overrides[email]; found && email != "" {
+		return limit
+	}
+	limit := m.ForLevel(u.level).RateLimit
+	if u.uplinkOverride > 0 {
+		limit.Uplink = u.uplinkOverride
+	}
+	if u.downlinkOverride > 0 {
+		limit.Downlink = u.downlinkOverride
+	}
+	return limit
+}
+
+// apply updates the token buckets of u. Must be called with m.limiters locked.
+func (m *Instance) apply(email string, u *userLimiter) policy.RateLimit {
+	limit := m.rateLimitFor(email, u)
+	applyRateLimit(u.uplink, limit.Uplink, limit.UplinkBurst)
+	applyRateLimit(u.downlink, limit.Downlink, limit.DownlinkBurst)
+	return limit
+}
+
+// refreshLimiters applies the current rate limits to the token buckets of all known users.
+func (m *Instance) refreshLimiters() {
+	m.limiters.Lock()
+	defer m.limiters.Unlock()
+
+	for email, u := range m.limiters.users {
+		m.apply(email, u)
+	}
+}
+
+// LimitersForUser implements policy.UserRateLimiter.
+func (m *Instance) LimitersForUser(user *protocol.MemoryUser) (*rate.Limiter, *rate.Limiter, func()) {
+	m.limiters.Lock()
+	defer m.limiters.Unlock()
+
+	u, found := m.limiters.users[user.Email]
+	if !found {
+		u = &userLimiter{
+			uplink:   rate.NewLimiter(rate.Inf, 0),
+			downlink: rate.NewLimiter(rate.Inf, 0),
+		}
+	}
+	u.level = user.Level
+	u.uplinkOverride = user.UplinkRateLimit
+	u.downlinkOverride = user.DownlinkRateLimit
+	limit := m.apply(user.Email, u)
+
+	// Limiters of users with email are kept even if they are unlimited, so that limits set later
+	// through the API apply to their live connections. Users without email can't be told apart,
+	// so each of their connections is limited on its own.
+	if user.Email != "" {
+		u.conns++
+		m.limiters.users[user.Email] = u
+		var once sync.Once
+		return u.uplink, u.downlink, func() {
+			once.Do(func() {
+				m.limiters.Lock()
+				defer m.limiters.Unlock()
+
+				// The buckets are dropped with the last connection, and the next connection gets full ones.
+				if u.conns--; u.conns == 0 {
+					delete(m.limiters.users, user.Email)
+				}
+			})
+		}
+	}
+
+	var uplink, downlink *rate.Limiter
+	if limit.Uplink > 0 {
+		uplink = u.uplink
+	}
+	if limit.Downlink > 0 {
+		downlink = u.downlink
+	}
+	return uplink, downlink, func() {}
+}
+
+// SetUserRateLimit overrides the rate limit of the user with the given email, including its live connections.
+func (m *Instance) SetUserRateLimit(email string, limit policy.RateLimit) {
+	m.limiters.Lock()
+	defer m.limiters.Unlock()
+
+	m.limiters.overrides[email] = limit
+	if u, found := m.limiters.users[email]; found {
+		m.apply(email, u)
+	}
+}
+
+// ClearUserRateLimit removes the override set by SetUserRateLimit.
+func (m *Instance) ClearUserRateLimit(email string) {
+	m.limiters.Lock()
+	defer m.limiters.Unlock()
+
+	delete(m.limiters.overrides, email)
+	if u, found := m.limiters.users[email]; found {
+		m.apply(email, u)
+	}
+}
+
+// UserRateLimit returns the effective rate limit of the user with the given email,
+// and whether it is overridden through SetUserRateLimit.
+func (m *Instance) UserRateLimit(email string, level uint32) (policy.RateLimit, bool) {
+	m.limiters.Lock()
+	defer m.limiters.Unlock()
+
+	if limit, found := m.limiters.overrides[email]; found {
+		return limit, true
+	}
+	u, found := m.limiters.users[email]
+	if !found {
+		u = &userLimiter{level: level}
+	}
+	return m.rateLimitFor(email, u), false
+}
+
+// SetLevelRateLimit changes the rate limit of the given level, including live connections of its users.
+func (m *Instance) SetLevelRateLimit(level uint32, limit policy.RateLimit) {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	old := m.policies.Load()
+	levels := maps.Clone(old.levels)
+	p := defaultPolicy()
+	if old, found := levels[level]; found {
+		p = proto.Clone(old).(*Policy)
+	}
+	p.RateLimit = &Policy_RateLimit{
+		Uplink:        limit.Uplink,
+		Downlink:      limit.Downlink,
+		UplinkBurst:   limit.UplinkBurst,
+		DownlinkBurst: limit.DownlinkBurst,
+	}
+	levels[level] = p
+	m.policies.Store(&policies{levels: levels, system: old.system})
+	m.refreshLimiters()
+}
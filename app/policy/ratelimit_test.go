@@ -0,0 +1,88 @@
+package policy_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/xtls/xray-core/app/policy"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/features/policy"
+	"golang.org/x/time/rate"
+)
+
+func TestUserRateLimit(t *testing.T) {
+	manager, err := New(context.Background(), &Config{
+		Level: map[uint32]*Policy{
+			1: {
+				RateLimit: &Policy_RateLimit{
+					Uplink:   1000,
+					Downlink: 2000,
+				},
+			},
+		},
+	})
+	common.Must(err)
+
+	{
+		up, down, _ := manager.LimitersForUser(&protocol.MemoryUser{Level: 0})
+		if up != nil || down != nil {
+			t.Error("expect no limiters for unlimited user without email")
+		}
+	}
+
+	free := &protocol.MemoryUser{Email: "free", Level: 0}
+	freeUp, freeDown, _ := manager.LimitersForUser(free)
+	if freeUp == nil || freeUp.Limit() != rate.Inf || freeDown == nil || freeDown.Limit() != rate.Inf {
+		t.Fatal("expect unlimited limiters for unlimited user with email, got ", freeUp, freeDown)
+	}
+	manager.SetUserRateLimit("free", policy.RateLimit{Downlink: 50})
+	if freeDown.Limit() != 50 {
+		t.Error("override not applied to live limiters of unlimited user: ", freeDown.Limit())
+	}
+
+	alice := &protocol.MemoryUser{Email: "alice", Level: 1}
+	up, down, release := manager.LimitersForUser(alice)
+	if up == nil || up.Limit() != 1000 || down == nil || down.Limit() != 2000 {
+		t.Fatal("unexpected limiters ", up, down)
+	}
+	up2, down2, release2 := manager.LimitersForUser(alice)
+	if up2 != up || down2 != down {
+		t.Error("expect connections of the same user to share limiters")
+	}
+
+	manager.SetUserRateLimit("alice", policy.RateLimit{Uplink: 10})
+	if up.Limit() != 10 || down.Limit() != rate.Inf {
+		t.Error("override not applied to live limiters: ", up.Limit(), down.Limit())
+	}
+	if limit, overridden := manager.UserRateLimit("alice", 1); !overridden || limit.Uplink != 10 {
+		t.Error("unexpected rate limit ", limit, overridden)
+	}
+
+	manager.ClearUserRateLimit("alice")
+	if up.Limit() != 1000 {
+		t.Error("expect config limit after clearing override, got ", up.Limit())
+	}
+
+	manager.SetLevelRateLimit(1, policy.RateLimit{Uplink: 500, Downlink: 500})
+	if up.Limit() != 500 || down.Limit() != 500 {
+		t.Error("level limit not applied to live limiters: ", up.Limit(), down.Limit())
+	}
+
+	bob := &protocol.MemoryUser{Email: "bob", Level: 1, DownlinkRateLimit: 100}
+	if _, down, _ := manager.LimitersForUser(bob); down == nil || down.Limit() != 100 {
+		t.Error("expect per-user limit to take precedence over level")
+	}
+
+	release()
+	release()
+	up3, _, release3 := manager.LimitersForUser(alice)
+	release3()
+	if up3 != up {
+		t.Error("expect limiters kept while the user has connections")
+	}
+	release2()
+	if up4, _, _ := manager.LimitersForUser(alice); up4 == up {
+		t.Error("expect limiters dropped after all connections of the user are closed")
+	}
+}
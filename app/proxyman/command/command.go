@@ -34,8 +34,19 @@ func getInbound(handler inbound.Handler) (proxy.Inbound, error) {
 	return gi.GetInbound(), nil
 }
 
+// checkUsersFile returns an error if the users of the handler are loaded from a users file.
+func checkUsersFile(handler inbound.Handler) error {
+	if f, ok := handler.(proxy.GetUsersFile); ok && f.GetUsersFile() != "" {
+		return errors.New("users of inbound ", handler.Tag(), " are managed by users file ", f.GetUsersFile(), ", change the file instead")
+	}
+	return nil
+}
+
 // ApplyInbound implements InboundOperation.
 func (op *AddUserOperation) ApplyInbound(ctx context.Context, handler inbound.Handler) error {
+	if err := checkUsersFile(handler); err != nil {
+		return err
+	}
 	p, err := getInbound(handler)
 	if err != nil {
 		return err
@@ -53,6 +64,9 @@ func (op *AddUserOperation) ApplyInbound(ctx context.Context, handler inbound.Ha
 
 // ApplyInbound implements InboundOperation.
 func (op *RemoveUserOperation) ApplyInbound(ctx context.Context, handler inbound.Handler) error {
+	if err := checkUsersFile(handler); err != nil {
+		return err
+	}
 	p, err := getInbound(handler)
 	if err != nil {
 		return err
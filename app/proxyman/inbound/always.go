@@ -11,6 +11,7 @@ import (
 	"github.com/xtls/xray-core/common/serial"
 	"github.com/xtls/xray-core/common/session"
 	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/ban"
 	"github.com/xtls/xray-core/features/policy"
 	"github.com/xtls/xray-core/features/stats"
 	"github.com/xtls/xray-core/proxy"
@@ -50,6 +51,7 @@ type AlwaysOnInboundHandler struct {
 	workers        []worker
 	mux            *mux.Server
 	tag            string
+	usersFile      *usersFile
 }
 
 func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *proxyman.ReceiverConfig, proxyConfig interface{}) (*AlwaysOnInboundHandler, error) {
@@ -84,7 +86,19 @@ func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *
 		tag:            tag,
 	}
 
+	if receiverConfig.UsersFile != nil {
+		f, err := newUsersFile(ctx, receiverConfig.UsersFile, p)
+		if err != nil {
+			return nil, err
+		}
+		h.usersFile = f
+	}
+
 	uplinkCounter, downlinkCounter := getStatCounter(core.MustFromContext(ctx), tag)
+	bans, _ := core.MustFromContext(ctx).GetFeature(ban.ManagerType()).(ban.Manager)
+	if bans != nil {
+		ctx = ban.ContextWithManager(ctx, bans)
+	}
 
 	nl := p.Network()
 	pl := receiverConfig.PortList
@@ -142,6 +156,7 @@ func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *
 						sniffingConfig:  receiverConfig.SniffingSettings,
 						uplinkCounter:   uplinkCounter,
 						downlinkCounter: downlinkCounter,
+						bans:            bans,
 						ctx:             ctx,
 					}
 					h.workers = append(h.workers, worker)
@@ -157,6 +172,7 @@ func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *
 						sniffingConfig:  receiverConfig.SniffingSettings,
 						uplinkCounter:   uplinkCounter,
 						downlinkCounter: downlinkCounter,
+						bans:            bans,
 						stream:          mss,
 						ctx:             ctx,
 					}
@@ -176,6 +192,9 @@ func (h *AlwaysOnInboundHandler) Start() error {
 			return err
 		}
 	}
+	if h.usersFile != nil {
+		return h.usersFile.Start()
+	}
 	return nil
 }
 
@@ -186,6 +205,9 @@ func (h *AlwaysOnInboundHandler) Close() error {
 		errs = append(errs, worker.Close())
 	}
 	errs = append(errs, h.mux.Close())
+	if h.usersFile != nil {
+		errs = append(errs, h.usersFile.Close())
+	}
 	if err := errors.Combine(errs...); err != nil {
 		return errors.New("failed to close all resources").Base(err)
 	}
@@ -200,6 +222,14 @@ func (h *AlwaysOnInboundHandler) GetInbound() proxy.Inbound {
 	return h.proxy
 }
 
+// GetUsersFile implements proxy.GetUsersFile.
+func (h *AlwaysOnInboundHandler) GetUsersFile() string {
+	if h.usersFile == nil {
+		return ""
+	}
+	return h.usersFile.path
+}
+
 // ReceiverSettings implements inbound.Handler.
 func (h *AlwaysOnInboundHandler) ReceiverSettings() *serial.TypedMessage {
 	return serial.ToTypedMessage(h.receiverConfig)
@@ -0,0 +1,258 @@
+package inbound
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xtls/xray-core/app/proxyman"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/common/task"
+	"github.com/xtls/xray-core/proxy"
+)
+
+// fileUser is a user in a users file.
+type fileUser struct {
+	Email    string `json:"email"`
+	ID       string `json:"id"`
+	Password string `json:"password"`
+	Level    uint32 `json:"level"`
+	Flow     string `json:"flow"`
+}
+
+// usersFile keeps the users of an inbound in sync with a users file. The file is checked
+// periodically, and the users added, changed or removed in it are added to or removed from
+// the inbound, like by the API.
+//
+// The file is the only source of truth of the users, so the API rejects adding or removing
+// users of the inbound, whose changes would be lost on restart.
+type usersFile struct {
+	ctx     context.Context
+	path    string
+	manager proxy.UserManager
+	creator proxy.AccountCreator
+
+	// users are the users of the file added to the inbound, by their emails.
+	users   map[string]fileUser
+	modTime time.Time
+	size    int64
+
+	periodic *task.Periodic
+}
+
+// newUsersFile loads the users file to the inbound.
+func newUsersFile(ctx context.Context, config *proxyman.UsersFile, p proxy.Inbound) (*usersFile, error) {
+	manager, ok := p.(proxy.UserManager)
+	if !ok {
+		return nil, errors.New("inbound doesn't support users file")
+	}
+	creator, ok := p.(proxy.AccountCreator)
+	if !ok {
+		return nil, errors.New("inbound doesn't support users file")
+	}
+	f := &usersFile{
+		ctx:     ctx,
+		path:    config.Path,
+		manager: manager,
+		creator: creator,
+		users:   make(map[string]fileUser),
+	}
+	if _, err := f.reload(); err != nil {
+		return nil, errors.New("failed to load users file ", f.path).Base(err)
+	}
+
+	interval := 10 * time.Second
+	if config.Interval > 0 {
+		interval = time.Duration(config.Interval)
+	}
+	f.periodic = &task.Periodic{
+		Interval: interval,
+		Execute: func() error {
+			if reloaded, err := f.reload(); err != nil {
+				errors.LogWarningInner(ctx, err, "failed to reload users file ", f.path)
+			} else if reloaded {
+				errors.LogInfo(ctx, "users file ", f.path, " reloaded")
+			}
+			return nil
+		},
+	}
+	return f, nil
+}
+
+// Start implements common.Runnable.
+func (f *usersFile) Start() error {
+	return f.periodic.Start()
+}
+
+// Close implements common.Closable.
+func (f *usersFile) Close() error {
+	return f.periodic.Close()
+}
+
+// reload applies the changes of the users file to the inbound if the file has changed. The
+// file is rejected as a whole if any of its users is invalid. If some of the changes fail to
+// be applied, the others are kept, and the file is applied again on the next reload.
+func (f *usersFile) reload() (bool, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return false, err
+	}
+	if info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return false, nil
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return false, err
+	}
+	var users []fileUser
+	if strings.HasSuffix(strings.ToLower(f.path), ".csv") {
+		users, err = parseCSVUsers(data)
+	} else {
+		err = json.Unmarshal(data, &users)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	updated := make(map[string]*protocol.MemoryUser)
+	emails := make(map[string]bool, len(users))
+	for _, u := range users {
+		if u.Email == "" {
+			return false, errors.New("user without email")
+		}
+		if emails[u.Email] {
+			return false, errors.New("duplicated user ", u.Email)
+		}
+		emails[u.Email] = true
+		if old, found := f.users[u.Email]; found && old == u {
+			continue
+		}
+		account, err := f.creator.CreateAccount(u.ID, u.Password, u.Flow)
+		if err != nil {
+			return false, errors.New("invalid user ", u.Email).Base(err)
+		}
+		updated[u.Email] = &protocol.MemoryUser{
+			Account: account,
+			Email:   u.Email,
+			Level:   u.Level,
+		}
+	}
+
+	for email := range f.users {
+		if !emails[email] {
+			if err := f.manager.RemoveUser(f.ctx, email); err != nil {
+				errors.LogWarningInner(f.ctx, err, "failed to remove user ", email, " of users file")
+			}
+			delete(f.users, email)
+		}
+	}
+	var errs []error
+	for _, u := range users {
+		mUser, found := updated[u.Email]
+		if !found {
+			continue
+		}
+		old, changed := f.users[u.Email]
+		if changed {
+			if err := f.manager.RemoveUser(f.ctx, u.Email); err != nil {
+				errors.LogWarningInner(f.ctx, err, "failed to remove user ", u.Email, " of users file")
+			}
+		}
+		if err := f.manager.AddUser(f.ctx, mUser); err != nil {
+			errs = append(errs, errors.New("failed to add user ", u.Email).Base(err))
+			// The previous user is kept until the change can be applied.
+			if changed && f.restore(old) != nil {
+				delete(f.users, u.Email)
+			}
+			continue
+		}
+		f.users[u.Email] = u
+	}
+	if len(errs) > 0 {
+		// The file is applied again on the next check, even if it doesn't change.
+		return false, errors.Combine(errs...)
+	}
+
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	return true, nil
+}
+
+// restore adds back a user of the file whose change failed.
+func (f *usersFile) restore(u fileUser) error {
+	account, err := f.creator.CreateAccount(u.ID, u.Password, u.Flow)
+	if err == nil {
+		err = f.manager.AddUser(f.ctx, &protocol.MemoryUser{
+			Account: account,
+			Email:   u.Email,
+			Level:   u.Level,
+		})
+	}
+	if err != nil {
+		errors.LogWarningInner(f.ctx, err, "failed to restore user ", u.Email, " of users file")
+	}
+	return err
+}
+
+// parseCSVUsers parses users in CSV, whose header names the columns of email, id, password, level and flow.
+func parseCSVUsers(data []byte) ([]fileUser, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.TrimLeadingSpace = true
+	r.Comment = '#'
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+		switch header[i] {
+		case "email", "id", "password", "level", "flow":
+		default:
+			return nil, errors.New("unknown column ", name)
+		}
+	}
+
+	var users []fileUser
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return users, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var u fileUser
+		for i, value := range record {
+			switch header[i] {
+			case "email":
+				u.Email = value
+			case "id":
+				u.ID = value
+			case "password":
+				u.Password = value
+			case "level":
+				if value == "" {
+					continue
+				}
+				level, err := strconv.ParseUint(value, 10, 32)
+				if err != nil {
+					return nil, errors.New("invalid level ", value).Base(err)
+				}
+				u.Level = uint32(level)
+			case "flow":
+				u.Flow = value
+			}
+		}
+		users = append(users, u)
+	}
+}
@@ -0,0 +1,200 @@
+package inbound
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/app/policy"
+	"github.com/xtls/xray-core/app/proxyman"
+	"github.com/xtls/xray-core/app/proxyman/command"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/inbound"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/proxy"
+	"github.com/xtls/xray-core/proxy/trojan"
+	"github.com/xtls/xray-core/transport/internet/stat"
+)
+
+type testInbound struct {
+	users map[string]*protocol.MemoryUser
+	// rejected is the password of users failing to be added.
+	rejected string
+}
+
+func (*testInbound) Network() []net.Network {
+	return []net.Network{net.Network_TCP}
+}
+
+func (*testInbound) Process(context.Context, net.Network, stat.Connection, routing.Dispatcher) error {
+	return nil
+}
+
+func (*testInbound) CreateAccount(id, password, flow string) (protocol.Account, error) {
+	if password == "" {
+		return nil, errors.New("password is not specified")
+	}
+	return (&trojan.Account{Password: password}).AsAccount()
+}
+
+func (i *testInbound) AddUser(ctx context.Context, u *protocol.MemoryUser) error {
+	if _, found := i.users[u.Email]; found {
+		return errors.New("user ", u.Email, " already exists")
+	}
+	if i.rejected != "" && u.Account.(*trojan.MemoryAccount).Password == i.rejected {
+		return errors.New("user ", u.Email, " rejected")
+	}
+	i.users[u.Email] = u
+	return nil
+}
+
+func (i *testInbound) RemoveUser(ctx context.Context, email string) error {
+	delete(i.users, email)
+	return nil
+}
+
+func (i *testInbound) GetUser(ctx context.Context, email string) *protocol.MemoryUser {
+	return i.users[email]
+}
+
+func (i *testInbound) GetUsers(context.Context) []*protocol.MemoryUser {
+	return nil
+}
+
+func (i *testInbound) GetUsersCount(context.Context) int64 {
+	return int64(len(i.users))
+}
+
+func TestUsersFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.csv")
+	modTime := time.Now()
+	write := func(content string) {
+		common.Must(os.WriteFile(path, []byte(content), 0o600))
+		// Make sure the change is noticed on file systems with coarse modification times.
+		modTime = modTime.Add(time.Second)
+		common.Must(os.Chtimes(path, modTime, modTime))
+	}
+	password := func(p *testInbound, email string) string {
+		if u := p.users[email]; u != nil {
+			return u.Account.(*trojan.MemoryAccount).Password
+		}
+		return ""
+	}
+
+	write("email,password,level\nalice,a,1\nbob,b,0\n")
+	p := &testInbound{users: map[string]*protocol.MemoryUser{"carol": {Email: "carol"}}}
+	f, err := newUsersFile(context.Background(), &proxyman.UsersFile{Path: path}, p)
+	common.Must(err)
+	if len(p.users) != 3 || password(p, "alice") != "a" || p.users["alice"].Level != 1 {
+		t.Fatal("unexpected users ", p.users)
+	}
+	alice := p.users["alice"]
+
+	write("email,password,level\nalice,a,1\nbob,b2,0\ndave,d,2\n")
+	if reloaded, err := f.reload(); !reloaded || err != nil {
+		t.Fatal("failed to reload ", err)
+	}
+	if p.users["alice"] != alice {
+		t.Error("expect unchanged user kept")
+	}
+	if password(p, "bob") != "b2" || password(p, "dave") != "d" || len(p.users) != 4 {
+		t.Error("unexpected users ", p.users)
+	}
+
+	write("email,password\nalice,a\nbob,\n")
+	if _, err := f.reload(); err == nil {
+		t.Error("expect error for invalid user")
+	}
+	if len(p.users) != 4 {
+		t.Error("expect users kept after invalid file")
+	}
+
+	write("email,password,level\nalice,a,1\ncarol,c,0\n")
+	if _, err := f.reload(); err == nil {
+		t.Error("expect error for user existing in inbound")
+	}
+	if p.users["bob"] != nil || p.users["dave"] != nil {
+		t.Error("expect removed users removed")
+	}
+	if p.users["carol"].Account != nil {
+		t.Error("expect user not of the file untouched")
+	}
+
+	delete(p.users, "carol")
+	if reloaded, err := f.reload(); !reloaded || err != nil {
+		t.Fatal("expect failed file applied again, but got ", err)
+	}
+	if password(p, "carol") != "c" {
+		t.Error("unexpected users ", p.users)
+	}
+	if reloaded, _ := f.reload(); reloaded {
+		t.Error("expect unchanged file not reloaded")
+	}
+
+	p.rejected = "a2"
+	write("email,password,level\nalice,a2,1\ncarol,c,0\n")
+	if _, err := f.reload(); err == nil {
+		t.Error("expect error for user failing to be added")
+	}
+	if password(p, "alice") != "a" {
+		t.Error("expect previous user kept, but got ", p.users["alice"])
+	}
+	p.rejected = ""
+	common.Must2(f.reload())
+	if password(p, "alice") != "a2" {
+		t.Error("expect change applied on next reload, but got ", p.users["alice"])
+	}
+
+	jsonPath := filepath.Join(t.TempDir(), "users.json")
+	common.Must(os.WriteFile(jsonPath, []byte(`[{"email": "erin", "password": "e", "level": 3}]`), 0o600))
+	p = &testInbound{users: map[string]*protocol.MemoryUser{}}
+	common.Must2(newUsersFile(context.Background(), &proxyman.UsersFile{Path: jsonPath}, p))
+	if password(p, "erin") != "e" || p.users["erin"].Level != 3 {
+		t.Error("unexpected users ", p.users)
+	}
+}
+
+func TestUsersFileOfAddedInbound(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.csv")
+	common.Must(os.WriteFile(path, []byte("email,password\nalice,a\n"), 0o600))
+
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&policy.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+		},
+	})
+	common.Must(err)
+	// Inbounds added by the API are created like the ones in the config, and on reload.
+	common.Must(core.AddInboundHandler(v, &core.InboundHandlerConfig{
+		Tag:              "in",
+		ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{UsersFile: &proxyman.UsersFile{Path: path}}),
+		ProxySettings:    serial.ToTypedMessage(&trojan.ServerConfig{}),
+	}))
+
+	h, err := v.GetFeature(inbound.ManagerType()).(inbound.Manager).GetHandler(context.Background(), "in")
+	common.Must(err)
+	manager := h.(proxy.GetInbound).GetInbound().(proxy.UserManager)
+	if u := manager.GetUser(context.Background(), "alice"); u == nil || u.Account.(*trojan.MemoryAccount).Password != "a" {
+		t.Error("expect users of the file added, but got ", u)
+	}
+
+	add := &command.AddUserOperation{User: &protocol.User{
+		Email:   "bob",
+		Account: serial.ToTypedMessage(&trojan.Account{Password: "b"}),
+	}}
+	if err := add.ApplyInbound(context.Background(), h); err == nil || manager.GetUser(context.Background(), "bob") != nil {
+		t.Error("expect adding users by the API rejected")
+	}
+	remove := &command.RemoveUserOperation{Email: "alice"}
+	if err := remove.ApplyInbound(context.Background(), h); err == nil || manager.GetUser(context.Background(), "alice") == nil {
+		t.Error("expect removing users by the API rejected")
+	}
+}
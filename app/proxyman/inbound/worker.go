@@ -16,6 +16,7 @@ import (
 	"github.com/xtls/xray-core/common/session"
 	"github.com/xtls/xray-core/common/signal/done"
 	"github.com/xtls/xray-core/common/task"
+	"github.com/xtls/xray-core/features/ban"
 	"github.com/xtls/xray-core/features/routing"
 	"github.com/xtls/xray-core/features/stats"
 	"github.com/xtls/xray-core/proxy"
@@ -24,6 +25,7 @@ import (
 	"github.com/xtls/xray-core/transport/internet"
 	"github.com/xtls/xray-core/transport/internet/stat"
 	"github.com/xtls/xray-core/transport/internet/tcp"
+	"github.com/xtls/xray-core/transport/internet/tls"
 	"github.com/xtls/xray-core/transport/internet/udp"
 	"github.com/xtls/xray-core/transport/pipe"
 )
@@ -46,6 +48,7 @@ type tcpWorker struct {
 	sniffingConfig  *proxyman.SniffingConfig
 	uplinkCounter   stats.Counter
 	downlinkCounter stats.Counter
+	bans            ban.Manager
 
 	hub internet.Listener
 
@@ -60,6 +63,11 @@ func getTProxyType(s *internet.MemoryStreamConfig) internet.SocketConfig_TProxyM
 }
 
 func (w *tcpWorker) callback(conn stat.Connection) {
+	if w.bans != nil && w.bans.IsBanned(net.DestinationFromAddr(conn.RemoteAddr()).Address) {
+		errors.LogDebug(w.ctx, "rejected connection from banned ", conn.RemoteAddr())
+		conn.Close()
+		return
+	}
 	ctx, cancel := context.WithCancel(w.ctx)
 	sid := session.NewID()
 	ctx = c.ContextWithID(ctx, sid)
@@ -102,6 +110,7 @@ func (w *tcpWorker) callback(conn stat.Connection) {
 	}
 	ctx = session.ContextWithOutbounds(ctx, outbounds)
 
+	clientCertSubject := tls.ClientCertSubject(conn)
 	if w.uplinkCounter != nil || w.downlinkCounter != nil {
 		conn = &stat.CounterConnection{
 			Connection:   conn,
@@ -115,6 +124,8 @@ func (w *tcpWorker) callback(conn stat.Connection) {
 		Gateway: net.TCPDestination(w.address, w.port),
 		Tag:     w.tag,
 		Conn:    conn,
+
+		ClientCertSubject: clientCertSubject,
 	})
 
 	content := new(session.Content)
@@ -278,6 +289,7 @@ type udpWorker struct {
 	sniffingConfig  *proxyman.SniffingConfig
 	uplinkCounter   stats.Counter
 	downlinkCounter stats.Counter
+	bans            ban.Manager
 
 	checker    *task.Periodic
 	activeConn map[connID]*udpConn
@@ -321,6 +333,10 @@ func (w *udpWorker) getConnection(id connID) (*udpConn, bool) {
 }
 
 func (w *udpWorker) callback(b *buf.Buffer, source net.Destination, originalDest net.Destination) {
+	if w.bans != nil && w.bans.IsBanned(source.Address) {
+		b.Release()
+		return
+	}
 	id := connID{
 		src: source,
 	}
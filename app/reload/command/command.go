@@ -0,0 +1,41 @@
+package command
+
+import (
+	"context"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/core"
+	grpc "google.golang.org/grpc"
+)
+
+type ReloadServer struct {
+	V *core.Instance
+}
+
+// ReloadConfig implements ReloadService.
+func (s *ReloadServer) ReloadConfig(ctx context.Context, request *ReloadConfigRequest) (*ReloadConfigResponse, error) {
+	if err := s.V.ReloadConfig(); err != nil {
+		return nil, errors.New("failed to reload config").Base(err)
+	}
+	return &ReloadConfigResponse{}, nil
+}
+
+func (s *ReloadServer) mustEmbedUnimplementedReloadServiceServer() {}
+
+type service struct {
+	v *core.Instance
+}
+
+func (s *service) Register(server *grpc.Server) {
+	RegisterReloadServiceServer(server, &ReloadServer{
+		V: s.v,
+	})
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		s := core.MustFromContext(ctx)
+		return &service{v: s}, nil
+	}))
+}
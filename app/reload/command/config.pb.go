@@ -0,0 +1,194 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.5
+// source: app/reload/command/config.proto
+
+package command
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Config struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	mi := &file_app_reload_command_config_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_app_reload_command_config_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_app_reload_command_config_proto_rawDescGZIP(), []int{0}
+}
+
+type ReloadConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReloadConfigRequest) Reset() {
+	*x = ReloadConfigRequest{}
+	mi := &file_app_reload_command_config_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReloadConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadConfigRequest) ProtoMessage() {}
+
+func (x *ReloadConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_reload_command_config_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadConfigRequest.ProtoReflect.Descriptor instead.
+func (*ReloadConfigRequest) Descriptor() ([]byte, []int) {
+	return file_app_reload_command_config_proto_rawDescGZIP(), []int{1}
+}
+
+type ReloadConfigResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReloadConfigResponse) Reset() {
+	*x = ReloadConfigResponse{}
+	mi := &file_app_reload_command_config_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReloadConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadConfigResponse) ProtoMessage() {}
+
+func (x *ReloadConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_reload_command_config_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadConfigResponse.ProtoReflect.Descriptor instead.
+func (*ReloadConfigResponse) Descriptor() ([]byte, []int) {
+	return file_app_reload_command_config_proto_rawDescGZIP(), []int{2}
+}
+
+var File_app_reload_command_config_proto protoreflect.FileDescriptor
+
+const file_app_reload_command_config_proto_rawDesc = "" +
+	"\n" +
+	"\x1fapp/reload/command/config.proto\x12\x17xray.app.reload.command\"\b\n" +
+	"\x06Config\"\x15\n" +
+	"\x13ReloadConfigRequest\"\x16\n" +
+	"\x14ReloadConfigResponse2~\n" +
+	"\rReloadService\x12m\n" +
+	"\fReloadConfig\x12,.xray.app.reload.command.ReloadConfigRequest\x1a-.xray.app.reload.command.ReloadConfigResponse\"\x00Bg\n" +
+	"\x1bcom.xray.app.reload.commandP\x01Z,github.com/xtls/xray-core/app/reload/command\xaa\x02\x17Xray.App.Reload.Commandb\x06proto3"
+
+var (
+	file_app_reload_command_config_proto_rawDescOnce sync.Once
+	file_app_reload_command_config_proto_rawDescData []byte
+)
+
+func file_app_reload_command_config_proto_rawDescGZIP() []byte {
+	file_app_reload_command_config_proto_rawDescOnce.Do(func() {
+		file_app_reload_command_config_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_app_reload_command_config_proto_rawDesc), len(file_app_reload_command_config_proto_rawDesc)))
+	})
+	return file_app_reload_command_config_proto_rawDescData
+}
+
+var file_app_reload_command_config_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_app_reload_command_config_proto_goTypes = []any{
+	(*Config)(nil),               // 0: xray.app.reload.command.Config
+	(*ReloadConfigRequest)(nil),  // 1: xray.app.reload.command.ReloadConfigRequest
+	(*ReloadConfigResponse)(nil), // 2: xray.app.reload.command.ReloadConfigResponse
+}
+var file_app_reload_command_config_proto_depIdxs = []int32{
+	1, // 0: xray.app.reload.command.ReloadService.ReloadConfig:input_type -> xray.app.reload.command.ReloadConfigRequest
+	2, // 1: xray.app.reload.command.ReloadService.ReloadConfig:output_type -> xray.app.reload.command.ReloadConfigResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_app_reload_command_config_proto_init() }
+func file_app_reload_command_config_proto_init() {
+	if File_app_reload_command_config_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_reload_command_config_proto_rawDesc), len(file_app_reload_command_config_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_app_reload_command_config_proto_goTypes,
+		DependencyIndexes: file_app_reload_command_config_proto_depIdxs,
+		MessageInfos:      file_app_reload_command_config_proto_msgTypes,
+	}.Build()
+	File_app_reload_command_config_proto = out.File
+	file_app_reload_command_config_proto_goTypes = nil
+	file_app_reload_command_config_proto_depIdxs = nil
+}
@@ -10,12 +10,18 @@ import (
 	"github.com/xtls/xray-core/core"
 	"github.com/xtls/xray-core/features/extension"
 	"github.com/xtls/xray-core/features/outbound"
+	"github.com/xtls/xray-core/features/routing"
 )
 
 type BalancingStrategy interface {
 	PickOutbound([]string) string
 }
 
+// BalancingContextStrategy is a BalancingStrategy which picks by the routing context.
+type BalancingContextStrategy interface {
+	PickOutboundWithContext(routing.Context, []string) string
+}
+
 type BalancingPrincipleTarget interface {
 	GetPrincipleTarget([]string) []string
 }
@@ -91,8 +97,8 @@ type Balancer struct {
 	override override
 }
 
-// PickOutbound picks the tag of a outbound
-func (b *Balancer) PickOutbound() (string, error) {
+// PickOutbound picks the tag of a outbound for the routing context, which may be nil
+func (b *Balancer) PickOutbound(ctx routing.Context) (string, error) {
 	candidates, err := b.SelectOutbounds()
 	if err != nil {
 		if b.fallbackTag != "" {
@@ -104,6 +110,8 @@ func (b *Balancer) PickOutbound() (string, error) {
 	var tag string
 	if o := b.override.Get(); o != "" {
 		tag = o
+	} else if s, ok := b.strategy.(BalancingContextStrategy); ok && ctx != nil {
+		tag = s.PickOutboundWithContext(ctx, candidates)
 	} else {
 		tag = b.strategy.PickOutbound(candidates)
 	}
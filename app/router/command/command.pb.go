@@ -43,6 +43,10 @@ type RoutingContext struct {
 	LocalIPs          [][]byte               `protobuf:"bytes,13,rep,name=LocalIPs,proto3" json:"LocalIPs,omitempty"`
 	LocalPort         uint32                 `protobuf:"varint,14,opt,name=LocalPort,proto3" json:"LocalPort,omitempty"`
 	VlessRoute        uint32                 `protobuf:"varint,15,opt,name=VlessRoute,proto3" json:"VlessRoute,omitempty"`
+	ALPN              []string               `protobuf:"bytes,16,rep,name=ALPN,proto3" json:"ALPN,omitempty"`
+	TLSVersion        string                 `protobuf:"bytes,17,opt,name=TLSVersion,proto3" json:"TLSVersion,omitempty"`
+	JA3               string                 `protobuf:"bytes,18,opt,name=JA3,proto3" json:"JA3,omitempty"`
+	JA4               string                 `protobuf:"bytes,19,opt,name=JA4,proto3" json:"JA4,omitempty"`
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
@@ -182,6 +186,34 @@ func (x *RoutingContext) GetVlessRoute() uint32 {
 	return 0
 }
 
+func (x *RoutingContext) GetALPN() []string {
+	if x != nil {
+		return x.ALPN
+	}
+	return nil
+}
+
+func (x *RoutingContext) GetTLSVersion() string {
+	if x != nil {
+		return x.TLSVersion
+	}
+	return ""
+}
+
+func (x *RoutingContext) GetJA3() string {
+	if x != nil {
+		return x.JA3
+	}
+	return ""
+}
+
+func (x *RoutingContext) GetJA4() string {
+	if x != nil {
+		return x.JA4
+	}
+	return ""
+}
+
 // SubscribeRoutingStatsRequest subscribes to routing statistics channel if
 // opened by xray-core.
 // * FieldSelectors selects a subset of fields in routing statistics to return.
@@ -196,6 +228,8 @@ func (x *RoutingContext) GetVlessRoute() uint32 {
 //   - protocol: Select connection's protocol.
 //   - user: Select connection's inbound user email.
 //   - attributes: Select connection's additional attributes.
+//   - tls: Select ALPN, TLS version and JA3/JA4 fingerprints of the sniffed
+//     TLS ClientHello.
 //   - outbound: Equivalent as "outbound" and "outbound_group", select both
 //     outbound tag and outbound group tags.
 //
@@ -967,7 +1001,7 @@ var File_app_router_command_command_proto protoreflect.FileDescriptor
 
 const file_app_router_command_command_proto_rawDesc = "" +
 	"\n" +
-	" app/router/command/command.proto\x12\x17xray.app.router.command\x1a\x18common/net/network.proto\x1a!common/serial/typed_message.proto\"\xf6\x04\n" +
+	" app/router/command/command.proto\x12\x17xray.app.router.command\x1a\x18common/net/network.proto\x1a!common/serial/typed_message.proto\"\xce\x05\n" +
 	"\x0eRoutingContext\x12\x1e\n" +
 	"\n" +
 	"InboundTag\x18\x01 \x01(\tR\n" +
@@ -994,7 +1028,13 @@ const file_app_router_command_command_proto_rawDesc = "" +
 	"\tLocalPort\x18\x0e \x01(\rR\tLocalPort\x12\x1e\n" +
 	"\n" +
 	"VlessRoute\x18\x0f \x01(\rR\n" +
-	"VlessRoute\x1a=\n" +
+	"VlessRoute\x12\x12\n" +
+	"\x04ALPN\x18\x10 \x03(\tR\x04ALPN\x12\x1e\n" +
+	"\n" +
+	"TLSVersion\x18\x11 \x01(\tR\n" +
+	"TLSVersion\x12\x10\n" +
+	"\x03JA3\x18\x12 \x01(\tR\x03JA3\x12\x10\n" +
+	"\x03JA4\x18\x13 \x01(\tR\x03JA4\x1a=\n" +
 	"\x0fAttributesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"F\n" +
@@ -61,6 +61,13 @@ func AsRoutingRoute(r *RoutingContext) routing.Route {
 	return routingContext{r}
 }
 
+func copyClientHello(s *RoutingContext, r routing.Route) {
+	s.ALPN = r.GetALPN()
+	s.TLSVersion = r.GetTLSVersion()
+	s.JA3 = r.GetJA3()
+	s.JA4 = r.GetJA4()
+}
+
 var fieldMap = map[string]func(*RoutingContext, routing.Route){
 	"inbound":        func(s *RoutingContext, r routing.Route) { s.InboundTag = r.GetInboundTag() },
 	"network":        func(s *RoutingContext, r routing.Route) { s.Network = r.GetNetwork() },
@@ -74,6 +81,7 @@ var fieldMap = map[string]func(*RoutingContext, routing.Route){
 	"protocol":       func(s *RoutingContext, r routing.Route) { s.Protocol = r.GetProtocol() },
 	"user":           func(s *RoutingContext, r routing.Route) { s.User = r.GetUser() },
 	"attributes":     func(s *RoutingContext, r routing.Route) { s.Attributes = r.GetAttributes() },
+	"tls":            func(s *RoutingContext, r routing.Route) { copyClientHello(s, r) },
 	"outbound_group": func(s *RoutingContext, r routing.Route) { s.OutboundGroupTags = r.GetOutboundGroupTags() },
 	"outbound":       func(s *RoutingContext, r routing.Route) { s.OutboundTag = r.GetOutboundTag() },
 }
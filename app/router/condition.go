@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/net"
@@ -430,3 +431,123 @@ func (m *ProcessNameMatcher) Apply(ctx routing.Context) bool {
 	}
 	return false
 }
+
+// TimeMatcher matches the time of the day and the day of the week, in its location.
+type TimeMatcher struct {
+	ranges   []*TimeRange
+	weekdays [7]bool
+	anyDay   bool
+	location *time.Location
+}
+
+func NewTimeMatcher(ranges []*TimeRange, weekdays []uint32, timezone string) (*TimeMatcher, error) {
+	m := &TimeMatcher{
+		ranges:   ranges,
+		anyDay:   len(weekdays) == 0,
+		location: time.Local,
+	}
+	for _, r := range ranges {
+		if r.From >= 24*60*60 || r.To > 24*60*60 {
+			return nil, errors.New("invalid time range: ", r.From, "-", r.To)
+		}
+	}
+	for _, d := range weekdays {
+		if d > 6 {
+			return nil, errors.New("invalid weekday: ", d)
+		}
+		m.weekdays[d] = true
+	}
+	if timezone != "" {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, errors.New("unknown time zone ", timezone).Base(err)
+		}
+		m.location = location
+	}
+	return m, nil
+}
+
+func (m *TimeMatcher) matchDay(d time.Weekday) bool {
+	return m.anyDay || m.weekdays[d]
+}
+
+// Match returns whether the time is in the time ranges and weekdays.
+func (m *TimeMatcher) Match(t time.Time) bool {
+	t = t.In(m.location)
+	if len(m.ranges) == 0 {
+		return m.matchDay(t.Weekday())
+	}
+	seconds := uint32(t.Hour()*60*60 + t.Minute()*60 + t.Second())
+	for _, r := range m.ranges {
+		switch {
+		case r.From < r.To:
+			if seconds >= r.From && seconds < r.To && m.matchDay(t.Weekday()) {
+				return true
+			}
+		case r.From == r.To:
+			if m.matchDay(t.Weekday()) {
+				return true
+			}
+		default:
+			// The window wraps around midnight, and belongs to the day it starts.
+			if seconds >= r.From && m.matchDay(t.Weekday()) {
+				return true
+			}
+			if seconds < r.To && m.matchDay((t.Weekday()+6)%7) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Apply implements Condition.
+func (m *TimeMatcher) Apply(ctx routing.Context) bool {
+	return m.Match(time.Now())
+}
+
+// ClientHelloMatcher matches the sniffed TLS ClientHello. Each of the lists
+// that are not empty must be matched.
+type ClientHelloMatcher struct {
+	alpn     map[string]bool
+	versions map[string]bool
+	ja3      map[string]bool
+	ja4      map[string]bool
+}
+
+func toSet(values []string) map[string]bool {
+	if len(values) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[strings.ToLower(v)] = true
+	}
+	return set
+}
+
+func NewClientHelloMatcher(alpn, versions, ja3, ja4 []string) *ClientHelloMatcher {
+	return &ClientHelloMatcher{
+		alpn:     toSet(alpn),
+		versions: toSet(versions),
+		ja3:      toSet(ja3),
+		ja4:      toSet(ja4),
+	}
+}
+
+// Apply implements Condition.
+func (m *ClientHelloMatcher) Apply(ctx routing.Context) bool {
+	if m.alpn != nil && !slices.ContainsFunc(ctx.GetALPN(), func(p string) bool { return m.alpn[strings.ToLower(p)] }) {
+		return false
+	}
+	if m.versions != nil && !m.versions[strings.ToLower(ctx.GetTLSVersion())] {
+		return false
+	}
+	if m.ja3 != nil && !m.ja3[strings.ToLower(ctx.GetJA3())] {
+		return false
+	}
+	if m.ja4 != nil && !m.ja4[strings.ToLower(ctx.GetJA4())] {
+		return false
+	}
+	return true
+}
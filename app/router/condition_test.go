@@ -3,6 +3,7 @@ package router_test
 import (
 	"strconv"
 	"testing"
+	"time"
 
 	. "github.com/xtls/xray-core/app/router"
 	"github.com/xtls/xray-core/common"
@@ -285,6 +286,43 @@ func TestRoutingRule(t *testing.T) {
 				},
 			},
 		},
+		{
+			rule: &RoutingRule{
+				Alpn:       []string{"h2"},
+				TlsVersion: []string{"1.3"},
+				Ja4:        []string{"t13d1516h2_8daaf6152771_e5627efa2ab1"},
+			},
+			test: []ruleTest{
+				{
+					input: withContent(&session.Content{ClientHello: &session.ClientHello{
+						ALPN:    []string{"h2", "http/1.1"},
+						Version: "1.3",
+						JA4:     "t13d1516h2_8daaf6152771_e5627efa2ab1",
+					}}),
+					output: true,
+				},
+				{
+					input: withContent(&session.Content{ClientHello: &session.ClientHello{
+						ALPN:    []string{"http/1.1"},
+						Version: "1.3",
+						JA4:     "t13d1516h2_8daaf6152771_e5627efa2ab1",
+					}}),
+					output: false,
+				},
+				{
+					input: withContent(&session.Content{ClientHello: &session.ClientHello{
+						ALPN:    []string{"h2"},
+						Version: "1.3",
+						JA4:     "t13d1715h2_5b57614c22b0_3d5424432f57",
+					}}),
+					output: false,
+				},
+				{
+					input:  withContent(&session.Content{Protocol: "tls"}),
+					output: false,
+				},
+			},
+		},
 	}
 
 	for _, test := range cases {
@@ -458,3 +496,41 @@ func BenchmarkMultiGeoIPMatcher(b *testing.B) {
 		_ = matcher.Apply(ctx)
 	}
 }
+
+func TestTimeMatcher(t *testing.T) {
+	// Friday 22:00 to Saturday 06:00, and 12:00 to 13:00 on Fridays.
+	matcher, err := NewTimeMatcher([]*TimeRange{
+		{From: 22 * 60 * 60, To: 6 * 60 * 60},
+		{From: 12 * 60 * 60, To: 13 * 60 * 60},
+	}, []uint32{5}, "Asia/Shanghai")
+	common.Must(err)
+
+	location, err := time.LoadLocation("Asia/Shanghai")
+	common.Must(err)
+	cases := []struct {
+		time   time.Time
+		output bool
+	}{
+		{time: time.Date(2026, 10, 16, 22, 0, 0, 0, location), output: true},
+		{time: time.Date(2026, 10, 17, 5, 59, 59, 0, location), output: true},
+		{time: time.Date(2026, 10, 17, 6, 0, 0, 0, location), output: false},
+		{time: time.Date(2026, 10, 16, 5, 0, 0, 0, location), output: false},
+		{time: time.Date(2026, 10, 16, 12, 30, 0, 0, location), output: true},
+		{time: time.Date(2026, 10, 17, 12, 30, 0, 0, location), output: false},
+		{time: time.Date(2026, 10, 17, 23, 0, 0, 0, location), output: false},
+		// Friday 22:30 in Shanghai.
+		{time: time.Date(2026, 10, 16, 14, 30, 0, 0, time.UTC), output: true},
+	}
+	for _, c := range cases {
+		if v := matcher.Match(c.time); v != c.output {
+			t.Error("unexpected output ", v, " for time ", c.time)
+		}
+	}
+
+	if _, err := NewTimeMatcher(nil, []uint32{7}, ""); err == nil {
+		t.Error("expect error of invalid weekday")
+	}
+	if _, err := NewTimeMatcher(nil, []uint32{0}, "Invalid/Zone"); err == nil {
+		t.Error("expect error of invalid time zone")
+	}
+}
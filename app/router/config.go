@@ -20,9 +20,9 @@ type Rule struct {
 	Condition Condition
 }
 
-func (r *Rule) GetTag() (string, error) {
+func (r *Rule) GetTag(ctx routing.Context) (string, error) {
 	if r.Balancer != nil {
-		return r.Balancer.PickOutbound()
+		return r.Balancer.PickOutbound(ctx)
 	}
 	return r.Tag, nil
 }
@@ -33,8 +33,23 @@ func (r *Rule) Apply(ctx routing.Context) bool {
 }
 
 func (rr *RoutingRule) BuildCondition() (Condition, error) {
+	return rr.BuildConditionWithRuleSets(nil)
+}
+
+// BuildConditionWithRuleSets builds the condition of the rule, in which the
+// rule sets are looked up by name.
+func (rr *RoutingRule) BuildConditionWithRuleSets(ruleSets map[string]*RuleSetMatcher) (Condition, error) {
 	conds := NewConditionChan()
 
+	domainRuleSets, err := newRuleSetCondition(rr.DomainRuleSet, ruleSets, false)
+	if err != nil {
+		return nil, err
+	}
+	ipRuleSets, err := newRuleSetCondition(rr.IpRuleSet, ruleSets, true)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(rr.InboundTag) > 0 {
 		conds.Add(NewInboundTagMatcher(rr.InboundTag))
 	}
@@ -80,10 +95,17 @@ func (rr *RoutingRule) BuildCondition() (Condition, error) {
 		if err != nil {
 			return nil, err
 		}
-		conds.Add(cond)
+		if ipRuleSets != nil {
+			ipRuleSets.static = cond
+		} else {
+			conds.Add(cond)
+		}
 		rr.Geoip = nil
 		runtime.GC()
 	}
+	if ipRuleSets != nil {
+		conds.Add(ipRuleSets)
+	}
 
 	if len(rr.SourceGeoip) > 0 {
 		cond, err := NewIPMatcher(rr.SourceGeoip, MatcherAsType_Source)
@@ -126,15 +148,34 @@ func (rr *RoutingRule) BuildCondition() (Condition, error) {
 			}
 			errors.LogDebug(context.Background(), "MphDomainMatcher is enabled for ", len(rr.Domain), " domain rule(s)")
 		}
-		conds.Add(matcher)
+		if domainRuleSets != nil {
+			domainRuleSets.static = matcher
+		} else {
+			conds.Add(matcher)
+		}
 		rr.Domain = nil
 		runtime.GC()
 	}
+	if domainRuleSets != nil {
+		conds.Add(domainRuleSets)
+	}
 
 	if len(rr.Process) > 0 {
 		conds.Add(NewProcessNameMatcher(rr.Process))
 	}
 
+	if len(rr.Alpn) > 0 || len(rr.TlsVersion) > 0 || len(rr.Ja3) > 0 || len(rr.Ja4) > 0 {
+		conds.Add(NewClientHelloMatcher(rr.Alpn, rr.TlsVersion, rr.Ja3, rr.Ja4))
+	}
+
+	if len(rr.TimeRange) > 0 || len(rr.Weekday) > 0 {
+		cond, err := NewTimeMatcher(rr.TimeRange, rr.Weekday, rr.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		conds.Add(cond)
+	}
+
 	if conds.Len() == 0 {
 		return nil, errors.New("this rule has no effective fields").AtWarning()
 	}
@@ -175,6 +216,24 @@ func (br *BalancingRule) Build(ohm outbound.Manager, dispatcher routing.Dispatch
 			fallbackTag: br.FallbackTag,
 			strategy:    leastLoadStrategy,
 		}, nil
+	case "consistenthash":
+		s := new(StrategyConsistentHashConfig)
+		if br.StrategySettings != nil {
+			i, err := br.StrategySettings.GetInstance()
+			if err != nil {
+				return nil, err
+			}
+			var ok bool
+			if s, ok = i.(*StrategyConsistentHashConfig); !ok {
+				return nil, errors.New("not a StrategyConsistentHashConfig").AtError()
+			}
+		}
+		return &Balancer{
+			selectors:   br.OutboundSelector,
+			ohm:         ohm,
+			fallbackTag: br.FallbackTag,
+			strategy:    NewConsistentHashStrategy(s),
+		}, nil
 	case "random":
 		fallthrough
 	case "":
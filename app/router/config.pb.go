@@ -80,6 +80,157 @@ func (Domain_Type) EnumDescriptor() ([]byte, []int) {
 	return file_app_router_config_proto_rawDescGZIP(), []int{0, 0}
 }
 
+type RuleSet_Type int32
+
+const (
+	RuleSet_Domain RuleSet_Type = 0
+	RuleSet_IP     RuleSet_Type = 1
+)
+
+// Enum value maps for RuleSet_Type.
+var (
+	RuleSet_Type_name = map[int32]string{
+		0: "Domain",
+		1: "IP",
+	}
+	RuleSet_Type_value = map[string]int32{
+		"Domain": 0,
+		"IP":     1,
+	}
+)
+
+func (x RuleSet_Type) Enum() *RuleSet_Type {
+	p := new(RuleSet_Type)
+	*p = x
+	return p
+}
+
+func (x RuleSet_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RuleSet_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_router_config_proto_enumTypes[1].Descriptor()
+}
+
+func (RuleSet_Type) Type() protoreflect.EnumType {
+	return &file_app_router_config_proto_enumTypes[1]
+}
+
+func (x RuleSet_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RuleSet_Type.Descriptor instead.
+func (RuleSet_Type) EnumDescriptor() ([]byte, []int) {
+	return file_app_router_config_proto_rawDescGZIP(), []int{7, 0}
+}
+
+type RuleSet_Format int32
+
+const (
+	// One domain or IP per line. Domains may have a prefix of "full:",
+	// "domain:", "keyword:" or "regexp:", default "domain:".
+	RuleSet_Plain RuleSet_Format = 0
+	// geosite.dat or geoip.dat.
+	RuleSet_Dat RuleSet_Format = 1
+	// MaxMind DB of countries, only for IPs.
+	RuleSet_MMDB RuleSet_Format = 2
+)
+
+// Enum value maps for RuleSet_Format.
+var (
+	RuleSet_Format_name = map[int32]string{
+		0: "Plain",
+		1: "Dat",
+		2: "MMDB",
+	}
+	RuleSet_Format_value = map[string]int32{
+		"Plain": 0,
+		"Dat":   1,
+		"MMDB":  2,
+	}
+)
+
+func (x RuleSet_Format) Enum() *RuleSet_Format {
+	p := new(RuleSet_Format)
+	*p = x
+	return p
+}
+
+func (x RuleSet_Format) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RuleSet_Format) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_router_config_proto_enumTypes[2].Descriptor()
+}
+
+func (RuleSet_Format) Type() protoreflect.EnumType {
+	return &file_app_router_config_proto_enumTypes[2]
+}
+
+func (x RuleSet_Format) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RuleSet_Format.Descriptor instead.
+func (RuleSet_Format) EnumDescriptor() ([]byte, []int) {
+	return file_app_router_config_proto_rawDescGZIP(), []int{7, 1}
+}
+
+type StrategyConsistentHashConfig_Key int32
+
+const (
+	// Source IP of the connection.
+	StrategyConsistentHashConfig_SourceIP StrategyConsistentHashConfig_Key = 0
+	// Email of the user, or source IP if there is no user.
+	StrategyConsistentHashConfig_Email StrategyConsistentHashConfig_Key = 1
+	// Target domain, or target IP if there is no domain.
+	StrategyConsistentHashConfig_Domain StrategyConsistentHashConfig_Key = 2
+)
+
+// Enum value maps for StrategyConsistentHashConfig_Key.
+var (
+	StrategyConsistentHashConfig_Key_name = map[int32]string{
+		0: "SourceIP",
+		1: "Email",
+		2: "Domain",
+	}
+	StrategyConsistentHashConfig_Key_value = map[string]int32{
+		"SourceIP": 0,
+		"Email":    1,
+		"Domain":   2,
+	}
+)
+
+func (x StrategyConsistentHashConfig_Key) Enum() *StrategyConsistentHashConfig_Key {
+	p := new(StrategyConsistentHashConfig_Key)
+	*p = x
+	return p
+}
+
+func (x StrategyConsistentHashConfig_Key) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StrategyConsistentHashConfig_Key) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_router_config_proto_enumTypes[3].Descriptor()
+}
+
+func (StrategyConsistentHashConfig_Key) Type() protoreflect.EnumType {
+	return &file_app_router_config_proto_enumTypes[3]
+}
+
+func (x StrategyConsistentHashConfig_Key) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StrategyConsistentHashConfig_Key.Descriptor instead.
+func (StrategyConsistentHashConfig_Key) EnumDescriptor() ([]byte, []int) {
+	return file_app_router_config_proto_rawDescGZIP(), []int{12, 0}
+}
+
 type Config_DomainStrategy int32
 
 const (
@@ -116,11 +267,11 @@ func (x Config_DomainStrategy) String() string {
 }
 
 func (Config_DomainStrategy) Descriptor() protoreflect.EnumDescriptor {
-	return file_app_router_config_proto_enumTypes[1].Descriptor()
+	return file_app_router_config_proto_enumTypes[4].Descriptor()
 }
 
 func (Config_DomainStrategy) Type() protoreflect.EnumType {
-	return &file_app_router_config_proto_enumTypes[1]
+	return &file_app_router_config_proto_enumTypes[4]
 }
 
 func (x Config_DomainStrategy) Number() protoreflect.EnumNumber {
@@ -129,7 +280,7 @@ func (x Config_DomainStrategy) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Config_DomainStrategy.Descriptor instead.
 func (Config_DomainStrategy) EnumDescriptor() ([]byte, []int) {
-	return file_app_router_config_proto_rawDescGZIP(), []int{10, 0}
+	return file_app_router_config_proto_rawDescGZIP(), []int{13, 0}
 }
 
 // Domain for routing decision.
@@ -483,8 +634,26 @@ type RoutingRule struct {
 	LocalPortList  *net.PortList     `protobuf:"bytes,18,opt,name=local_port_list,json=localPortList,proto3" json:"local_port_list,omitempty"`
 	VlessRouteList *net.PortList     `protobuf:"bytes,20,opt,name=vless_route_list,json=vlessRouteList,proto3" json:"vless_route_list,omitempty"`
 	Process        []string          `protobuf:"bytes,21,rep,name=process,proto3" json:"process,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Time windows of the day, in the time zone below. Matches if the time is in
+	// any of them.
+	TimeRange []*TimeRange `protobuf:"bytes,22,rep,name=time_range,json=timeRange,proto3" json:"time_range,omitempty"`
+	// Days of the week, 0 for Sunday. Matches if the day is any of them.
+	Weekday []uint32 `protobuf:"varint,23,rep,packed,name=weekday,proto3" json:"weekday,omitempty"`
+	// IANA name of the time zone for time_range and weekday, like
+	// "Asia/Shanghai". Default the local time zone.
+	Timezone string `protobuf:"bytes,24,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	// Conditions on the sniffed TLS ClientHello of TLS and QUIC traffic. ALPN
+	// matches if any protocol offered is in the list, tls_version matches the
+	// highest version offered, like "1.3", and ja3 and ja4 match fingerprints.
+	Alpn       []string `protobuf:"bytes,25,rep,name=alpn,proto3" json:"alpn,omitempty"`
+	TlsVersion []string `protobuf:"bytes,26,rep,name=tls_version,json=tlsVersion,proto3" json:"tls_version,omitempty"`
+	Ja3        []string `protobuf:"bytes,27,rep,name=ja3,proto3" json:"ja3,omitempty"`
+	Ja4        []string `protobuf:"bytes,28,rep,name=ja4,proto3" json:"ja4,omitempty"`
+	// Names of rule sets in Config, for target domain and target IP matching.
+	DomainRuleSet []string `protobuf:"bytes,29,rep,name=domain_rule_set,json=domainRuleSet,proto3" json:"domain_rule_set,omitempty"`
+	IpRuleSet     []string `protobuf:"bytes,30,rep,name=ip_rule_set,json=ipRuleSet,proto3" json:"ip_rule_set,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RoutingRule) Reset() {
@@ -647,6 +816,69 @@ func (x *RoutingRule) GetProcess() []string {
 	return nil
 }
 
+func (x *RoutingRule) GetTimeRange() []*TimeRange {
+	if x != nil {
+		return x.TimeRange
+	}
+	return nil
+}
+
+func (x *RoutingRule) GetWeekday() []uint32 {
+	if x != nil {
+		return x.Weekday
+	}
+	return nil
+}
+
+func (x *RoutingRule) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *RoutingRule) GetAlpn() []string {
+	if x != nil {
+		return x.Alpn
+	}
+	return nil
+}
+
+func (x *RoutingRule) GetTlsVersion() []string {
+	if x != nil {
+		return x.TlsVersion
+	}
+	return nil
+}
+
+func (x *RoutingRule) GetJa3() []string {
+	if x != nil {
+		return x.Ja3
+	}
+	return nil
+}
+
+func (x *RoutingRule) GetJa4() []string {
+	if x != nil {
+		return x.Ja4
+	}
+	return nil
+}
+
+func (x *RoutingRule) GetDomainRuleSet() []string {
+	if x != nil {
+		return x.DomainRuleSet
+	}
+	return nil
+}
+
+func (x *RoutingRule) GetIpRuleSet() []string {
+	if x != nil {
+		return x.IpRuleSet
+	}
+	return nil
+}
+
 type isRoutingRule_TargetTag interface {
 	isRoutingRule_TargetTag()
 }
@@ -665,6 +897,150 @@ func (*RoutingRule_Tag) isRoutingRule_TargetTag() {}
 
 func (*RoutingRule_BalancingTag) isRoutingRule_TargetTag() {}
 
+// RuleSet is a set of domains or IPs in a file, which is reloaded when the
+// file changes.
+type RuleSet struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Name   string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type   RuleSet_Type           `protobuf:"varint,2,opt,name=type,proto3,enum=xray.app.router.RuleSet_Type" json:"type,omitempty"`
+	Format RuleSet_Format         `protobuf:"varint,3,opt,name=format,proto3,enum=xray.app.router.RuleSet_Format" json:"format,omitempty"`
+	Path   string                 `protobuf:"bytes,4,opt,name=path,proto3" json:"path,omitempty"`
+	// Code of the list in dat and MMDB files, like "CN".
+	Code string `protobuf:"bytes,5,opt,name=code,proto3" json:"code,omitempty"`
+	// Interval to check the file for changes, in nanoseconds. Default 10
+	// seconds.
+	Interval      int64 `protobuf:"varint,6,opt,name=interval,proto3" json:"interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RuleSet) Reset() {
+	*x = RuleSet{}
+	mi := &file_app_router_config_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuleSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleSet) ProtoMessage() {}
+
+func (x *RuleSet) ProtoReflect() protoreflect.Message {
+	mi := &file_app_router_config_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleSet.ProtoReflect.Descriptor instead.
+func (*RuleSet) Descriptor() ([]byte, []int) {
+	return file_app_router_config_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RuleSet) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RuleSet) GetType() RuleSet_Type {
+	if x != nil {
+		return x.Type
+	}
+	return RuleSet_Domain
+}
+
+func (x *RuleSet) GetFormat() RuleSet_Format {
+	if x != nil {
+		return x.Format
+	}
+	return RuleSet_Plain
+}
+
+func (x *RuleSet) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *RuleSet) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *RuleSet) GetInterval() int64 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+// TimeRange is a time window of the day, in seconds since midnight. A window
+// where from is greater than to wraps around midnight, like 22:00 to 06:00,
+// and belongs to the day it starts.
+type TimeRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          uint32                 `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            uint32                 `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeRange) Reset() {
+	*x = TimeRange{}
+	mi := &file_app_router_config_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeRange) ProtoMessage() {}
+
+func (x *TimeRange) ProtoReflect() protoreflect.Message {
+	mi := &file_app_router_config_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeRange.ProtoReflect.Descriptor instead.
+func (*TimeRange) Descriptor() ([]byte, []int) {
+	return file_app_router_config_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TimeRange) GetFrom() uint32 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *TimeRange) GetTo() uint32 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
 type BalancingRule struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	Tag              string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
@@ -678,7 +1054,7 @@ type BalancingRule struct {
 
 func (x *BalancingRule) Reset() {
 	*x = BalancingRule{}
-	mi := &file_app_router_config_proto_msgTypes[7]
+	mi := &file_app_router_config_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -690,7 +1066,7 @@ func (x *BalancingRule) String() string {
 func (*BalancingRule) ProtoMessage() {}
 
 func (x *BalancingRule) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_config_proto_msgTypes[7]
+	mi := &file_app_router_config_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -703,7 +1079,7 @@ func (x *BalancingRule) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BalancingRule.ProtoReflect.Descriptor instead.
 func (*BalancingRule) Descriptor() ([]byte, []int) {
-	return file_app_router_config_proto_rawDescGZIP(), []int{7}
+	return file_app_router_config_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *BalancingRule) GetTag() string {
@@ -752,7 +1128,7 @@ type StrategyWeight struct {
 
 func (x *StrategyWeight) Reset() {
 	*x = StrategyWeight{}
-	mi := &file_app_router_config_proto_msgTypes[8]
+	mi := &file_app_router_config_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -764,7 +1140,7 @@ func (x *StrategyWeight) String() string {
 func (*StrategyWeight) ProtoMessage() {}
 
 func (x *StrategyWeight) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_config_proto_msgTypes[8]
+	mi := &file_app_router_config_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -777,7 +1153,7 @@ func (x *StrategyWeight) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StrategyWeight.ProtoReflect.Descriptor instead.
 func (*StrategyWeight) Descriptor() ([]byte, []int) {
-	return file_app_router_config_proto_rawDescGZIP(), []int{8}
+	return file_app_router_config_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *StrategyWeight) GetRegexp() bool {
@@ -819,7 +1195,7 @@ type StrategyLeastLoadConfig struct {
 
 func (x *StrategyLeastLoadConfig) Reset() {
 	*x = StrategyLeastLoadConfig{}
-	mi := &file_app_router_config_proto_msgTypes[9]
+	mi := &file_app_router_config_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -831,7 +1207,7 @@ func (x *StrategyLeastLoadConfig) String() string {
 func (*StrategyLeastLoadConfig) ProtoMessage() {}
 
 func (x *StrategyLeastLoadConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_config_proto_msgTypes[9]
+	mi := &file_app_router_config_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -844,7 +1220,7 @@ func (x *StrategyLeastLoadConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StrategyLeastLoadConfig.ProtoReflect.Descriptor instead.
 func (*StrategyLeastLoadConfig) Descriptor() ([]byte, []int) {
-	return file_app_router_config_proto_rawDescGZIP(), []int{9}
+	return file_app_router_config_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *StrategyLeastLoadConfig) GetCosts() []*StrategyWeight {
@@ -882,18 +1258,64 @@ func (x *StrategyLeastLoadConfig) GetTolerance() float32 {
 	return 0
 }
 
+type StrategyConsistentHashConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key of connections to hash, connections of the same key go to the same outbound
+	Key           StrategyConsistentHashConfig_Key `protobuf:"varint,1,opt,name=key,proto3,enum=xray.app.router.StrategyConsistentHashConfig_Key" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StrategyConsistentHashConfig) Reset() {
+	*x = StrategyConsistentHashConfig{}
+	mi := &file_app_router_config_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StrategyConsistentHashConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StrategyConsistentHashConfig) ProtoMessage() {}
+
+func (x *StrategyConsistentHashConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_app_router_config_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StrategyConsistentHashConfig.ProtoReflect.Descriptor instead.
+func (*StrategyConsistentHashConfig) Descriptor() ([]byte, []int) {
+	return file_app_router_config_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *StrategyConsistentHashConfig) GetKey() StrategyConsistentHashConfig_Key {
+	if x != nil {
+		return x.Key
+	}
+	return StrategyConsistentHashConfig_SourceIP
+}
+
 type Config struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	DomainStrategy Config_DomainStrategy  `protobuf:"varint,1,opt,name=domain_strategy,json=domainStrategy,proto3,enum=xray.app.router.Config_DomainStrategy" json:"domain_strategy,omitempty"`
 	Rule           []*RoutingRule         `protobuf:"bytes,2,rep,name=rule,proto3" json:"rule,omitempty"`
 	BalancingRule  []*BalancingRule       `protobuf:"bytes,3,rep,name=balancing_rule,json=balancingRule,proto3" json:"balancing_rule,omitempty"`
+	RuleSet        []*RuleSet             `protobuf:"bytes,4,rep,name=rule_set,json=ruleSet,proto3" json:"rule_set,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
 func (x *Config) Reset() {
 	*x = Config{}
-	mi := &file_app_router_config_proto_msgTypes[10]
+	mi := &file_app_router_config_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -905,7 +1327,7 @@ func (x *Config) String() string {
 func (*Config) ProtoMessage() {}
 
 func (x *Config) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_config_proto_msgTypes[10]
+	mi := &file_app_router_config_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -918,7 +1340,7 @@ func (x *Config) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Config.ProtoReflect.Descriptor instead.
 func (*Config) Descriptor() ([]byte, []int) {
-	return file_app_router_config_proto_rawDescGZIP(), []int{10}
+	return file_app_router_config_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *Config) GetDomainStrategy() Config_DomainStrategy {
@@ -942,6 +1364,13 @@ func (x *Config) GetBalancingRule() []*BalancingRule {
 	return nil
 }
 
+func (x *Config) GetRuleSet() []*RuleSet {
+	if x != nil {
+		return x.RuleSet
+	}
+	return nil
+}
+
 type Domain_Attribute struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -956,7 +1385,7 @@ type Domain_Attribute struct {
 
 func (x *Domain_Attribute) Reset() {
 	*x = Domain_Attribute{}
-	mi := &file_app_router_config_proto_msgTypes[11]
+	mi := &file_app_router_config_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -968,7 +1397,7 @@ func (x *Domain_Attribute) String() string {
 func (*Domain_Attribute) ProtoMessage() {}
 
 func (x *Domain_Attribute) ProtoReflect() protoreflect.Message {
-	mi := &file_app_router_config_proto_msgTypes[11]
+	mi := &file_app_router_config_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1066,7 +1495,7 @@ const file_app_router_config_proto_rawDesc = "" +
 	"\fcountry_code\x18\x01 \x01(\tR\vcountryCode\x12/\n" +
 	"\x06domain\x18\x02 \x03(\v2\x17.xray.app.router.DomainR\x06domain\"=\n" +
 	"\vGeoSiteList\x12.\n" +
-	"\x05entry\x18\x01 \x03(\v2\x18.xray.app.router.GeoSiteR\x05entry\"\x82\a\n" +
+	"\x05entry\x18\x01 \x03(\v2\x18.xray.app.router.GeoSiteR\x05entry\"\x94\t\n" +
 	"\vRoutingRule\x12\x12\n" +
 	"\x03tag\x18\x01 \x01(\tH\x00R\x03tag\x12%\n" +
 	"\rbalancing_tag\x18\f \x01(\tH\x00R\fbalancingTag\x12\x19\n" +
@@ -1090,12 +1519,41 @@ const file_app_router_config_proto_rawDesc = "" +
 	"localGeoip\x12A\n" +
 	"\x0flocal_port_list\x18\x12 \x01(\v2\x19.xray.common.net.PortListR\rlocalPortList\x12C\n" +
 	"\x10vless_route_list\x18\x14 \x01(\v2\x19.xray.common.net.PortListR\x0evlessRouteList\x12\x18\n" +
-	"\aprocess\x18\x15 \x03(\tR\aprocess\x1a=\n" +
+	"\aprocess\x18\x15 \x03(\tR\aprocess\x129\n" +
+	"\n" +
+	"time_range\x18\x16 \x03(\v2\x1a.xray.app.router.TimeRangeR\ttimeRange\x12\x18\n" +
+	"\aweekday\x18\x17 \x03(\rR\aweekday\x12\x1a\n" +
+	"\btimezone\x18\x18 \x01(\tR\btimezone\x12\x12\n" +
+	"\x04alpn\x18\x19 \x03(\tR\x04alpn\x12\x1f\n" +
+	"\vtls_version\x18\x1a \x03(\tR\n" +
+	"tlsVersion\x12\x10\n" +
+	"\x03ja3\x18\x1b \x03(\tR\x03ja3\x12\x10\n" +
+	"\x03ja4\x18\x1c \x03(\tR\x03ja4\x12&\n" +
+	"\x0fdomain_rule_set\x18\x1d \x03(\tR\rdomainRuleSet\x12\x1e\n" +
+	"\vip_rule_set\x18\x1e \x03(\tR\tipRuleSet\x1a=\n" +
 	"\x0fAttributesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\f\n" +
 	"\n" +
-	"target_tag\"\xdc\x01\n" +
+	"target_tag\"\x91\x02\n" +
+	"\aRuleSet\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x121\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x1d.xray.app.router.RuleSet.TypeR\x04type\x127\n" +
+	"\x06format\x18\x03 \x01(\x0e2\x1f.xray.app.router.RuleSet.FormatR\x06format\x12\x12\n" +
+	"\x04path\x18\x04 \x01(\tR\x04path\x12\x12\n" +
+	"\x04code\x18\x05 \x01(\tR\x04code\x12\x1a\n" +
+	"\binterval\x18\x06 \x01(\x03R\binterval\"\x1a\n" +
+	"\x04Type\x12\n" +
+	"\n" +
+	"\x06Domain\x10\x00\x12\x06\n" +
+	"\x02IP\x10\x01\"&\n" +
+	"\x06Format\x12\t\n" +
+	"\x05Plain\x10\x00\x12\a\n" +
+	"\x03Dat\x10\x01\x12\b\n" +
+	"\x04MMDB\x10\x02\"/\n" +
+	"\tTimeRange\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\rR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\rR\x02to\"\xdc\x01\n" +
 	"\rBalancingRule\x12\x10\n" +
 	"\x03tag\x18\x01 \x01(\tR\x03tag\x12+\n" +
 	"\x11outbound_selector\x18\x02 \x03(\tR\x10outboundSelector\x12\x1a\n" +
@@ -1111,11 +1569,19 @@ const file_app_router_config_proto_rawDesc = "" +
 	"\tbaselines\x18\x03 \x03(\x03R\tbaselines\x12\x1a\n" +
 	"\bexpected\x18\x04 \x01(\x05R\bexpected\x12\x16\n" +
 	"\x06maxRTT\x18\x05 \x01(\x03R\x06maxRTT\x12\x1c\n" +
-	"\ttolerance\x18\x06 \x01(\x02R\ttolerance\"\x90\x02\n" +
+	"\ttolerance\x18\x06 \x01(\x02R\ttolerance\"\x8f\x01\n" +
+	"\x1cStrategyConsistentHashConfig\x12C\n" +
+	"\x03key\x18\x01 \x01(\x0e21.xray.app.router.StrategyConsistentHashConfig.KeyR\x03key\"*\n" +
+	"\x03Key\x12\f\n" +
+	"\bSourceIP\x10\x00\x12\t\n" +
+	"\x05Email\x10\x01\x12\n" +
+	"\n" +
+	"\x06Domain\x10\x02\"\xc5\x02\n" +
 	"\x06Config\x12O\n" +
 	"\x0fdomain_strategy\x18\x01 \x01(\x0e2&.xray.app.router.Config.DomainStrategyR\x0edomainStrategy\x120\n" +
 	"\x04rule\x18\x02 \x03(\v2\x1c.xray.app.router.RoutingRuleR\x04rule\x12E\n" +
-	"\x0ebalancing_rule\x18\x03 \x03(\v2\x1e.xray.app.router.BalancingRuleR\rbalancingRule\"<\n" +
+	"\x0ebalancing_rule\x18\x03 \x03(\v2\x1e.xray.app.router.BalancingRuleR\rbalancingRule\x123\n" +
+	"\brule_set\x18\x04 \x03(\v2\x18.xray.app.router.RuleSetR\aruleSet\"<\n" +
 	"\x0eDomainStrategy\x12\b\n" +
 	"\x04AsIs\x10\x00\x12\x10\n" +
 	"\fIpIfNonMatch\x10\x02\x12\x0e\n" +
@@ -1135,55 +1601,66 @@ func file_app_router_config_proto_rawDescGZIP() []byte {
 	return file_app_router_config_proto_rawDescData
 }
 
-var file_app_router_config_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_app_router_config_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_app_router_config_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_app_router_config_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_app_router_config_proto_goTypes = []any{
-	(Domain_Type)(0),                // 0: xray.app.router.Domain.Type
-	(Config_DomainStrategy)(0),      // 1: xray.app.router.Config.DomainStrategy
-	(*Domain)(nil),                  // 2: xray.app.router.Domain
-	(*CIDR)(nil),                    // 3: xray.app.router.CIDR
-	(*GeoIP)(nil),                   // 4: xray.app.router.GeoIP
-	(*GeoIPList)(nil),               // 5: xray.app.router.GeoIPList
-	(*GeoSite)(nil),                 // 6: xray.app.router.GeoSite
-	(*GeoSiteList)(nil),             // 7: xray.app.router.GeoSiteList
-	(*RoutingRule)(nil),             // 8: xray.app.router.RoutingRule
-	(*BalancingRule)(nil),           // 9: xray.app.router.BalancingRule
-	(*StrategyWeight)(nil),          // 10: xray.app.router.StrategyWeight
-	(*StrategyLeastLoadConfig)(nil), // 11: xray.app.router.StrategyLeastLoadConfig
-	(*Config)(nil),                  // 12: xray.app.router.Config
-	(*Domain_Attribute)(nil),        // 13: xray.app.router.Domain.Attribute
-	nil,                             // 14: xray.app.router.RoutingRule.AttributesEntry
-	(*net.PortList)(nil),            // 15: xray.common.net.PortList
-	(net.Network)(0),                // 16: xray.common.net.Network
-	(*serial.TypedMessage)(nil),     // 17: xray.common.serial.TypedMessage
+	(Domain_Type)(0),                      // 0: xray.app.router.Domain.Type
+	(RuleSet_Type)(0),                     // 1: xray.app.router.RuleSet.Type
+	(RuleSet_Format)(0),                   // 2: xray.app.router.RuleSet.Format
+	(StrategyConsistentHashConfig_Key)(0), // 3: xray.app.router.StrategyConsistentHashConfig.Key
+	(Config_DomainStrategy)(0),            // 4: xray.app.router.Config.DomainStrategy
+	(*Domain)(nil),                        // 5: xray.app.router.Domain
+	(*CIDR)(nil),                          // 6: xray.app.router.CIDR
+	(*GeoIP)(nil),                         // 7: xray.app.router.GeoIP
+	(*GeoIPList)(nil),                     // 8: xray.app.router.GeoIPList
+	(*GeoSite)(nil),                       // 9: xray.app.router.GeoSite
+	(*GeoSiteList)(nil),                   // 10: xray.app.router.GeoSiteList
+	(*RoutingRule)(nil),                   // 11: xray.app.router.RoutingRule
+	(*RuleSet)(nil),                       // 12: xray.app.router.RuleSet
+	(*TimeRange)(nil),                     // 13: xray.app.router.TimeRange
+	(*BalancingRule)(nil),                 // 14: xray.app.router.BalancingRule
+	(*StrategyWeight)(nil),                // 15: xray.app.router.StrategyWeight
+	(*StrategyLeastLoadConfig)(nil),       // 16: xray.app.router.StrategyLeastLoadConfig
+	(*StrategyConsistentHashConfig)(nil),  // 17: xray.app.router.StrategyConsistentHashConfig
+	(*Config)(nil),                        // 18: xray.app.router.Config
+	(*Domain_Attribute)(nil),              // 19: xray.app.router.Domain.Attribute
+	nil,                                   // 20: xray.app.router.RoutingRule.AttributesEntry
+	(*net.PortList)(nil),                  // 21: xray.common.net.PortList
+	(net.Network)(0),                      // 22: xray.common.net.Network
+	(*serial.TypedMessage)(nil),           // 23: xray.common.serial.TypedMessage
 }
 var file_app_router_config_proto_depIdxs = []int32{
 	0,  // 0: xray.app.router.Domain.type:type_name -> xray.app.router.Domain.Type
-	13, // 1: xray.app.router.Domain.attribute:type_name -> xray.app.router.Domain.Attribute
-	3,  // 2: xray.app.router.GeoIP.cidr:type_name -> xray.app.router.CIDR
-	4,  // 3: xray.app.router.GeoIPList.entry:type_name -> xray.app.router.GeoIP
-	2,  // 4: xray.app.router.GeoSite.domain:type_name -> xray.app.router.Domain
-	6,  // 5: xray.app.router.GeoSiteList.entry:type_name -> xray.app.router.GeoSite
-	2,  // 6: xray.app.router.RoutingRule.domain:type_name -> xray.app.router.Domain
-	4,  // 7: xray.app.router.RoutingRule.geoip:type_name -> xray.app.router.GeoIP
-	15, // 8: xray.app.router.RoutingRule.port_list:type_name -> xray.common.net.PortList
-	16, // 9: xray.app.router.RoutingRule.networks:type_name -> xray.common.net.Network
-	4,  // 10: xray.app.router.RoutingRule.source_geoip:type_name -> xray.app.router.GeoIP
-	15, // 11: xray.app.router.RoutingRule.source_port_list:type_name -> xray.common.net.PortList
-	14, // 12: xray.app.router.RoutingRule.attributes:type_name -> xray.app.router.RoutingRule.AttributesEntry
-	4,  // 13: xray.app.router.RoutingRule.local_geoip:type_name -> xray.app.router.GeoIP
-	15, // 14: xray.app.router.RoutingRule.local_port_list:type_name -> xray.common.net.PortList
-	15, // 15: xray.app.router.RoutingRule.vless_route_list:type_name -> xray.common.net.PortList
-	17, // 16: xray.app.router.BalancingRule.strategy_settings:type_name -> xray.common.serial.TypedMessage
-	10, // 17: xray.app.router.StrategyLeastLoadConfig.costs:type_name -> xray.app.router.StrategyWeight
-	1,  // 18: xray.app.router.Config.domain_strategy:type_name -> xray.app.router.Config.DomainStrategy
-	8,  // 19: xray.app.router.Config.rule:type_name -> xray.app.router.RoutingRule
-	9,  // 20: xray.app.router.Config.balancing_rule:type_name -> xray.app.router.BalancingRule
-	21, // [21:21] is the sub-list for method output_type
-	21, // [21:21] is the sub-list for method input_type
-	21, // [21:21] is the sub-list for extension type_name
-	21, // [21:21] is the sub-list for extension extendee
-	0,  // [0:21] is the sub-list for field type_name
+	19, // 1: xray.app.router.Domain.attribute:type_name -> xray.app.router.Domain.Attribute
+	6,  // 2: xray.app.router.GeoIP.cidr:type_name -> xray.app.router.CIDR
+	7,  // 3: xray.app.router.GeoIPList.entry:type_name -> xray.app.router.GeoIP
+	5,  // 4: xray.app.router.GeoSite.domain:type_name -> xray.app.router.Domain
+	9,  // 5: xray.app.router.GeoSiteList.entry:type_name -> xray.app.router.GeoSite
+	5,  // 6: xray.app.router.RoutingRule.domain:type_name -> xray.app.router.Domain
+	7,  // 7: xray.app.router.RoutingRule.geoip:type_name -> xray.app.router.GeoIP
+	21, // 8: xray.app.router.RoutingRule.port_list:type_name -> xray.common.net.PortList
+	22, // 9: xray.app.router.RoutingRule.networks:type_name -> xray.common.net.Network
+	7,  // 10: xray.app.router.RoutingRule.source_geoip:type_name -> xray.app.router.GeoIP
+	21, // 11: xray.app.router.RoutingRule.source_port_list:type_name -> xray.common.net.PortList
+	20, // 12: xray.app.router.RoutingRule.attributes:type_name -> xray.app.router.RoutingRule.AttributesEntry
+	7,  // 13: xray.app.router.RoutingRule.local_geoip:type_name -> xray.app.router.GeoIP
+	21, // 14: xray.app.router.RoutingRule.local_port_list:type_name -> xray.common.net.PortList
+	21, // 15: xray.app.router.RoutingRule.vless_route_list:type_name -> xray.common.net.PortList
+	13, // 16: xray.app.router.RoutingRule.time_range:type_name -> xray.app.router.TimeRange
+	1,  // 17: xray.app.router.RuleSet.type:type_name -> xray.app.router.RuleSet.Type
+	2,  // 18: xray.app.router.RuleSet.format:type_name -> xray.app.router.RuleSet.Format
+	23, // 19: xray.app.router.BalancingRule.strategy_settings:type_name -> xray.common.serial.TypedMessage
+	15, // 20: xray.app.router.StrategyLeastLoadConfig.costs:type_name -> xray.app.router.StrategyWeight
+	3,  // 21: xray.app.router.StrategyConsistentHashConfig.key:type_name -> xray.app.router.StrategyConsistentHashConfig.Key
+	4,  // 22: xray.app.router.Config.domain_strategy:type_name -> xray.app.router.Config.DomainStrategy
+	11, // 23: xray.app.router.Config.rule:type_name -> xray.app.router.RoutingRule
+	14, // 24: xray.app.router.Config.balancing_rule:type_name -> xray.app.router.BalancingRule
+	12, // 25: xray.app.router.Config.rule_set:type_name -> xray.app.router.RuleSet
+	26, // [26:26] is the sub-list for method output_type
+	26, // [26:26] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
 }
 
 func init() { file_app_router_config_proto_init() }
@@ -1195,7 +1672,7 @@ func file_app_router_config_proto_init() {
 		(*RoutingRule_Tag)(nil),
 		(*RoutingRule_BalancingTag)(nil),
 	}
-	file_app_router_config_proto_msgTypes[11].OneofWrappers = []any{
+	file_app_router_config_proto_msgTypes[14].OneofWrappers = []any{
 		(*Domain_Attribute_BoolValue)(nil),
 		(*Domain_Attribute_IntValue)(nil),
 	}
@@ -1204,8 +1681,8 @@ func file_app_router_config_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_router_config_proto_rawDesc), len(file_app_router_config_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   13,
+			NumEnums:      5,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
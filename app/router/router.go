@@ -19,6 +19,7 @@ type Router struct {
 	domainStrategy Config_DomainStrategy
 	rules          []*Rule
 	balancers      map[string]*Balancer
+	ruleSets       map[string]*RuleSetMatcher
 	dns            dns.Client
 
 	ctx        context.Context
@@ -43,21 +44,39 @@ func (r *Router) Init(ctx context.Context, config *Config, d dns.Client, ohm out
 	r.ohm = ohm
 	r.dispatcher = dispatcher
 
-	r.balancers = make(map[string]*Balancer, len(config.BalancingRule))
+	ruleSets, err := NewRuleSetMatchers(config.RuleSet)
+	if err != nil {
+		return err
+	}
+	balancers, rules, err := r.build(config, ruleSets)
+	if err != nil {
+		CloseRuleSetMatchers(ruleSets)
+		return err
+	}
+	r.balancers = balancers
+	r.rules = rules
+	r.ruleSets = ruleSets
+
+	return nil
+}
+
+// build creates the balancers and rules described by config.
+func (r *Router) build(config *Config, ruleSets map[string]*RuleSetMatcher) (map[string]*Balancer, []*Rule, error) {
+	balancers := make(map[string]*Balancer, len(config.BalancingRule))
 	for _, rule := range config.BalancingRule {
-		balancer, err := rule.Build(ohm, dispatcher)
+		balancer, err := rule.Build(r.ohm, r.dispatcher)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
-		balancer.InjectContext(ctx)
-		r.balancers[rule.Tag] = balancer
+		balancer.InjectContext(r.ctx)
+		balancers[rule.Tag] = balancer
 	}
 
-	r.rules = make([]*Rule, 0, len(config.Rule))
+	rules := make([]*Rule, 0, len(config.Rule))
 	for _, rule := range config.Rule {
-		cond, err := rule.BuildCondition()
+		cond, err := rule.BuildConditionWithRuleSets(ruleSets)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 		rr := &Rule{
 			Condition: cond,
@@ -66,16 +85,48 @@ func (r *Router) Init(ctx context.Context, config *Config, d dns.Client, ohm out
 		}
 		btag := rule.GetBalancingTag()
 		if len(btag) > 0 {
-			brule, found := r.balancers[btag]
+			brule, found := balancers[btag]
 			if !found {
-				return errors.New("balancer ", btag, " not found")
+				return nil, nil, errors.New("balancer ", btag, " not found")
 			}
 			rr.Balancer = brule
 		}
-		r.rules = append(r.rules, rr)
+		rules = append(rules, rr)
 	}
 
-	return nil
+	return balancers, rules, nil
+}
+
+// PrepareReload implements features.Reloadable.
+func (r *Router) PrepareReload(config interface{}) (func(), func(), error) {
+	c, ok := config.(*Config)
+	if !ok {
+		return nil, nil, errors.New("Reload: config type error")
+	}
+	ruleSets, err := NewRuleSetMatchers(c.RuleSet)
+	if err != nil {
+		return nil, nil, err
+	}
+	balancers, rules, err := r.build(c, ruleSets)
+	if err != nil {
+		CloseRuleSetMatchers(ruleSets)
+		return nil, nil, err
+	}
+
+	commit := func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		CloseRuleSetMatchers(r.ruleSets)
+		r.domainStrategy = c.DomainStrategy
+		r.balancers = balancers
+		r.rules = rules
+		r.ruleSets = ruleSets
+	}
+	abort := func() {
+		CloseRuleSetMatchers(ruleSets)
+	}
+	return commit, abort, nil
 }
 
 // PickRoute implements routing.Router.
@@ -84,7 +135,7 @@ func (r *Router) PickRoute(ctx routing.Context) (routing.Route, error) {
 	if err != nil {
 		return nil, err
 	}
-	tag, err := rule.GetTag()
+	tag, err := rule.GetTag(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -129,7 +180,7 @@ func (r *Router) ReloadRules(config *Config, shouldAppend bool) error {
 		if r.RuleExists(rule.GetRuleTag()) {
 			return errors.New("duplicate ruleTag ", rule.GetRuleTag())
 		}
-		cond, err := rule.BuildCondition()
+		cond, err := rule.BuildConditionWithRuleSets(r.ruleSets)
 		if err != nil {
 			return err
 		}
@@ -202,24 +253,30 @@ func (r *Router) pickRouteInternal(ctx routing.Context) (*Rule, routing.Context,
 	// this prevents cycle resolving dead loop
 	skipDNSResolve := ctx.GetSkipDNSResolve()
 
-	if r.domainStrategy == Config_IpOnDemand && !skipDNSResolve {
+	// The rules are only read here, as they are replaced rather than modified. Rule sets closed
+	// by a reload meanwhile still match, they just stop watching their files.
+	r.mu.Lock()
+	domainStrategy, rules := r.domainStrategy, r.rules
+	r.mu.Unlock()
+
+	if domainStrategy == Config_IpOnDemand && !skipDNSResolve {
 		ctx = routing_dns.ContextWithDNSClient(ctx, r.dns)
 	}
 
-	for _, rule := range r.rules {
+	for _, rule := range rules {
 		if rule.Apply(ctx) {
 			return rule, ctx, nil
 		}
 	}
 
-	if r.domainStrategy != Config_IpIfNonMatch || len(ctx.GetTargetDomain()) == 0 || skipDNSResolve {
+	if domainStrategy != Config_IpIfNonMatch || len(ctx.GetTargetDomain()) == 0 || skipDNSResolve {
 		return nil, ctx, common.ErrNoClue
 	}
 
 	ctx = routing_dns.ContextWithDNSClient(ctx, r.dns)
 
 	// Try applying rules again if we have IPs.
-	for _, rule := range r.rules {
+	for _, rule := range rules {
 		if rule.Apply(ctx) {
 			return rule, ctx, nil
 		}
@@ -235,6 +292,10 @@ func (r *Router) Start() error {
 
 // Close implements common.Closable.
 func (r *Router) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	CloseRuleSetMatchers(r.ruleSets)
 	return nil
 }
 
@@ -0,0 +1,365 @@
+package router
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/mmdb"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/platform"
+	"github.com/xtls/xray-core/common/task"
+	"github.com/xtls/xray-core/features/routing"
+	"google.golang.org/protobuf/proto"
+)
+
+// RuleSetMatcher matches domains or IPs of a rule set. The file of the rule set
+// is checked periodically, and the matcher is swapped when it changes, so the
+// rules referencing the rule set don't need to be rebuilt.
+type RuleSetMatcher struct {
+	config *RuleSet
+	path   string
+
+	domains atomic.Pointer[DomainMatcher]
+	ips     atomic.Pointer[GeoIPMatcher]
+
+	modTime  time.Time
+	size     int64
+	periodic *task.Periodic
+}
+
+// NewRuleSetMatcher loads the rule set, and starts watching its file.
+func NewRuleSetMatcher(config *RuleSet) (*RuleSetMatcher, error) {
+	if config.Type == RuleSet_Domain && config.Format == RuleSet_MMDB {
+		return nil, errors.New("MMDB rule set ", config.Name, " can only be of IPs")
+	}
+	if config.Format != RuleSet_Plain && config.Code == "" {
+		return nil, errors.New("code of rule set ", config.Name, " is not specified")
+	}
+	m := &RuleSetMatcher{
+		config: config,
+		path:   config.Path,
+	}
+	if !filepath.IsAbs(m.path) {
+		m.path = platform.GetAssetLocation(m.path)
+	}
+	if _, err := m.reload(); err != nil {
+		return nil, errors.New("failed to load rule set ", config.Name).Base(err)
+	}
+
+	interval := 10 * time.Second
+	if config.Interval > 0 {
+		interval = time.Duration(config.Interval)
+	}
+	m.periodic = &task.Periodic{
+		Interval: interval,
+		Execute: func() error {
+			if reloaded, err := m.reload(); err != nil {
+				errors.LogWarningInner(context.Background(), err, "failed to reload rule set ", config.Name, ", keeping the previous one")
+			} else if reloaded {
+				errors.LogInfo(context.Background(), "rule set ", config.Name, " reloaded from ", m.path)
+			}
+			return nil
+		},
+	}
+	return m, m.periodic.Start()
+}
+
+// Name returns the name of the rule set.
+func (m *RuleSetMatcher) Name() string {
+	return m.config.Name
+}
+
+// Type returns whether the rule set is of domains or IPs.
+func (m *RuleSetMatcher) Type() RuleSet_Type {
+	return m.config.Type
+}
+
+// reload loads the file of the rule set if it has changed.
+func (m *RuleSetMatcher) reload() (bool, error) {
+	info, err := os.Stat(m.path)
+	if err != nil {
+		return false, err
+	}
+	if info.ModTime().Equal(m.modTime) && info.Size() == m.size {
+		return false, nil
+	}
+	data, err := os.ReadFile(m.path)
+	if err != nil {
+		return false, err
+	}
+
+	switch m.config.Type {
+	case RuleSet_Domain:
+		domains, err := m.parseDomains(data)
+		if err != nil {
+			return false, err
+		}
+		matcher, err := NewMphMatcherGroup(domains)
+		if err != nil {
+			return false, err
+		}
+		m.domains.Store(matcher)
+	case RuleSet_IP:
+		cidrs, err := m.parseIPs(data)
+		if err != nil {
+			return false, err
+		}
+		matcher, err := BuildOptimizedGeoIPMatcher(&GeoIP{Cidr: cidrs})
+		if err != nil {
+			return false, err
+		}
+		m.ips.Store(&matcher)
+	default:
+		return false, errors.New("unknown type of rule set ", m.config.Type)
+	}
+	m.modTime = info.ModTime()
+	m.size = info.Size()
+	return true, nil
+}
+
+func (m *RuleSetMatcher) parseDomains(data []byte) ([]*Domain, error) {
+	switch m.config.Format {
+	case RuleSet_Plain:
+		var domains []*Domain
+		err := readLines(data, func(line string) error {
+			domain := &Domain{Type: Domain_Domain, Value: line}
+			if prefix, value, found := strings.Cut(line, ":"); found {
+				switch strings.ToLower(prefix) {
+				case "full":
+					domain.Type = Domain_Full
+				case "domain":
+					domain.Type = Domain_Domain
+				case "keyword":
+					domain.Type = Domain_Plain
+				case "regexp":
+					domain.Type = Domain_Regex
+				default:
+					return errors.New("unknown domain rule: ", line)
+				}
+				domain.Value = value
+			}
+			if domain.Type != Domain_Regex {
+				domain.Value = strings.ToLower(domain.Value)
+			}
+			domains = append(domains, domain)
+			return nil
+		})
+		return domains, err
+	case RuleSet_Dat:
+		var list GeoSiteList
+		if err := proto.Unmarshal(data, &list); err != nil {
+			return nil, err
+		}
+		for _, site := range list.Entry {
+			if strings.EqualFold(site.CountryCode, m.config.Code) {
+				return site.Domain, nil
+			}
+		}
+		return nil, errors.New("code not found: ", m.config.Code)
+	default:
+		return nil, errors.New("unsupported format of domain rule set: ", m.config.Format)
+	}
+}
+
+func (m *RuleSetMatcher) parseIPs(data []byte) ([]*CIDR, error) {
+	switch m.config.Format {
+	case RuleSet_Plain:
+		var cidrs []*CIDR
+		err := readLines(data, func(line string) error {
+			prefix, err := netip.ParsePrefix(line)
+			if err != nil {
+				addr, err := netip.ParseAddr(line)
+				if err != nil {
+					return errors.New("invalid IP: ", line)
+				}
+				prefix = netip.PrefixFrom(addr, addr.BitLen())
+			}
+			cidrs = append(cidrs, &CIDR{
+				Ip:     prefix.Addr().Unmap().AsSlice(),
+				Prefix: uint32(prefix.Bits()),
+			})
+			return nil
+		})
+		return cidrs, err
+	case RuleSet_Dat:
+		var list GeoIPList
+		if err := proto.Unmarshal(data, &list); err != nil {
+			return nil, err
+		}
+		for _, geoip := range list.Entry {
+			if strings.EqualFold(geoip.CountryCode, m.config.Code) {
+				return geoip.Cidr, nil
+			}
+		}
+		return nil, errors.New("code not found: ", m.config.Code)
+	case RuleSet_MMDB:
+		return LoadMMDBCIDRs(data, strings.ToUpper(m.config.Code))
+	default:
+		return nil, errors.New("unsupported format of IP rule set: ", m.config.Format)
+	}
+}
+
+// readLines calls f with each line of data, except empty lines and comments.
+func readLines(data []byte, f func(line string) error) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if err := f(line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// MatchDomain returns whether the domain is in the rule set.
+func (m *RuleSetMatcher) MatchDomain(domain string) bool {
+	matcher := m.domains.Load()
+	return matcher != nil && domain != "" && matcher.ApplyDomain(domain)
+}
+
+// MatchIPs returns whether any of the IPs is in the rule set.
+func (m *RuleSetMatcher) MatchIPs(ips []net.IP) bool {
+	matcher := m.ips.Load()
+	return matcher != nil && (*matcher).AnyMatch(ips)
+}
+
+// Close stops watching the file of the rule set. The rule set still matches with
+// the rules last loaded, so rules being applied while it is closed are unaffected.
+func (m *RuleSetMatcher) Close() error {
+	return m.periodic.Close()
+}
+
+// LoadMMDBCIDRs returns the networks of the country code in the MaxMind DB.
+func LoadMMDBCIDRs(data []byte, code string) ([]*CIDR, error) {
+	db, err := mmdb.New(data)
+	if err != nil {
+		return nil, errors.New("failed to load MMDB").Base(err)
+	}
+
+	// Networks of a country mostly share a few records, so only decode each of them once.
+	matched := make(map[uint]bool)
+	var cidrs []*CIDR
+	err = db.Networks(func(prefix netip.Prefix, offset uint) error {
+		match, found := matched[offset]
+		if !found {
+			record, err := db.Decode(offset)
+			if err != nil {
+				return err
+			}
+			match = slices.Contains(mmdb.CountryCodes(record), code)
+			matched[offset] = match
+		}
+		if match {
+			cidrs = append(cidrs, &CIDR{
+				Ip:     prefix.Addr().AsSlice(),
+				Prefix: uint32(prefix.Bits()),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.New("failed to read MMDB").Base(err)
+	}
+	if len(cidrs) == 0 {
+		return nil, errors.New("code not found in MMDB: ", code)
+	}
+	return cidrs, nil
+}
+
+// NewRuleSetMatchers creates the matchers of the rule sets, by their names.
+func NewRuleSetMatchers(configs []*RuleSet) (map[string]*RuleSetMatcher, error) {
+	matchers := make(map[string]*RuleSetMatcher, len(configs))
+	for _, config := range configs {
+		if _, found := matchers[config.Name]; found {
+			CloseRuleSetMatchers(matchers)
+			return nil, errors.New("duplicated rule set ", config.Name)
+		}
+		matcher, err := NewRuleSetMatcher(config)
+		if err != nil {
+			CloseRuleSetMatchers(matchers)
+			return nil, err
+		}
+		matchers[config.Name] = matcher
+	}
+	return matchers, nil
+}
+
+// CloseRuleSetMatchers stops watching the files of the rule sets.
+func CloseRuleSetMatchers(matchers map[string]*RuleSetMatcher) {
+	for _, m := range matchers {
+		m.Close()
+	}
+}
+
+// RuleSetCondition matches the target domain or IPs with rule sets, and with the
+// domains or IPs listed in the rule itself, so that they match like entries of the same list.
+type RuleSetCondition struct {
+	matchers []*RuleSetMatcher
+	ip       bool
+	static   Condition
+}
+
+// newRuleSetCondition looks up the rule sets by names, or returns nil if no names are given.
+func newRuleSetCondition(names []string, ruleSets map[string]*RuleSetMatcher, ip bool) (*RuleSetCondition, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	cond := &RuleSetCondition{ip: ip}
+	for _, name := range names {
+		m, found := ruleSets[name]
+		if !found {
+			return nil, errors.New("rule set ", name, " not found")
+		}
+		if (m.Type() == RuleSet_IP) != ip {
+			return nil, errors.New("rule set ", name, " of ", m.Type(), " can't be used here")
+		}
+		cond.matchers = append(cond.matchers, m)
+	}
+	return cond, nil
+}
+
+// Apply implements Condition.
+func (c *RuleSetCondition) Apply(ctx routing.Context) bool {
+	if c.static != nil && c.static.Apply(ctx) {
+		return true
+	}
+	if c.ip {
+		ips := ctx.GetTargetIPs()
+		if len(ips) == 0 {
+			return false
+		}
+		for _, m := range c.matchers {
+			if m.MatchIPs(ips) {
+				return true
+			}
+		}
+		return false
+	}
+	domain := ctx.GetTargetDomain()
+	if domain == "" {
+		return false
+	}
+	for _, m := range c.matchers {
+		if m.MatchDomain(domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package router_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/xtls/xray-core/app/router"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/session"
+)
+
+func TestRuleSetMatcherReload(t *testing.T) {
+	dir := t.TempDir()
+	domainPath := filepath.Join(dir, "domains.txt")
+	ipPath := filepath.Join(dir, "ips.txt")
+	common.Must(os.WriteFile(domainPath, []byte("# blocked\nexample.com\nfull:www.example.org\nkeyword:tracker\n"), 0o644))
+	common.Must(os.WriteFile(ipPath, []byte("10.0.0.0/8\n192.168.1.1\n"), 0o644))
+
+	domains, err := NewRuleSetMatcher(&RuleSet{
+		Name:     "domains",
+		Type:     RuleSet_Domain,
+		Path:     domainPath,
+		Interval: int64(10 * time.Millisecond),
+	})
+	common.Must(err)
+	defer domains.Close()
+	ips, err := NewRuleSetMatcher(&RuleSet{
+		Name:     "ips",
+		Type:     RuleSet_IP,
+		Path:     ipPath,
+		Interval: int64(10 * time.Millisecond),
+	})
+	common.Must(err)
+	defer ips.Close()
+
+	for domain, expected := range map[string]bool{
+		"example.com":     true,
+		"a.example.com":   true,
+		"www.example.org": true,
+		"example.org":     false,
+		"ad.tracker.net":  true,
+		"example.net":     false,
+	} {
+		if actual := domains.MatchDomain(domain); actual != expected {
+			t.Error("domain ", domain, ": expected ", expected, ", got ", actual)
+		}
+	}
+	if !ips.MatchIPs([]net.IP{net.ParseIP("10.1.2.3")}) || !ips.MatchIPs([]net.IP{net.ParseIP("192.168.1.1")}) {
+		t.Error("expected IPs to match")
+	}
+	if ips.MatchIPs([]net.IP{net.ParseIP("192.168.1.2")}) {
+		t.Error("unexpected IP match")
+	}
+
+	// A broken file keeps the previous rules.
+	common.Must(os.WriteFile(ipPath, []byte("not an IP\n"), 0o644))
+	time.Sleep(100 * time.Millisecond)
+	if !ips.MatchIPs([]net.IP{net.ParseIP("10.1.2.3")}) {
+		t.Error("expected previous IPs to be kept")
+	}
+
+	common.Must(os.WriteFile(domainPath, []byte("example.net\n"), 0o644))
+	common.Must(os.WriteFile(ipPath, []byte("172.16.0.0/12\n"), 0o644))
+	later := time.Now().Add(time.Minute)
+	common.Must(os.Chtimes(domainPath, later, later))
+	common.Must(os.Chtimes(ipPath, later, later))
+	deadline := time.Now().Add(5 * time.Second)
+	for !domains.MatchDomain("example.net") || !ips.MatchIPs([]net.IP{net.ParseIP("172.16.1.1")}) {
+		if time.Now().After(deadline) {
+			t.Fatal("rule sets are not reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if domains.MatchDomain("example.com") || ips.MatchIPs([]net.IP{net.ParseIP("10.1.2.3")}) {
+		t.Error("expected previous rules to be replaced")
+	}
+}
+
+func TestRuleSetCondition(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "domains.txt")
+	common.Must(os.WriteFile(path, []byte("example.com\n"), 0o644))
+
+	ruleSets, err := NewRuleSetMatchers([]*RuleSet{{Name: "blocked", Type: RuleSet_Domain, Path: path}})
+	common.Must(err)
+	defer CloseRuleSetMatchers(ruleSets)
+
+	rule := &RoutingRule{DomainRuleSet: []string{"blocked"}}
+	cond, err := rule.BuildConditionWithRuleSets(ruleSets)
+	common.Must(err)
+	if !cond.Apply(withOutbound(&session.Outbound{Target: net.TCPDestination(net.DomainAddress("www.example.com"), 80)})) {
+		t.Error("expected rule set to match")
+	}
+	if cond.Apply(withOutbound(&session.Outbound{Target: net.TCPDestination(net.DomainAddress("example.org"), 80)})) {
+		t.Error("unexpected rule set match")
+	}
+
+	if _, err := (&RoutingRule{DomainRuleSet: []string{"unknown"}}).BuildConditionWithRuleSets(ruleSets); err == nil {
+		t.Error("expected error for unknown rule set")
+	}
+	if _, err := (&RoutingRule{IpRuleSet: []string{"blocked"}}).BuildConditionWithRuleSets(ruleSets); err == nil {
+		t.Error("expected error for rule set of domains used for IPs")
+	}
+}
+
+func TestRuleSetConditionWithListedRules(t *testing.T) {
+	dir := t.TempDir()
+	domainPath := filepath.Join(dir, "domains.txt")
+	ipPath := filepath.Join(dir, "ips.txt")
+	common.Must(os.WriteFile(domainPath, []byte("example.com\n"), 0o644))
+	common.Must(os.WriteFile(ipPath, []byte("10.0.0.0/8\n"), 0o644))
+
+	ruleSets, err := NewRuleSetMatchers([]*RuleSet{
+		{Name: "domains", Type: RuleSet_Domain, Path: domainPath},
+		{Name: "ips", Type: RuleSet_IP, Path: ipPath},
+	})
+	common.Must(err)
+	defer CloseRuleSetMatchers(ruleSets)
+
+	domainRule := &RoutingRule{
+		Domain:        []*Domain{{Type: Domain_Domain, Value: "example.org"}},
+		DomainRuleSet: []string{"domains"},
+	}
+	cond, err := domainRule.BuildConditionWithRuleSets(ruleSets)
+	common.Must(err)
+	for domain, expected := range map[string]bool{
+		"www.example.com": true,
+		"www.example.org": true,
+		"example.net":     false,
+	} {
+		ctx := withOutbound(&session.Outbound{Target: net.TCPDestination(net.DomainAddress(domain), 80)})
+		if actual := cond.Apply(ctx); actual != expected {
+			t.Error("domain ", domain, ": expected ", expected, ", got ", actual)
+		}
+	}
+
+	ipRule := &RoutingRule{
+		Geoip:     []*GeoIP{{Cidr: []*CIDR{{Ip: []byte{192, 168, 0, 0}, Prefix: 16}}}},
+		IpRuleSet: []string{"ips"},
+	}
+	cond, err = ipRule.BuildConditionWithRuleSets(ruleSets)
+	common.Must(err)
+	for ip, expected := range map[string]bool{
+		"10.1.2.3":    true,
+		"192.168.1.1": true,
+		"172.16.1.1":  false,
+	} {
+		ctx := withOutbound(&session.Outbound{Target: net.TCPDestination(net.ParseAddress(ip), 80)})
+		if actual := cond.Apply(ctx); actual != expected {
+			t.Error("IP ", ip, ": expected ", expected, ", got ", actual)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package router
+
+import (
+	"context"
+	"hash/fnv"
+
+	"github.com/xtls/xray-core/app/observatory"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/extension"
+	"github.com/xtls/xray-core/features/routing"
+)
+
+// ConsistentHashStrategy picks outbounds by rendezvous hashing of a key of
+// connections, so that connections of the same key go to the same outbound.
+// When an outbound is dead, only the connections picking it are moved to others.
+type ConsistentHashStrategy struct {
+	settings *StrategyConsistentHashConfig
+
+	ctx         context.Context
+	observatory extension.Observatory
+}
+
+// NewConsistentHashStrategy creates a new ConsistentHashStrategy with settings
+func NewConsistentHashStrategy(settings *StrategyConsistentHashConfig) *ConsistentHashStrategy {
+	return &ConsistentHashStrategy{
+		settings: settings,
+	}
+}
+
+func (s *ConsistentHashStrategy) InjectContext(ctx context.Context) {
+	s.ctx = ctx
+	common.Must(core.OptionalFeatures(s.ctx, func(observatory extension.Observatory) error {
+		s.observatory = observatory
+		return nil
+	}))
+}
+
+func (s *ConsistentHashStrategy) GetPrincipleTarget(tags []string) []string {
+	return tags
+}
+
+// PickOutbound implements BalancingStrategy, for connections without a key.
+func (s *ConsistentHashStrategy) PickOutbound(tags []string) string {
+	return s.PickOutboundWithContext(nil, tags)
+}
+
+// PickOutboundWithContext implements BalancingContextStrategy.
+func (s *ConsistentHashStrategy) PickOutboundWithContext(ctx routing.Context, tags []string) string {
+	key := s.key(ctx)
+	var picked string
+	var max uint64
+	for _, tag := range s.aliveTags(tags) {
+		if score := rendezvousHash(key, tag); picked == "" || score > max {
+			picked = tag
+			max = score
+		}
+	}
+	return picked
+}
+
+// key returns the key of the connection to hash.
+func (s *ConsistentHashStrategy) key(ctx routing.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	switch s.settings.GetKey() {
+	case StrategyConsistentHashConfig_Email:
+		if user := ctx.GetUser(); user != "" {
+			return user
+		}
+	case StrategyConsistentHashConfig_Domain:
+		if domain := ctx.GetTargetDomain(); domain != "" {
+			return domain
+		}
+		if ips := ctx.GetTargetIPs(); len(ips) > 0 {
+			return ips[0].String()
+		}
+		return ""
+	}
+	if ips := ctx.GetSourceIPs(); len(ips) > 0 {
+		return ips[0].String()
+	}
+	return ""
+}
+
+// aliveTags filters out the outbounds which are dead in the observatory.
+func (s *ConsistentHashStrategy) aliveTags(tags []string) []string {
+	if s.observatory == nil {
+		return tags
+	}
+	observeReport, err := s.observatory.GetObservation(s.ctx)
+	if err != nil {
+		return tags
+	}
+	result, ok := observeReport.(*observatory.ObservationResult)
+	if !ok {
+		return tags
+	}
+	dead := make(map[string]bool)
+	for _, status := range result.Status {
+		if !status.Alive {
+			dead[status.OutboundTag] = true
+		}
+	}
+	alive := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		// unfound candidate is considered alive
+		if !dead[tag] {
+			alive = append(alive, tag)
+		}
+	}
+	return alive
+}
+
+// rendezvousHash returns the score of the tag for the key.
+func rendezvousHash(key, tag string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	h.Write([]byte{0})
+	h.Write([]byte(tag))
+	// Mix the bits, as FNV of similar inputs are close to each other.
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
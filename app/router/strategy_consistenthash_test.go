@@ -0,0 +1,76 @@
+package router
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/common/session"
+	routing_session "github.com/xtls/xray-core/features/routing/session"
+)
+
+func TestConsistentHashStrategy(t *testing.T) {
+	s := NewConsistentHashStrategy(&StrategyConsistentHashConfig{Key: StrategyConsistentHashConfig_SourceIP})
+	tags := []string{"a", "b", "c", "d"}
+	withSource := func(i int) *routing_session.Context {
+		return &routing_session.Context{Inbound: &session.Inbound{
+			Source: net.TCPDestination(net.ParseAddress("10.0.0."+strconv.Itoa(i)), 1234),
+		}}
+	}
+
+	picked := make(map[int]string)
+	counts := make(map[string]int)
+	for i := 0; i < 200; i++ {
+		tag := s.PickOutboundWithContext(withSource(i), tags)
+		if again := s.PickOutboundWithContext(withSource(i), tags); again != tag {
+			t.Fatal("source ", i, " picked ", tag, " and then ", again)
+		}
+		picked[i] = tag
+		counts[tag]++
+	}
+	for _, tag := range tags {
+		if counts[tag] < 20 {
+			t.Error("outbound ", tag, " is picked only ", counts[tag], " times")
+		}
+	}
+
+	// Only sources of the removed outbound are moved.
+	remaining := []string{"a", "c", "d"}
+	for i := 0; i < 200; i++ {
+		tag := s.PickOutboundWithContext(withSource(i), remaining)
+		if picked[i] != "b" && tag != picked[i] {
+			t.Error("source ", i, " moved from ", picked[i], " to ", tag)
+		}
+		if tag == "b" {
+			t.Error("source ", i, " picked removed outbound")
+		}
+	}
+}
+
+func TestConsistentHashStrategyKey(t *testing.T) {
+	ctx := &routing_session.Context{
+		Inbound: &session.Inbound{
+			Source: net.TCPDestination(net.ParseAddress("10.0.0.1"), 1234),
+			User:   &protocol.MemoryUser{Email: "love@xray.com"},
+		},
+		Outbound: &session.Outbound{Target: net.TCPDestination(net.ParseAddress("example.com"), 443)},
+	}
+	for key, expected := range map[StrategyConsistentHashConfig_Key]string{
+		StrategyConsistentHashConfig_SourceIP: "10.0.0.1",
+		StrategyConsistentHashConfig_Email:    "love@xray.com",
+		StrategyConsistentHashConfig_Domain:   "example.com",
+	} {
+		s := NewConsistentHashStrategy(&StrategyConsistentHashConfig{Key: key})
+		if actual := s.key(ctx); actual != expected {
+			t.Error("key ", key, ": expected ", expected, ", got ", actual)
+		}
+	}
+
+	// Fall back to the source IP without a user.
+	s := NewConsistentHashStrategy(&StrategyConsistentHashConfig{Key: StrategyConsistentHashConfig_Email})
+	ctx.Inbound.User = nil
+	if actual := s.key(ctx); actual != "10.0.0.1" {
+		t.Error("expected source IP, got ", actual)
+	}
+}
@@ -120,6 +120,18 @@ func (m *Manager) GetOnlineMap(name string) stats.OnlineMap {
 	return nil
 }
 
+// VisitOnlineMaps calls visitor function on all managed onlinemaps.
+func (m *Manager) VisitOnlineMaps(visitor func(string, stats.OnlineMap) bool) {
+	m.access.RLock()
+	defer m.access.RUnlock()
+
+	for name, om := range m.onlineMap {
+		if !visitor(name, om) {
+			break
+		}
+	}
+}
+
 // RegisterChannel implements stats.Manager.
 func (m *Manager) RegisterChannel(name string) (stats.Channel, error) {
 	m.access.Lock()
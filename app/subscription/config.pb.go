@@ -0,0 +1,204 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.5
+// source: app/subscription/config.proto
+
+package subscription
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SubscriptionConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Outbounds of the subscription are tagged with the prefix followed by their names.
+	TagPrefix string `protobuf:"bytes,1,opt,name=tag_prefix,json=tagPrefix,proto3" json:"tag_prefix,omitempty"`
+	// URL of http or https, or path of a local file. The document is a list of
+	// share links, which may be encoded in base64 as a whole, or outbounds in JSON.
+	Url string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// Interval to update the subscription, in nanoseconds. Default 1 hour.
+	Interval int64 `protobuf:"varint,3,opt,name=interval,proto3" json:"interval,omitempty"`
+	// Tag of the outbound to fetch the subscription through, if not empty.
+	Outbound      string `protobuf:"bytes,4,opt,name=outbound,proto3" json:"outbound,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscriptionConfig) Reset() {
+	*x = SubscriptionConfig{}
+	mi := &file_app_subscription_config_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionConfig) ProtoMessage() {}
+
+func (x *SubscriptionConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_app_subscription_config_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionConfig.ProtoReflect.Descriptor instead.
+func (*SubscriptionConfig) Descriptor() ([]byte, []int) {
+	return file_app_subscription_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SubscriptionConfig) GetTagPrefix() string {
+	if x != nil {
+		return x.TagPrefix
+	}
+	return ""
+}
+
+func (x *SubscriptionConfig) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *SubscriptionConfig) GetInterval() int64 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+func (x *SubscriptionConfig) GetOutbound() string {
+	if x != nil {
+		return x.Outbound
+	}
+	return ""
+}
+
+type Config struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscriptions []*SubscriptionConfig  `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	mi := &file_app_subscription_config_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_app_subscription_config_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_app_subscription_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Config) GetSubscriptions() []*SubscriptionConfig {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+var File_app_subscription_config_proto protoreflect.FileDescriptor
+
+const file_app_subscription_config_proto_rawDesc = "" +
+	"\n" +
+	"\x1dapp/subscription/config.proto\x12\x15xray.app.subscription\"}\n" +
+	"\x12SubscriptionConfig\x12\x1d\n" +
+	"\n" +
+	"tag_prefix\x18\x01 \x01(\tR\ttagPrefix\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x1a\n" +
+	"\binterval\x18\x03 \x01(\x03R\binterval\x12\x1a\n" +
+	"\boutbound\x18\x04 \x01(\tR\boutbound\"Y\n" +
+	"\x06Config\x12O\n" +
+	"\rsubscriptions\x18\x01 \x03(\v2).xray.app.subscription.SubscriptionConfigR\rsubscriptionsBa\n" +
+	"\x19com.xray.app.subscriptionP\x01Z*github.com/xtls/xray-core/app/subscription\xaa\x02\x15Xray.App.Subscriptionb\x06proto3"
+
+var (
+	file_app_subscription_config_proto_rawDescOnce sync.Once
+	file_app_subscription_config_proto_rawDescData []byte
+)
+
+func file_app_subscription_config_proto_rawDescGZIP() []byte {
+	file_app_subscription_config_proto_rawDescOnce.Do(func() {
+		file_app_subscription_config_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_app_subscription_config_proto_rawDesc), len(file_app_subscription_config_proto_rawDesc)))
+	})
+	return file_app_subscription_config_proto_rawDescData
+}
+
+var file_app_subscription_config_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_app_subscription_config_proto_goTypes = []any{
+	(*SubscriptionConfig)(nil), // 0: xray.app.subscription.SubscriptionConfig
+	(*Config)(nil),             // 1: xray.app.subscription.Config
+}
+var file_app_subscription_config_proto_depIdxs = []int32{
+	0, // 0: xray.app.subscription.Config.subscriptions:type_name -> xray.app.subscription.SubscriptionConfig
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_app_subscription_config_proto_init() }
+func file_app_subscription_config_proto_init() {
+	if File_app_subscription_config_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_subscription_config_proto_rawDesc), len(file_app_subscription_config_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_app_subscription_config_proto_goTypes,
+		DependencyIndexes: file_app_subscription_config_proto_depIdxs,
+		MessageInfos:      file_app_subscription_config_proto_msgTypes,
+	}.Build()
+	File_app_subscription_config_proto = out.File
+	file_app_subscription_config_proto_goTypes = nil
+	file_app_subscription_config_proto_depIdxs = nil
+}
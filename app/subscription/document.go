@@ -0,0 +1,110 @@
+package subscription
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/core"
+)
+
+// LinkConverter converts a share link to an outbound, in the JSON of config files.
+type LinkConverter func(link string) (json.RawMessage, error)
+
+var linkConverter LinkConverter
+
+// RegisterLinkConverter registers the converter of share links in subscriptions.
+// It is registered by infra/conf/sharelink, which can't be imported by apps.
+func RegisterLinkConverter(converter LinkConverter) {
+	linkConverter = converter
+}
+
+// outboundsJSON converts a subscription document to outbounds in JSON.
+func outboundsJSON(ctx context.Context, document []byte) ([]json.RawMessage, error) {
+	document = bytes.TrimSpace(document)
+	if len(document) == 0 {
+		return nil, errors.New("empty subscription")
+	}
+
+	switch document[0] {
+	case '[':
+		var outbounds []json.RawMessage
+		if err := json.Unmarshal(document, &outbounds); err != nil {
+			return nil, errors.New("invalid outbounds in subscription").Base(err)
+		}
+		return outbounds, nil
+	case '{':
+		var config struct {
+			Outbounds []json.RawMessage `json:"outbounds"`
+		}
+		if err := json.Unmarshal(document, &config); err != nil {
+			return nil, errors.New("invalid outbounds in subscription").Base(err)
+		}
+		return config.Outbounds, nil
+	}
+
+	if !bytes.Contains(document, []byte("://")) {
+		s := strings.Join(strings.Fields(string(document)), "")
+		decoded, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			decoded, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+		}
+		if err != nil {
+			return nil, errors.New("subscription is neither share links nor outbounds").Base(err)
+		}
+		document = decoded
+	}
+	if linkConverter == nil {
+		return nil, errors.New("share links are not supported")
+	}
+
+	var outbounds []json.RawMessage
+	scanner := bufio.NewScanner(bytes.NewReader(document))
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		link := strings.TrimSpace(scanner.Text())
+		if link == "" {
+			continue
+		}
+		outbound, err := linkConverter(link)
+		if err != nil {
+			// Subscriptions may have links of unsupported protocols, or comments.
+			errors.LogInfoInner(ctx, err, "ignoring share link in subscription")
+			continue
+		}
+		outbounds = append(outbounds, outbound)
+	}
+	return outbounds, scanner.Err()
+}
+
+// parseDocument returns the outbounds of a subscription document, tagged with the prefix.
+func parseDocument(ctx context.Context, document []byte, prefix string) ([]*core.OutboundHandlerConfig, error) {
+	outbounds, err := outboundsJSON(ctx, document)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(map[string]interface{}{"outbounds": outbounds})
+	if err != nil {
+		return nil, err
+	}
+	config, err := core.LoadConfig("json", bytes.NewReader(b))
+	if err != nil {
+		return nil, errors.New("failed to load outbounds of subscription").Base(err)
+	}
+
+	tags := make(map[string]bool, len(config.Outbound))
+	for i, outbound := range config.Outbound {
+		tag := prefix + outbound.Tag
+		if outbound.Tag == "" || tags[tag] {
+			tag = prefix + strconv.Itoa(i)
+		}
+		tags[tag] = true
+		outbound.Tag = tag
+	}
+	return config.Outbound, nil
+}
@@ -0,0 +1,224 @@
+package subscription
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	v2net "github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/task"
+	"github.com/xtls/xray-core/common/utils"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/outbound"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/transport/internet/tagged"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxDocumentSize is the limit of the size of subscription documents.
+const maxDocumentSize = 16 * 1024 * 1024
+
+// Manager keeps the outbounds of subscriptions up to date.
+type Manager struct {
+	ctx        context.Context
+	instance   *core.Instance
+	ohm        outbound.Manager
+	dispatcher routing.Dispatcher
+
+	subscriptions []*subscription
+}
+
+// subscription is a subscription and the outbounds added from it.
+type subscription struct {
+	config   *SubscriptionConfig
+	periodic *task.Periodic
+	// first is the timer of the first update.
+	first *time.Timer
+
+	access    sync.Mutex
+	outbounds map[string]*core.OutboundHandlerConfig
+}
+
+// New creates a Manager.
+func New(ctx context.Context, config *Config) (*Manager, error) {
+	m := &Manager{
+		ctx:      ctx,
+		instance: core.MustFromContext(ctx),
+	}
+	for _, c := range config.Subscriptions {
+		if c.Url == "" {
+			return nil, errors.New("subscription URL is not specified")
+		}
+		m.subscriptions = append(m.subscriptions, &subscription{
+			config:    c,
+			outbounds: make(map[string]*core.OutboundHandlerConfig),
+		})
+	}
+	if err := core.RequireFeatures(ctx, func(om outbound.Manager, d routing.Dispatcher) {
+		m.ohm = om
+		m.dispatcher = d
+	}); err != nil {
+		return nil, errors.New("failed to get depended features").Base(err)
+	}
+	return m, nil
+}
+
+// Type implements common.HasType.
+func (*Manager) Type() interface{} {
+	return (*Manager)(nil)
+}
+
+// Start implements common.Runnable.
+func (m *Manager) Start() error {
+	for _, s := range m.subscriptions {
+		s := s
+		interval := time.Hour
+		if s.config.Interval > 0 {
+			interval = time.Duration(s.config.Interval)
+		}
+		update := func() {
+			if err := m.update(s); err != nil {
+				errors.LogWarningInner(m.ctx, err, "failed to update subscription ", s.config.Url)
+			}
+		}
+		started := false
+		s.periodic = &task.Periodic{
+			Interval: interval,
+			Execute: func() error {
+				// The first run is inline in Start. Fetching may take long, and may depend on outbounds
+				// that are not started yet, so the first update runs later.
+				if !started {
+					started = true
+					s.first = time.AfterFunc(0, update)
+					return nil
+				}
+				update()
+				// Keep updating, the outbounds of the last update are kept until then.
+				return nil
+			},
+		}
+		if err := s.periodic.Start(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements common.Closable.
+func (m *Manager) Close() error {
+	for _, s := range m.subscriptions {
+		if s.periodic != nil {
+			s.periodic.Close()
+		}
+		if s.first != nil {
+			s.first.Stop()
+		}
+	}
+	return nil
+}
+
+// update fetches the subscription and applies its outbounds.
+func (m *Manager) update(s *subscription) error {
+	document, err := m.fetch(s.config)
+	if err != nil {
+		return err
+	}
+	outbounds, err := parseDocument(m.ctx, document, s.config.TagPrefix)
+	if err != nil {
+		return err
+	}
+	m.apply(s, outbounds)
+	return nil
+}
+
+// apply adds the new and changed outbounds of the subscription, and removes the stale ones.
+func (m *Manager) apply(s *subscription, outbounds []*core.OutboundHandlerConfig) {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	latest := make(map[string]*core.OutboundHandlerConfig, len(outbounds))
+	for _, c := range outbounds {
+		latest[c.Tag] = c
+	}
+	for tag, c := range s.outbounds {
+		if n, found := latest[tag]; found && proto.Equal(c, n) {
+			continue
+		}
+		if err := m.ohm.RemoveHandler(m.ctx, tag); err != nil {
+			errors.LogWarningInner(m.ctx, err, "failed to remove outbound ", tag)
+		}
+		delete(s.outbounds, tag)
+		errors.LogInfo(m.ctx, "outbound ", tag, " of subscription removed")
+	}
+	for _, c := range outbounds {
+		if _, found := s.outbounds[c.Tag]; found {
+			continue
+		}
+		if err := core.AddOutboundHandler(m.instance, c); err != nil {
+			errors.LogWarningInner(m.ctx, err, "failed to add outbound ", c.Tag, " of subscription")
+			continue
+		}
+		s.outbounds[c.Tag] = c
+		errors.LogInfo(m.ctx, "outbound ", c.Tag, " of subscription added")
+	}
+}
+
+// fetch returns the document of the subscription.
+func (m *Manager) fetch(config *SubscriptionConfig) ([]byte, error) {
+	if !strings.HasPrefix(config.Url, "http://") && !strings.HasPrefix(config.Url, "https://") {
+		f, err := os.Open(strings.TrimPrefix(config.Url, "file://"))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return io.ReadAll(io.LimitReader(f, maxDocumentSize))
+	}
+
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		TLSHandshakeTimeout: time.Second * 10,
+	}
+	if config.Outbound != "" {
+		transport.Proxy = nil
+		transport.DialContext = func(ctx context.Context, network string, addr string) (net.Conn, error) {
+			dest, err := v2net.ParseDestination(network + ":" + addr)
+			if err != nil {
+				return nil, errors.New("cannot understand address").Base(err)
+			}
+			return tagged.Dialer(m.ctx, m.dispatcher, dest, config.Outbound)
+		}
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   time.Minute,
+	}
+	defer transport.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(m.ctx, http.MethodGet, config.Url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", utils.ChromeUA)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("unexpected status ", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxDocumentSize))
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return New(ctx, config.(*Config))
+	}))
+}
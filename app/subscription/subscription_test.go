@@ -0,0 +1,94 @@
+package subscription_test
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/app/dispatcher"
+	"github.com/xtls/xray-core/app/proxyman"
+	. "github.com/xtls/xray-core/app/subscription"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/outbound"
+	_ "github.com/xtls/xray-core/main/distro/all"
+)
+
+const (
+	vlessLink  = "vless://27848739-7e62-4138-9fd3-098a63964b6b@example.com:443?security=tls&sni=example.com#a"
+	trojanLink = "trojan://password@example.com:443#b"
+)
+
+// waitOutbounds waits until the outbounds of the tags are present, and the others are absent.
+func waitOutbounds(t *testing.T, ohm outbound.Manager, present []string, absent []string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		ok := true
+		for _, tag := range present {
+			if ohm.GetHandler(tag) == nil {
+				ok = false
+			}
+		}
+		for _, tag := range absent {
+			if ohm.GetHandler(tag) != nil {
+				ok = false
+			}
+		}
+		if ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("unexpected outbounds, expect ", present, " but not ", absent)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestSubscriptionOutbounds(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "subscription")
+	document := strings.Join([]string{vlessLink, trojanLink, "socks://unsupported"}, "\n")
+	common.Must(os.WriteFile(file, []byte(base64.StdEncoding.EncodeToString([]byte(document))), 0o600))
+
+	config := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&Config{
+				Subscriptions: []*SubscriptionConfig{
+					{
+						TagPrefix: "sub-",
+						Url:       file,
+						Interval:  int64(100 * time.Millisecond),
+					},
+				},
+			}),
+		},
+	}
+	instance, err := core.New(config)
+	common.Must(err)
+	common.Must(instance.Start())
+	defer instance.Close()
+
+	ohm := instance.GetFeature(outbound.ManagerType()).(outbound.Manager)
+	waitOutbounds(t, ohm, []string{"sub-a", "sub-b"}, nil)
+
+	// Stale outbounds are removed, and the others are kept.
+	handler := ohm.GetHandler("sub-a")
+	common.Must(os.WriteFile(file, []byte(`{"outbounds": [{"tag": "a", "protocol": "vless", "settings": {"address": "example.com", "port": 443, "id": "27848739-7e62-4138-9fd3-098a63964b6b", "encryption": "none"}, "streamSettings": {"security": "tls", "tlsSettings": {"serverName": "example.com"}}}, {"protocol": "freedom"}]}`), 0o600))
+	waitOutbounds(t, ohm, []string{"sub-a", "sub-1"}, []string{"sub-b"})
+	if ohm.GetHandler("sub-a") != handler {
+		t.Error("unchanged outbound is replaced")
+	}
+
+	// Outbounds are kept if the subscription is broken.
+	common.Must(os.WriteFile(file, []byte("{"), 0o600))
+	time.Sleep(300 * time.Millisecond)
+	waitOutbounds(t, ohm, []string{"sub-a", "sub-1"}, nil)
+}
@@ -11,6 +11,9 @@ type Lru interface {
 	GetKeyFromValue(value interface{}) (key interface{}, ok bool)
 	PeekKeyFromValue(value interface{}) (key interface{}, ok bool) // Peek means check but NOT bring to top
 	Put(key, value interface{})
+	Delete(key interface{}) bool
+	// Range calls f for each entry from the least recently used one, until f returns false.
+	Range(f func(key, value interface{}) bool)
 }
 
 type lru struct {
@@ -87,3 +90,32 @@ func (l *lru) Put(key, value interface{}) {
 	}
 	l.mu.Unlock()
 }
+
+func (l *lru) Delete(key interface{}) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	v, ok := l.keyToElement.Load(key)
+	if !ok {
+		return false
+	}
+	element := v.(*list.Element)
+	l.doubleLinkedlist.Remove(element)
+	l.keyToElement.Delete(key)
+	l.valueToElement.Delete(element.Value.(*lruElement).value)
+	return true
+}
+
+func (l *lru) Range(f func(key, value interface{}) bool) {
+	l.mu.Lock()
+	elements := make([]*lruElement, 0, l.doubleLinkedlist.Len())
+	for e := l.doubleLinkedlist.Back(); e != nil; e = e.Prev() {
+		elements = append(elements, e.Value.(*lruElement))
+	}
+	l.mu.Unlock()
+
+	for _, e := range elements {
+		if !f(e.key, e.value) {
+			return
+		}
+	}
+}
@@ -84,3 +84,29 @@ func TestPeekKeyFromValue(t *testing.T) {
 		t.Error("should get 2", v)
 	}
 }
+
+func TestLruDeleteAndRange(t *testing.T) {
+	lru := NewLru(3)
+	lru.Put(1, 10)
+	lru.Put(2, 20)
+	lru.Put(3, 30)
+	lru.Get(1)
+	if !lru.Delete(2) {
+		t.Error("should delete 2")
+	}
+	if lru.Delete(2) {
+		t.Error("should not delete 2 again")
+	}
+	if _, ok := lru.GetKeyFromValue(20); ok {
+		t.Error("should not get key of deleted value")
+	}
+
+	var keys []interface{}
+	lru.Range(func(key, value interface{}) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if len(keys) != 2 || keys[0] != 3 || keys[1] != 1 {
+		t.Error("should range from the least recently used", keys)
+	}
+}
@@ -2,7 +2,9 @@ package log
 
 import (
 	"context"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/xtls/xray-core/common/serial"
 )
@@ -18,6 +20,7 @@ type AccessStatus string
 const (
 	AccessAccepted = AccessStatus("accepted")
 	AccessRejected = AccessStatus("rejected")
+	AccessClosed   = AccessStatus("closed")
 )
 
 type AccessMessage struct {
@@ -27,6 +30,19 @@ type AccessMessage struct {
 	Reason interface{}
 	Email  string
 	Detour string
+
+	// The following fields are only written in JSON format.
+	InboundTag  string
+	OutboundTag string
+	// Domain and Protocol are the results of sniffing.
+	Domain   string
+	Protocol string
+	RuleTag  string
+
+	// Traffic and duration of the connection, only for closed connections.
+	Uplink   uint64
+	Downlink uint64
+	Duration time.Duration
 }
 
 func (m *AccessMessage) String() string {
@@ -55,6 +71,15 @@ func (m *AccessMessage) String() string {
 		builder.WriteString(m.Email)
 	}
 
+	if m.Status == AccessClosed {
+		builder.WriteString(" uplink: ")
+		builder.WriteString(strconv.FormatUint(m.Uplink, 10))
+		builder.WriteString(" downlink: ")
+		builder.WriteString(strconv.FormatUint(m.Downlink, 10))
+		builder.WriteString(" duration: ")
+		builder.WriteString(m.Duration.Round(time.Millisecond).String())
+	}
+
 	return builder.String()
 }
 
@@ -0,0 +1,148 @@
+package log
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/xtls/xray-core/common/serial"
+)
+
+// JSONMessage formats a message as a single line JSON object, with the time it is recorded.
+type JSONMessage struct {
+	Message Message
+	Time    time.Time
+	// Mask, if set, is applied to all fields but the time, e.g. to hide IP addresses.
+	Mask func(string) string
+}
+
+type accessJSON struct {
+	Time        string  `json:"time"`
+	Type        string  `json:"type"`
+	InboundTag  string  `json:"inbound_tag,omitempty"`
+	OutboundTag string  `json:"outbound_tag,omitempty"`
+	Source      string  `json:"source,omitempty"`
+	Destination string  `json:"destination,omitempty"`
+	Domain      string  `json:"domain,omitempty"`
+	Protocol    string  `json:"protocol,omitempty"`
+	RuleTag     string  `json:"rule_tag,omitempty"`
+	Email       string  `json:"email,omitempty"`
+	Status      string  `json:"status"`
+	Reason      string  `json:"reason,omitempty"`
+	Detour      string  `json:"detour,omitempty"`
+	Uplink      *uint64 `json:"uplink,omitempty"`
+	Downlink    *uint64 `json:"downlink,omitempty"`
+	DurationMs  *int64  `json:"duration_ms,omitempty"`
+}
+
+type generalJSON struct {
+	Time    string `json:"time"`
+	Type    string `json:"type"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+type dnsJSON struct {
+	Time    string   `json:"time"`
+	Type    string   `json:"type"`
+	Server  string   `json:"server"`
+	Domain  string   `json:"domain"`
+	Status  string   `json:"status"`
+	Result  []string `json:"result"`
+	Elapsed string   `json:"elapsed,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+type otherJSON struct {
+	Time    string `json:"time"`
+	Message string `json:"message"`
+}
+
+// String implements Message.
+func (m *JSONMessage) String() string {
+	ts := m.Time.Format(time.RFC3339Nano)
+
+	var v interface{}
+	switch msg := m.Message.(type) {
+	case *AccessMessage:
+		a := &accessJSON{
+			Time:        ts,
+			Type:        "access",
+			InboundTag:  msg.InboundTag,
+			OutboundTag: msg.OutboundTag,
+			Source:      serial.ToString(msg.From),
+			Destination: serial.ToString(msg.To),
+			Domain:      msg.Domain,
+			Protocol:    msg.Protocol,
+			RuleTag:     msg.RuleTag,
+			Email:       msg.Email,
+			Status:      string(msg.Status),
+			Reason:      serial.ToString(msg.Reason),
+			Detour:      msg.Detour,
+		}
+		if msg.Status == AccessClosed {
+			durationMs := msg.Duration.Milliseconds()
+			a.Uplink, a.Downlink, a.DurationMs = &msg.Uplink, &msg.Downlink, &durationMs
+		}
+		v = a
+	case *GeneralMessage:
+		v = &generalJSON{
+			Time:    ts,
+			Type:    "error",
+			Level:   strings.ToLower(msg.Severity.String()),
+			Message: serial.ToString(msg.Content),
+		}
+	case *DNSLog:
+		d := &dnsJSON{
+			Time:   ts,
+			Type:   "dns",
+			Server: msg.Server,
+			Domain: msg.Domain,
+			Status: strings.TrimSuffix(string(msg.Status), ":"),
+			Result: make([]string, 0, len(msg.Result)),
+		}
+		for _, ip := range msg.Result {
+			d.Result = append(d.Result, ip.String())
+		}
+		if msg.Elapsed > 0 {
+			d.Elapsed = msg.Elapsed.String()
+		}
+		if msg.Error != nil {
+			d.Error = msg.Error.Error()
+		}
+		v = d
+	default:
+		v = &otherJSON{
+			Time:    ts,
+			Message: m.Message.String(),
+		}
+	}
+	if m.Mask != nil {
+		maskFields(reflect.ValueOf(v).Elem(), m.Mask)
+	}
+
+	// Detours are like "in -> out", keep them readable.
+	b := &strings.Builder{}
+	encoder := json.NewEncoder(b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return m.Message.String()
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// maskFields applies mask to the string fields of v, which is one of the structs above.
+// The first field, which is the time, is skipped.
+func maskFields(v reflect.Value, mask func(string) string) {
+	for i := 1; i < v.NumField(); i++ {
+		switch f := v.Field(i); f.Kind() {
+		case reflect.String:
+			f.SetString(mask(f.String()))
+		case reflect.Slice:
+			for j := 0; j < f.Len(); j++ {
+				f.Index(j).SetString(mask(f.Index(j).String()))
+			}
+		}
+	}
+}
@@ -144,24 +144,40 @@ func (w *fileLogWriter) Close() error {
 
 // CreateStdoutLogWriter returns a LogWriterCreator that creates LogWriter for stdout.
 func CreateStdoutLogWriter() WriterCreator {
-	return func() Writer {
-		return &consoleLogWriter{
-			logger: log.New(os.Stdout, "", log.Ldate|log.Ltime|log.Lmicroseconds),
-		}
-	}
+	return createConsoleLogWriter(os.Stdout, log.Ldate|log.Ltime|log.Lmicroseconds)
 }
 
 // CreateStderrLogWriter returns a LogWriterCreator that creates LogWriter for stderr.
 func CreateStderrLogWriter() WriterCreator {
+	return createConsoleLogWriter(os.Stderr, log.Ldate|log.Ltime|log.Lmicroseconds)
+}
+
+// CreatePlainStdoutLogWriter returns a LogWriterCreator that creates LogWriter for stdout,
+// which writes the messages as is, without timestamp prefix.
+func CreatePlainStdoutLogWriter() WriterCreator {
+	return createConsoleLogWriter(os.Stdout, 0)
+}
+
+func createConsoleLogWriter(out io.Writer, flag int) WriterCreator {
 	return func() Writer {
 		return &consoleLogWriter{
-			logger: log.New(os.Stderr, "", log.Ldate|log.Ltime|log.Lmicroseconds),
+			logger: log.New(out, "", flag),
 		}
 	}
 }
 
 // CreateFileLogWriter returns a LogWriterCreator that creates LogWriter for the given file.
 func CreateFileLogWriter(path string) (WriterCreator, error) {
+	return createFileLogWriter(path, log.Ldate|log.Ltime|log.Lmicroseconds)
+}
+
+// CreatePlainFileLogWriter returns a LogWriterCreator that creates LogWriter for the given file,
+// which writes the messages as is, without timestamp prefix.
+func CreatePlainFileLogWriter(path string) (WriterCreator, error) {
+	return createFileLogWriter(path, 0)
+}
+
+func createFileLogWriter(path string, flag int) (WriterCreator, error) {
 	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
 	if err != nil {
 		return nil, err
@@ -174,7 +190,7 @@ func CreateFileLogWriter(path string) (WriterCreator, error) {
 		}
 		return &fileLogWriter{
 			file:   file,
-			logger: log.New(file, "", log.Ldate|log.Ltime|log.Lmicroseconds),
+			logger: log.New(file, "", flag),
 		}
 	}, nil
 }
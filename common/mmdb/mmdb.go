@@ -0,0 +1,371 @@
+// Package mmdb reads IP databases in the MaxMind DB format, such as GeoLite2-Country
+// and the sing-geoip databases.
+package mmdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"net/netip"
+	"strings"
+
+	"github.com/xtls/xray-core/common/errors"
+)
+
+var metadataStartMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// Metadata is the description of a database.
+type Metadata struct {
+	DatabaseType string
+	IPVersion    uint
+	NodeCount    uint
+	RecordSize   uint
+}
+
+// Reader reads a database loaded in memory.
+type Reader struct {
+	Metadata Metadata
+
+	tree          []byte
+	data          decoder
+	nodeBytes     uint
+	ipv4Start     uint
+	ipv4StartBits int
+}
+
+// New parses the database in data.
+func New(data []byte) (*Reader, error) {
+	i := bytes.LastIndex(data, metadataStartMarker)
+	if i < 0 {
+		return nil, errors.New("invalid MaxMind DB: metadata not found")
+	}
+	meta, _, err := decoder{buf: data[i+len(metadataStartMarker):]}.decode(0, 0)
+	if err != nil {
+		return nil, errors.New("invalid MaxMind DB metadata").Base(err)
+	}
+	m, ok := meta.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid MaxMind DB metadata")
+	}
+
+	r := &Reader{}
+	r.Metadata.DatabaseType, _ = m["database_type"].(string)
+	r.Metadata.IPVersion = toUint(m["ip_version"])
+	r.Metadata.NodeCount = toUint(m["node_count"])
+	r.Metadata.RecordSize = toUint(m["record_size"])
+
+	switch r.Metadata.RecordSize {
+	case 24, 28, 32:
+	default:
+		return nil, errors.New("unsupported MaxMind DB record size: ", r.Metadata.RecordSize)
+	}
+	if r.Metadata.IPVersion != 4 && r.Metadata.IPVersion != 6 {
+		return nil, errors.New("unsupported MaxMind DB IP version: ", r.Metadata.IPVersion)
+	}
+	r.nodeBytes = r.Metadata.RecordSize / 4
+	treeSize := r.Metadata.NodeCount * r.nodeBytes
+	if treeSize+16 > uint(i) {
+		return nil, errors.New("invalid MaxMind DB: search tree is too large")
+	}
+	r.tree = data[:treeSize]
+	r.data = decoder{buf: data[treeSize+16 : i]}
+
+	if r.Metadata.IPVersion == 6 {
+		node := uint(0)
+		for r.ipv4StartBits = 0; r.ipv4StartBits < 96 && node < r.Metadata.NodeCount; r.ipv4StartBits++ {
+			node = r.record(node, 0)
+		}
+		r.ipv4Start = node
+	}
+	return r, nil
+}
+
+func toUint(v interface{}) uint {
+	switch v := v.(type) {
+	case uint64:
+		return uint(v)
+	case uint32:
+		return uint(v)
+	case uint16:
+		return uint(v)
+	}
+	return 0
+}
+
+// record returns the left (bit 0) or right (bit 1) record of the node.
+func (r *Reader) record(node uint, bit int) uint {
+	b := r.tree[node*r.nodeBytes : (node+1)*r.nodeBytes]
+	switch r.Metadata.RecordSize {
+	case 24:
+		b = b[bit*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		return uint(binary.BigEndian.Uint32(b[bit*4:]))
+	}
+}
+
+type walkNode struct {
+	node uint
+	ip   [16]byte
+	bits int
+}
+
+// Networks calls visit with every network in the database and the offset of its record.
+// IPv4 networks are reported once in their IPv4 form, even if they are aliased in the IPv6 space.
+func (r *Reader) Networks(visit func(prefix netip.Prefix, offset uint) error) error {
+	maxBits := 32
+	if r.Metadata.IPVersion == 6 {
+		maxBits = 128
+	}
+	stack := []walkNode{{}}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if n.node == r.ipv4Start && r.Metadata.IPVersion == 6 && r.ipv4StartBits == 96 &&
+			(n.bits != 96 || n.ip != [16]byte{}) {
+			// Aliases of the IPv4 space, like ::ffff:0:0/96 and 2002::/16.
+			continue
+		}
+
+		for bit := 1; bit >= 0; bit-- {
+			child := walkNode{node: r.record(n.node, bit), ip: n.ip, bits: n.bits + 1}
+			if bit == 1 {
+				child.ip[n.bits/8] |= 0x80 >> (n.bits % 8)
+			}
+			switch {
+			case child.node < r.Metadata.NodeCount:
+				if child.bits >= maxBits {
+					return errors.New("invalid MaxMind DB: search tree is too deep")
+				}
+				stack = append(stack, child)
+			case child.node == r.Metadata.NodeCount:
+				// Empty.
+			default:
+				offset := child.node - r.Metadata.NodeCount - 16
+				if err := visit(r.prefix(child.ip, child.bits), offset); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Reader) prefix(ip [16]byte, bits int) netip.Prefix {
+	if r.Metadata.IPVersion == 4 {
+		return netip.PrefixFrom(netip.AddrFrom4([4]byte(ip[:4])), bits)
+	}
+	if bits >= 96 && [12]byte(ip[:12]) == [12]byte{} {
+		return netip.PrefixFrom(netip.AddrFrom4([4]byte(ip[12:])), bits-96)
+	}
+	return netip.PrefixFrom(netip.AddrFrom16(ip), bits)
+}
+
+// Decode returns the record at the given offset of the data section. Maps are decoded to
+// map[string]interface{}, and arrays to []interface{}.
+func (r *Reader) Decode(offset uint) (interface{}, error) {
+	v, _, err := r.data.decode(offset, 0)
+	return v, err
+}
+
+// CountryCodes returns the upper-case country codes in a record. It understands the
+// records of MaxMind country and city databases, which are maps with country.iso_code, and
+// those of sing-geoip and Meta-geoip databases, which are strings or lists of strings.
+func CountryCodes(record interface{}) []string {
+	switch v := record.(type) {
+	case string:
+		return []string{strings.ToUpper(v)}
+	case []interface{}:
+		var codes []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				codes = append(codes, strings.ToUpper(s))
+			}
+		}
+		return codes
+	case map[string]interface{}:
+		for _, key := range []string{"country", "registered_country"} {
+			if c, ok := v[key].(map[string]interface{}); ok {
+				if code, ok := c["iso_code"].(string); ok && code != "" {
+					return []string{strings.ToUpper(code)}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+const (
+	typeExtended = iota
+	typePointer
+	typeString
+	typeFloat64
+	typeBytes
+	typeUint16
+	typeUint32
+	typeMap
+	typeInt32
+	typeUint64
+	typeUint128
+	typeSlice
+	typeContainer
+	typeEndMarker
+	typeBool
+	typeFloat32
+)
+
+const maxDepth = 32
+
+var errInvalidData = errors.New("invalid MaxMind DB data")
+
+type decoder struct {
+	buf []byte
+}
+
+func (d decoder) bytes(offset, size uint) ([]byte, error) {
+	if offset+size > uint(len(d.buf)) || offset+size < offset {
+		return nil, errInvalidData
+	}
+	return d.buf[offset : offset+size], nil
+}
+
+func (d decoder) uint(offset, size uint) (uint64, error) {
+	b, err := d.bytes(offset, size)
+	if err != nil || size > 8 {
+		return 0, errInvalidData
+	}
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v, nil
+}
+
+// decode decodes the value at offset, and returns it with the offset of the next value.
+func (d decoder) decode(offset uint, depth int) (interface{}, uint, error) {
+	if depth > maxDepth {
+		return nil, 0, errInvalidData
+	}
+	ctrl, err := d.bytes(offset, 1)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset++
+	typ := uint(ctrl[0] >> 5)
+
+	if typ == typePointer {
+		ss := uint(ctrl[0]>>3) & 0x3
+		p, err := d.uint(offset, ss+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		offset += ss + 1
+		switch ss {
+		case 0:
+			p |= uint64(ctrl[0]&0x7) << 8
+		case 1:
+			p = p | uint64(ctrl[0]&0x7)<<16 + 2048
+		case 2:
+			p = p | uint64(ctrl[0]&0x7)<<24 + 526336
+		}
+		v, _, err := d.decode(uint(p), depth+1)
+		return v, offset, err
+	}
+
+	if typ == typeExtended {
+		t, err := d.bytes(offset, 1)
+		if err != nil {
+			return nil, 0, err
+		}
+		offset++
+		typ = uint(t[0]) + 7
+	}
+
+	size := uint(ctrl[0] & 0x1f)
+	if size >= 29 {
+		n := size - 28
+		s, err := d.uint(offset, n)
+		if err != nil {
+			return nil, 0, err
+		}
+		offset += n
+		switch n {
+		case 1:
+			size = 29 + uint(s)
+		case 2:
+			size = 285 + uint(s)
+		default:
+			size = 65821 + uint(s)
+		}
+	}
+
+	switch typ {
+	case typeString:
+		b, err := d.bytes(offset, size)
+		return string(b), offset + size, err
+	case typeBytes:
+		b, err := d.bytes(offset, size)
+		return b, offset + size, err
+	case typeFloat64:
+		v, err := d.uint(offset, size)
+		return math.Float64frombits(v), offset + size, err
+	case typeFloat32:
+		v, err := d.uint(offset, size)
+		return math.Float32frombits(uint32(v)), offset + size, err
+	case typeUint16:
+		v, err := d.uint(offset, size)
+		return uint16(v), offset + size, err
+	case typeUint32:
+		v, err := d.uint(offset, size)
+		return uint32(v), offset + size, err
+	case typeInt32:
+		v, err := d.uint(offset, size)
+		return int32(v), offset + size, err
+	case typeUint64:
+		v, err := d.uint(offset, size)
+		return v, offset + size, err
+	case typeUint128:
+		b, err := d.bytes(offset, size)
+		return b, offset + size, err
+	case typeBool:
+		return size != 0, offset, nil
+	case typeMap:
+		m := make(map[string]interface{}, size)
+		for i := uint(0); i < size; i++ {
+			k, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, 0, errInvalidData
+			}
+			v, next, err := d.decode(next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			m[key] = v
+			offset = next
+		}
+		return m, offset, nil
+	case typeSlice:
+		s := make([]interface{}, 0, min(size, 64))
+		for i := uint(0); i < size; i++ {
+			v, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			s = append(s, v)
+			offset = next
+		}
+		return s, offset, nil
+	default:
+		return nil, 0, errInvalidData
+	}
+}
@@ -0,0 +1,163 @@
+package mmdb_test
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"slices"
+	"testing"
+
+	"github.com/xtls/xray-core/common"
+	. "github.com/xtls/xray-core/common/mmdb"
+)
+
+type testNode struct {
+	child [2]*testNode
+	data  int
+}
+
+func (n *testNode) insert(prefix netip.Prefix, data int) {
+	ip := prefix.Addr().As16()
+	bits := prefix.Bits()
+	if prefix.Addr().Is4() {
+		// IPv4 networks are in ::/96 of IPv6 databases.
+		ip = [16]byte{}
+		copy(ip[12:], prefix.Addr().AsSlice())
+		bits += 96
+	}
+	for i := 0; i < bits; i++ {
+		bit := ip[i/8] >> (7 - i%8) & 1
+		if n.child[bit] == nil {
+			n.child[bit] = &testNode{data: -1}
+		}
+		n = n.child[bit]
+	}
+	n.data = data
+}
+
+func (n *testNode) find(prefix netip.Prefix) *testNode {
+	ip := prefix.Addr().As16()
+	for i := 0; i < prefix.Bits(); i++ {
+		n = n.child[ip[i/8]>>(7-i%8)&1]
+	}
+	return n
+}
+
+func str(s string) []byte {
+	return append([]byte{2<<5 | byte(len(s))}, s...)
+}
+
+func uint16Value(v uint16) []byte {
+	return []byte{5<<5 | 2, byte(v >> 8), byte(v)}
+}
+
+// buildDatabase builds an IPv6 database with 24 bits records, where data are the offsets of
+// the records of the networks in the data section.
+func buildDatabase(root *testNode, dataSection []byte) []byte {
+	var nodes []*testNode
+	index := make(map[*testNode]int)
+	queue := []*testNode{root}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if _, found := index[n]; found || n.data >= 0 {
+			continue
+		}
+		index[n] = len(nodes)
+		nodes = append(nodes, n)
+		for _, c := range n.child {
+			if c != nil {
+				queue = append(queue, c)
+			}
+		}
+	}
+
+	var tree []byte
+	for _, n := range nodes {
+		for _, c := range n.child {
+			v := len(nodes)
+			if c != nil && c.data >= 0 {
+				v = len(nodes) + 16 + c.data
+			} else if c != nil {
+				v = index[c]
+			}
+			tree = append(tree, byte(v>>16), byte(v>>8), byte(v))
+		}
+	}
+
+	db := append(tree, make([]byte, 16)...)
+	db = append(db, dataSection...)
+	db = append(db, "\xAB\xCD\xEFMaxMind.com"...)
+	db = append(db, 7<<5|4)
+	db = append(db, str("node_count")...)
+	db = append(db, 6<<5|4)
+	db = binary.BigEndian.AppendUint32(db, uint32(len(nodes)))
+	db = append(db, str("record_size")...)
+	db = append(db, uint16Value(24)...)
+	db = append(db, str("ip_version")...)
+	db = append(db, uint16Value(6)...)
+	db = append(db, str("database_type")...)
+	db = append(db, str("test")...)
+	return db
+}
+
+func TestNetworks(t *testing.T) {
+	var data []byte
+	maxmindRecord := len(data)
+	data = append(data, 7<<5|1)
+	data = append(data, str("country")...)
+	data = append(data, 7<<5|1)
+	data = append(data, str("iso_code")...)
+	data = append(data, str("CN")...)
+	pointerRecord := len(data)
+	data = append(data, 1<<5, byte(maxmindRecord))
+	stringRecord := len(data)
+	data = append(data, str("us")...)
+
+	root := &testNode{data: -1}
+	root.insert(netip.MustParsePrefix("1.0.0.0/8"), maxmindRecord)
+	root.insert(netip.MustParsePrefix("2.2.0.0/16"), pointerRecord)
+	root.insert(netip.MustParsePrefix("2001:db8::/32"), stringRecord)
+	// ::ffff:0:0/96 is an alias of the IPv4 space.
+	root.insert(netip.MustParsePrefix("::ffff:0:0/96"), 0)
+	alias := root.find(netip.MustParsePrefix("::ffff:0:0/95"))
+	alias.child[1] = root.find(netip.MustParsePrefix("::/96"))
+
+	db, err := New(buildDatabase(root, data))
+	common.Must(err)
+	if db.Metadata.DatabaseType != "test" || db.Metadata.IPVersion != 6 || db.Metadata.RecordSize != 24 {
+		t.Fatal("unexpected metadata ", db.Metadata)
+	}
+
+	codes := make(map[netip.Prefix][]string)
+	common.Must(db.Networks(func(prefix netip.Prefix, offset uint) error {
+		if _, found := codes[prefix]; found {
+			t.Error("network reported twice: ", prefix)
+		}
+		record, err := db.Decode(offset)
+		if err != nil {
+			return err
+		}
+		codes[prefix] = CountryCodes(record)
+		return nil
+	}))
+
+	expected := map[netip.Prefix][]string{
+		netip.MustParsePrefix("1.0.0.0/8"):     {"CN"},
+		netip.MustParsePrefix("2.2.0.0/16"):    {"CN"},
+		netip.MustParsePrefix("2001:db8::/32"): {"US"},
+	}
+	if len(codes) != len(expected) {
+		t.Error("unexpected networks ", codes)
+	}
+	for prefix, code := range expected {
+		if !slices.Equal(codes[prefix], code) {
+			t.Error("expect ", code, " for ", prefix, ", got ", codes[prefix])
+		}
+	}
+}
+
+func TestInvalidDatabase(t *testing.T) {
+	if _, err := New([]byte("not a database")); err == nil {
+		t.Error("expect error for invalid database")
+	}
+}
@@ -54,3 +54,22 @@ func CopyFile(dst string, src string) error {
 	_, err = f.Write(bytes)
 	return err
 }
+
+// WriteFileAtomic writes data to a temporary file and renames it to the path,
+// so that the file is never left partially written.
+func WriteFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+)
+
+// ParseUnixURL parses a URL like http+unix:///path/to/socket.sock/api/endpoint, and returns the path of
+// the socket and the URL of the endpoint to be requested through it.
+func ParseUnixURL(target string) (socketPath, url string, err error) {
+	path := strings.TrimPrefix(target, "http+unix://")
+	if !strings.HasPrefix(path, "/") {
+		return "", "", errors.New("unix socket path must be absolute")
+	}
+	sockIdx := strings.Index(path, ".sock")
+	if sockIdx == -1 {
+		return "", "", errors.New("cannot determine socket path, socket file should have .sock extension")
+	}
+	socketPath, httpPath := path[:sockIdx+5], path[sockIdx+5:]
+	if httpPath == "" {
+		httpPath = "/"
+	}
+	if _, err := os.Stat(socketPath); err != nil {
+		return "", "", errors.New("socket file not found: ", socketPath).Base(err)
+	}
+	return socketPath, "http://localhost" + httpPath, nil
+}
+
+// NewUnixClient returns an HTTP client sending all requests through the unix socket.
+func NewUnixClient(socketPath string, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+}
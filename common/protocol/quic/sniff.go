@@ -18,6 +18,7 @@ import (
 
 type SniffHeader struct {
 	domain string
+	hello  *ptls.SniffHeader
 }
 
 func (s SniffHeader) Protocol() string {
@@ -28,6 +29,26 @@ func (s SniffHeader) Domain() string {
 	return s.domain
 }
 
+// ALPN returns the protocols in the ALPN extension of the TLS ClientHello.
+func (s SniffHeader) ALPN() []string {
+	return s.hello.ALPN()
+}
+
+// TLSVersion returns the highest TLS version offered by the TLS ClientHello.
+func (s SniffHeader) TLSVersion() string {
+	return s.hello.TLSVersion()
+}
+
+// JA3 returns the JA3 fingerprint of the TLS ClientHello.
+func (s SniffHeader) JA3() string {
+	return s.hello.JA3()
+}
+
+// JA4 returns the JA4 fingerprint of the TLS ClientHello.
+func (s SniffHeader) JA4() string {
+	return s.hello.JA4()
+}
+
 const (
 	versionDraft29 uint32 = 0xff00001d
 	version1       uint32 = 0x1
@@ -252,7 +273,7 @@ func SniffQUIC(b []byte) (*SniffHeader, error) {
 			}
 		}
 
-		tlsHdr := &ptls.SniffHeader{}
+		tlsHdr := ptls.NewQUICSniffHeader()
 		err = ptls.ReadClientHello(cryptoDataBuf.BytesRange(0, cryptoLen), tlsHdr)
 		if err != nil {
 			// The crypto data may have not been fully recovered in current packets,
@@ -260,7 +281,7 @@ func SniffQUIC(b []byte) (*SniffHeader, error) {
 			b = restPayload
 			continue
 		}
-		return &SniffHeader{domain: tlsHdr.Domain()}, nil
+		return &SniffHeader{domain: tlsHdr.Domain(), hello: tlsHdr}, nil
 	}
 	// All payload is parsed as valid QUIC packets, but we need more packets for crypto data to read client hello.
 	return nil, protocol.ErrProtoNeedMoreData
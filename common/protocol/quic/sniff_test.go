@@ -1,13 +1,21 @@
 package quic_test
 
 import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/apernet/quic-go/quicvarint"
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/protocol"
 	"github.com/xtls/xray-core/common/protocol/quic"
+	"golang.org/x/crypto/hkdf"
 )
 
 func TestSniffQUIC(t *testing.T) {
@@ -17,6 +25,9 @@ func TestSniffQUIC(t *testing.T) {
 	if err != nil || quicHdr.Domain() != "www.google.com" {
 		t.Error("failed")
 	}
+	if ja4 := quicHdr.JA4(); !strings.HasPrefix(ja4, "q13d") || quicHdr.TLSVersion() != "1.3" {
+		t.Error("unexpected JA4 ", ja4, " of TLS ", quicHdr.TLSVersion())
+	}
 }
 
 func TestSniffQUICComplex(t *testing.T) {
@@ -258,6 +269,96 @@ func TestSniffQUICIncompleteServerName(t *testing.T) {
 	}
 }
 
+// tlsExtension encodes a TLS extension.
+func tlsExtension(typ uint16, body []byte) []byte {
+	b := binary.BigEndian.AppendUint16(nil, typ)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(body)))
+	return append(b, body...)
+}
+
+// clientHello encodes a TLS 1.3 ClientHello handshake message with the given extensions.
+func clientHello(extensions ...[]byte) []byte {
+	body := []byte{0x03, 0x03}
+	body = append(body, make([]byte, 32)...)    // random
+	body = append(body, 0x00)                   // session id
+	body = append(body, 0x00, 0x02, 0x13, 0x01) // cipher suites
+	body = append(body, 0x01, 0x00)             // compression methods
+	ext := slices.Concat(extensions...)
+	body = binary.BigEndian.AppendUint16(body, uint16(len(ext)))
+	body = append(body, ext...)
+	return append([]byte{0x01, 0x00, byte(len(body) >> 8), byte(len(body))}, body...)
+}
+
+func expandLabel(secret []byte, label string, length int) []byte {
+	info := binary.BigEndian.AppendUint16(nil, uint16(length))
+	info = append(info, byte(6+len(label)))
+	info = append(info, "tls13 "+label...)
+	info = append(info, 0x00)
+	out := make([]byte, length)
+	common.Must2(hkdf.Expand(crypto.SHA256.New, secret, info).Read(out))
+	return out
+}
+
+// sealInitial encodes a QUIC v1 Initial packet of the client, carrying the given crypto data at offset.
+func sealInitial(destConnID []byte, pn byte, offset int, data []byte) []byte {
+	payload := []byte{0x06}
+	payload = quicvarint.Append(payload, uint64(offset))
+	payload = quicvarint.Append(payload, uint64(len(data)))
+	payload = append(payload, data...)
+	payload = append(payload, make([]byte, 32)...) // PADDING
+
+	hdr := []byte{0xc0, 0x00, 0x00, 0x00, 0x01, byte(len(destConnID))}
+	hdr = append(hdr, destConnID...)
+	hdr = append(hdr, 0x00, 0x00) // source connection id and token
+	hdr = quicvarint.AppendWithLen(hdr, uint64(1+len(payload)+16), 2)
+	pnOffset := len(hdr)
+	hdr = append(hdr, pn)
+
+	salt, _ := hex.DecodeString("38762cf7f55934b34d179ae6a4c80cadccbb7f0a")
+	secret := expandLabel(hkdf.Extract(crypto.SHA256.New, destConnID, salt), "client in", crypto.SHA256.Size())
+	aead := quic.AEADAESGCMTLS13(expandLabel(secret, "quic key", 16), expandLabel(secret, "quic iv", 12))
+	nonce := make([]byte, aead.NonceSize())
+	nonce[len(nonce)-1] = pn
+	pkt := aead.Seal(hdr, nonce, payload, hdr)
+
+	block, err := aes.NewCipher(expandLabel(secret, "quic hp", 16))
+	common.Must(err)
+	mask := make([]byte, block.BlockSize())
+	block.Encrypt(mask, pkt[pnOffset+4:])
+	pkt[0] ^= mask[0] & 0x0f
+	pkt[pnOffset] ^= mask[1]
+	return pkt
+}
+
+func TestSniffQUICSplitClientHello(t *testing.T) {
+	alpn := []byte{0x00, 0x09, 0x02, 'h', '3', 0x05, 'h', '3', '-', '2', '9'}
+	hello := clientHello(
+		tlsExtension(0x00, []byte{0x00, 0x0e, 0x00, 0x00, 0x0b, 'e', 'x', 'a', 'm', 'p', 'l', 'e', '.', 'c', 'o', 'm'}),
+		tlsExtension(0x10, alpn),
+		tlsExtension(0x2b, []byte{0x02, 0x03, 0x04}),
+	)
+	// The crypto frames arrive out of order, and the one in the middle of ALPN is missing at first.
+	gapStart := bytes.Index(hello, []byte("\x05h3-29"))
+	gapEnd := gapStart + 6
+	dcid := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	packets := [][]byte{
+		sealInitial(dcid, 0, 0, hello[:gapStart]),
+		sealInitial(dcid, 1, gapEnd, hello[gapEnd:]),
+		sealInitial(dcid, 2, gapStart, hello[gapStart:gapEnd]),
+	}
+
+	if _, err := quic.SniffQUIC(slices.Concat(packets[:2]...)); !errors.Is(err, protocol.ErrProtoNeedMoreData) {
+		t.Fatal("expect to need more data for partial client hello, but got ", err)
+	}
+	quicHdr, err := quic.SniffQUIC(slices.Concat(packets...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quicHdr.Domain() != "example.com" || !slices.Equal(quicHdr.ALPN(), []string{"h3", "h3-29"}) {
+		t.Error("unexpected client hello ", quicHdr.Domain(), quicHdr.ALPN())
+	}
+}
+
 func TestSniffQUICPacketNumberLength4(t *testing.T) {
 	// packetNumberLength = 4
 	pkt, err := hex.DecodeString("c60000000108fa6cc47e912693590000449e5ea65fc129945b27f59f0b18f737a53e44f322681ecec36beeb943293db0ed751bd86413a840f9b9e9be718f3e2dba1100a8bab3a21e1541580ffd98e79aa89fa723e8d3a46f7876504c82b9ef3a081b0f8cb551370d9801ee86da77f09eec5aa19b7bf580a80ded3c9c83378285177115fd30b350c2a596ae265b3b538a81c183c0cfd13eabecfbbeb38416a5b19259731b838842c0eb33e646b9bb1f672043e90de33c3442151ee8db7d9cd66238f769f4486432ac28785a5083c616539f8320321060f64f9a0dc6af718754d645892397ff32956c4c1c97d0d9e44cdfa8d1a0ad90c3bbb7810b2196d638fd772a172a9510ea12ef12fe4050c5678851be26ec6ea6ab11824cc86ce071d110f72816166c01622c0207e9d97f8867ec7c63149e974c5a81db9cb5e0061cff2713538daa1c9ad1382ab7d883ecc85158dc76587793b258b4b0aded3f4c12b515a9183ee419b304cf748fc321f15b3f80cc53da1b889d1ac06b996d35e8d01306885851ad253083f37d0d588c9f619da25f6eb8360b846bc26913af616e2c3eabce9dbb61f7dc96b6dcb79e19905ac9ba8f3938d03f8a3647403dc919f37bb585a0d67b7ce955547d15c82eed6d94b04dfa009eaf8b30448e1450043c48845acb4fefcf29bdd55ec14e395d0e8cd8400ffdda5a58747c6e8a66d0dc5fb25f3615081b2b546e004079573e99290f5daf72705ca495707468dd26d94c7f04d7e6f89d8148ecbab67c8c0062984e0ba02539527370a2a157a58eab342ad671641812ed35b4a52ba07d244d9b5d64e29f012133d21fa4afa31645c21f6d836ad937bb75f7177768b5f94bb77e95aaf9a85f8fb7e599d482724f694cf5d7d3f61bfca892794bdcb3de7a5f321db8120560bc32b8839c0a5994917c151cc6bd4c1614c5f117e637c19dab7cff28c4848c3b328eb97e49cefded2d44a824f2705807770c2ef9dd07f0fe0198659ad062e1889e280e5f3d1c52a92ef27d4565ebae9b9a18a803b70f38e5db237ed99583d8952c79492e35e1f1c41664f1f45566126a7ec44a90004b015b893aa805fcd772737fb8dbbe7af56b9eac288ef6cab7cbb6f7a3c0b29a43bc84b6280def0f7d727b3238cba3eda2e2d110de87ad0f10e25a60783cdb0c05116df5359b19b40007812b898d03dc1d697690761856d785b83ac95778db69c3df7a8f0e092ee6ed2c9c189ebe40734b02cee2d02599e931d4cc560d38a7ec355b9f339b932613ee18f8162e8d3cb81301bfc6d726b7c26ec96d5edcaf0de171563482ed2f2de3001dbca2aee1029fdaece4340fd2d5ae8333819d5ece7c9d3f77f99a81fccd1fbcc3ea585c1538e0363141e0fd20338a193695377987afacd0baf1f0dce11b4bbf29965109bddb508e8a0974c08906d4ea340d51af376c3dda55bf97e3ba5d688533980e12704679bb18d0ef4ddd5b3af1b7676528c4bf4a84c84d40892715c0a8808ad51d6ebcc6469da708d6953e9ddcfbd19bae90e9b078f1b6641401b979304b0ef52b1441e1797ed366bb0519ca8bf9c6eba72518647d0a1400ca66a20fdd8e3ff06ee52c199bd8f941b1722a0bbf8c15447452788ba81a68431f735d99e8a80691ef64d1bf470350c8878aed3e2421223d0ba6a3d84928c8e6db0972263df9da49b8f5")
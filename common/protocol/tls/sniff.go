@@ -1,8 +1,15 @@
 package tls
 
 import (
+	"crypto/md5"
+	"crypto/sha256"
 	"encoding/binary"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/protocol"
@@ -10,6 +17,21 @@ import (
 
 type SniffHeader struct {
 	domain string
+
+	quic                bool
+	legacyVersion       uint16
+	versions            []uint16
+	cipherSuites        []uint16
+	extensions          []uint16
+	curves              []uint16
+	pointFormats        []uint8
+	signatureAlgorithms []uint16
+	alpn                []string
+}
+
+// NewQUICSniffHeader returns a SniffHeader for the ClientHello in QUIC Initial packets.
+func NewQUICSniffHeader() *SniffHeader {
+	return &SniffHeader{quic: true}
 }
 
 func (h *SniffHeader) Protocol() string {
@@ -20,6 +42,157 @@ func (h *SniffHeader) Domain() string {
 	return h.domain
 }
 
+// ALPN returns the protocols in the ALPN extension of the ClientHello.
+func (h *SniffHeader) ALPN() []string {
+	return h.alpn
+}
+
+// version returns the highest TLS version offered by the ClientHello.
+func (h *SniffHeader) version() uint16 {
+	var version uint16
+	for _, v := range h.versions {
+		if !isGREASE(v) && v > version {
+			version = v
+		}
+	}
+	if version == 0 {
+		version = h.legacyVersion
+	}
+	return version
+}
+
+// TLSVersion returns the highest TLS version offered by the ClientHello, like "1.3", or "ssl3".
+func (h *SniffHeader) TLSVersion() string {
+	switch v := h.version(); v {
+	case 0x0300:
+		return "ssl3"
+	case 0x0301, 0x0302, 0x0303, 0x0304:
+		return "1." + strconv.Itoa(int(v-0x0301))
+	default:
+		return ""
+	}
+}
+
+// JA3 returns the JA3 fingerprint of the ClientHello, which is an MD5 hash in hex.
+// See https://github.com/salesforce/ja3.
+func (h *SniffHeader) JA3() string {
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(int(h.legacyVersion)))
+	for _, list := range [][]uint16{h.cipherSuites, h.extensions, h.curves} {
+		b.WriteByte(',')
+		first := true
+		for _, v := range list {
+			if isGREASE(v) {
+				continue
+			}
+			if !first {
+				b.WriteByte('-')
+			}
+			first = false
+			b.WriteString(strconv.Itoa(int(v)))
+		}
+	}
+	b.WriteByte(',')
+	for i, v := range h.pointFormats {
+		if i > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteString(strconv.Itoa(int(v)))
+	}
+	sum := md5.Sum([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// JA4 returns the JA4 fingerprint of the ClientHello, like "t13d1516h2_8daaf6152771_e5627efa2ab1".
+// See https://github.com/FoxIO-LLC/ja4.
+func (h *SniffHeader) JA4() string {
+	var b strings.Builder
+	if h.quic {
+		b.WriteByte('q')
+	} else {
+		b.WriteByte('t')
+	}
+	switch v := h.version(); v {
+	case 0x0300:
+		b.WriteString("s3")
+	case 0x0301, 0x0302, 0x0303, 0x0304:
+		b.WriteString("1" + strconv.Itoa(int(v-0x0301)))
+	default:
+		b.WriteString("00")
+	}
+	if h.domain != "" {
+		b.WriteByte('d')
+	} else {
+		b.WriteByte('i')
+	}
+	cipherSuites := withoutGREASE(h.cipherSuites)
+	extensions := withoutGREASE(h.extensions)
+	fmt.Fprintf(&b, "%02d%02d", min(len(cipherSuites), 99), min(len(extensions), 99))
+	if len(h.alpn) > 0 && h.alpn[0] != "" {
+		alpn := h.alpn[0]
+		first, last := alpn[0], alpn[len(alpn)-1]
+		if isAlphanumeric(first) && isAlphanumeric(last) {
+			b.WriteByte(first)
+			b.WriteByte(last)
+		} else {
+			b.WriteByte(hex.EncodeToString([]byte{first})[0])
+			b.WriteByte(hex.EncodeToString([]byte{last})[1])
+		}
+	} else {
+		b.WriteString("00")
+	}
+
+	slices.Sort(cipherSuites)
+	b.WriteByte('_')
+	b.WriteString(truncatedHash(hexList(cipherSuites), len(cipherSuites) == 0))
+
+	// SNI and ALPN are not in the hash of extensions, as they are in the first part.
+	extensions = slices.DeleteFunc(extensions, func(v uint16) bool { return v == 0x0000 || v == 0x0010 })
+	slices.Sort(extensions)
+	s := hexList(extensions)
+	if algorithms := withoutGREASE(h.signatureAlgorithms); len(algorithms) > 0 {
+		s += "_" + hexList(algorithms)
+	}
+	b.WriteByte('_')
+	b.WriteString(truncatedHash(s, len(extensions) == 0))
+	return b.String()
+}
+
+// isGREASE returns whether the value is reserved by RFC 8701 to prevent ossification.
+func isGREASE(v uint16) bool {
+	return v&0x0f0f == 0x0a0a && v>>8 == v&0xff
+}
+
+func withoutGREASE(list []uint16) []uint16 {
+	r := make([]uint16, 0, len(list))
+	for _, v := range list {
+		if !isGREASE(v) {
+			r = append(r, v)
+		}
+	}
+	return r
+}
+
+func isAlphanumeric(b byte) bool {
+	return ('0' <= b && b <= '9') || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+func hexList(list []uint16) string {
+	parts := make([]string, len(list))
+	for i, v := range list {
+		parts[i] = fmt.Sprintf("%04x", v)
+	}
+	return strings.Join(parts, ",")
+}
+
+func truncatedHash(s string, empty bool) string {
+	if empty {
+		return "000000000000"
+	}
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:6])
+}
+
 var (
 	errNotTLS         = errors.New("not TLS header")
 	errNotClientHello = errors.New("not client hello")
@@ -29,12 +202,33 @@ func IsValidTLSVersion(major, minor byte) bool {
 	return major == 3
 }
 
-// ReadClientHello returns server name (if any) from TLS client hello message.
+// readUint16List reads a list of uint16 values, prefixed with its length in bytes of lengthSize.
+func readUint16List(d []byte, lengthSize int) ([]uint16, bool) {
+	if len(d) < lengthSize {
+		return nil, false
+	}
+	length := int(d[0])
+	if lengthSize == 2 {
+		length = length<<8 | int(d[1])
+	}
+	d = d[lengthSize:]
+	if length%2 == 1 || len(d) < length {
+		return nil, false
+	}
+	list := make([]uint16, 0, length/2)
+	for i := 0; i < length; i += 2 {
+		list = append(list, binary.BigEndian.Uint16(d[i:]))
+	}
+	return list, true
+}
+
+// ReadClientHello returns server name (if any) and the other details for fingerprints from TLS client hello message.
 // https://github.com/golang/go/blob/master/src/crypto/tls/handshake_messages.go#L300
 func ReadClientHello(data []byte, h *SniffHeader) error {
 	if len(data) < 42 {
 		return common.ErrNoClue
 	}
+	h.legacyVersion = uint16(data[4])<<8 | uint16(data[5])
 	sessionIDLen := int(data[38])
 	if sessionIDLen > 32 || len(data) < 39+sessionIDLen {
 		return common.ErrNoClue
@@ -49,6 +243,7 @@ func ReadClientHello(data []byte, h *SniffHeader) error {
 	if cipherSuiteLen%2 == 1 || len(data) < 2+cipherSuiteLen {
 		return errNotClientHello
 	}
+	h.cipherSuites, _ = readUint16List(data, 2)
 	data = data[2+cipherSuiteLen:]
 	if len(data) < 1 {
 		return common.ErrNoClue
@@ -69,19 +264,31 @@ func ReadClientHello(data []byte, h *SniffHeader) error {
 		return errNotClientHello
 	}
 
+	// truncated is returned when the extensions end early. Once the server name is read, the rest
+	// of the hello may have not been received yet (like the crypto frames of QUIC), so wait for it
+	// instead of reporting a fingerprint of the partial hello.
+	truncated := func() error {
+		if h.domain != "" {
+			return protocol.ErrProtoNeedMoreData
+		}
+		return errNotClientHello
+	}
 	for len(data) != 0 {
 		if len(data) < 4 {
-			return errNotClientHello
+			return truncated()
 		}
 		extension := uint16(data[0])<<8 | uint16(data[1])
 		length := int(data[2])<<8 | int(data[3])
 		data = data[4:]
 		if len(data) < length {
-			return errNotClientHello
+			return truncated()
 		}
+		h.extensions = append(h.extensions, extension)
+		d := data[:length]
+		data = data[length:]
 
-		if extension == 0x00 { /* extensionServerName */
-			d := data[:length]
+		switch extension {
+		case 0x00: /* extensionServerName */
 			if len(d) < 2 {
 				return errNotClientHello
 			}
@@ -115,17 +322,47 @@ func ReadClientHello(data []byte, h *SniffHeader) error {
 					if b == '.' {
 						return errNotClientHello
 					}
-					serverName := string(d[:nameLen])
-					h.domain = serverName
-					return nil
+					h.domain = string(d[:nameLen])
+					break
 				}
 				d = d[nameLen:]
 			}
+		case 0x0a: /* extensionSupportedCurves */
+			if list, ok := readUint16List(d, 2); ok {
+				h.curves = list
+			}
+		case 0x0b: /* extensionSupportedPoints */
+			if len(d) >= 1 && len(d) >= 1+int(d[0]) {
+				h.pointFormats = append([]uint8(nil), d[1:1+int(d[0])]...)
+			}
+		case 0x0d: /* extensionSignatureAlgorithms */
+			if list, ok := readUint16List(d, 2); ok {
+				h.signatureAlgorithms = list
+			}
+		case 0x10: /* extensionALPN */
+			if len(d) < 2 {
+				return truncated()
+			}
+			d = d[2:]
+			for len(d) > 0 {
+				protoLen := int(d[0])
+				if protoLen == 0 || len(d) < 1+protoLen {
+					return truncated()
+				}
+				h.alpn = append(h.alpn, string(d[1:1+protoLen]))
+				d = d[1+protoLen:]
+			}
+		case 0x2b: /* extensionSupportedVersions */
+			if list, ok := readUint16List(d, 1); ok {
+				h.versions = list
+			}
 		}
-		data = data[length:]
 	}
 
-	return errNotTLS
+	if h.domain == "" {
+		return errNotTLS
+	}
+	return nil
 }
 
 func SniffTLS(b []byte) (*SniffHeader, error) {
@@ -1,8 +1,13 @@
 package tls_test
 
 import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	. "github.com/xtls/xray-core/common/protocol/tls"
 )
 
@@ -159,3 +164,56 @@ func TestTLSHeaders(t *testing.T) {
 		}
 	}
 }
+
+func TestClientHelloFingerprints(t *testing.T) {
+	// ClientHello of Chrome, with GREASE values, and without supported_versions.
+	input := []byte{
+		0x16, 0x03, 0x01, 0x00, 0xc8, 0x01, 0x00, 0x00,
+		0xc4, 0x03, 0x03, 0x1a, 0xac, 0xb2, 0xa8, 0xfe,
+		0xb4, 0x96, 0x04, 0x5b, 0xca, 0xf7, 0xc1, 0xf4,
+		0x2e, 0x53, 0x24, 0x6e, 0x34, 0x0c, 0x58, 0x36,
+		0x71, 0x97, 0x59, 0xe9, 0x41, 0x66, 0xe2, 0x43,
+		0xa0, 0x13, 0xb6, 0x00, 0x00, 0x20, 0x1a, 0x1a,
+		0xc0, 0x2b, 0xc0, 0x2f, 0xc0, 0x2c, 0xc0, 0x30,
+		0xcc, 0xa9, 0xcc, 0xa8, 0xcc, 0x14, 0xcc, 0x13,
+		0xc0, 0x13, 0xc0, 0x14, 0x00, 0x9c, 0x00, 0x9d,
+		0x00, 0x2f, 0x00, 0x35, 0x00, 0x0a, 0x01, 0x00,
+		0x00, 0x7b, 0xba, 0xba, 0x00, 0x00, 0xff, 0x01,
+		0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x16, 0x00,
+		0x14, 0x00, 0x00, 0x11, 0x63, 0x2e, 0x73, 0x2d,
+		0x6d, 0x69, 0x63, 0x72, 0x6f, 0x73, 0x6f, 0x66,
+		0x74, 0x2e, 0x63, 0x6f, 0x6d, 0x00, 0x17, 0x00,
+		0x00, 0x00, 0x23, 0x00, 0x00, 0x00, 0x0d, 0x00,
+		0x14, 0x00, 0x12, 0x04, 0x03, 0x08, 0x04, 0x04,
+		0x01, 0x05, 0x03, 0x08, 0x05, 0x05, 0x01, 0x08,
+		0x06, 0x06, 0x01, 0x02, 0x01, 0x00, 0x05, 0x00,
+		0x05, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x12,
+		0x00, 0x00, 0x00, 0x10, 0x00, 0x0e, 0x00, 0x0c,
+		0x02, 0x68, 0x32, 0x08, 0x68, 0x74, 0x74, 0x70,
+		0x2f, 0x31, 0x2e, 0x31, 0x00, 0x0b, 0x00, 0x02,
+		0x01, 0x00, 0x00, 0x0a, 0x00, 0x0a, 0x00, 0x08,
+		0xaa, 0xaa, 0x00, 0x1d, 0x00, 0x17, 0x00, 0x18,
+		0xaa, 0xaa, 0x00, 0x01, 0x00,
+	}
+	header, err := SniffTLS(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r := cmp.Diff(header.ALPN(), []string{"h2", "http/1.1"}); r != "" {
+		t.Error(r)
+	}
+	if v := header.TLSVersion(); v != "1.2" {
+		t.Error("unexpected TLS version ", v)
+	}
+	ja3 := md5.Sum([]byte("771,49195-49199-49196-49200-52393-52392-52244-52243-49171-49172-156-157-47-53-10,65281-0-23-35-13-5-18-16-11-10,29-23-24,0"))
+	if v := header.JA3(); v != hex.EncodeToString(ja3[:]) {
+		t.Error("unexpected JA3 ", v)
+	}
+	ciphers := sha256.Sum256([]byte("000a,002f,0035,009c,009d,c013,c014,c02b,c02c,c02f,c030,cc13,cc14,cca8,cca9"))
+	extensions := sha256.Sum256([]byte("0005,000a,000b,000d,0012,0017,0023,ff01_0403,0804,0401,0503,0805,0501,0806,0601,0201"))
+	ja4 := "t12d1510h2_" + hex.EncodeToString(ciphers[:6]) + "_" + hex.EncodeToString(extensions[:6])
+	if v := header.JA4(); v != ja4 {
+		t.Error("expect JA4 ", ja4, " but got ", v)
+	}
+}
@@ -1,6 +1,9 @@
 package protocol
 
 import (
+	"sync/atomic"
+	"time"
+
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/serial"
 )
@@ -28,22 +31,44 @@ func (u *User) ToMemoryUser() (*MemoryUser, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &MemoryUser{
-		Account: account,
-		Email:   u.Email,
-		Level:   u.Level,
-	}, nil
+	mu := &MemoryUser{
+		Account:           account,
+		Email:             u.Email,
+		Level:             u.Level,
+		UplinkRateLimit:   u.UplinkRateLimit,
+		DownlinkRateLimit: u.DownlinkRateLimit,
+		TrafficQuota:      u.TrafficQuota,
+	}
+	if u.ExpireTime > 0 {
+		mu.ExpireTime = time.Unix(u.ExpireTime, 0)
+	}
+	if u.TrafficQuota > 0 {
+		mu.Traffic = new(TrafficCounter)
+		mu.Traffic.used.Store(u.TrafficUsed)
+	}
+	return mu, nil
 }
 
 func ToProtoUser(mu *MemoryUser) *User {
 	if mu == nil {
 		return nil
 	}
-	return &User{
-		Account: serial.ToTypedMessage(mu.Account.ToProto()),
-		Email:   mu.Email,
-		Level:   mu.Level,
+	u := &User{
+		Account:           serial.ToTypedMessage(mu.Account.ToProto()),
+		Email:             mu.Email,
+		Level:             mu.Level,
+		UplinkRateLimit:   mu.UplinkRateLimit,
+		DownlinkRateLimit: mu.DownlinkRateLimit,
+		TrafficQuota:      mu.TrafficQuota,
+		TrafficUsed:       mu.TrafficUsed(),
+	}
+	if !mu.ExpireTime.IsZero() {
+		u.ExpireTime = mu.ExpireTime.Unix()
+	}
+	if u.TrafficQuota > u.TrafficUsed {
+		u.TrafficRemaining = u.TrafficQuota - u.TrafficUsed
 	}
+	return u
 }
 
 // MemoryUser is a parsed form of User, to reduce number of parsing of Account proto.
@@ -52,4 +77,54 @@ type MemoryUser struct {
 	Account Account
 	Email   string
 	Level   uint32
+	// Rate limits in bytes per second overriding the ones of the level. 0 for using the level's limits.
+	UplinkRateLimit   uint64
+	DownlinkRateLimit uint64
+	// TrafficQuota is the total bytes of uplink and downlink the user may use. 0 for unlimited.
+	TrafficQuota uint64
+	// ExpireTime is the time after which the user is disabled. Zero for never.
+	ExpireTime time.Time
+	// Traffic counts the bytes used by the user. Only set when TrafficQuota is.
+	Traffic *TrafficCounter
+}
+
+// TrafficCounter is the traffic used by a user, shared by all its connections.
+type TrafficCounter struct {
+	used atomic.Uint64
+}
+
+var (
+	ErrUserExpired        = errors.New("user expired")
+	ErrUserQuotaExhausted = errors.New("user traffic quota exhausted")
+)
+
+// HasQuota returns true if the user is limited by traffic quota or expire time.
+func (u *MemoryUser) HasQuota() bool {
+	return u.TrafficQuota > 0 || !u.ExpireTime.IsZero()
+}
+
+// TrafficUsed returns the bytes used by the user so far.
+func (u *MemoryUser) TrafficUsed() uint64 {
+	if u.Traffic == nil {
+		return 0
+	}
+	return u.Traffic.used.Load()
+}
+
+// AddTraffic counts n bytes to the traffic used by the user.
+func (u *MemoryUser) AddTraffic(n uint64) {
+	if u.Traffic != nil {
+		u.Traffic.used.Add(n)
+	}
+}
+
+// CheckQuota returns an error if the user is expired or has used up its traffic quota.
+func (u *MemoryUser) CheckQuota() error {
+	if !u.ExpireTime.IsZero() && time.Now().After(u.ExpireTime) {
+		return ErrUserExpired
+	}
+	if u.TrafficQuota > 0 && u.TrafficUsed() >= u.TrafficQuota {
+		return ErrUserQuotaExhausted
+	}
+	return nil
 }
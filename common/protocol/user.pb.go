@@ -29,9 +29,23 @@ type User struct {
 	Email string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
 	// Protocol specific account information. Must be the account proto in one of
 	// the proxies.
-	Account       *serial.TypedMessage `protobuf:"bytes,3,opt,name=account,proto3" json:"account,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	Account *serial.TypedMessage `protobuf:"bytes,3,opt,name=account,proto3" json:"account,omitempty"`
+	// Rate limits of this user in bytes per second, overriding the ones of its
+	// level. 0 for using the level's limits.
+	UplinkRateLimit   uint64 `protobuf:"varint,4,opt,name=uplink_rate_limit,json=uplinkRateLimit,proto3" json:"uplink_rate_limit,omitempty"`
+	DownlinkRateLimit uint64 `protobuf:"varint,5,opt,name=downlink_rate_limit,json=downlinkRateLimit,proto3" json:"downlink_rate_limit,omitempty"`
+	// Total traffic in bytes, uplink and downlink together, the user may use.
+	// 0 for unlimited.
+	TrafficQuota uint64 `protobuf:"varint,6,opt,name=traffic_quota,json=trafficQuota,proto3" json:"traffic_quota,omitempty"`
+	// Traffic in bytes already used by the user, counted against traffic_quota.
+	TrafficUsed uint64 `protobuf:"varint,7,opt,name=traffic_used,json=trafficUsed,proto3" json:"traffic_used,omitempty"`
+	// Unix time in seconds after which the user is disabled. 0 for never.
+	ExpireTime int64 `protobuf:"varint,8,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	// Traffic in bytes the user may still use. Only filled when reading users
+	// from an inbound with traffic_quota set.
+	TrafficRemaining uint64 `protobuf:"varint,9,opt,name=traffic_remaining,json=trafficRemaining,proto3" json:"traffic_remaining,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *User) Reset() {
@@ -85,15 +99,64 @@ func (x *User) GetAccount() *serial.TypedMessage {
 	return nil
 }
 
+func (x *User) GetUplinkRateLimit() uint64 {
+	if x != nil {
+		return x.UplinkRateLimit
+	}
+	return 0
+}
+
+func (x *User) GetDownlinkRateLimit() uint64 {
+	if x != nil {
+		return x.DownlinkRateLimit
+	}
+	return 0
+}
+
+func (x *User) GetTrafficQuota() uint64 {
+	if x != nil {
+		return x.TrafficQuota
+	}
+	return 0
+}
+
+func (x *User) GetTrafficUsed() uint64 {
+	if x != nil {
+		return x.TrafficUsed
+	}
+	return 0
+}
+
+func (x *User) GetExpireTime() int64 {
+	if x != nil {
+		return x.ExpireTime
+	}
+	return 0
+}
+
+func (x *User) GetTrafficRemaining() uint64 {
+	if x != nil {
+		return x.TrafficRemaining
+	}
+	return 0
+}
+
 var File_common_protocol_user_proto protoreflect.FileDescriptor
 
 const file_common_protocol_user_proto_rawDesc = "" +
 	"\n" +
-	"\x1acommon/protocol/user.proto\x12\x14xray.common.protocol\x1a!common/serial/typed_message.proto\"n\n" +
+	"\x1acommon/protocol/user.proto\x12\x14xray.common.protocol\x1a!common/serial/typed_message.proto\"\xe0\x02\n" +
 	"\x04User\x12\x14\n" +
 	"\x05level\x18\x01 \x01(\rR\x05level\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12:\n" +
-	"\aaccount\x18\x03 \x01(\v2 .xray.common.serial.TypedMessageR\aaccountB^\n" +
+	"\aaccount\x18\x03 \x01(\v2 .xray.common.serial.TypedMessageR\aaccount\x12*\n" +
+	"\x11uplink_rate_limit\x18\x04 \x01(\x04R\x0fuplinkRateLimit\x12.\n" +
+	"\x13downlink_rate_limit\x18\x05 \x01(\x04R\x11downlinkRateLimit\x12#\n" +
+	"\rtraffic_quota\x18\x06 \x01(\x04R\ftrafficQuota\x12!\n" +
+	"\ftraffic_used\x18\a \x01(\x04R\vtrafficUsed\x12\x1f\n" +
+	"\vexpire_time\x18\b \x01(\x03R\n" +
+	"expireTime\x12+\n" +
+	"\x11traffic_remaining\x18\t \x01(\x04R\x10trafficRemainingB^\n" +
 	"\x18com.xray.common.protocolP\x01Z)github.com/xtls/xray-core/common/protocol\xaa\x02\x14Xray.Common.Protocolb\x06proto3"
 
 var (
@@ -0,0 +1,39 @@
+package protocol_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	. "github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/proxy/vless"
+)
+
+func TestUserQuota(t *testing.T) {
+	user, err := (&User{
+		Email:        "love@example.com",
+		Account:      serial.ToTypedMessage(&vless.Account{Id: "27848739-7e62-4138-9fd3-098a63964b6b"}),
+		TrafficQuota: 100,
+		TrafficUsed:  40,
+	}).ToMemoryUser()
+	common.Must(err)
+
+	if err := user.CheckQuota(); err != nil {
+		t.Error("unexpected error: ", err)
+	}
+	user.AddTraffic(50)
+	if u := ToProtoUser(user); u.TrafficUsed != 90 || u.TrafficRemaining != 10 {
+		t.Error("unexpected traffic used ", u.TrafficUsed, " remaining ", u.TrafficRemaining)
+	}
+	user.AddTraffic(10)
+	if err := user.CheckQuota(); err != ErrUserQuotaExhausted {
+		t.Error("expect quota exhausted, got ", err)
+	}
+
+	user.TrafficQuota = 0
+	user.ExpireTime = time.Now().Add(-time.Second)
+	if err := user.CheckQuota(); err != ErrUserExpired {
+		t.Error("expect user expired, got ", err)
+	}
+}
@@ -55,6 +55,9 @@ type Inbound struct {
 	// CanSpliceCopy is a property for this connection
 	// 1 = can, 2 = after processing protocol info should be able to, 3 = cannot
 	CanSpliceCopy int
+	// ClientCertSubject is the name of the verified TLS client certificate of the inbound connection.
+	// Empty if the client isn't verified.
+	ClientCertSubject string
 }
 
 // Outbound is the metadata of an outbound connection.
@@ -95,10 +98,25 @@ type Content struct {
 	// HTTP traffic sniffed headers
 	Attributes map[string]string
 
+	// ClientHello is the sniffed TLS ClientHello of TLS and QUIC traffic.
+	ClientHello *ClientHello
+
 	// SkipDNSResolve is set from DNS module. the DOH remote server maybe a domain name, this prevents cycle resolving dead loop
 	SkipDNSResolve bool
 }
 
+// ClientHello is the details of a TLS ClientHello.
+type ClientHello struct {
+	// ALPN is the protocols in the ALPN extension.
+	ALPN []string
+	// Version is the highest TLS version offered, like "1.3".
+	Version string
+	// JA3 is the JA3 fingerprint, which is an MD5 hash in hex.
+	JA3 string
+	// JA4 is the JA4 fingerprint.
+	JA4 string
+}
+
 // Sockopt is the settings for socket connection.
 type Sockopt struct {
 	// Mark of the socket connection.
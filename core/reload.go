@@ -0,0 +1,388 @@
+package core
+
+import (
+	"context"
+	"slices"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/features"
+	"github.com/xtls/xray-core/features/inbound"
+	"github.com/xtls/xray-core/features/outbound"
+	"google.golang.org/protobuf/proto"
+)
+
+// ConfigProvider provides the latest config for an Instance, e.g. by reading the config files it was started with.
+type ConfigProvider func() (*Config, error)
+
+// SetConfigProvider sets the provider used by ReloadConfig.
+func (s *Instance) SetConfigProvider(provider ConfigProvider) {
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+
+	s.configProvider = provider
+}
+
+// ReloadConfig loads the config through the provider set by SetConfigProvider, and applies it to the Instance. See Reload.
+func (s *Instance) ReloadConfig() error {
+	s.reloadLock.Lock()
+	provider := s.configProvider
+	s.reloadLock.Unlock()
+
+	if provider == nil {
+		return errors.New("config reloading is not supported by this instance")
+	}
+	config, err := provider()
+	if err != nil {
+		return errors.New("failed to load config").Base(err)
+	}
+	return s.Reload(config)
+}
+
+// Reload applies the given config to a running Instance in place.
+// Apps whose config changed are reloaded if they implement features.Reloadable; other changed apps keep their current config until restart.
+// Tagged inbound and outbound handlers are diffed by tag, and only the added, removed and changed ones are touched, so unaffected handlers keep their connections.
+// The whole config is validated before any change is applied, so an invalid config changes nothing.
+// If a change fails to be applied, like an inbound failing to listen, the changes already applied are rolled back.
+func (s *Instance) Reload(config *Config) error {
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+
+	// Handlers are changed first, as only they may fail to be applied, and they can be rolled back.
+	tx := new(reloadTx)
+	if err := s.prepareInbounds(tx, config.Inbound); err != nil {
+		tx.abort()
+		return errors.New("failed to reload inbounds").Base(err)
+	}
+	if err := s.prepareOutbounds(tx, config.Outbound); err != nil {
+		tx.abort()
+		return errors.New("failed to reload outbounds").Base(err)
+	}
+	if err := s.prepareApps(tx, config); err != nil {
+		tx.abort()
+		return err
+	}
+	if err := tx.commit(); err != nil {
+		return err
+	}
+
+	s.config = config
+	errors.LogWarning(s.ctx, "Xray ", Version(), " reloaded")
+	return nil
+}
+
+// reloadTx collects the changes prepared for a reload, so that they are applied only after the whole config is validated.
+type reloadTx struct {
+	steps []reloadStep
+}
+
+// reloadStep is a prepared change. If commit fails, it leaves nothing of the change applied. rollback reverts a
+// committed change, and abort discards a change that is not committed.
+type reloadStep struct {
+	commit   func() error
+	rollback func()
+	abort    func()
+}
+
+func (tx *reloadTx) add(commit func() error, rollback func(), abort func()) {
+	tx.steps = append(tx.steps, reloadStep{commit, rollback, abort})
+}
+
+// abort discards all prepared changes.
+func (tx *reloadTx) abort() {
+	for i := len(tx.steps) - 1; i >= 0; i-- {
+		tx.steps[i].abort()
+	}
+}
+
+// commit applies all prepared changes in order. If one of them fails, the committed ones are rolled back and the
+// others are discarded, so the instance is left as before.
+func (tx *reloadTx) commit() error {
+	for i, step := range tx.steps {
+		if err := step.commit(); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				tx.steps[j].rollback()
+			}
+			for _, step := range tx.steps[i+1:] {
+				step.abort()
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Instance) prepareApps(tx *reloadTx, config *Config) error {
+	oldApps := make(map[string]proto.Message, len(s.config.App))
+	for _, app := range s.config.App {
+		oldApps[app.Type] = app
+	}
+
+	newTypes := make(map[string]bool, len(config.App))
+	for _, app := range config.App {
+		newTypes[app.Type] = true
+		old, found := oldApps[app.Type]
+		if found && proto.Equal(old, app) {
+			continue
+		}
+		feature, found := s.appFeatures[app.Type]
+		if !found {
+			errors.LogWarning(s.ctx, "app ", app.Type, " is added, restart is required to apply it")
+			continue
+		}
+		reloadable, ok := feature.(features.Reloadable)
+		if !ok {
+			errors.LogWarning(s.ctx, "app ", app.Type, " doesn't support reloading, restart is required to apply its changes")
+			continue
+		}
+		settings, err := app.GetInstance()
+		if err != nil {
+			return err
+		}
+		commit, abort, err := reloadable.PrepareReload(settings)
+		if err != nil {
+			return errors.New("failed to reload app ", app.Type).Base(err)
+		}
+		appType := app.Type
+		// Apps are committed last, so they are never rolled back.
+		tx.add(func() error {
+			commit()
+			errors.LogInfo(s.ctx, "app ", appType, " reloaded")
+			return nil
+		}, func() {}, abort)
+	}
+	for t := range oldApps {
+		if !newTypes[t] {
+			errors.LogWarning(s.ctx, "app ", t, " is removed, restart is required to apply it")
+		}
+	}
+	return nil
+}
+
+// diffHandlerConfigs returns the tags of handlers that have to be removed, and the indexes of configs in newConfigs that have to be added.
+// Untagged handlers can't be addressed, so they are left untouched.
+func diffHandlerConfigs[T interface {
+	proto.Message
+	GetTag() string
+}](oldConfigs, newConfigs []T) (removed []string, added []int) {
+	oldByTag := make(map[string]T, len(oldConfigs))
+	for _, c := range oldConfigs {
+		if c.GetTag() != "" {
+			oldByTag[c.GetTag()] = c
+		}
+	}
+	newTags := make(map[string]bool, len(newConfigs))
+	for i, c := range newConfigs {
+		tag := c.GetTag()
+		if tag == "" {
+			continue
+		}
+		newTags[tag] = true
+		old, found := oldByTag[tag]
+		if found && proto.Equal(old, c) {
+			continue
+		}
+		if found {
+			removed = append(removed, tag)
+		}
+		added = append(added, i)
+	}
+	for _, c := range oldConfigs {
+		if tag := c.GetTag(); tag != "" && !newTags[tag] {
+			removed = append(removed, tag)
+		}
+	}
+	return
+}
+
+func untaggedChanged[T interface {
+	proto.Message
+	GetTag() string
+}](oldConfigs, newConfigs []T) bool {
+	var oldUntagged, newUntagged []T
+	for _, c := range oldConfigs {
+		if c.GetTag() == "" {
+			oldUntagged = append(oldUntagged, c)
+		}
+	}
+	for _, c := range newConfigs {
+		if c.GetTag() == "" {
+			newUntagged = append(newUntagged, c)
+		}
+	}
+	if len(oldUntagged) != len(newUntagged) {
+		return true
+	}
+	for i := range oldUntagged {
+		if !proto.Equal(oldUntagged[i], newUntagged[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Instance) prepareInbounds(tx *reloadTx, configs []*InboundHandlerConfig) error {
+	if untaggedChanged(s.config.Inbound, configs) {
+		errors.LogWarning(s.ctx, "untagged inbounds can't be reloaded, restart is required to apply their changes")
+	}
+	removed, added := diffHandlerConfigs(s.config.Inbound, configs)
+
+	handlers := make([]inbound.Handler, 0, len(added))
+	closeHandlers := func() {
+		for _, handler := range handlers {
+			common.Close(handler)
+		}
+	}
+	for _, i := range added {
+		rawHandler, err := CreateObject(s, configs[i])
+		if err != nil {
+			closeHandlers()
+			return err
+		}
+		handler, ok := rawHandler.(inbound.Handler)
+		if !ok {
+			closeHandlers()
+			return errors.New("not an InboundHandler")
+		}
+		handlers = append(handlers, handler)
+	}
+
+	commit, rollback := replaceHandlers(s, "inbound", s.GetFeature(inbound.ManagerType()).(inbound.Manager), s.config.Inbound, removed, handlers)
+	tx.add(commit, rollback, closeHandlers)
+	return nil
+}
+
+func (s *Instance) prepareOutbounds(tx *reloadTx, configs []*OutboundHandlerConfig) error {
+	if untaggedChanged(s.config.Outbound, configs) {
+		errors.LogWarning(s.ctx, "untagged outbounds can't be reloaded, restart is required to apply their changes")
+	}
+	removed, added := diffHandlerConfigs(s.config.Outbound, configs)
+
+	// The first outbound is the default one. The outbound manager picks the first handler added after the
+	// default one is removed, so if the default changes, both the old and the new one are re-added, new one first.
+	var oldDefault, newDefault string
+	if len(s.config.Outbound) > 0 {
+		oldDefault = s.config.Outbound[0].Tag
+	}
+	if len(configs) > 0 {
+		newDefault = configs[0].Tag
+	}
+	if oldDefault != newDefault && oldDefault != "" && newDefault != "" {
+		if !slices.Contains(removed, oldDefault) {
+			removed = append(removed, oldDefault)
+			for i, c := range configs {
+				if c.Tag == oldDefault {
+					added = append(added, i)
+				}
+			}
+		}
+		if !slices.Contains(added, 0) {
+			removed = append(removed, newDefault)
+			added = append(added, 0)
+		}
+	}
+	if i := slices.Index(added, 0); i > 0 {
+		added[0], added[i] = added[i], added[0]
+	}
+
+	handlers := make([]outbound.Handler, 0, len(added))
+	closeHandlers := func() {
+		for _, handler := range handlers {
+			common.Close(handler)
+		}
+	}
+	for _, i := range added {
+		rawHandler, err := CreateObject(s, configs[i])
+		if err != nil {
+			closeHandlers()
+			return err
+		}
+		handler, ok := rawHandler.(outbound.Handler)
+		if !ok {
+			closeHandlers()
+			return errors.New("not an OutboundHandler")
+		}
+		handlers = append(handlers, handler)
+	}
+
+	commit, rollback := replaceHandlers(s, "outbound", s.GetFeature(outbound.ManagerType()).(outbound.Manager), s.config.Outbound, removed, handlers)
+	tx.add(commit, rollback, closeHandlers)
+	return nil
+}
+
+// handlerManager is the part of inbound.Manager and outbound.Manager used to reload handlers.
+type handlerManager[H interface{ Tag() string }] interface {
+	AddHandler(ctx context.Context, handler H) error
+	RemoveHandler(ctx context.Context, tag string) error
+}
+
+// replaceHandlers returns the commit that removes the handlers of the tags and adds the new handlers, and the rollback
+// that reverts it. The removed handlers are restored from their configs in oldConfigs.
+func replaceHandlers[H interface{ Tag() string }, C interface {
+	proto.Message
+	GetTag() string
+}](s *Instance, kind string, manager handlerManager[H], oldConfigs []C, removed []string, handlers []H) (func() error, func()) {
+	var removedTags []string
+	var added []H
+	rollback := func() {
+		for _, handler := range added {
+			if err := manager.RemoveHandler(s.ctx, handler.Tag()); err != nil {
+				errors.LogWarningInner(s.ctx, err, "failed to remove ", kind, " ", handler.Tag())
+			}
+		}
+		restoreHandlers(s, kind, manager, oldConfigs, removedTags)
+	}
+	commit := func() error {
+		for i, tag := range removed {
+			if err := manager.RemoveHandler(s.ctx, tag); err != nil {
+				rollback()
+				for _, handler := range handlers {
+					common.Close(handler)
+				}
+				return errors.New("failed to remove ", kind, " ", tag).Base(err)
+			}
+			removedTags = removed[:i+1]
+			errors.LogInfo(s.ctx, kind, " ", tag, " removed")
+		}
+		for i, handler := range handlers {
+			if err := manager.AddHandler(s.ctx, handler); err != nil {
+				// A handler failing to start is still added.
+				manager.RemoveHandler(s.ctx, handler.Tag())
+				rollback()
+				for _, handler := range handlers[i:] {
+					common.Close(handler)
+				}
+				return errors.New("failed to add ", kind, " ", handler.Tag()).Base(err)
+			}
+			added = handlers[:i+1]
+			errors.LogInfo(s.ctx, kind, " ", handler.Tag(), " added")
+		}
+		return nil
+	}
+	return commit, rollback
+}
+
+// restoreHandlers adds back the handlers of the tags, created from the configs, in the order of the configs.
+func restoreHandlers[H interface{ Tag() string }, C interface {
+	proto.Message
+	GetTag() string
+}](s *Instance, kind string, manager handlerManager[H], configs []C, tags []string) {
+	for _, config := range configs {
+		tag := config.GetTag()
+		if tag == "" || !slices.Contains(tags, tag) {
+			continue
+		}
+		rawHandler, err := CreateObject(s, config)
+		if err == nil {
+			if handler, ok := rawHandler.(H); ok {
+				err = manager.AddHandler(s.ctx, handler)
+			} else {
+				common.Close(rawHandler)
+				err = errors.New("not a handler")
+			}
+		}
+		if err != nil {
+			errors.LogErrorInner(s.ctx, err, "failed to restore ", kind, " ", tag)
+		}
+	}
+}
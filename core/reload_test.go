@@ -0,0 +1,161 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xtls/xray-core/app/dispatcher"
+	"github.com/xtls/xray-core/app/policy"
+	"github.com/xtls/xray-core/app/proxyman"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/serial"
+	. "github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/inbound"
+	"github.com/xtls/xray-core/features/outbound"
+	feature_policy "github.com/xtls/xray-core/features/policy"
+	_ "github.com/xtls/xray-core/main/distro/all"
+	"github.com/xtls/xray-core/proxy/blackhole"
+	"github.com/xtls/xray-core/proxy/dokodemo"
+	"github.com/xtls/xray-core/proxy/freedom"
+	"github.com/xtls/xray-core/testing/servers/tcp"
+)
+
+func reloadTestConfig(bufferSize int32, ports map[string]net.Port, outbounds ...*OutboundHandlerConfig) *Config {
+	config := &Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&policy.Config{
+				Level: map[uint32]*policy.Policy{
+					0: {Buffer: &policy.Policy_Buffer{Connection: bufferSize}},
+				},
+			}),
+		},
+		Outbound: outbounds,
+	}
+	for tag, port := range ports {
+		config.Inbound = append(config.Inbound, &InboundHandlerConfig{
+			Tag: tag,
+			ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+				PortList: &net.PortList{Range: []*net.PortRange{net.SinglePortRange(port)}},
+				Listen:   net.NewIPOrDomain(net.LocalHostIP),
+			}),
+			ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+				Address:  net.NewIPOrDomain(net.LocalHostIP),
+				Port:     uint32(port),
+				Networks: []net.Network{net.Network_TCP},
+			}),
+		})
+	}
+	return config
+}
+
+func TestInstanceReload(t *testing.T) {
+	keptPort := tcp.PickPort()
+	removedPort := tcp.PickPort()
+	addedPort := tcp.PickPort()
+
+	direct := &OutboundHandlerConfig{Tag: "direct", ProxySettings: serial.ToTypedMessage(&freedom.Config{})}
+	block := &OutboundHandlerConfig{Tag: "block", ProxySettings: serial.ToTypedMessage(&blackhole.Config{})}
+
+	server, err := New(reloadTestConfig(1, map[string]net.Port{"kept": keptPort, "removed": removedPort}, direct, block))
+	common.Must(err)
+	common.Must(server.Start())
+	defer server.Close()
+
+	im := server.GetFeature(inbound.ManagerType()).(inbound.Manager)
+	om := server.GetFeature(outbound.ManagerType()).(outbound.Manager)
+	pm := server.GetFeature(feature_policy.ManagerType()).(feature_policy.Manager)
+	kept, err := im.GetHandler(context.Background(), "kept")
+	common.Must(err)
+
+	if err := server.ReloadConfig(); err == nil {
+		t.Error("expected error when reloading without a config provider")
+	}
+	server.SetConfigProvider(func() (*Config, error) {
+		return reloadTestConfig(2, map[string]net.Port{"kept": keptPort, "added": addedPort}, block, direct), nil
+	})
+	common.Must(server.ReloadConfig())
+
+	if h, err := im.GetHandler(context.Background(), "kept"); err != nil || h != kept {
+		t.Error("unchanged inbound should be kept, got ", h, err)
+	}
+	if _, err := im.GetHandler(context.Background(), "removed"); err == nil {
+		t.Error("removed inbound still exists")
+	}
+	if _, err := im.GetHandler(context.Background(), "added"); err != nil {
+		t.Error("added inbound doesn't exist: ", err)
+	}
+	if h := om.GetDefaultHandler(); h == nil || h.Tag() != "block" {
+		t.Error("default outbound should be changed to block, got ", h)
+	}
+	if om.GetHandler("direct") == nil {
+		t.Error("outbound direct disappeared")
+	}
+	if size := pm.ForLevel(0).Buffer.PerConnection; size != 2 {
+		t.Error("policy not reloaded, buffer size: ", size)
+	}
+}
+
+func TestInstanceReloadInvalid(t *testing.T) {
+	port := tcp.PickPort()
+	direct := &OutboundHandlerConfig{Tag: "direct", ProxySettings: serial.ToTypedMessage(&freedom.Config{})}
+	invalid := &OutboundHandlerConfig{Tag: "invalid", ProxySettings: &serial.TypedMessage{Type: "xray.unknown.Config"}}
+
+	server, err := New(reloadTestConfig(1, map[string]net.Port{"kept": port}, direct))
+	common.Must(err)
+	common.Must(server.Start())
+	defer server.Close()
+
+	if err := server.Reload(reloadTestConfig(2, map[string]net.Port{"added": tcp.PickPort()}, direct, invalid)); err == nil {
+		t.Fatal("expected error when reloading an invalid config")
+	}
+
+	im := server.GetFeature(inbound.ManagerType()).(inbound.Manager)
+	pm := server.GetFeature(feature_policy.ManagerType()).(feature_policy.Manager)
+	if _, err := im.GetHandler(context.Background(), "kept"); err != nil {
+		t.Error("inbound removed by an invalid config: ", err)
+	}
+	if _, err := im.GetHandler(context.Background(), "added"); err == nil {
+		t.Error("inbound added by an invalid config")
+	}
+	if size := pm.ForLevel(0).Buffer.PerConnection; size != 1 {
+		t.Error("policy reloaded by an invalid config, buffer size: ", size)
+	}
+}
+
+func TestInstanceReloadRollback(t *testing.T) {
+	keptPort := tcp.PickPort()
+	busyPort := tcp.PickPort()
+	busy, err := net.Listen("tcp", net.TCPDestination(net.LocalHostIP, busyPort).NetAddr())
+	common.Must(err)
+	defer busy.Close()
+
+	direct := &OutboundHandlerConfig{Tag: "direct", ProxySettings: serial.ToTypedMessage(&freedom.Config{})}
+	server, err := New(reloadTestConfig(1, map[string]net.Port{"kept": keptPort}, direct))
+	common.Must(err)
+	common.Must(server.Start())
+	defer server.Close()
+
+	// The changed inbound is removed before the new one fails to listen.
+	if err := server.Reload(reloadTestConfig(2, map[string]net.Port{"kept": busyPort}, direct)); err == nil {
+		t.Fatal("expected error when an inbound fails to listen")
+	}
+
+	im := server.GetFeature(inbound.ManagerType()).(inbound.Manager)
+	pm := server.GetFeature(feature_policy.ManagerType()).(feature_policy.Manager)
+	if _, err := im.GetHandler(context.Background(), "kept"); err != nil {
+		t.Error("removed inbound not restored: ", err)
+	}
+	conn, err := net.Dial("tcp", net.TCPDestination(net.LocalHostIP, keptPort).NetAddr())
+	if err != nil {
+		t.Error("restored inbound not listening: ", err)
+	} else {
+		conn.Close()
+	}
+	if size := pm.ForLevel(0).Buffer.PerConnection; size != 1 {
+		t.Error("policy reloaded by a failed reload, buffer size: ", size)
+	}
+}
@@ -87,6 +87,13 @@ type Instance struct {
 	running                    bool
 	resolveLock                sync.Mutex
 
+	// config is the config the Instance is currently running with.
+	config *Config
+	// appFeatures maps the type of each app settings to the feature created from it.
+	appFeatures    map[string]features.Feature
+	reloadLock     sync.Mutex
+	configProvider ConfigProvider
+
 	ctx context.Context
 }
 
@@ -189,6 +196,8 @@ func NewWithContext(ctx context.Context, config *Config) (*Instance, error) {
 func initInstanceWithConfig(config *Config, server *Instance) (bool, error) {
 	server.ctx = context.WithValue(server.ctx, "cone",
 		platform.NewEnvFlag(platform.UseCone).GetValue(func() string { return "" }) != "true")
+	server.config = config
+	server.appFeatures = make(map[string]features.Feature, len(config.App))
 
 	for _, appSettings := range config.App {
 		settings, err := appSettings.GetInstance()
@@ -203,6 +212,7 @@ func initInstanceWithConfig(config *Config, server *Instance) (bool, error) {
 			if err := server.AddFeature(feature); err != nil {
 				return true, err
 			}
+			server.appFeatures[appSettings.Type] = feature
 		}
 	}
 
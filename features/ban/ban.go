@@ -0,0 +1,56 @@
+package ban
+
+import (
+	"context"
+
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/features"
+)
+
+// Manager is a feature that bans source IPs with too many failed authentications.
+//
+// xray:api:beta
+type Manager interface {
+	features.Feature
+
+	// RecordFailure counts a failed authentication from the IP, and bans it if it has failed too many times.
+	RecordFailure(ctx context.Context, ip net.Address)
+	// IsBanned returns whether the IP is banned.
+	IsBanned(ip net.Address) bool
+}
+
+// ManagerType returns the type of Manager interface. Can be used to implement common.HasType.
+//
+// xray:api:beta
+func ManagerType() interface{} {
+	return (*Manager)(nil)
+}
+
+type banKey int
+
+const managerKey banKey = 0
+
+// ContextWithManager returns a new context with the ban manager, for inbounds to report failed authentications.
+func ContextWithManager(ctx context.Context, m Manager) context.Context {
+	return context.WithValue(ctx, managerKey, m)
+}
+
+// ManagerFromContext returns the ban manager in the context, or nil if banning is not enabled.
+func ManagerFromContext(ctx context.Context) Manager {
+	if m, ok := ctx.Value(managerKey).(Manager); ok {
+		return m
+	}
+	return nil
+}
+
+// RecordFailure counts a failed authentication from the source of the inbound in the context, if banning is enabled.
+func RecordFailure(ctx context.Context) {
+	m := ManagerFromContext(ctx)
+	if m == nil {
+		return
+	}
+	if inbound := session.InboundFromContext(ctx); inbound != nil && inbound.Source.IsValid() {
+		m.RecordFailure(ctx, inbound.Source.Address)
+	}
+}
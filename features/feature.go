@@ -10,3 +10,14 @@ type Feature interface {
 	common.HasType
 	common.Runnable
 }
+
+// Reloadable is the interface for features that are able to apply a changed config in place,
+// so that the Instance doesn't have to be restarted.
+type Reloadable interface {
+	Feature
+	// PrepareReload validates the given config, which is of the same type as the one the feature was created from,
+	// and prepares everything needed to apply it, without changing the running feature.
+	// Exactly one of the returned functions must be called: commit applies the config and can't fail,
+	// abort releases what was prepared.
+	PrepareReload(config interface{}) (commit func(), abort func(), err error)
+}
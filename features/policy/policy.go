@@ -5,8 +5,13 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/log"
+	"github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/common/platform"
+	"github.com/xtls/xray-core/common/protocol"
 	"github.com/xtls/xray-core/features"
+	"golang.org/x/time/rate"
 )
 
 // Timeout contains limits for connection timeout.
@@ -37,6 +42,24 @@ type Buffer struct {
 	PerConnection int32
 }
 
+// RateLimit contains throughput limits shared by all connections of a user.
+type RateLimit struct {
+	// Rate limits in bytes per second. 0 for unlimited.
+	Uplink   uint64
+	Downlink uint64
+	// Maximum bytes that can be transferred at once. 0 for the same as the rate.
+	UplinkBurst   uint64
+	DownlinkBurst uint64
+}
+
+// ConnectionLimit contains limits of the simultaneous usage of a user.
+type ConnectionLimit struct {
+	// Maximum number of simultaneous connections. 0 for unlimited.
+	MaxConnections uint32
+	// Maximum number of distinct source IPs of the live connections of the user. 0 for unlimited.
+	MaxIPs uint32
+}
+
 // SystemStats contains stat policy settings on system level.
 type SystemStats struct {
 	// Whether or not to enable stat counter for uplink traffic in inbound handlers.
@@ -57,9 +80,12 @@ type System struct {
 
 // Session is session based settings for controlling Xray requests. It contains various settings (or limits) that may differ for different users in the context.
 type Session struct {
-	Timeouts Timeout // Timeout settings
-	Stats    Stats
-	Buffer   Buffer
+	Timeouts  Timeout // Timeout settings
+	Stats     Stats
+	Buffer    Buffer
+	RateLimit RateLimit
+	// Limits of connections shared by all inbounds.
+	ConnectionLimit ConnectionLimit
 }
 
 // Manager is a feature that provides Policy for the given user by its id or level.
@@ -75,6 +101,54 @@ type Manager interface {
 	ForSystem() System
 }
 
+// UserRateLimiter is an optional interface of Manager, for managers that limit the throughput of users.
+type UserRateLimiter interface {
+	// LimitersForUser returns the token buckets shared by all connections of the given user, and
+	// the function to call when the connection is closed.
+	// Users with email always get their limiters, which are unlimited until a limit is set.
+	// For users without email, a nil limiter means the direction is not limited.
+	LimitersForUser(user *protocol.MemoryUser) (uplink *rate.Limiter, downlink *rate.Limiter, release func())
+}
+
+// UserConnectionLimiter is an optional interface of Manager, for managers that limit the connections of users.
+type UserConnectionLimiter interface {
+	// AcquireConnection counts a connection of the user from the source address, and returns
+	// the function to call when the connection is closed. It fails if the connection exceeds
+	// the limits of the user.
+	AcquireConnection(user *protocol.MemoryUser, source net.Address) (release func(), err error)
+}
+
+var (
+	ErrTooManyConnections = errors.New("too many connections of user")
+	ErrTooManyIPs         = errors.New("too many IPs of user")
+)
+
+// AcquireUserConnection checks that the user is not expired and has quota left, and counts a connection of
+// the user from the source address if the manager limits connections of users. If the connection is not
+// allowed, it is recorded as rejected in the access log, from and to the given addresses. The returned
+// function must be called when the connection is closed.
+func AcquireUserConnection(m Manager, user *protocol.MemoryUser, source net.Address, from, to interface{}) (func(), error) {
+	if user == nil {
+		return func() {}, nil
+	}
+	release := func() {}
+	err := user.CheckQuota()
+	if l, ok := m.(UserConnectionLimiter); ok && err == nil {
+		release, err = l.AcquireConnection(user, source)
+	}
+	if err != nil {
+		log.Record(&log.AccessMessage{
+			From:   from,
+			To:     to,
+			Status: log.AccessRejected,
+			Reason: err,
+			Email:  user.Email,
+		})
+		return nil, errors.New("rejected user ", user.Email).Base(err).AtInfo()
+	}
+	return release, nil
+}
+
 // ManagerType returns the type of Manager interface. Can be used to implement common.HasType.
 //
 // xray:api:stable
@@ -44,6 +44,18 @@ type Context interface {
 	// GetVlessRoute returns the user-sent VLESS UUID's 7th<<8 | 8th bytes, if exists.
 	GetVlessRoute() net.Port
 
+	// GetALPN returns the protocols in the ALPN of the sniffed TLS ClientHello, if exists.
+	GetALPN() []string
+
+	// GetTLSVersion returns the highest TLS version offered by the sniffed TLS ClientHello, if exists.
+	GetTLSVersion() string
+
+	// GetJA3 returns the JA3 fingerprint of the sniffed TLS ClientHello, if exists.
+	GetJA3() string
+
+	// GetJA4 returns the JA4 fingerprint of the sniffed TLS ClientHello, if exists.
+	GetJA4() string
+
 	// GetAttributes returns extra attributes from the conneciont content.
 	GetAttributes() map[string]string
 
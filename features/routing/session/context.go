@@ -136,6 +136,38 @@ func (ctx *Context) GetVlessRoute() net.Port {
 	return ctx.Inbound.VlessRoute
 }
 
+// GetALPN implements routing.Context.
+func (ctx *Context) GetALPN() []string {
+	if ctx.Content == nil || ctx.Content.ClientHello == nil {
+		return nil
+	}
+	return ctx.Content.ClientHello.ALPN
+}
+
+// GetTLSVersion implements routing.Context.
+func (ctx *Context) GetTLSVersion() string {
+	if ctx.Content == nil || ctx.Content.ClientHello == nil {
+		return ""
+	}
+	return ctx.Content.ClientHello.Version
+}
+
+// GetJA3 implements routing.Context.
+func (ctx *Context) GetJA3() string {
+	if ctx.Content == nil || ctx.Content.ClientHello == nil {
+		return ""
+	}
+	return ctx.Content.ClientHello.JA3
+}
+
+// GetJA4 implements routing.Context.
+func (ctx *Context) GetJA4() string {
+	if ctx.Content == nil || ctx.Content.ClientHello == nil {
+		return ""
+	}
+	return ctx.Content.ClientHello.JA4
+}
+
 // GetAttributes implements routing.Context.
 func (ctx *Context) GetAttributes() map[string]string {
 	if ctx.Content == nil {
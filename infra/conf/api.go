@@ -3,20 +3,56 @@ package conf
 import (
 	"strings"
 
+	banservice "github.com/xtls/xray-core/app/ban/command"
 	"github.com/xtls/xray-core/app/commander"
+	connectionservice "github.com/xtls/xray-core/app/dispatcher/command"
+	fakednsservice "github.com/xtls/xray-core/app/dns/fakedns/command"
 	loggerservice "github.com/xtls/xray-core/app/log/command"
 	observatoryservice "github.com/xtls/xray-core/app/observatory/command"
+	policyservice "github.com/xtls/xray-core/app/policy/command"
 	handlerservice "github.com/xtls/xray-core/app/proxyman/command"
+	reloadservice "github.com/xtls/xray-core/app/reload/command"
 	routerservice "github.com/xtls/xray-core/app/router/command"
 	statsservice "github.com/xtls/xray-core/app/stats/command"
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/serial"
 )
 
+// APICredentialConfig is a credential allowed to call the API.
+type APICredentialConfig struct {
+	Token      string `json:"token"`
+	ClientName string `json:"clientName"`
+	Scope      string `json:"scope"`
+}
+
+func (c *APICredentialConfig) Build() (*commander.Credential, error) {
+	credential := &commander.Credential{
+		Token:      c.Token,
+		ClientName: c.ClientName,
+	}
+	switch strings.ToLower(c.Scope) {
+	case "", "read":
+		credential.Scope = commander.Scope_Read
+	case "users":
+		credential.Scope = commander.Scope_Users
+	case "full":
+		credential.Scope = commander.Scope_Full
+	default:
+		return nil, errors.New("unknown API scope: ", c.Scope)
+	}
+	return credential, nil
+}
+
 type APIConfig struct {
 	Tag      string   `json:"tag"`
 	Listen   string   `json:"listen"`
 	Services []string `json:"services"`
+
+	Credentials     []*APICredentialConfig `json:"credentials"`
+	CertificateFile string                 `json:"certificateFile"`
+	KeyFile         string                 `json:"keyFile"`
+	ClientCAFile    string                 `json:"clientCAFile"`
+	GatewayListen   string                 `json:"gatewayListen"`
 }
 
 func (c *APIConfig) Build() (*commander.Config, error) {
@@ -39,12 +75,36 @@ func (c *APIConfig) Build() (*commander.Config, error) {
 			services = append(services, serial.ToTypedMessage(&observatoryservice.Config{}))
 		case "routingservice":
 			services = append(services, serial.ToTypedMessage(&routerservice.Config{}))
+		case "policyservice":
+			services = append(services, serial.ToTypedMessage(&policyservice.Config{}))
+		case "reloadservice":
+			services = append(services, serial.ToTypedMessage(&reloadservice.Config{}))
+		case "connectionservice":
+			services = append(services, serial.ToTypedMessage(&connectionservice.Config{}))
+		case "fakednsservice":
+			services = append(services, serial.ToTypedMessage(&fakednsservice.Config{}))
+		case "banservice":
+			services = append(services, serial.ToTypedMessage(&banservice.Config{}))
+		}
+	}
+
+	credentials := make([]*commander.Credential, 0, len(c.Credentials))
+	for _, cc := range c.Credentials {
+		credential, err := cc.Build()
+		if err != nil {
+			return nil, err
 		}
+		credentials = append(credentials, credential)
 	}
 
 	return &commander.Config{
-		Tag:     c.Tag,
-		Listen:  c.Listen,
-		Service: services,
+		Tag:             c.Tag,
+		Listen:          c.Listen,
+		Service:         services,
+		Credentials:     credentials,
+		CertificateFile: c.CertificateFile,
+		KeyFile:         c.KeyFile,
+		ClientCaFile:    c.ClientCAFile,
+		GatewayListen:   c.GatewayListen,
 	}, nil
 }
@@ -0,0 +1,27 @@
+package conf
+
+import (
+	"github.com/xtls/xray-core/app/ban"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/infra/conf/cfgcommon/duration"
+)
+
+type BanConfig struct {
+	MaxFailures uint32            `json:"maxFailures"`
+	FindTime    duration.Duration `json:"findTime"`
+	BanTime     duration.Duration `json:"banTime"`
+}
+
+func (c *BanConfig) Build() (*ban.Config, error) {
+	if c.FindTime < 0 {
+		return nil, errors.New("invalid findTime of ban")
+	}
+	if c.BanTime < 0 {
+		return nil, errors.New("invalid banTime of ban")
+	}
+	return &ban.Config{
+		MaxFailures: c.MaxFailures,
+		FindTime:    int64(c.FindTime),
+		BanTime:     int64(c.BanTime),
+	}, nil
+}
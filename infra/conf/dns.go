@@ -2,6 +2,7 @@ package conf
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -13,6 +14,7 @@ import (
 	"github.com/xtls/xray-core/app/router"
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/infra/conf/cfgcommon/duration"
 )
 
 type NameServerConfig struct {
@@ -103,7 +105,8 @@ func (c *NameServerConfig) Build() (*dns.NameServer, error) {
 	var domains []*dns.NameServer_PriorityDomain
 	var originalRules []*dns.NameServer_OriginalRule
 
-	for _, rule := range c.Domains {
+	ruleSets, rules := splitRuleSets(c.Domains)
+	for _, rule := range rules {
 		parsedDomain, err := parseDomainRule(rule)
 		if err != nil {
 			return nil, errors.New("invalid domain rule: ", rule).Base(err)
@@ -172,6 +175,7 @@ func (c *NameServerConfig) Build() (*dns.NameServer, error) {
 		ClientIp:          myClientIP,
 		SkipFallback:      c.SkipFallback,
 		PrioritizedDomain: domains,
+		DomainRuleSet:     ruleSets,
 		ExpectedGeoip:     expectedGeoipList,
 		OriginalRules:     originalRules,
 		QueryStrategy:     resolveQueryStrategy(c.QueryStrategy),
@@ -208,6 +212,8 @@ type DNSConfig struct {
 	DisableFallbackIfMatch bool                `json:"disableFallbackIfMatch"`
 	EnableParallelQuery    bool                `json:"enableParallelQuery"`
 	UseSystemHosts         bool                `json:"useSystemHosts"`
+	CacheFile              string              `json:"cacheFile"`
+	CacheSaveInterval      duration.Duration   `json:"cacheSaveInterval"`
 }
 
 type HostAddress struct {
@@ -408,6 +414,15 @@ func (c *DNSConfig) Build() (*dns.Config, error) {
 		DisableFallbackIfMatch: c.DisableFallbackIfMatch,
 		EnableParallelQuery:    c.EnableParallelQuery,
 		QueryStrategy:          resolveQueryStrategy(c.QueryStrategy),
+		CacheFile:              c.CacheFile,
+		CacheSaveInterval:      int64(c.CacheSaveInterval),
+	}
+
+	if c.CacheSaveInterval < 0 {
+		return nil, errors.New("invalid cacheSaveInterval")
+	}
+	if c.CacheFile != "" && c.DisableCache {
+		errors.LogWarning(context.Background(), "DNS cacheFile is useless when cache is disabled")
 	}
 
 	if c.ClientIP != nil {
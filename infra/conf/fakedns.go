@@ -11,8 +11,9 @@ import (
 )
 
 type FakeDNSPoolElementConfig struct {
-	IPPool  string `json:"ipPool"`
-	LRUSize int64  `json:"poolSize"`
+	IPPool    string `json:"ipPool"`
+	LRUSize   int64  `json:"poolSize"`
+	CacheFile string `json:"cacheFile"`
 }
 
 type FakeDNSConfig struct {
@@ -52,15 +53,16 @@ func (f *FakeDNSConfig) Build() (*fakedns.FakeDnsPoolMulti, error) {
 
 	if f.pool != nil {
 		fakeDNSPool.Pools = append(fakeDNSPool.Pools, &fakedns.FakeDnsPool{
-			IpPool:  f.pool.IPPool,
-			LruSize: f.pool.LRUSize,
+			IpPool:    f.pool.IPPool,
+			LruSize:   f.pool.LRUSize,
+			CacheFile: f.pool.CacheFile,
 		})
 		return &fakeDNSPool, nil
 	}
 
 	if f.pools != nil {
 		for _, v := range f.pools {
-			fakeDNSPool.Pools = append(fakeDNSPool.Pools, &fakedns.FakeDnsPool{IpPool: v.IPPool, LruSize: v.LRUSize})
+			fakeDNSPool.Pools = append(fakeDNSPool.Pools, &fakedns.FakeDnsPool{IpPool: v.IPPool, LruSize: v.LRUSize, CacheFile: v.CacheFile})
 		}
 		return &fakeDNSPool, nil
 	}
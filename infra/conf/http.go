@@ -26,6 +26,7 @@ type HTTPServerConfig struct {
 	Accounts    []*HTTPAccount `json:"accounts"`
 	Transparent bool           `json:"allowTransparent"`
 	UserLevel   uint32         `json:"userLevel"`
+	Webhook     *WebhookConfig `json:"authWebhook"`
 }
 
 func (c *HTTPServerConfig) Build() (proto.Message, error) {
@@ -41,6 +42,14 @@ func (c *HTTPServerConfig) Build() (proto.Message, error) {
 		}
 	}
 
+	if c.Webhook != nil {
+		w, err := c.Webhook.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Webhook = w
+	}
+
 	return config, nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/xtls/xray-core/app/log"
+	"github.com/xtls/xray-core/common/errors"
 	clog "github.com/xtls/xray-core/common/log"
 )
 
@@ -21,11 +22,14 @@ type LogConfig struct {
 	LogLevel    string `json:"loglevel"`
 	DNSLog      bool   `json:"dnsLog"`
 	MaskAddress string `json:"maskAddress"`
+	Format      string `json:"format"`
+	// ConnectionLog enables the records of closed connections, see dispatcher.Config.
+	ConnectionLog bool `json:"connectionLog"`
 }
 
-func (v *LogConfig) Build() *log.Config {
+func (v *LogConfig) Build() (*log.Config, error) {
 	if v == nil {
-		return nil
+		return nil, nil
 	}
 	config := &log.Config{
 		ErrorLogType:  log.LogType_Console,
@@ -61,5 +65,14 @@ func (v *LogConfig) Build() *log.Config {
 		config.ErrorLogLevel = clog.Severity_Warning
 	}
 	config.MaskAddress = v.MaskAddress
-	return config
+
+	switch strings.ToLower(v.Format) {
+	case "", "text":
+		config.Format = log.LogFormat_Text
+	case "json":
+		config.Format = log.LogFormat_JSON
+	default:
+		return nil, errors.New("unknown log format: ", v.Format)
+	}
+	return config, nil
 }
@@ -1,6 +1,10 @@
 package conf
 
 import (
+	"net"
+	"net/url"
+	"strings"
+
 	"google.golang.org/protobuf/proto"
 
 	"github.com/xtls/xray-core/app/observatory"
@@ -14,24 +18,103 @@ type ObservatoryConfig struct {
 	ProbeURL          string            `json:"probeURL"`
 	ProbeInterval     duration.Duration `json:"probeInterval"`
 	EnableConcurrency bool              `json:"enableConcurrency"`
+	Probes            ProbesConfig      `json:"probes"`
 }
 
 func (o *ObservatoryConfig) Build() (proto.Message, error) {
-	return &observatory.Config{SubjectSelector: o.SubjectSelector, ProbeUrl: o.ProbeURL, ProbeInterval: int64(o.ProbeInterval), EnableConcurrency: o.EnableConcurrency}, nil
+	probes, err := o.Probes.Build()
+	if err != nil {
+		return nil, err
+	}
+	return &observatory.Config{SubjectSelector: o.SubjectSelector, ProbeUrl: o.ProbeURL, ProbeInterval: int64(o.ProbeInterval), EnableConcurrency: o.EnableConcurrency, Probes: probes}, nil
+}
+
+type ProbeConfig struct {
+	SubjectSelector []string `json:"subjectSelector"`
+	Type            string   `json:"type"`
+	Destination     string   `json:"destination"`
+	HTTPMethod      string   `json:"httpMethod"`
+	ExpectedStatus  uint32   `json:"expectedStatus"`
+	Expected        string   `json:"expected"`
+	Domain          string   `json:"domain"`
+	Payload         string   `json:"payload"`
+}
+
+func (p *ProbeConfig) Build() (*observatory.Probe, error) {
+	if len(p.SubjectSelector) == 0 {
+		return nil, errors.New("subjectSelector of probe is not specified")
+	}
+	if p.Destination == "" {
+		return nil, errors.New("destination of probe is not specified")
+	}
+	probe := &observatory.Probe{
+		SubjectSelector: p.SubjectSelector,
+		Destination:     p.Destination,
+		HttpMethod:      strings.ToUpper(strings.TrimSpace(p.HTTPMethod)),
+		ExpectedStatus:  p.ExpectedStatus,
+		Expected:        p.Expected,
+		Domain:          p.Domain,
+		Payload:         []byte(p.Payload),
+	}
+	switch strings.ToLower(p.Type) {
+	case "http", "":
+		probe.Type = observatory.Probe_HTTP
+		if u, err := url.Parse(p.Destination); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, errors.New("invalid URL of HTTP probe: ", p.Destination)
+		}
+	case "tcp":
+		probe.Type = observatory.Probe_TCP
+	case "udp":
+		probe.Type = observatory.Probe_UDP
+	case "dns":
+		probe.Type = observatory.Probe_DNS
+	default:
+		return nil, errors.New("unknown probe type: ", p.Type)
+	}
+	if probe.Type == observatory.Probe_TCP || probe.Type == observatory.Probe_UDP {
+		if _, _, err := net.SplitHostPort(p.Destination); err != nil {
+			return nil, errors.New("invalid destination of ", p.Type, " probe: ", p.Destination).Base(err)
+		}
+	}
+	// Connections through outbounds are established lazily, so a TCP probe sending nothing is only
+	// alive if the server speaks first, which it is expected to when a response is expected.
+	if probe.Type == observatory.Probe_TCP && p.Payload == "" && p.Expected == "" {
+		return nil, errors.New("TCP probe of ", p.Destination, " requires payload or expected")
+	}
+	return probe, nil
+}
+
+type ProbesConfig []*ProbeConfig
+
+func (c ProbesConfig) Build() ([]*observatory.Probe, error) {
+	var probes []*observatory.Probe
+	for _, p := range c {
+		probe, err := p.Build()
+		if err != nil {
+			return nil, err
+		}
+		probes = append(probes, probe)
+	}
+	return probes, nil
 }
 
 type BurstObservatoryConfig struct {
 	SubjectSelector []string `json:"subjectSelector"`
 	// health check settings
 	HealthCheck *healthCheckSettings `json:"pingConfig,omitempty"`
+	Probes      ProbesConfig         `json:"probes"`
 }
 
 func (b BurstObservatoryConfig) Build() (proto.Message, error) {
 	if b.HealthCheck == nil {
 		return nil, errors.New("BurstObservatory requires a valid pingConfig")
 	}
+	probes, err := b.Probes.Build()
+	if err != nil {
+		return nil, err
+	}
 	if result, err := b.HealthCheck.Build(); err == nil {
-		return &burst.Config{SubjectSelector: b.SubjectSelector, PingConfig: result.(*burst.HealthPingConfig)}, nil
+		return &burst.Config{SubjectSelector: b.SubjectSelector, PingConfig: result.(*burst.HealthPingConfig), Probes: probes}, nil
 	} else {
 		return nil, err
 	}
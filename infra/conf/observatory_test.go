@@ -0,0 +1,32 @@
+package conf_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/xtls/xray-core/app/observatory"
+	"github.com/xtls/xray-core/common"
+	. "github.com/xtls/xray-core/infra/conf"
+)
+
+func TestProbeConfig(t *testing.T) {
+	for _, c := range []struct {
+		input string
+		valid bool
+	}{
+		{`{"subjectSelector": ["a"], "type": "tcp", "destination": "example.com:80", "payload": "HEAD / HTTP/1.0\r\n\r\n"}`, true},
+		{`{"subjectSelector": ["a"], "type": "tcp", "destination": "example.com:22", "expected": "SSH-"}`, true},
+		{`{"subjectSelector": ["a"], "type": "tcp", "destination": "example.com:80"}`, false},
+		{`{"subjectSelector": ["a"], "type": "udp", "destination": "example.com:53"}`, true},
+	} {
+		config := new(ProbeConfig)
+		common.Must(json.Unmarshal([]byte(c.input), config))
+		probe, err := config.Build()
+		if (err == nil) != c.valid {
+			t.Error("unexpected result of ", c.input, ": ", err)
+		}
+		if err == nil && probe.Type != observatory.Probe_TCP && probe.Type != observatory.Probe_UDP {
+			t.Error("unexpected type of ", c.input, ": ", probe.Type)
+		}
+	}
+}
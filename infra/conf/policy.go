@@ -1,9 +1,109 @@
 package conf
 
 import (
+	"encoding/json"
+	"strconv"
+	"time"
+
 	"github.com/xtls/xray-core/app/policy"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/common/units"
 )
 
+// ByteSize is an amount of bytes, either a number or a string with unit like "10MB".
+type ByteSize uint64
+
+// UnmarshalJSON implements encoding/json.Unmarshaler.UnmarshalJSON
+func (v *ByteSize) UnmarshalJSON(data []byte) error {
+	var n uint64
+	if err := json.Unmarshal(data, &n); err == nil {
+		*v = ByteSize(n)
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return errors.New("invalid byte size: ", string(data))
+	}
+	if n, err := strconv.ParseUint(str, 10, 64); err == nil {
+		*v = ByteSize(n)
+		return nil
+	}
+	var size units.ByteSize
+	if err := size.Parse(str); err != nil {
+		return errors.New("invalid byte size: ", str).Base(err)
+	}
+	*v = ByteSize(size)
+	return nil
+}
+
+// Value returns the size in bytes, 0 if v is nil.
+func (v *ByteSize) Value() uint64 {
+	if v == nil {
+		return 0
+	}
+	return uint64(*v)
+}
+
+// RateLimitConfig is the throughput limit of each user, in bytes per second.
+type RateLimitConfig struct {
+	Uplink        *ByteSize `json:"uplink"`
+	Downlink      *ByteSize `json:"downlink"`
+	UplinkBurst   *ByteSize `json:"uplinkBurst"`
+	DownlinkBurst *ByteSize `json:"downlinkBurst"`
+}
+
+func (c *RateLimitConfig) Build() *policy.Policy_RateLimit {
+	return &policy.Policy_RateLimit{
+		Uplink:        c.Uplink.Value(),
+		Downlink:      c.Downlink.Value(),
+		UplinkBurst:   c.UplinkBurst.Value(),
+		DownlinkBurst: c.DownlinkBurst.Value(),
+	}
+}
+
+// Timestamp is a point in time, either Unix seconds or a string in RFC 3339 format.
+type Timestamp int64
+
+// UnmarshalJSON implements encoding/json.Unmarshaler.UnmarshalJSON
+func (v *Timestamp) UnmarshalJSON(data []byte) error {
+	var n int64
+	if err := json.Unmarshal(data, &n); err == nil {
+		*v = Timestamp(n)
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return errors.New("invalid time: ", string(data))
+	}
+	t, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		return errors.New("invalid time: ", str).Base(err)
+	}
+	*v = Timestamp(t.Unix())
+	return nil
+}
+
+// UserPolicyConfig is the per-user policy settings shared by the clients of all protocols.
+type UserPolicyConfig struct {
+	UplinkRateLimit   *ByteSize  `json:"uplinkRateLimit"`
+	DownlinkRateLimit *ByteSize  `json:"downlinkRateLimit"`
+	TrafficQuota      *ByteSize  `json:"trafficQuota"`
+	TrafficUsed       *ByteSize  `json:"trafficUsed"`
+	ExpireTime        *Timestamp `json:"expireTime"`
+}
+
+// Apply sets the per-user policy settings to user.
+func (c *UserPolicyConfig) Apply(user *protocol.User) {
+	user.UplinkRateLimit = c.UplinkRateLimit.Value()
+	user.DownlinkRateLimit = c.DownlinkRateLimit.Value()
+	user.TrafficQuota = c.TrafficQuota.Value()
+	user.TrafficUsed = c.TrafficUsed.Value()
+	if c.ExpireTime != nil {
+		user.ExpireTime = int64(*c.ExpireTime)
+	}
+}
+
 type Policy struct {
 	Handshake         *uint32 `json:"handshake"`
 	ConnectionIdle    *uint32 `json:"connIdle"`
@@ -13,6 +113,11 @@ type Policy struct {
 	StatsUserDownlink bool    `json:"statsUserDownlink"`
 	StatsUserOnline   bool    `json:"statsUserOnline"`
 	BufferSize        *int32  `json:"bufferSize"`
+
+	RateLimit *RateLimitConfig `json:"rateLimit"`
+
+	MaxConnections uint32 `json:"maxConnections"`
+	MaxIPs         uint32 `json:"maxIPs"`
 }
 
 func (t *Policy) Build() (*policy.Policy, error) {
@@ -49,6 +154,17 @@ func (t *Policy) Build() (*policy.Policy, error) {
 		}
 	}
 
+	if t.RateLimit != nil {
+		p.RateLimit = t.RateLimit.Build()
+	}
+
+	if t.MaxConnections > 0 || t.MaxIPs > 0 {
+		p.ConnectionLimit = &policy.Policy_ConnectionLimit{
+			MaxConnections: t.MaxConnections,
+			MaxIps:         t.MaxIPs,
+		}
+	}
+
 	return p, nil
 }
 
@@ -1,6 +1,7 @@
 package conf_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/xtls/xray-core/common"
@@ -38,3 +39,34 @@ func TestBufferSize(t *testing.T) {
 		}
 	}
 }
+
+func TestRateLimit(t *testing.T) {
+	var p Policy
+	common.Must(json.Unmarshal([]byte(`{"rateLimit": {"uplink": "1MB", "downlink": 2048, "downlinkBurst": "4096"}}`), &p))
+	pb, err := p.Build()
+	common.Must(err)
+	if r := pb.RateLimit; r.Uplink != 1024*1024 || r.Downlink != 2048 || r.UplinkBurst != 0 || r.DownlinkBurst != 4096 {
+		t.Error("unexpected rate limit ", r)
+	}
+
+	if err := json.Unmarshal([]byte(`{"rateLimit": {"uplink": "1XB"}}`), &p); err == nil {
+		t.Error("expect error for invalid unit")
+	}
+}
+
+func TestConnectionLimit(t *testing.T) {
+	var p Policy
+	common.Must(json.Unmarshal([]byte(`{"maxConnections": 10, "maxIPs": 2}`), &p))
+	pb, err := p.Build()
+	common.Must(err)
+	if l := pb.ConnectionLimit; l.MaxConnections != 10 || l.MaxIps != 2 {
+		t.Error("unexpected connection limit ", l)
+	}
+
+	p = Policy{}
+	pb, err = p.Build()
+	common.Must(err)
+	if pb.ConnectionLimit != nil {
+		t.Error("expect no connection limit by default")
+	}
+}
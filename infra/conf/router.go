@@ -3,11 +3,14 @@ package conf
 import (
 	"bufio"
 	"bytes"
+	"crypto/md5"
+	"encoding/hex"
 	"encoding/json"
 	"io"
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/xtls/xray-core/app/router"
 	"github.com/xtls/xray-core/common/errors"
@@ -44,7 +47,7 @@ func (r *BalancingRule) Build() (*router.BalancingRule, error) {
 	switch r.Strategy.Type {
 	case "":
 		r.Strategy.Type = strategyRandom
-	case strategyRandom, strategyLeastLoad, strategyLeastPing, strategyRoundRobin:
+	case strategyRandom, strategyLeastLoad, strategyLeastPing, strategyRoundRobin, strategyConsistentHash:
 	default:
 		return nil, errors.New("unknown balancing strategy: " + r.Strategy.Type)
 	}
@@ -204,6 +207,19 @@ func loadIP(file, code string) ([]*router.CIDR, error) {
 	return geoip.Cidr, nil
 }
 
+func loadMMDBIP(file, code string) ([]*router.CIDR, error) {
+	bs, err := filesystem.ReadAsset(file)
+	if err != nil {
+		return nil, errors.New("failed to open file: ", file).Base(err)
+	}
+	cidrs, err := router.LoadMMDBCIDRs(bs, code)
+	if err != nil {
+		return nil, errors.New("failed to load IPs from ", file).Base(err)
+	}
+	defer runtime.GC()
+	return cidrs, nil
+}
+
 func loadSite(file, code string) ([]*router.Domain, error) {
 
 	// Check if domain matcher cache is provided via environment
@@ -465,6 +481,7 @@ func ToCidrList(ips StringList) ([]*router.GeoIP, error) {
 			continue
 		}
 		isExtDatFile := 0
+		isMMDB := false
 		{
 			const prefix = "ext:"
 			if strings.HasPrefix(ip, prefix) {
@@ -474,6 +491,11 @@ func ToCidrList(ips StringList) ([]*router.GeoIP, error) {
 			if strings.HasPrefix(ip, prefixQualified) {
 				isExtDatFile = len(prefixQualified)
 			}
+			const prefixMMDB = "ext-mmdb:"
+			if strings.HasPrefix(ip, prefixMMDB) {
+				isExtDatFile = len(prefixMMDB)
+				isMMDB = true
+			}
 		}
 		if isExtDatFile != 0 {
 			kv := strings.Split(ip[isExtDatFile:], ":")
@@ -492,7 +514,11 @@ func ToCidrList(ips StringList) ([]*router.GeoIP, error) {
 				country = country[1:]
 				isReverseMatch = true
 			}
-			geoip, err := loadIP(filename, strings.ToUpper(country))
+			load := loadIP
+			if isMMDB {
+				load = loadMMDBIP
+			}
+			geoip, err := load(filename, strings.ToUpper(country))
 			if err != nil {
 				return nil, errors.New("failed to load IPs: ", country, " from ", filename).Base(err)
 			}
@@ -541,6 +567,13 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 		LocalIP    *StringList       `json:"localIP"`
 		LocalPort  *PortList         `json:"localPort"`
 		Process    *StringList       `json:"process"`
+		Time       *StringList       `json:"time"`
+		Weekday    *StringList       `json:"weekday"`
+		Timezone   string            `json:"timezone"`
+		ALPN       *StringList       `json:"alpn"`
+		TLSVersion *StringList       `json:"tlsVersion"`
+		JA3        *StringList       `json:"ja3"`
+		JA4        *StringList       `json:"ja4"`
 	}
 	rawFieldRule := new(RawFieldRule)
 	err := json.Unmarshal(msg, rawFieldRule)
@@ -564,7 +597,9 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 	}
 
 	if rawFieldRule.Domain != nil {
-		for _, domain := range *rawFieldRule.Domain {
+		ruleSets, domains := splitRuleSets(*rawFieldRule.Domain)
+		rule.DomainRuleSet = append(rule.DomainRuleSet, ruleSets...)
+		for _, domain := range domains {
 			rules, err := parseDomainRule(domain)
 			if err != nil {
 				return nil, errors.New("failed to parse domain rule: ", domain).Base(err)
@@ -574,7 +609,9 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 	}
 
 	if rawFieldRule.Domains != nil {
-		for _, domain := range *rawFieldRule.Domains {
+		ruleSets, domains := splitRuleSets(*rawFieldRule.Domains)
+		rule.DomainRuleSet = append(rule.DomainRuleSet, ruleSets...)
+		for _, domain := range domains {
 			rules, err := parseDomainRule(domain)
 			if err != nil {
 				return nil, errors.New("failed to parse domain rule: ", domain).Base(err)
@@ -584,11 +621,15 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 	}
 
 	if rawFieldRule.IP != nil {
-		geoipList, err := ToCidrList(*rawFieldRule.IP)
-		if err != nil {
-			return nil, err
+		ruleSets, ips := splitRuleSets(*rawFieldRule.IP)
+		rule.IpRuleSet = ruleSets
+		if len(ips) > 0 {
+			geoipList, err := ToCidrList(ips)
+			if err != nil {
+				return nil, err
+			}
+			rule.Geoip = geoipList
 		}
-		rule.Geoip = geoipList
 	}
 
 	if rawFieldRule.Port != nil {
@@ -657,9 +698,150 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 		rule.Process = *rawFieldRule.Process
 	}
 
+	if rawFieldRule.ALPN != nil {
+		for _, s := range *rawFieldRule.ALPN {
+			rule.Alpn = append(rule.Alpn, strings.TrimSpace(s))
+		}
+	}
+
+	if rawFieldRule.TLSVersion != nil {
+		for _, s := range *rawFieldRule.TLSVersion {
+			switch v := strings.ToLower(strings.TrimSpace(s)); v {
+			case "ssl3", "1.0", "1.1", "1.2", "1.3":
+				rule.TlsVersion = append(rule.TlsVersion, v)
+			default:
+				return nil, errors.New("invalid tlsVersion: ", s)
+			}
+		}
+	}
+
+	if rawFieldRule.JA3 != nil {
+		for _, s := range *rawFieldRule.JA3 {
+			v := strings.ToLower(strings.TrimSpace(s))
+			if b, err := hex.DecodeString(v); err != nil || len(b) != md5.Size {
+				return nil, errors.New("invalid ja3, which should be an MD5 hash in hex: ", s)
+			}
+			rule.Ja3 = append(rule.Ja3, v)
+		}
+	}
+
+	if rawFieldRule.JA4 != nil {
+		for _, s := range *rawFieldRule.JA4 {
+			rule.Ja4 = append(rule.Ja4, strings.ToLower(strings.TrimSpace(s)))
+		}
+	}
+
+	if rawFieldRule.Time != nil {
+		for _, s := range *rawFieldRule.Time {
+			r, err := parseTimeRange(s)
+			if err != nil {
+				return nil, err
+			}
+			rule.TimeRange = append(rule.TimeRange, r)
+		}
+	}
+
+	if rawFieldRule.Weekday != nil {
+		for _, s := range *rawFieldRule.Weekday {
+			days, err := parseWeekdays(s)
+			if err != nil {
+				return nil, err
+			}
+			rule.Weekday = append(rule.Weekday, days...)
+		}
+	}
+
+	if rawFieldRule.Timezone != "" {
+		if len(rule.TimeRange) == 0 && len(rule.Weekday) == 0 {
+			return nil, errors.New("timezone is specified without time or weekday in routing rule")
+		}
+		if _, err := time.LoadLocation(rawFieldRule.Timezone); err != nil {
+			return nil, errors.New("unknown timezone ", rawFieldRule.Timezone).Base(err)
+		}
+		rule.Timezone = rawFieldRule.Timezone
+	}
+
 	return rule, nil
 }
 
+// parseClock parses a time of the day like "22:30" or "22:30:15", in seconds since midnight.
+// "24:00" is accepted as the end of the day.
+func parseClock(s string) (uint32, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, errors.New("invalid time: ", s)
+	}
+	var values [3]int
+	for i, part := range parts {
+		v, err := strconv.Atoi(part)
+		if err != nil || v < 0 {
+			return 0, errors.New("invalid time: ", s)
+		}
+		values[i] = v
+	}
+	hour, minute, second := values[0], values[1], values[2]
+	if minute > 59 || second > 59 || hour > 24 || (hour == 24 && (minute > 0 || second > 0)) {
+		return 0, errors.New("invalid time: ", s)
+	}
+	return uint32(hour*60*60 + minute*60 + second), nil
+}
+
+// parseTimeRange parses a time window of the day like "22:00-06:00".
+func parseTimeRange(s string) (*router.TimeRange, error) {
+	from, to, found := strings.Cut(s, "-")
+	if !found {
+		return nil, errors.New("invalid time range: ", s)
+	}
+	r := new(router.TimeRange)
+	var err error
+	if r.From, err = parseClock(from); err != nil {
+		return nil, err
+	}
+	if r.To, err = parseClock(to); err != nil {
+		return nil, err
+	}
+	if r.From == r.To || r.From == 24*60*60 {
+		return nil, errors.New("invalid time range: ", s)
+	}
+	return r, nil
+}
+
+func parseWeekday(s string) (uint32, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if d, err := strconv.ParseUint(s, 10, 32); err == nil && d <= 6 {
+		return uint32(d), nil
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return uint32(d), nil
+		}
+	}
+	return 0, errors.New("invalid weekday: ", s)
+}
+
+// parseWeekdays parses a day of the week like "sat", "saturday" or "6", or a range of days like "mon-fri".
+func parseWeekdays(s string) ([]uint32, error) {
+	from, to, found := strings.Cut(s, "-")
+	first, err := parseWeekday(from)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return []uint32{first}, nil
+	}
+	last, err := parseWeekday(to)
+	if err != nil {
+		return nil, err
+	}
+	days := []uint32{first}
+	for d := first; d != last; {
+		d = (d + 1) % 7
+		days = append(days, d)
+	}
+	return days, nil
+}
+
 func parseRule(msg json.RawMessage) (*router.RoutingRule, error) {
 	rawRule := new(RouterRule)
 	err := json.Unmarshal(msg, rawRule)
@@ -6,6 +6,7 @@ import (
 
 	"github.com/xtls/xray-core/app/observatory/burst"
 	"github.com/xtls/xray-core/app/router"
+	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/infra/conf/cfgcommon/duration"
 )
 
@@ -14,6 +15,8 @@ const (
 	strategyLeastPing  string = "leastping"
 	strategyRoundRobin string = "roundrobin"
 	strategyLeastLoad  string = "leastload"
+
+	strategyConsistentHash string = "consistenthash"
 )
 
 var (
@@ -22,6 +25,8 @@ var (
 		strategyLeastPing:  func() interface{} { return new(strategyEmptyConfig) },
 		strategyRoundRobin: func() interface{} { return new(strategyEmptyConfig) },
 		strategyLeastLoad:  func() interface{} { return new(strategyLeastLoadConfig) },
+
+		strategyConsistentHash: func() interface{} { return new(strategyConsistentHashConfig) },
 	}, "type", "settings")
 )
 
@@ -100,3 +105,24 @@ func (v *strategyLeastLoadConfig) Build() (proto.Message, error) {
 	}
 	return config, nil
 }
+
+type strategyConsistentHashConfig struct {
+	// key of connections to hash: sourceIP, email or domain
+	Key string `json:"key,omitempty"`
+}
+
+// Build implements Buildable.
+func (v *strategyConsistentHashConfig) Build() (proto.Message, error) {
+	config := &router.StrategyConsistentHashConfig{}
+	switch strings.ToLower(v.Key) {
+	case "sourceip", "":
+		config.Key = router.StrategyConsistentHashConfig_SourceIP
+	case "email", "user":
+		config.Key = router.StrategyConsistentHashConfig_Email
+	case "domain":
+		config.Key = router.StrategyConsistentHashConfig_Domain
+	default:
+		return nil, errors.New("unknown key of consistentHash strategy: ", v.Key)
+	}
+	return config, nil
+}
@@ -109,6 +109,21 @@ func TestRouterConfig(t *testing.T) {
 					},{
 						"port": 123,
 						"outboundTag": "test"
+					},{
+						"time": "22:00-06:00, 12:00:30-13:00",
+						"weekday": ["fri-mon", "Wednesday"],
+						"timezone": "Asia/Shanghai",
+						"outboundTag": "test"
+					},{
+						"alpn": ["h2", "h3"],
+						"tlsVersion": "1.3",
+						"ja3": "E7D705A3286E19EA42F587B344EE6865",
+						"ja4": "t13d1516h2_8daaf6152771_e5627efa2ab1",
+						"outboundTag": "test"
+					},{
+						"domain": ["ruleset:ads", "domain:example.com"],
+						"ip": ["ruleset:bogon", "10.0.0.0/8"],
+						"outboundTag": "test"
 					}
 				],
 				"balancers": [
@@ -237,6 +252,49 @@ func TestRouterConfig(t *testing.T) {
 							Tag: "test",
 						},
 					},
+					{
+						TimeRange: []*router.TimeRange{
+							{From: 22 * 60 * 60, To: 6 * 60 * 60},
+							{From: 12*60*60 + 30, To: 13 * 60 * 60},
+						},
+						Weekday:  []uint32{5, 6, 0, 1, 3},
+						Timezone: "Asia/Shanghai",
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "test",
+						},
+					},
+					{
+						Alpn:       []string{"h2", "h3"},
+						TlsVersion: []string{"1.3"},
+						Ja3:        []string{"e7d705a3286e19ea42f587b344ee6865"},
+						Ja4:        []string{"t13d1516h2_8daaf6152771_e5627efa2ab1"},
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "test",
+						},
+					},
+					{
+						Domain: []*router.Domain{
+							{
+								Type:  router.Domain_Domain,
+								Value: "example.com",
+							},
+						},
+						DomainRuleSet: []string{"ads"},
+						Geoip: []*router.GeoIP{
+							{
+								Cidr: []*router.CIDR{
+									{
+										Ip:     []byte{10, 0, 0, 0},
+										Prefix: 8,
+									},
+								},
+							},
+						},
+						IpRuleSet: []string{"bogon"},
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "test",
+						},
+					},
 				},
 			},
 		},
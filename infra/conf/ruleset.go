@@ -0,0 +1,103 @@
+package conf
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/xtls/xray-core/app/router"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/infra/conf/cfgcommon/duration"
+)
+
+// ruleSetPrefix references a rule set in the domain and IP lists of routing rules and DNS servers.
+const ruleSetPrefix = "ruleset:"
+
+type RuleSetConfig struct {
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	Format   string            `json:"format"`
+	Path     string            `json:"path"`
+	Code     string            `json:"code"`
+	Interval duration.Duration `json:"interval"`
+}
+
+func (c *RuleSetConfig) Build() (*router.RuleSet, error) {
+	if c.Name == "" {
+		return nil, errors.New("name of rule set is not specified")
+	}
+	if c.Path == "" {
+		return nil, errors.New("path of rule set ", c.Name, " is not specified")
+	}
+	if c.Interval < 0 {
+		return nil, errors.New("invalid interval of rule set ", c.Name)
+	}
+	config := &router.RuleSet{
+		Name:     c.Name,
+		Path:     c.Path,
+		Code:     c.Code,
+		Interval: int64(c.Interval),
+	}
+
+	switch strings.ToLower(c.Type) {
+	case "domain", "":
+		config.Type = router.RuleSet_Domain
+	case "ip":
+		config.Type = router.RuleSet_IP
+	default:
+		return nil, errors.New("unknown type of rule set ", c.Name, ": ", c.Type)
+	}
+
+	format := strings.ToLower(c.Format)
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(c.Path)), ".")
+	}
+	switch format {
+	case "dat":
+		config.Format = router.RuleSet_Dat
+	case "mmdb":
+		config.Format = router.RuleSet_MMDB
+	default:
+		if c.Format != "" && format != "plain" {
+			return nil, errors.New("unknown format of rule set ", c.Name, ": ", c.Format)
+		}
+		config.Format = router.RuleSet_Plain
+	}
+	if config.Format != router.RuleSet_Plain && config.Code == "" {
+		return nil, errors.New("code of rule set ", c.Name, " is not specified")
+	}
+	if config.Type == router.RuleSet_Domain && config.Format == router.RuleSet_MMDB {
+		return nil, errors.New("MMDB rule set ", c.Name, " can only be of IPs")
+	}
+	return config, nil
+}
+
+type RuleSetsConfig []*RuleSetConfig
+
+func (c RuleSetsConfig) Build() ([]*router.RuleSet, error) {
+	var ruleSets []*router.RuleSet
+	names := make(map[string]bool, len(c))
+	for _, s := range c {
+		r, err := s.Build()
+		if err != nil {
+			return nil, err
+		}
+		if names[r.Name] {
+			return nil, errors.New("duplicated rule set ", r.Name)
+		}
+		names[r.Name] = true
+		ruleSets = append(ruleSets, r)
+	}
+	return ruleSets, nil
+}
+
+// splitRuleSets separates the references of rule sets from the other rules.
+func splitRuleSets(rules []string) (ruleSets []string, others []string) {
+	for _, rule := range rules {
+		if name, found := strings.CutPrefix(rule, ruleSetPrefix); found {
+			ruleSets = append(ruleSets, name)
+		} else {
+			others = append(others, rule)
+		}
+	}
+	return
+}
@@ -37,6 +37,7 @@ type ShadowsocksUserConfig struct {
 	Email    string   `json:"email"`
 	Address  *Address `json:"address"`
 	Port     uint16   `json:"port"`
+	UserPolicyConfig
 }
 
 type ShadowsocksServerConfig struct {
@@ -71,11 +72,13 @@ func (v *ShadowsocksServerConfig) Build() (proto.Message, error) {
 				account.CipherType > shadowsocks.CipherType_XCHACHA20_POLY1305 {
 				return nil, errors.New("unsupported cipher method: ", user.Cipher)
 			}
-			config.Users = append(config.Users, &protocol.User{
+			u := &protocol.User{
 				Email:   user.Email,
 				Level:   uint32(user.Level),
 				Account: serial.ToTypedMessage(account),
-			})
+			}
+			user.UserPolicyConfig.Apply(u)
+			config.Users = append(config.Users, u)
 		}
 	} else {
 		account := &shadowsocks.Account{
@@ -128,11 +131,13 @@ func buildShadowsocks2022(v *ShadowsocksServerConfig) (proto.Message, error) {
 			account := &shadowsocks_2022.Account{
 				Key: user.Password,
 			}
-			config.Users = append(config.Users, &protocol.User{
+			u := &protocol.User{
 				Email:   user.Email,
 				Level:   uint32(user.Level),
 				Account: serial.ToTypedMessage(account),
-			})
+			}
+			user.UserPolicyConfig.Apply(u)
+			config.Users = append(config.Users, u)
 		}
 		return config, nil
 	}
@@ -0,0 +1,594 @@
+package sharelink
+
+import (
+	"crypto/ecdh"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/cloudflare/circl/sign/mldsa/mldsa65"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/infra/conf"
+)
+
+// object is a JSON object of config.
+type object = map[string]interface{}
+
+// Outbound returns the outbound config of the link, in the JSON of conf.OutboundDetourConfig.
+func (l *Link) Outbound() (json.RawMessage, error) {
+	outbound := object{
+		"protocol": l.Protocol,
+	}
+	if l.Name != "" {
+		outbound["tag"] = l.Name
+	}
+	settings := object{
+		"address": l.Address,
+		"port":    l.Port,
+	}
+	switch l.Protocol {
+	case "vless":
+		settings["id"] = l.ID
+		settings["encryption"] = l.Encryption
+		if l.Flow != "" {
+			settings["flow"] = l.Flow
+		}
+	case "vmess":
+		settings["id"] = l.ID
+		if l.Encryption != "" {
+			settings["security"] = l.Encryption
+		}
+	case "trojan":
+		settings["password"] = l.ID
+	case "shadowsocks":
+		settings["method"] = l.Encryption
+		settings["password"] = l.ID
+	case "hysteria2":
+		outbound["protocol"] = "hysteria"
+		settings["version"] = 2
+	default:
+		return nil, errors.New("unsupported protocol: ", l.Protocol)
+	}
+	outbound["settings"] = settings
+
+	stream, err := l.streamSettings()
+	if err != nil {
+		return nil, err
+	}
+	if len(stream) > 0 {
+		outbound["streamSettings"] = stream
+	}
+	return json.Marshal(outbound)
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// hostPath returns the settings of HTTP based transports.
+func hostPath(p url.Values) object {
+	settings := object{"path": p.Get("path")}
+	if host := p.Get("host"); host != "" {
+		settings["host"] = host
+	}
+	return settings
+}
+
+func (l *Link) streamSettings() (object, error) {
+	stream := object{}
+	p := l.Params
+	network := p.Get("type")
+	switch network {
+	case "", "tcp", "raw":
+		if p.Get("headerType") == "http" {
+			request := object{}
+			if path := p.Get("path"); path != "" {
+				request["path"] = splitList(path)
+			}
+			if host := p.Get("host"); host != "" {
+				request["headers"] = object{"Host": splitList(host)}
+			}
+			stream["rawSettings"] = object{
+				"header": object{"type": "http", "request": request},
+			}
+		}
+		network = ""
+	case "kcp", "mkcp":
+		kcp := object{}
+		if headerType := p.Get("headerType"); headerType != "" && headerType != "none" {
+			kcp["header"] = object{"type": headerType}
+		}
+		if seed := p.Get("seed"); seed != "" {
+			kcp["seed"] = seed
+		}
+		stream["kcpSettings"] = kcp
+		network = "kcp"
+	case "ws", "websocket":
+		stream["wsSettings"] = hostPath(p)
+		network = "ws"
+	case "httpupgrade":
+		stream["httpupgradeSettings"] = hostPath(p)
+	case "grpc":
+		grpc := object{"serviceName": p.Get("serviceName")}
+		if authority := p.Get("authority"); authority != "" {
+			grpc["authority"] = authority
+		}
+		if p.Get("mode") == "multi" {
+			grpc["multiMode"] = true
+		}
+		stream["grpcSettings"] = grpc
+	case "xhttp", "splithttp":
+		xhttp := hostPath(p)
+		if mode := p.Get("mode"); mode != "" {
+			xhttp["mode"] = mode
+		}
+		if extra := p.Get("extra"); extra != "" {
+			var v json.RawMessage
+			if err := json.Unmarshal([]byte(extra), &v); err != nil {
+				return nil, errors.New("invalid xhttp extra").Base(err)
+			}
+			xhttp["extra"] = v
+		}
+		stream["xhttpSettings"] = xhttp
+		network = "xhttp"
+	case "hysteria":
+		hysteria := object{"version": 2, "auth": l.ID}
+		if mport := p.Get("mport"); mport != "" {
+			hysteria["udphop"] = object{"port": mport}
+		}
+		stream["hysteriaSettings"] = hysteria
+		if obfs := p.Get("obfs"); obfs != "" {
+			if obfs != "salamander" {
+				return nil, errors.New("unsupported hysteria2 obfs: ", obfs)
+			}
+			stream["finalmask"] = object{
+				"udp": []object{{
+					"type":     "salamander",
+					"settings": object{"password": p.Get("obfsPassword")},
+				}},
+			}
+		}
+	default:
+		return nil, errors.New("unsupported transport: ", network)
+	}
+	if network != "" {
+		stream["network"] = network
+	}
+
+	switch security := p.Get("security"); security {
+	case "", "none":
+	case "tls":
+		tls := object{}
+		setString := func(key string, param string) {
+			if v := p.Get(param); v != "" {
+				tls[key] = v
+			}
+		}
+		setString("serverName", "sni")
+		setString("fingerprint", "fp")
+		setString("echConfigList", "ech")
+		setString("pinnedPeerCertSha256", "pcs")
+		setString("verifyPeerCertByName", "vcn")
+		if alpn := p.Get("alpn"); alpn != "" {
+			tls["alpn"] = splitList(alpn)
+		}
+		// "allowInsecure" is ignored, as it is replaced by "pcs" and "vcn".
+		stream["security"] = "tls"
+		stream["tlsSettings"] = tls
+	case "reality":
+		reality := object{
+			"serverName":  p.Get("sni"),
+			"fingerprint": p.Get("fp"),
+			"publicKey":   p.Get("pbk"),
+			"shortId":     p.Get("sid"),
+		}
+		if reality["fingerprint"] == "" {
+			reality["fingerprint"] = "chrome"
+		}
+		if spiderX := p.Get("spx"); spiderX != "" {
+			reality["spiderX"] = spiderX
+		}
+		if verify := p.Get("pqv"); verify != "" {
+			reality["mldsa65Verify"] = verify
+		}
+		stream["security"] = "reality"
+		stream["realitySettings"] = reality
+	default:
+		return nil, errors.New("unsupported security: ", security)
+	}
+	return stream, nil
+}
+
+// userConfig is the fields of users of all protocols.
+type userConfig struct {
+	ID       string `json:"id"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+	Method   string `json:"method"`
+	Security string `json:"security"`
+	Flow     string `json:"flow"`
+	Email    string `json:"email"`
+	// Encryption of VLESS users in vnext.
+	Encryption string `json:"encryption"`
+}
+
+// serverConfig is the fields of outbound settings of all protocols.
+type serverConfig struct {
+	userConfig
+	Address *conf.Address `json:"address"`
+	Port    uint16        `json:"port"`
+	Servers []*struct {
+		userConfig
+		Address *conf.Address `json:"address"`
+		Port    uint16        `json:"port"`
+	} `json:"servers"`
+	Vnext []*struct {
+		Address *conf.Address     `json:"address"`
+		Port    uint16            `json:"port"`
+		Users   []json.RawMessage `json:"users"`
+	} `json:"vnext"`
+}
+
+// FromOutbound returns the share link of an outbound.
+func FromOutbound(c *conf.OutboundDetourConfig) (*Link, error) {
+	l := &Link{
+		Protocol: strings.ToLower(c.Protocol),
+		Name:     c.Tag,
+		Params:   url.Values{},
+	}
+	if l.Protocol == "hysteria" {
+		l.Protocol = "hysteria2"
+	}
+	var settings serverConfig
+	if c.Settings != nil {
+		if err := json.Unmarshal(*c.Settings, &settings); err != nil {
+			return nil, errors.New("failed to parse outbound settings").Base(err)
+		}
+	}
+	user := settings.userConfig
+	switch {
+	case settings.Address != nil:
+	case len(settings.Vnext) > 0:
+		settings.Address = settings.Vnext[0].Address
+		settings.Port = settings.Vnext[0].Port
+		if len(settings.Vnext[0].Users) == 0 {
+			return nil, errors.New("no user in outbound ", c.Tag)
+		}
+		if err := json.Unmarshal(settings.Vnext[0].Users[0], &user); err != nil {
+			return nil, errors.New("failed to parse outbound user").Base(err)
+		}
+	case len(settings.Servers) > 0:
+		settings.Address = settings.Servers[0].Address
+		settings.Port = settings.Servers[0].Port
+		user = settings.Servers[0].userConfig
+	default:
+		return nil, errors.New("no server in outbound ", c.Tag)
+	}
+	if settings.Address == nil {
+		return nil, errors.New("no address in outbound ", c.Tag)
+	}
+	if settings.Address.Family().IsDomain() {
+		l.Address = settings.Address.Domain()
+	} else {
+		l.Address = settings.Address.IP().String()
+	}
+	l.Port = settings.Port
+
+	switch l.Protocol {
+	case "vless":
+		l.ID, l.Flow, l.Encryption = user.ID, user.Flow, user.Encryption
+		if l.Encryption == "" {
+			l.Encryption = "none"
+		}
+	case "vmess":
+		l.ID, l.Encryption = user.ID, user.Security
+	case "trojan":
+		l.ID = user.Password
+	case "shadowsocks":
+		l.ID, l.Encryption = user.Password, user.Method
+	case "hysteria2":
+	default:
+		return nil, errors.New("unsupported protocol: ", c.Protocol)
+	}
+	if err := l.setStreamParams(c.StreamSetting, false); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// serverUsers is the fields of inbound settings of all protocols.
+type serverUsers struct {
+	Clients    []*userConfig `json:"clients"`
+	Decryption string        `json:"decryption"`
+	Flow       string        `json:"flow"`
+	Method     string        `json:"method"`
+	Password   string        `json:"password"`
+	Email      string        `json:"email"`
+}
+
+// FromInbound returns the share links of all users of an inbound, for clients to connect to it at address.
+func FromInbound(c *conf.InboundDetourConfig, address string) ([]*Link, error) {
+	if c.PortList == nil || len(c.PortList.Range) == 0 {
+		return nil, errors.New("no port in inbound ", c.Tag)
+	}
+	var settings serverUsers
+	if c.Settings != nil {
+		if err := json.Unmarshal(*c.Settings, &settings); err != nil {
+			return nil, errors.New("failed to parse inbound settings").Base(err)
+		}
+	}
+	protocol := strings.ToLower(c.Protocol)
+	switch protocol {
+	case "vless":
+		if settings.Decryption != "" && settings.Decryption != "none" {
+			return nil, errors.New("share links of VLESS inbounds with decryption are not supported")
+		}
+	case "hysteria":
+		protocol = "hysteria2"
+	case "shadowsocks":
+		if len(settings.Clients) == 0 {
+			settings.Clients = []*userConfig{{Email: settings.Email}}
+		}
+	case "vmess", "trojan":
+	default:
+		return nil, errors.New("unsupported protocol: ", c.Protocol)
+	}
+
+	links := make([]*Link, 0, len(settings.Clients))
+	for _, user := range settings.Clients {
+		l := &Link{
+			Protocol: protocol,
+			Name:     user.Email,
+			Address:  address,
+			Port:     uint16(c.PortList.Range[0].From),
+			Params:   url.Values{},
+		}
+		if l.Name == "" {
+			l.Name = c.Tag
+		}
+		switch protocol {
+		case "vless":
+			l.ID, l.Flow, l.Encryption = user.ID, user.Flow, "none"
+			if l.Flow == "" {
+				l.Flow = settings.Flow
+			}
+		case "vmess":
+			l.ID, l.Encryption = user.ID, user.Security
+		case "trojan":
+			l.ID = user.Password
+		case "shadowsocks":
+			l.ID, l.Encryption = user.Password, user.Method
+			if l.Encryption == "" {
+				l.Encryption = settings.Method
+			}
+			if l.ID == "" {
+				l.ID = settings.Password
+			} else if strings.HasPrefix(settings.Method, "2022-") {
+				// Users of multi-user Shadowsocks 2022 connect with both keys.
+				l.ID = settings.Password + ":" + l.ID
+			}
+		case "hysteria2":
+			l.ID = user.Auth
+		}
+		if err := l.setStreamParams(c.StreamSetting, true); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	return links, nil
+}
+
+// setStreamParams sets the params from the stream settings of an outbound, or of an inbound
+// if server is true.
+func (l *Link) setStreamParams(c *conf.StreamConfig, server bool) error {
+	p := l.Params
+	setParam := func(key string, value string) {
+		if value != "" {
+			p.Set(key, value)
+		}
+	}
+	if c == nil {
+		c = &conf.StreamConfig{}
+	}
+	network := "tcp"
+	if c.Network != nil {
+		network = strings.ToLower(string(*c.Network))
+	}
+	switch network {
+	case "", "tcp", "raw":
+		network = "tcp"
+		tcp := c.RAWSettings
+		if tcp == nil {
+			tcp = c.TCPSettings
+		}
+		if tcp != nil && len(tcp.HeaderConfig) > 0 {
+			var header struct {
+				Type    string                     `json:"type"`
+				Request *conf.AuthenticatorRequest `json:"request"`
+			}
+			if err := json.Unmarshal(tcp.HeaderConfig, &header); err != nil {
+				return errors.New("invalid raw header").Base(err)
+			}
+			if header.Type == "http" {
+				setParam("headerType", "http")
+				if header.Request != nil {
+					setParam("path", strings.Join(header.Request.Path, ","))
+					for name, values := range header.Request.Headers {
+						if strings.EqualFold(name, "host") && values != nil {
+							setParam("host", strings.Join(*values, ","))
+						}
+					}
+				}
+			}
+		}
+	case "kcp", "mkcp":
+		network = "kcp"
+		if kcp := c.KCPSettings; kcp != nil {
+			if len(kcp.HeaderConfig) > 0 {
+				var header struct {
+					Type string `json:"type"`
+				}
+				if err := json.Unmarshal(kcp.HeaderConfig, &header); err != nil {
+					return errors.New("invalid mKCP header").Base(err)
+				}
+				setParam("headerType", header.Type)
+			}
+			if kcp.Seed != nil {
+				setParam("seed", *kcp.Seed)
+			}
+		}
+	case "ws", "websocket":
+		network = "ws"
+		if ws := c.WSSettings; ws != nil {
+			setParam("host", ws.Host)
+			setParam("path", ws.Path)
+		}
+	case "httpupgrade":
+		if hu := c.HTTPUPGRADESettings; hu != nil {
+			setParam("host", hu.Host)
+			setParam("path", hu.Path)
+		}
+	case "grpc":
+		if grpc := c.GRPCSettings; grpc != nil {
+			setParam("serviceName", grpc.ServiceName)
+			setParam("authority", grpc.Authority)
+			if grpc.MultiMode {
+				setParam("mode", "multi")
+			}
+		}
+	case "xhttp", "splithttp":
+		network = "xhttp"
+		xhttp := c.XHTTPSettings
+		if xhttp == nil {
+			xhttp = c.SplitHTTPSettings
+		}
+		if xhttp != nil {
+			setParam("host", xhttp.Host)
+			setParam("path", xhttp.Path)
+			setParam("mode", xhttp.Mode)
+			if len(xhttp.Extra) > 0 && !server {
+				var extra json.RawMessage
+				if err := json.Unmarshal(xhttp.Extra, &extra); err != nil {
+					return errors.New("invalid xhttp extra").Base(err)
+				}
+				b, _ := json.Marshal(extra)
+				setParam("extra", string(b))
+			}
+		}
+	case "hysteria":
+		if hysteria := c.HysteriaSettings; hysteria != nil {
+			if !server {
+				l.ID = hysteria.Auth
+			}
+			if len(hysteria.UdpHop.PortList) > 0 {
+				setParam("mport", strings.Trim(string(hysteria.UdpHop.PortList), `"`))
+			}
+		}
+		if c.FinalMask != nil {
+			for _, mask := range c.FinalMask.Udp {
+				if mask.Type == "salamander" && mask.Settings != nil {
+					var salamander conf.Salamander
+					if err := json.Unmarshal(*mask.Settings, &salamander); err != nil {
+						return errors.New("invalid salamander settings").Base(err)
+					}
+					setParam("obfs", "salamander")
+					setParam("obfsPassword", salamander.Password)
+				}
+			}
+		}
+	default:
+		return errors.New("unsupported transport: ", network)
+	}
+	switch l.Protocol {
+	case "vless", "vmess", "trojan", "hysteria2":
+		p.Set("type", network)
+	}
+
+	switch security := strings.ToLower(c.Security); security {
+	case "", "none":
+		if l.Protocol == "vless" || l.Protocol == "vmess" {
+			p.Set("security", "none")
+		}
+	case "tls":
+		p.Set("security", "tls")
+		if tls := c.TLSSettings; tls != nil {
+			setParam("sni", tls.ServerName)
+			setParam("fp", tls.Fingerprint)
+			if tls.ALPN != nil {
+				setParam("alpn", strings.Join(*tls.ALPN, ","))
+			}
+			setParam("pcs", tls.PinnedPeerCertSha256)
+			if !server {
+				setParam("vcn", tls.VerifyPeerCertByName)
+				setParam("ech", tls.ECHConfigList)
+			}
+		}
+	case "reality":
+		p.Set("security", "reality")
+		reality := c.REALITYSettings
+		if reality == nil {
+			return errors.New("no REALITY settings")
+		}
+		if !server {
+			setParam("sni", reality.ServerName)
+			setParam("fp", reality.Fingerprint)
+			setParam("pbk", reality.PublicKey)
+			if reality.PublicKey == "" {
+				setParam("pbk", reality.Password)
+			}
+			setParam("sid", reality.ShortId)
+			setParam("spx", reality.SpiderX)
+			setParam("pqv", reality.Mldsa65Verify)
+			break
+		}
+		publicKey, err := realityPublicKey(reality.PrivateKey)
+		if err != nil {
+			return err
+		}
+		p.Set("pbk", publicKey)
+		if len(reality.ServerNames) > 0 {
+			setParam("sni", reality.ServerNames[0])
+		}
+		if len(reality.ShortIds) > 0 {
+			setParam("sid", reality.ShortIds[0])
+		}
+		p.Set("fp", "chrome")
+		if reality.Mldsa65Seed != "" {
+			verify, err := mldsa65Verify(reality.Mldsa65Seed)
+			if err != nil {
+				return err
+			}
+			p.Set("pqv", verify)
+		}
+	default:
+		return errors.New("unsupported security: ", security)
+	}
+	return nil
+}
+
+// realityPublicKey returns the public key for clients of a REALITY server.
+func realityPublicKey(privateKey string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(privateKey)
+	if err != nil {
+		return "", errors.New(`invalid REALITY "privateKey": `, privateKey).Base(err)
+	}
+	key, err := ecdh.X25519().NewPrivateKey(b)
+	if err != nil {
+		return "", errors.New(`invalid REALITY "privateKey": `, privateKey).Base(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()), nil
+}
+
+// mldsa65Verify returns the ML-DSA-65 public key for clients of a REALITY server.
+func mldsa65Verify(seed string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(seed)
+	if err != nil || len(b) != mldsa65.SeedSize {
+		return "", errors.New(`invalid REALITY "mldsa65Seed": `, seed)
+	}
+	pub, _ := mldsa65.NewKeyFromSeed((*[mldsa65.SeedSize]byte)(b))
+	return base64.RawURLEncoding.EncodeToString(pub.Bytes()), nil
+}
@@ -0,0 +1,416 @@
+// Package sharelink converts share links, like vless:// and ss://, from and to configs.
+package sharelink
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/xtls/xray-core/common/errors"
+)
+
+// Link is a parsed share link.
+type Link struct {
+	// Protocol is one of "vless", "vmess", "trojan", "shadowsocks" and "hysteria2".
+	Protocol string
+	Name     string
+	Address  string
+	Port     uint16
+	// ID is the UUID of VLESS and VMess, the password of Trojan and Shadowsocks, or the auth of Hysteria 2.
+	ID string
+	// Encryption is the encryption of VLESS, the security of VMess, or the method of Shadowsocks.
+	Encryption string
+	Flow       string
+	// Params are the transport and security parameters, in the query keys of VLESS share links,
+	// like "type", "security", "sni" and "pbk".
+	Params url.Values
+}
+
+// Parse parses a share link.
+func Parse(link string) (*Link, error) {
+	link = strings.TrimSpace(link)
+	scheme, rest, found := strings.Cut(link, "://")
+	if !found {
+		return nil, errors.New("not a share link: ", link)
+	}
+	switch strings.ToLower(scheme) {
+	case "vless":
+		return parseURL("vless", link)
+	case "vmess":
+		if !strings.Contains(rest, "@") {
+			return parseVMessJSON(rest)
+		}
+		return parseURL("vmess", link)
+	case "trojan":
+		l, err := parseURL("trojan", link)
+		if err == nil && l.Params.Get("security") == "" {
+			l.Params.Set("security", "tls")
+		}
+		return l, err
+	case "ss":
+		return parseShadowsocks(link)
+	case "hysteria2", "hy2":
+		return parseHysteria2(link)
+	default:
+		return nil, errors.New("unsupported share link scheme: ", scheme)
+	}
+}
+
+func parsePort(s string) (uint16, error) {
+	port, err := strconv.ParseUint(s, 10, 16)
+	if err != nil || port == 0 {
+		return 0, errors.New("invalid port: ", s)
+	}
+	return uint16(port), nil
+}
+
+// parseURL parses links in the form of "vless://id@address:port?params#name".
+func parseURL(protocol string, link string) (*Link, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, errors.New("failed to parse ", protocol, " link").Base(err)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, errors.New("no user in ", protocol, " link")
+	}
+	port, err := parsePort(u.Port())
+	if err != nil {
+		return nil, err
+	}
+	l := &Link{
+		Protocol: protocol,
+		Name:     u.Fragment,
+		Address:  u.Hostname(),
+		Port:     port,
+		ID:       u.User.Username(),
+		Params:   u.Query(),
+	}
+	if password, ok := u.User.Password(); ok {
+		// Trojan passwords may contain ":".
+		l.ID += ":" + password
+	}
+	switch protocol {
+	case "vless":
+		l.Encryption = l.Params.Get("encryption")
+		if l.Encryption == "" {
+			l.Encryption = "none"
+		}
+	case "vmess":
+		l.Encryption = l.Params.Get("encryption")
+	}
+	l.Flow = l.Params.Get("flow")
+	l.Params.Del("encryption")
+	l.Params.Del("flow")
+	return l, nil
+}
+
+// decodeBase64 decodes any of the base64 encodings used in share links.
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if strings.ContainsAny(s, "-_") {
+		return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	}
+	return base64.RawStdEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// vmessJSON is the link format of V2RayN, "vmess://" followed by base64 of the JSON.
+type vmessJSON struct {
+	V    json.RawMessage `json:"v"`
+	PS   string          `json:"ps"`
+	Add  string          `json:"add"`
+	Port json.RawMessage `json:"port"`
+	ID   string          `json:"id"`
+	Aid  json.RawMessage `json:"aid,omitempty"`
+	Scy  string          `json:"scy,omitempty"`
+	Net  string          `json:"net"`
+	Type string          `json:"type"`
+	Host string          `json:"host"`
+	Path string          `json:"path"`
+	TLS  string          `json:"tls"`
+	SNI  string          `json:"sni,omitempty"`
+	ALPN string          `json:"alpn,omitempty"`
+	FP   string          `json:"fp,omitempty"`
+}
+
+// rawString returns the value of a JSON field which may be a string or a number.
+func rawString(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return string(raw)
+}
+
+func parseVMessJSON(encoded string) (*Link, error) {
+	b, err := decodeBase64(encoded)
+	if err != nil {
+		return nil, errors.New("failed to decode vmess link").Base(err)
+	}
+	var v vmessJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, errors.New("failed to parse vmess link").Base(err)
+	}
+	port, err := parsePort(rawString(v.Port))
+	if err != nil {
+		return nil, err
+	}
+	l := &Link{
+		Protocol:   "vmess",
+		Name:       v.PS,
+		Address:    v.Add,
+		Port:       port,
+		ID:         v.ID,
+		Encryption: v.Scy,
+		Params:     url.Values{},
+	}
+	setParam := func(key string, value string) {
+		if value != "" {
+			l.Params.Set(key, value)
+		}
+	}
+	setParam("type", v.Net)
+	switch v.Net {
+	case "grpc":
+		setParam("serviceName", v.Path)
+		setParam("authority", v.Host)
+		setParam("mode", v.Type)
+	case "kcp", "mkcp":
+		setParam("seed", v.Path)
+		setParam("headerType", v.Type)
+	case "xhttp", "splithttp":
+		setParam("host", v.Host)
+		setParam("path", v.Path)
+		setParam("mode", v.Type)
+	default:
+		setParam("host", v.Host)
+		setParam("path", v.Path)
+		if v.Type != "none" {
+			setParam("headerType", v.Type)
+		}
+	}
+	setParam("security", v.TLS)
+	setParam("sni", v.SNI)
+	setParam("alpn", v.ALPN)
+	setParam("fp", v.FP)
+	return l, nil
+}
+
+// parseShadowsocks parses SIP002 links, and the legacy ones with everything but the name in base64.
+func parseShadowsocks(link string) (*Link, error) {
+	rest := strings.TrimPrefix(link[strings.Index(link, "://")+3:], "//")
+	name := ""
+	if i := strings.IndexByte(rest, '#'); i >= 0 {
+		name, _ = url.PathUnescape(rest[i+1:])
+		rest = rest[:i]
+	}
+	if !strings.Contains(rest, "@") {
+		b, err := decodeBase64(rest)
+		if err != nil {
+			return nil, errors.New("failed to decode shadowsocks link").Base(err)
+		}
+		rest = string(b)
+		at := strings.LastIndexByte(rest, '@')
+		if at < 0 {
+			return nil, errors.New("no server in shadowsocks link")
+		}
+		rest = url.PathEscape(rest[:at]) + rest[at:]
+	}
+	u, err := url.Parse("ss://" + rest)
+	if err != nil {
+		return nil, errors.New("failed to parse shadowsocks link").Base(err)
+	}
+	if u.Query().Get("plugin") != "" {
+		return nil, errors.New("shadowsocks plugins are not supported")
+	}
+	userinfo := u.User.String()
+	if unescaped, err := url.PathUnescape(userinfo); err == nil {
+		userinfo = unescaped
+	}
+	method, password, found := strings.Cut(userinfo, ":")
+	if !found {
+		b, err := decodeBase64(userinfo)
+		if err != nil {
+			return nil, errors.New("failed to decode shadowsocks user").Base(err)
+		}
+		method, password, found = strings.Cut(string(b), ":")
+		if !found {
+			return nil, errors.New("no password in shadowsocks link")
+		}
+	}
+	port, err := parsePort(u.Port())
+	if err != nil {
+		return nil, err
+	}
+	return &Link{
+		Protocol:   "shadowsocks",
+		Name:       name,
+		Address:    u.Hostname(),
+		Port:       port,
+		ID:         password,
+		Encryption: method,
+		Params:     url.Values{},
+	}, nil
+}
+
+// parseHysteria2 parses Hysteria 2 links, whose parameters are translated to those of VLESS links.
+func parseHysteria2(link string) (*Link, error) {
+	// Ports for port hopping, like "host:1000-2000,3000", are not accepted by url.Parse.
+	mport := ""
+	start := strings.Index(link, "://") + 3
+	end := start + strings.IndexAny(link[start:]+"/", "/?#")
+	if colon := strings.LastIndexByte(link[start:end], ':'); colon >= 0 {
+		colon += start
+		if port := link[colon+1 : end]; strings.ContainsAny(port, "-,") {
+			mport = port
+			first, _, _ := strings.Cut(strings.Split(port, ",")[0], "-")
+			link = link[:colon+1] + first + link[end:]
+		}
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, errors.New("failed to parse hysteria2 link").Base(err)
+	}
+	l := &Link{
+		Protocol: "hysteria2",
+		Name:     u.Fragment,
+		Address:  u.Hostname(),
+		Port:     443,
+		Params:   url.Values{},
+	}
+	if u.User != nil {
+		l.ID = u.User.String()
+		if unescaped, err := url.PathUnescape(l.ID); err == nil {
+			l.ID = unescaped
+		}
+	}
+	if port := u.Port(); port != "" {
+		if l.Port, err = parsePort(port); err != nil {
+			return nil, err
+		}
+	}
+	query := u.Query()
+	if mport != "" {
+		query.Set("mport", mport)
+	}
+	l.Params.Set("type", "hysteria")
+	l.Params.Set("security", "tls")
+	for key, param := range map[string]string{
+		"sni":           "sni",
+		"alpn":          "alpn",
+		"pinSHA256":     "pcs",
+		"obfs":          "obfs",
+		"obfs-password": "obfsPassword",
+		"mport":         "mport",
+	} {
+		if v := query.Get(key); v != "" {
+			l.Params.Set(param, v)
+		}
+	}
+	return l, nil
+}
+
+// String returns the share link.
+func (l *Link) String() string {
+	host := net.JoinHostPort(l.Address, strconv.Itoa(int(l.Port)))
+	switch l.Protocol {
+	case "vmess":
+		return l.vmessString()
+	case "shadowsocks":
+		userinfo := base64.RawURLEncoding.EncodeToString([]byte(l.Encryption + ":" + l.ID))
+		if strings.HasPrefix(l.Encryption, "2022-") {
+			// SIP022 requires the userinfo not to be encoded in base64.
+			userinfo = url.PathEscape(l.Encryption) + ":" + url.PathEscape(l.ID)
+		}
+		return "ss://" + userinfo + "@" + host + l.fragment()
+	case "hysteria2":
+		query := url.Values{}
+		for param, key := range map[string]string{
+			"sni":          "sni",
+			"alpn":         "alpn",
+			"pcs":          "pinSHA256",
+			"obfs":         "obfs",
+			"obfsPassword": "obfs-password",
+			"mport":        "mport",
+		} {
+			if v := l.Params.Get(param); v != "" {
+				query.Set(key, v)
+			}
+		}
+		u := url.URL{
+			Scheme:   "hysteria2",
+			User:     url.User(l.ID),
+			Host:     host,
+			Path:     "/",
+			RawQuery: query.Encode(),
+		}
+		return u.String() + l.fragment()
+	default:
+		query := url.Values{}
+		for key, values := range l.Params {
+			query[key] = values
+		}
+		if l.Protocol == "vless" {
+			query.Set("encryption", l.Encryption)
+		}
+		if l.Flow != "" {
+			query.Set("flow", l.Flow)
+		}
+		u := url.URL{
+			Scheme:   l.Protocol,
+			User:     url.User(l.ID),
+			Host:     host,
+			RawQuery: query.Encode(),
+		}
+		return u.String() + l.fragment()
+	}
+}
+
+func (l *Link) fragment() string {
+	if l.Name == "" {
+		return ""
+	}
+	return "#" + url.PathEscape(l.Name)
+}
+
+func (l *Link) vmessString() string {
+	v := vmessJSON{
+		V:    json.RawMessage(`"2"`),
+		PS:   l.Name,
+		Add:  l.Address,
+		Port: json.RawMessage(strconv.Itoa(int(l.Port))),
+		ID:   l.ID,
+		Aid:  json.RawMessage("0"),
+		Scy:  l.Encryption,
+		Net:  l.Params.Get("type"),
+		Type: "none",
+		Host: l.Params.Get("host"),
+		Path: l.Params.Get("path"),
+		TLS:  l.Params.Get("security"),
+		SNI:  l.Params.Get("sni"),
+		ALPN: l.Params.Get("alpn"),
+		FP:   l.Params.Get("fp"),
+	}
+	switch v.Net {
+	case "":
+		v.Net = "tcp"
+	case "grpc":
+		v.Host = l.Params.Get("authority")
+		v.Path = l.Params.Get("serviceName")
+		v.Type = l.Params.Get("mode")
+	case "kcp":
+		v.Path = l.Params.Get("seed")
+	case "xhttp":
+		v.Type = l.Params.Get("mode")
+	}
+	if headerType := l.Params.Get("headerType"); headerType != "" && v.Net != "grpc" && v.Net != "xhttp" {
+		v.Type = headerType
+	}
+	if v.TLS == "none" {
+		v.TLS = ""
+	}
+	b, _ := json.Marshal(v)
+	return "vmess://" + base64.StdEncoding.EncodeToString(b)
+}
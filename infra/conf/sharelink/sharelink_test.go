@@ -0,0 +1,196 @@
+package sharelink_test
+
+import (
+	"crypto/ecdh"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/infra/conf"
+	. "github.com/xtls/xray-core/infra/conf/sharelink"
+)
+
+// buildOutbound checks that the outbound of the link is a valid config.
+func buildOutbound(t *testing.T, l *Link) *conf.OutboundDetourConfig {
+	t.Helper()
+
+	b, err := l.Outbound()
+	common.Must(err)
+	outbound := new(conf.OutboundDetourConfig)
+	common.Must(json.Unmarshal(b, outbound))
+	if _, err := outbound.Build(); err != nil {
+		t.Fatal("invalid outbound ", string(b), ": ", err)
+	}
+	return outbound
+}
+
+func TestLinks(t *testing.T) {
+	cases := []struct {
+		link     string
+		protocol string
+		address  string
+		port     uint16
+		id       string
+	}{
+		{
+			link:     "vless://27848739-7e62-4138-9fd3-098a63964b6b@example.com:443?type=xhttp&path=%2Fxhttp&mode=auto&security=reality&sni=www.example.com&fp=chrome&pbk=OJ2aVFhIwbPhC4UsQEa5y0bwG_GkOt5pdUXLrnZ6iBk&sid=6ba85179e30d4fc2&flow=xtls-rprx-vision&extra=%7B%22xPaddingBytes%22%3A%22100-1000%22%7D#reality",
+			protocol: "vless",
+			address:  "example.com",
+			port:     443,
+			id:       "27848739-7e62-4138-9fd3-098a63964b6b",
+		},
+		{
+			link:     "vless://27848739-7e62-4138-9fd3-098a63964b6b@1.2.3.4:8443?encryption=none&type=ws&host=cdn.example.com&path=%2Fws%3Fed%3D2048&security=tls&sni=cdn.example.com&alpn=h2,http%2F1.1&vcn=example.com",
+			protocol: "vless",
+			address:  "1.2.3.4",
+			port:     8443,
+			id:       "27848739-7e62-4138-9fd3-098a63964b6b",
+		},
+		{
+			link:     "vmess://" + base64.StdEncoding.EncodeToString([]byte(`{"v":"2","ps":"vmess","add":"example.com","port":"443","id":"27848739-7e62-4138-9fd3-098a63964b6b","aid":"0","scy":"auto","net":"grpc","type":"multi","host":"","path":"svc","tls":"tls","sni":"example.com"}`)),
+			protocol: "vmess",
+			address:  "example.com",
+			port:     443,
+			id:       "27848739-7e62-4138-9fd3-098a63964b6b",
+		},
+		{
+			link:     "trojan://pass%3Aword@example.com:443?type=tcp&headerType=http&host=a.com&path=%2F#trojan",
+			protocol: "trojan",
+			address:  "example.com",
+			port:     443,
+			id:       "pass:word",
+		},
+		{
+			link:     "ss://" + base64.RawURLEncoding.EncodeToString([]byte("aes-256-gcm:password")) + "@example.com:8388#ss",
+			protocol: "shadowsocks",
+			address:  "example.com",
+			port:     8388,
+			id:       "password",
+		},
+		{
+			link:     "ss://" + base64.StdEncoding.EncodeToString([]byte("chacha20-ietf-poly1305:p@ss@example.com:8388")) + "#legacy",
+			protocol: "shadowsocks",
+			address:  "example.com",
+			port:     8388,
+			id:       "p@ss",
+		},
+		{
+			link:     "ss://2022-blake3-aes-128-gcm:AAAAAAAAAAAAAAAAAAAAAA%3D%3D@[::1]:8388",
+			protocol: "shadowsocks",
+			address:  "::1",
+			port:     8388,
+			id:       "AAAAAAAAAAAAAAAAAAAAAA==",
+		},
+		{
+			link:     "hysteria2://secret@example.com:443/?sni=example.com&obfs=salamander&obfs-password=obfs&pinSHA256=e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855#hy2",
+			protocol: "hysteria2",
+			address:  "example.com",
+			port:     443,
+			id:       "secret",
+		},
+		{
+			link:     "hy2://secret@example.com:20000-30000/?sni=example.com",
+			protocol: "hysteria2",
+			address:  "example.com",
+			port:     20000,
+			id:       "secret",
+		},
+	}
+
+	for _, c := range cases {
+		l, err := Parse(c.link)
+		if err != nil {
+			t.Error("failed to parse ", c.link, ": ", err)
+			continue
+		}
+		if l.Protocol != c.protocol || l.Address != c.address || l.Port != c.port || l.ID != c.id {
+			t.Error("unexpected link of ", c.link, ": ", l)
+		}
+		outbound := buildOutbound(t, l)
+
+		// The link of the outbound should be the same as the original one.
+		l2, err := FromOutbound(outbound)
+		if err != nil {
+			t.Error("failed to convert outbound of ", c.link, ": ", err)
+			continue
+		}
+		l3, err := Parse(l2.String())
+		common.Must(err)
+		if r := cmp.Diff(l2, l3); r != "" {
+			t.Error(c.link, ": ", r)
+		}
+		if l3.Protocol != l.Protocol || l3.ID != l.ID || l3.Address != l.Address || l3.Port != l.Port || l3.Name != l.Name {
+			t.Error("unexpected link of outbound of ", c.link, ": ", l3.String())
+		}
+		for _, key := range []string{"type", "security", "sni", "pbk", "sid", "path", "host", "mode", "serviceName", "obfsPassword", "mport", "vcn", "pcs"} {
+			if l.Params.Get(key) != l3.Params.Get(key) && !(key == "type" && l.Params.Get(key) == "") {
+				t.Error("unexpected ", key, " in link of outbound of ", c.link, ": ", l3.String())
+			}
+		}
+	}
+}
+
+func TestInvalidLinks(t *testing.T) {
+	for _, link := range []string{
+		"example.com:443",
+		"socks://example.com:1080",
+		"vless://example.com:443",
+		"vless://id@example.com:0",
+		"ss://YWVzLTI1Ni1nY206cGFzcw@example.com:8388?plugin=obfs-local",
+	} {
+		if _, err := Parse(link); err == nil {
+			t.Error("expect error of ", link)
+		}
+	}
+}
+
+func TestFromInbound(t *testing.T) {
+	key, err := ecdh.X25519().GenerateKey(nil)
+	if err != nil {
+		key, err = ecdh.X25519().NewPrivateKey(make([]byte, 32))
+		common.Must(err)
+	}
+	inbound := new(conf.InboundDetourConfig)
+	common.Must(json.Unmarshal([]byte(`{
+		"tag": "vless-in",
+		"port": 443,
+		"protocol": "vless",
+		"settings": {
+			"clients": [
+				{"id": "27848739-7e62-4138-9fd3-098a63964b6b", "email": "alice", "flow": "xtls-rprx-vision"},
+				{"id": "5783a3e7-e373-51cd-8642-c83782b807c5"}
+			],
+			"decryption": "none"
+		},
+		"streamSettings": {
+			"network": "raw",
+			"security": "reality",
+			"realitySettings": {
+				"target": "www.example.com:443",
+				"serverNames": ["www.example.com"],
+				"privateKey": "`+base64.RawURLEncoding.EncodeToString(key.Bytes())+`",
+				"shortIds": ["6ba85179e30d4fc2"]
+			}
+		}
+	}`), inbound))
+
+	links, err := FromInbound(inbound, "example.com")
+	common.Must(err)
+	if len(links) != 2 {
+		t.Fatal("expect 2 links, but got ", len(links))
+	}
+	if links[0].Name != "alice" || links[1].Name != "vless-in" || links[0].Flow != "xtls-rprx-vision" {
+		t.Error("unexpected links: ", links[0].String(), " ", links[1].String())
+	}
+	l, err := Parse(links[0].String())
+	common.Must(err)
+	if pbk := l.Params.Get("pbk"); pbk != base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()) {
+		t.Error("unexpected public key: ", pbk)
+	}
+	if l.Address != "example.com" || l.Port != 443 || l.Params.Get("sni") != "www.example.com" || l.Params.Get("sid") != "6ba85179e30d4fc2" {
+		t.Error("unexpected link: ", l.String())
+	}
+	buildOutbound(t, l)
+}
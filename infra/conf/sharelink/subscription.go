@@ -0,0 +1,17 @@
+package sharelink
+
+import (
+	"encoding/json"
+
+	"github.com/xtls/xray-core/app/subscription"
+)
+
+func init() {
+	subscription.RegisterLinkConverter(func(link string) (json.RawMessage, error) {
+		l, err := Parse(link)
+		if err != nil {
+			return nil, err
+		}
+		return l.Outbound()
+	})
+}
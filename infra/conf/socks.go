@@ -33,6 +33,7 @@ type SocksServerConfig struct {
 	UDP        bool            `json:"udp"`
 	Host       *Address        `json:"ip"`
 	UserLevel  uint32          `json:"userLevel"`
+	Webhook    *WebhookConfig  `json:"authWebhook"`
 }
 
 func (v *SocksServerConfig) Build() (proto.Message, error) {
@@ -60,6 +61,17 @@ func (v *SocksServerConfig) Build() (proto.Message, error) {
 	}
 
 	config.UserLevel = v.UserLevel
+
+	if v.Webhook != nil {
+		if config.AuthType != socks.AuthType_PASSWORD {
+			return nil, errors.New(`SOCKS "authWebhook" requires "auth": "password"`)
+		}
+		w, err := v.Webhook.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Webhook = w
+	}
 	return config, nil
 }
 
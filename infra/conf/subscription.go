@@ -0,0 +1,52 @@
+package conf
+
+import (
+	"github.com/xtls/xray-core/app/subscription"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/infra/conf/cfgcommon/duration"
+)
+
+type SubscriptionConfig struct {
+	TagPrefix string            `json:"tagPrefix"`
+	URL       string            `json:"url"`
+	Interval  duration.Duration `json:"interval"`
+	Outbound  string            `json:"outbound"`
+}
+
+func (c *SubscriptionConfig) Build() (*subscription.SubscriptionConfig, error) {
+	if c.URL == "" {
+		return nil, errors.New("subscription URL is not specified")
+	}
+	if c.TagPrefix == "" {
+		return nil, errors.New("tagPrefix of subscription ", c.URL, " is not specified")
+	}
+	if c.Interval < 0 {
+		return nil, errors.New("invalid interval of subscription ", c.URL)
+	}
+	return &subscription.SubscriptionConfig{
+		TagPrefix: c.TagPrefix,
+		Url:       c.URL,
+		Interval:  int64(c.Interval),
+		Outbound:  c.Outbound,
+	}, nil
+}
+
+type SubscriptionsConfig []*SubscriptionConfig
+
+func (c SubscriptionsConfig) Build() (*subscription.Config, error) {
+	config := new(subscription.Config)
+	prefixes := make(map[string]bool, len(c))
+	for _, s := range c {
+		r, err := s.Build()
+		if err != nil {
+			return nil, err
+		}
+		// Outbounds of a subscription with the same prefix would be replaced by each other.
+		if prefixes[r.TagPrefix] {
+			return nil, errors.New("duplicated tagPrefix of subscriptions: ", r.TagPrefix)
+		}
+		prefixes[r.TagPrefix] = true
+		config.Subscriptions = append(config.Subscriptions, r)
+	}
+	return config, nil
+}
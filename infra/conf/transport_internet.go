@@ -746,6 +746,8 @@ type TLSConfig struct {
 	ECHConfigList           string           `json:"echConfigList"`
 	ECHForceQuery           string           `json:"echForceQuery"`
 	ECHSocketSettings       *SocketConfig    `json:"echSockopt"`
+	ClientAuth              string           `json:"clientAuth"`
+	ClientCAs               []string         `json:"clientCAs"`
 }
 
 // Build implements Buildable.
@@ -850,6 +852,32 @@ func (c *TLSConfig) Build() (proto.Message, error) {
 		config.EchSocketSettings = ss
 	}
 
+	switch strings.ToLower(c.ClientAuth) {
+	case "", "none":
+		config.ClientAuth = tls.Config_NO_CLIENT_CERT
+	case "optional":
+		config.ClientAuth = tls.Config_VERIFY_IF_GIVEN
+	case "require":
+		config.ClientAuth = tls.Config_REQUIRE_AND_VERIFY
+	default:
+		return nil, errors.New(`invalid "clientAuth": `, c.ClientAuth)
+	}
+	for _, ca := range c.ClientCAs {
+		// Either a file path or the certificate in PEM format.
+		if strings.HasPrefix(strings.TrimSpace(ca), "-----BEGIN") {
+			config.ClientCa = append(config.ClientCa, []byte(ca))
+			continue
+		}
+		pem, err := filesystem.ReadCert(ca)
+		if err != nil {
+			return nil, errors.New("failed to read client CA certificate: ", ca).Base(err)
+		}
+		config.ClientCa = append(config.ClientCa, pem)
+	}
+	if config.ClientAuth != tls.Config_NO_CLIENT_CERT && len(config.ClientCa) == 0 {
+		return nil, errors.New(`"clientCAs" is required to verify client certificates`)
+	}
+
 	return config, nil
 }
 
@@ -107,12 +107,14 @@ type TrojanUserConfig struct {
 	Level    byte   `json:"level"`
 	Email    string `json:"email"`
 	Flow     string `json:"flow"`
+	UserPolicyConfig
 }
 
 // TrojanServerConfig is Inbound configuration
 type TrojanServerConfig struct {
 	Clients   []*TrojanUserConfig      `json:"clients"`
 	Fallbacks []*TrojanInboundFallback `json:"fallbacks"`
+	Webhook   *WebhookConfig           `json:"authWebhook"`
 }
 
 // Build implements Buildable
@@ -135,6 +137,7 @@ func (c *TrojanServerConfig) Build() (proto.Message, error) {
 				Password: rawUser.Password,
 			}),
 		}
+		rawUser.UserPolicyConfig.Apply(config.Users[idx])
 	}
 
 	for _, fb := range c.Fallbacks {
@@ -190,5 +193,13 @@ func (c *TrojanServerConfig) Build() (proto.Message, error) {
 		}
 	}
 
+	if c.Webhook != nil {
+		w, err := c.Webhook.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Webhook = w
+	}
+
 	return config, nil
 }
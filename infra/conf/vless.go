@@ -35,6 +35,7 @@ type VLessInboundConfig struct {
 	Fallbacks  []*VLessInboundFallback `json:"fallbacks"`
 	Flow       string                  `json:"flow"`
 	Testseed   []uint32                `json:"testseed"`
+	Webhook    *WebhookConfig          `json:"authWebhook"`
 }
 
 // Build implements Buildable
@@ -55,6 +56,11 @@ func (c *VLessInboundConfig) Build() (proto.Message, error) {
 		if err := json.Unmarshal(rawUser, account); err != nil {
 			return nil, errors.New(`VLESS clients: invalid user`).Base(err)
 		}
+		userPolicy := new(UserPolicyConfig)
+		if err := json.Unmarshal(rawUser, userPolicy); err != nil {
+			return nil, errors.New(`VLESS clients: invalid user`).Base(err)
+		}
+		userPolicy.Apply(user)
 
 		u, err := uuid.ParseString(account.Id)
 		if err != nil {
@@ -197,6 +203,14 @@ func (c *VLessInboundConfig) Build() (proto.Message, error) {
 		}
 	}
 
+	if c.Webhook != nil {
+		w, err := c.Webhook.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Webhook = w
+	}
+
 	return config, nil
 }
 
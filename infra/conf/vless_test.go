@@ -155,5 +155,36 @@ func TestVLessInbound(t *testing.T) {
 				},
 			},
 		},
+		{
+			Input: `{
+				"clients": [
+					{
+						"id": "27848739-7e62-4138-9fd3-098a63964b6b",
+						"email": "love@example.com",
+						"uplinkRateLimit": "1MB",
+						"trafficQuota": "10GB",
+						"trafficUsed": 1024,
+						"expireTime": "2030-01-01T00:00:00Z"
+					}
+				],
+				"decryption": "none"
+			}`,
+			Parser: loadJSON(creator),
+			Output: &inbound.Config{
+				Clients: []*protocol.User{
+					{
+						Account: serial.ToTypedMessage(&vless.Account{
+							Id: "27848739-7e62-4138-9fd3-098a63964b6b",
+						}),
+						Email:           "love@example.com",
+						UplinkRateLimit: 1024 * 1024,
+						TrafficQuota:    10 * 1024 * 1024 * 1024,
+						TrafficUsed:     1024,
+						ExpireTime:      1893456000,
+					},
+				},
+				Decryption: "none",
+			},
+		},
 	})
 }
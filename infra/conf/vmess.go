@@ -82,6 +82,11 @@ func (c *VMessInboundConfig) Build() (proto.Message, error) {
 		if err := json.Unmarshal(rawData, account); err != nil {
 			return nil, errors.New("invalid VMess user").Base(err)
 		}
+		userPolicy := new(UserPolicyConfig)
+		if err := json.Unmarshal(rawData, userPolicy); err != nil {
+			return nil, errors.New("invalid VMess user").Base(err)
+		}
+		userPolicy.Apply(user)
 
 		u, err := uuid.ParseString(account.ID)
 		if err != nil {
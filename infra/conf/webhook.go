@@ -0,0 +1,32 @@
+package conf
+
+import (
+	"strings"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/infra/conf/cfgcommon/duration"
+	"github.com/xtls/xray-core/proxy/webhook"
+)
+
+// WebhookConfig is the endpoint validating credentials unknown to an inbound.
+type WebhookConfig struct {
+	URL              string            `json:"url"`
+	Timeout          duration.Duration `json:"timeout"`
+	CacheTTL         duration.Duration `json:"cacheTtl"`
+	NegativeCacheTTL duration.Duration `json:"negativeCacheTtl"`
+}
+
+func (c *WebhookConfig) Build() (*webhook.Config, error) {
+	if !strings.HasPrefix(c.URL, "http://") && !strings.HasPrefix(c.URL, "https://") && !strings.HasPrefix(c.URL, "http+unix://") {
+		return nil, errors.New("invalid URL of authWebhook: ", c.URL)
+	}
+	if c.Timeout < 0 || c.CacheTTL < 0 || c.NegativeCacheTTL < 0 {
+		return nil, errors.New("invalid durations of authWebhook")
+	}
+	return &webhook.Config{
+		Url:              c.URL,
+		Timeout:          int64(c.Timeout),
+		CacheTtl:         int64(c.CacheTTL),
+		NegativeCacheTtl: int64(c.NegativeCacheTTL),
+	}, nil
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/xtls/xray-core/common/platform"
 	"github.com/xtls/xray-core/common/serial"
 	core "github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/infra/conf/cfgcommon/duration"
 	"github.com/xtls/xray-core/transport/internet"
 )
 
@@ -99,6 +100,25 @@ func (c *SniffingConfig) Build() (*proxyman.SniffingConfig, error) {
 	}, nil
 }
 
+type UsersFileConfig struct {
+	Path     string            `json:"path"`
+	Interval duration.Duration `json:"interval"`
+}
+
+// Build implements Buildable.
+func (c *UsersFileConfig) Build() (*proxyman.UsersFile, error) {
+	if c.Path == "" {
+		return nil, errors.New("path of users file is not specified")
+	}
+	if c.Interval < 0 {
+		return nil, errors.New("invalid interval of users file ", c.Path)
+	}
+	return &proxyman.UsersFile{
+		Path:     c.Path,
+		Interval: int64(c.Interval),
+	}, nil
+}
+
 type MuxConfig struct {
 	Enabled         bool   `json:"enabled"`
 	Concurrency     int16  `json:"concurrency"`
@@ -131,6 +151,7 @@ type InboundDetourConfig struct {
 	Tag            string           `json:"tag"`
 	StreamSetting  *StreamConfig    `json:"streamSettings"`
 	SniffingConfig *SniffingConfig  `json:"sniffing"`
+	UsersFile      *UsersFileConfig `json:"usersFile"`
 }
 
 // Build implements Buildable.
@@ -182,6 +203,13 @@ func (c *InboundDetourConfig) Build() (*core.InboundHandlerConfig, error) {
 		}
 		receiverSettings.SniffingSettings = s
 	}
+	if c.UsersFile != nil {
+		f, err := c.UsersFile.Build()
+		if err != nil {
+			return nil, errors.New("failed to build users file config").Base(err)
+		}
+		receiverSettings.UsersFile = f
+	}
 
 	settings := []byte("{}")
 	if c.Settings != nil {
@@ -357,6 +385,9 @@ type Config struct {
 	FakeDNS          *FakeDNSConfig          `json:"fakeDns"`
 	Observatory      *ObservatoryConfig      `json:"observatory"`
 	BurstObservatory *BurstObservatoryConfig `json:"burstObservatory"`
+	Subscriptions    SubscriptionsConfig     `json:"subscriptions"`
+	Ban              *BanConfig              `json:"ban"`
+	RuleSets         RuleSetsConfig          `json:"ruleSets"`
 	Version          *VersionConfig          `json:"version"`
 }
 
@@ -426,6 +457,18 @@ func (c *Config) Override(o *Config, fn string) {
 		c.BurstObservatory = o.BurstObservatory
 	}
 
+	if o.Subscriptions != nil {
+		c.Subscriptions = o.Subscriptions
+	}
+
+	if o.Ban != nil {
+		c.Ban = o.Ban
+	}
+
+	if o.RuleSets != nil {
+		c.RuleSets = o.RuleSets
+	}
+
 	if o.Version != nil {
 		c.Version = o.Version
 	}
@@ -476,7 +519,9 @@ func (c *Config) Build() (*core.Config, error) {
 
 	config := &core.Config{
 		App: []*serial.TypedMessage{
-			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&dispatcher.Config{
+				ConnectionRecords: c.LogConfig != nil && c.LogConfig.ConnectionLog,
+			}),
 			serial.ToTypedMessage(&proxyman.InboundConfig{}),
 			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
 		},
@@ -506,7 +551,11 @@ func (c *Config) Build() (*core.Config, error) {
 
 	var logConfMsg *serial.TypedMessage
 	if c.LogConfig != nil {
-		logConfMsg = serial.ToTypedMessage(c.LogConfig.Build())
+		logConf, err := c.LogConfig.Build()
+		if err != nil {
+			return nil, errors.New("failed to build log configuration").Base(err)
+		}
+		logConfMsg = serial.ToTypedMessage(logConf)
 	} else {
 		logConfMsg = serial.ToTypedMessage(DefaultLogConfig())
 	}
@@ -514,11 +563,18 @@ func (c *Config) Build() (*core.Config, error) {
 	// so that other modules could print log during initiating
 	config.App = append([]*serial.TypedMessage{logConfMsg}, config.App...)
 
+	ruleSets, err := c.RuleSets.Build()
+	if err != nil {
+		return nil, errors.New("failed to build rule sets").Base(err)
+	}
+
 	if c.RouterConfig != nil {
 		routerConfig, err := c.RouterConfig.Build()
 		if err != nil {
 			return nil, errors.New("failed to build routing configuration").Base(err)
 		}
+		// All rule sets are loaded by the router, so that rules added by API can reference them.
+		routerConfig.RuleSet = ruleSets
 		config.App = append(config.App, serial.ToTypedMessage(routerConfig))
 	}
 
@@ -527,6 +583,17 @@ func (c *Config) Build() (*core.Config, error) {
 		if err != nil {
 			return nil, errors.New("failed to build DNS configuration").Base(err)
 		}
+		referenced := make(map[string]bool)
+		for _, ns := range dnsApp.NameServer {
+			for _, name := range ns.DomainRuleSet {
+				referenced[name] = true
+			}
+		}
+		for _, r := range ruleSets {
+			if referenced[r.Name] {
+				dnsApp.RuleSet = append(dnsApp.RuleSet, r)
+			}
+		}
 		config.App = append(config.App, serial.ToTypedMessage(dnsApp))
 	}
 
@@ -570,6 +637,22 @@ func (c *Config) Build() (*core.Config, error) {
 		config.App = append(config.App, serial.ToTypedMessage(r))
 	}
 
+	if len(c.Subscriptions) > 0 {
+		r, err := c.Subscriptions.Build()
+		if err != nil {
+			return nil, errors.New("failed to build subscription configuration").Base(err)
+		}
+		config.App = append(config.App, serial.ToTypedMessage(r))
+	}
+
+	if c.Ban != nil {
+		r, err := c.Ban.Build()
+		if err != nil {
+			return nil, errors.New("failed to build ban configuration").Base(err)
+		}
+		config.App = append(config.App, serial.ToTypedMessage(r))
+	}
+
 	if c.Version != nil {
 		r, err := c.Version.Build()
 		if err != nil {
@@ -650,6 +733,10 @@ func (c *Config) BuildMPHCache(customMatcherFilePath *string) error {
 		var manualDomains []*router.Domain
 		var dDeps []string
 		for _, dStr := range rawDomains {
+			if strings.HasPrefix(dStr, ruleSetPrefix) {
+				// Rule sets are reloaded at runtime, so they are never cached.
+				continue
+			}
 			if processGeosite(dStr) {
 				dDeps = append(dDeps, strings.ToLower(dStr))
 			} else {
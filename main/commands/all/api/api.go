@@ -9,9 +9,25 @@ var CmdAPI = &base.Command{
 	UsageLine: "{{.Exec}} api",
 	Short:     "Call an API in an Xray process",
 	Long: `{{.Exec}} {{.LongName}} provides tools to manipulate Xray via its API.
+
+Besides their own arguments, all the commands accept the credentials for
+calling an API with authentication:
+
+	-token <token>
+		The bearer token. Default the XRAY_API_TOKEN environment variable
+
+	-tls
+		Connect with TLS, verifying the server with the system CAs
+
+	-ca <file>
+		Connect with TLS, verifying the server with the CA in the PEM file
+
+	-cert <file>, -key <file>
+		The client certificate and key in PEM files, connecting with TLS
 `,
 	Commands: []*base.Command{
 		cmdRestartLogger,
+		cmdReloadConfig,
 		cmdGetStats,
 		cmdQueryStats,
 		cmdSysStats,
@@ -34,5 +50,10 @@ var CmdAPI = &base.Command{
 		cmdOnlineStats,
 		cmdOnlineStatsIpList,
 		cmdGetAllOnlineUsers,
+		cmdSetRateLimit,
+		cmdGetRateLimit,
+		cmdConns,
+		cmdFakeDNS,
+		cmdBans,
 	},
 }
@@ -0,0 +1,98 @@
+package api
+
+import (
+	banService "github.com/xtls/xray-core/app/ban/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdBans = &base.Command{
+	UsageLine: "{{.Exec}} api bans",
+	Short:     "List and lift bans of source IPs",
+	Long: `{{.Exec}} {{.LongName}} manages the source IPs banned for failed authentications in an Xray process.
+
+> Ensure that the "BanService" is properly configured under "config.api.services" in the server configuration.
+`,
+	Commands: []*base.Command{
+		cmdListBans,
+		cmdUnban,
+	},
+}
+
+var cmdListBans = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api bans list [--server=127.0.0.1:8080]",
+	Short:       "List banned source IPs",
+	Long: `
+List the banned source IPs, with the number of failed authentications and the
+time the bans expire.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080
+`,
+	Run: executeListBans,
+}
+
+var cmdUnban = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api bans unban [--server=127.0.0.1:8080] <ip>...",
+	Short:       "Lift bans of source IPs",
+	Long: `
+Lift the bans of the source IPs, and forget their failed authentications.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 203.0.113.1
+`,
+	Run: executeUnban,
+}
+
+func executeListBans(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := banService.NewBanServiceClient(conn)
+	resp, err := client.ListBans(ctx, &banService.ListBansRequest{})
+	if err != nil {
+		base.Fatalf("failed to list bans: %s", err)
+	}
+	showJSONResponse(resp)
+}
+
+func executeUnban(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+	ips := cmd.Flag.Args()
+	if len(ips) == 0 {
+		base.Fatalf("no IP specified")
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := banService.NewBanServiceClient(conn)
+	for _, ip := range ips {
+		if _, err := client.Unban(ctx, &banService.UnbanRequest{Ip: ip}); err != nil {
+			base.Fatalf("failed to unban %s: %s", ip, err)
+		}
+	}
+}
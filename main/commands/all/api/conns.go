@@ -0,0 +1,181 @@
+package api
+
+import (
+	"context"
+	"io"
+
+	connectionService "github.com/xtls/xray-core/app/dispatcher/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdConns = &base.Command{
+	UsageLine: "{{.Exec}} api conns",
+	Short:     "List, watch and close active connections",
+	Long: `{{.Exec}} {{.LongName}} manages the connections in an Xray process.
+
+> Ensure that the "ConnectionService" is properly configured under "config.api.services" in the server configuration.
+`,
+	Commands: []*base.Command{
+		cmdListConns,
+		cmdWatchConns,
+		cmdCloseConns,
+	},
+}
+
+var cmdListConns = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api conns list [--server=127.0.0.1:8080] [-email '']",
+	Short:       "List active connections",
+	Long: `
+List the active connections, with their traffic so far.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-email
+		Only list the connections of the user.
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -email "xray@love.com"
+`,
+	Run: executeListConns,
+}
+
+var cmdWatchConns = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api conns watch [--server=127.0.0.1:8080] [-opened]",
+	Short:       "Print connections when they are closed",
+	Long: `
+Print the record of each connection when it is closed, until interrupted.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for connecting to the API server. Default 3
+
+	-opened
+		Also print connections when they are opened. Default false
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -opened
+`,
+	Run: executeWatchConns,
+}
+
+var cmdCloseConns = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api conns close [--server=127.0.0.1:8080] [-id 0] [-email '']",
+	Short:       "Close a connection or all connections of a user",
+	Long: `
+Close an active connection by its ID, or all active connections of a user.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-id
+		ID of the connection, as listed by "{{.Exec}} api conns list".
+
+	-email
+		The user's email address.
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -id 42
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -email "xray@love.com"
+`,
+	Run: executeCloseConns,
+}
+
+func executeListConns(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	email := cmd.Flag.String("email", "", "")
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := connectionService.NewConnectionServiceClient(conn)
+	resp, err := client.ListConnections(ctx, &connectionService.ListConnectionsRequest{
+		Email: *email,
+	})
+	if err != nil {
+		base.Fatalf("failed to list connections: %s", err)
+	}
+	showJSONResponse(resp)
+}
+
+func executeWatchConns(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	opened := cmd.Flag.Bool("opened", false, "")
+	cmd.Flag.Parse(args)
+
+	conn, _, close := dialAPIServer()
+	defer close()
+
+	// The timeout only applies to connecting, as the stream lasts until interrupted.
+	client := connectionService.NewConnectionServiceClient(conn)
+	stream, err := client.SubscribeConnectionRecords(context.Background(), &connectionService.SubscribeConnectionRecordsRequest{
+		Opened: *opened,
+	})
+	if err != nil {
+		base.Fatalf("failed to watch connections: %s", err)
+	}
+	for {
+		record, err := stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			base.Fatalf("failed to watch connections: %s", err)
+		}
+		showJSONResponse(record)
+	}
+}
+
+func executeCloseConns(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	id := cmd.Flag.Uint64("id", 0, "")
+	email := cmd.Flag.String("email", "", "")
+	cmd.Flag.Parse(args)
+
+	if (*id == 0) == (*email == "") {
+		base.Fatalf("either -id or -email must be specified")
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := connectionService.NewConnectionServiceClient(conn)
+	if *id != 0 {
+		resp, err := client.CloseConnection(ctx, &connectionService.CloseConnectionRequest{
+			Id: *id,
+		})
+		if err != nil {
+			base.Fatalf("failed to close connection: %s", err)
+		}
+		showJSONResponse(resp)
+		return
+	}
+	resp, err := client.CloseUserConnections(ctx, &connectionService.CloseUserConnectionsRequest{
+		Email: *email,
+	})
+	if err != nil {
+		base.Fatalf("failed to close connections: %s", err)
+	}
+	showJSONResponse(resp)
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	fakednsService "github.com/xtls/xray-core/app/dns/fakedns/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdFakeDNS = &base.Command{
+	UsageLine: "{{.Exec}} api fakedns",
+	Short:     "Look up and flush fake DNS mappings",
+	Long: `{{.Exec}} {{.LongName}} manages the mappings between domains and fake IPs in an Xray process.
+
+> Ensure that the "FakeDNSService" is properly configured under "config.api.services" in the server configuration.
+`,
+	Commands: []*base.Command{
+		cmdLookupFakeDNS,
+		cmdFlushFakeDNS,
+	},
+}
+
+var cmdLookupFakeDNS = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api fakedns lookup [--server=127.0.0.1:8080] [-domain ''] [-ip '']",
+	Short:       "Look up fake DNS mappings",
+	Long: `
+Look up the fake IP of a domain, or the domain of a fake IP. All mappings are
+listed if neither is specified.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-domain
+		The domain to look up.
+
+	-ip
+		The fake IP to look up.
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -domain example.com
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -ip 198.18.0.1
+`,
+	Run: executeLookupFakeDNS,
+}
+
+var cmdFlushFakeDNS = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api fakedns flush [--server=127.0.0.1:8080] [-domain '']",
+	Short:       "Remove fake DNS mappings",
+	Long: `
+Remove the mapping of a domain, or all mappings if no domain is specified.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-domain
+		The domain to remove the mapping of.
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -domain example.com
+`,
+	Run: executeFlushFakeDNS,
+}
+
+func executeLookupFakeDNS(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	domain := cmd.Flag.String("domain", "", "")
+	ip := cmd.Flag.String("ip", "", "")
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := fakednsService.NewFakeDNSServiceClient(conn)
+	resp, err := client.LookupFakeDNS(ctx, &fakednsService.LookupFakeDNSRequest{
+		Domain: *domain,
+		Ip:     *ip,
+	})
+	if err != nil {
+		base.Fatalf("failed to look up fake DNS: %s", err)
+	}
+	showJSONResponse(resp)
+}
+
+func executeFlushFakeDNS(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	domain := cmd.Flag.String("domain", "", "")
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := fakednsService.NewFakeDNSServiceClient(conn)
+	resp, err := client.FlushFakeDNS(ctx, &fakednsService.FlushFakeDNSRequest{
+		Domain: *domain,
+	})
+	if err != nil {
+		base.Fatalf("failed to flush fake DNS: %s", err)
+	}
+	showJSONResponse(resp)
+}
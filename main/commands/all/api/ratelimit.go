@@ -0,0 +1,169 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/xtls/xray-core/app/policy"
+	policyService "github.com/xtls/xray-core/app/policy/command"
+	"github.com/xtls/xray-core/common/units"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdSetRateLimit = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api setratelimit [--server=127.0.0.1:8080] [-email ''] [-level 0] [-up 0] [-down 0] [-r]",
+	Short:       "Set the bandwidth limit of a user or level",
+	Long: `
+Set the bandwidth limit of a user or of all users of a level. The new limit
+applies to live connections immediately, and lasts until Xray restarts or,
+for a level, until the config is reloaded.
+
+> Ensure that the "PolicyService" is properly configured under "config.api.services" in the server configuration.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-email
+		The user's email address. If not set, the limit applies to the level.
+
+	-level
+		The user level. Default 0
+
+	-up, -down
+		Uplink and downlink limits per second, like 1MB. 0 means unlimited.
+
+	-upburst, -downburst
+		Maximum burst sizes. Default the same as the limits.
+
+	-r, -remove
+		Remove the limit set on the user through the API, and use the config again.
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -email "xray@love.com" -up 1MB -down 10MB
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -level 1 -down 5MB
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -email "xray@love.com" -r
+`,
+	Run: executeSetRateLimit,
+}
+
+var cmdGetRateLimit = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api getratelimit [--server=127.0.0.1:8080] [-email ''] [-level 0]",
+	Short:       "Get the bandwidth limit of a user",
+	Long: `
+Get the bandwidth limit in effect for a user, in bytes per second.
+
+> Ensure that the "PolicyService" is properly configured under "config.api.services" in the server configuration.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-email
+		The user's email address.
+
+	-level
+		The user level, used when the user has no live connections. Default 0
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -email "xray@love.com"
+`,
+	Run: executeGetRateLimit,
+}
+
+func executeSetRateLimit(cmd *base.Command, args []string) {
+	var (
+		email     string
+		level     uint
+		remove    bool
+		up        string
+		down      string
+		upBurst   string
+		downBurst string
+	)
+	setSharedFlags(cmd)
+	cmd.Flag.StringVar(&email, "email", "", "")
+	cmd.Flag.UintVar(&level, "level", 0, "")
+	cmd.Flag.BoolVar(&remove, "r", false, "")
+	cmd.Flag.BoolVar(&remove, "remove", false, "")
+	cmd.Flag.StringVar(&up, "up", "0", "")
+	cmd.Flag.StringVar(&down, "down", "0", "")
+	cmd.Flag.StringVar(&upBurst, "upburst", "0", "")
+	cmd.Flag.StringVar(&downBurst, "downburst", "0", "")
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := policyService.NewPolicyServiceClient(conn)
+	if remove {
+		if email == "" {
+			base.Fatalf("email not specified")
+		}
+		resp, err := client.ClearRateLimit(ctx, &policyService.ClearRateLimitRequest{Email: email})
+		if err != nil {
+			base.Fatalf("failed to clear rate limit: %s", err)
+		}
+		showJSONResponse(resp)
+		return
+	}
+
+	r := &policyService.SetRateLimitRequest{
+		Email: email,
+		Level: uint32(level),
+		RateLimit: &policy.Policy_RateLimit{
+			Uplink:        parseByteSize(up),
+			Downlink:      parseByteSize(down),
+			UplinkBurst:   parseByteSize(upBurst),
+			DownlinkBurst: parseByteSize(downBurst),
+		},
+	}
+	resp, err := client.SetRateLimit(ctx, r)
+	if err != nil {
+		base.Fatalf("failed to set rate limit: %s", err)
+	}
+	showJSONResponse(resp)
+}
+
+func parseByteSize(s string) uint64 {
+	if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return n
+	}
+	var size units.ByteSize
+	if err := size.Parse(s); err != nil {
+		base.Fatalf("invalid size %s: %s", s, err)
+	}
+	return uint64(size)
+}
+
+func executeGetRateLimit(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	email := cmd.Flag.String("email", "", "")
+	level := cmd.Flag.Uint("level", 0, "")
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := policyService.NewPolicyServiceClient(conn)
+	r := &policyService.GetRateLimitRequest{
+		Email: *email,
+		Level: uint32(*level),
+	}
+	resp, err := client.GetRateLimit(ctx, r)
+	if err != nil {
+		base.Fatalf("failed to get rate limit: %s", err)
+	}
+	showJSONResponse(resp)
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	reloadService "github.com/xtls/xray-core/app/reload/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdReloadConfig = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api reload [--server=127.0.0.1:8080]",
+	Short:       "Reload the config",
+	Long: `
+Reload the config of Xray from the config files it was started with,
+and apply the changes without restarting. Equivalent to sending SIGHUP
+to the Xray process.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080
+`,
+	Run: executeReloadConfig,
+}
+
+func executeReloadConfig(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := reloadService.NewReloadServiceClient(conn)
+	r := &reloadService.ReloadConfigRequest{}
+	resp, err := client.ReloadConfig(ctx, r)
+	if err != nil {
+		base.Fatalf("failed to reload config: %s", err)
+	}
+	showJSONResponse(resp)
+}
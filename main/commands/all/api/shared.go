@@ -3,6 +3,8 @@ package api
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
@@ -13,6 +15,7 @@ import (
 	"strings"
 	"time"
 
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/xtls/xray-core/common/buf"
@@ -28,6 +31,12 @@ var (
 	apiServerAddrPtr string
 	apiTimeout       int
 	apiJSON          bool
+
+	apiToken    string
+	apiTLS      bool
+	apiCAFile   string
+	apiCertFile string
+	apiKeyFile  string
 )
 
 func setSharedFlags(cmd *base.Command) {
@@ -36,11 +45,62 @@ func setSharedFlags(cmd *base.Command) {
 	cmd.Flag.IntVar(&apiTimeout, "t", 3, "")
 	cmd.Flag.IntVar(&apiTimeout, "timeout", 3, "")
 	cmd.Flag.BoolVar(&apiJSON, "json", false, "")
+	cmd.Flag.StringVar(&apiToken, "token", os.Getenv("XRAY_API_TOKEN"), "")
+	cmd.Flag.BoolVar(&apiTLS, "tls", false, "")
+	cmd.Flag.StringVar(&apiCAFile, "ca", "", "")
+	cmd.Flag.StringVar(&apiCertFile, "cert", "", "")
+	cmd.Flag.StringVar(&apiKeyFile, "key", "", "")
+}
+
+// tokenCredentials sends the token of the API as bearer token.
+type tokenCredentials string
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+func (tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// dialOptions returns the options for the credentials flags.
+func dialOptions() ([]grpc.DialOption, error) {
+	options := []grpc.DialOption{grpc.WithBlock()}
+	if apiToken != "" {
+		options = append(options, grpc.WithPerRPCCredentials(tokenCredentials(apiToken)))
+	}
+	if !apiTLS && apiCAFile == "" && apiCertFile == "" {
+		return append(options, grpc.WithTransportCredentials(insecure.NewCredentials())), nil
+	}
+
+	tlsConfig := &tls.Config{}
+	if apiCAFile != "" {
+		pem, err := os.ReadFile(apiCAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate in %s", apiCAFile)
+		}
+	}
+	if apiCertFile != "" || apiKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(apiCertFile, apiKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return append(options, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))), nil
 }
 
 func dialAPIServer() (conn *grpc.ClientConn, ctx context.Context, close func()) {
+	options, err := dialOptions()
+	if err != nil {
+		base.Fatalf("failed to load API credentials: %s", err)
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(apiTimeout)*time.Second)
-	conn, err := grpc.DialContext(ctx, apiServerAddrPtr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	conn, err = grpc.DialContext(ctx, apiServerAddrPtr, options...)
 	if err != nil {
 		base.Fatalf("failed to dial %s", apiServerAddrPtr)
 	}
@@ -0,0 +1,209 @@
+package convert
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xtls/xray-core/infra/conf/serial"
+	"github.com/xtls/xray-core/infra/conf/sharelink"
+	"github.com/xtls/xray-core/main/commands/base"
+	"github.com/xtls/xray-core/main/confloader"
+)
+
+var cmdLink = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} convert link [share link | stdin: | file] ...",
+	Short:       "Convert share links to outbounds",
+	Long: `
+Convert share links of VLESS, VMess, Trojan, Shadowsocks and Hysteria 2 to
+outbounds in JSON. Each argument is either a share link, or a file or URL with
+one share link per line, which may be encoded in base64 as a whole like most
+subscriptions.
+
+The name of a link is used as the tag of its outbound.
+
+Examples:
+
+    {{.Exec}} convert link "vless://uuid@example.com:443?security=reality&pbk=...#proxy"
+    {{.Exec}} convert link links.txt
+    {{.Exec}} convert link https://example.com/subscription
+	`,
+	Run: executeLinkToOutbounds,
+}
+
+var cmdToLink = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} convert tolink [-address host] [stdin:] [json file] ...",
+	Short:       "Convert inbounds and outbounds to share links",
+	Long: `
+Convert the VLESS, VMess, Trojan, Shadowsocks and Hysteria 2 inbounds and
+outbounds in JSON configs to share links, one per line. An inbound produces a
+link for each of its clients, named by the email of the client.
+
+Arguments:
+
+	-a, -address
+		The address for clients to connect to inbounds. Default the listen
+		address of the inbound, if it is not a wildcard.
+
+Examples:
+
+    {{.Exec}} convert tolink -address example.com config.json
+	`,
+	Run: executeToLink,
+}
+
+// isLink returns whether the argument is a share link, rather than a file or URL.
+func isLink(arg string) bool {
+	scheme, _, found := strings.Cut(arg, "://")
+	if !found {
+		return false
+	}
+	switch strings.ToLower(scheme) {
+	case "vless", "vmess", "trojan", "ss", "hysteria2", "hy2":
+		return true
+	}
+	return false
+}
+
+// readLinks reads share links from a file, in plain text or base64.
+func readLinks(arg string) ([]string, error) {
+	reader, err := confloader.LoadConfig(arg)
+	if err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	b = bytes.TrimSpace(b)
+	if !bytes.Contains(b, []byte("://")) {
+		s := strings.Join(strings.Fields(string(b)), "")
+		decoded, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			decoded, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("no share link in %s", arg)
+		}
+		b = decoded
+	}
+	var links []string
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			links = append(links, line)
+		}
+	}
+	return links, scanner.Err()
+}
+
+func executeLinkToOutbounds(cmd *base.Command, args []string) {
+	cmd.Flag.Parse(args)
+	if cmd.Flag.NArg() < 1 {
+		base.Fatalf("empty input list")
+	}
+
+	var links []string
+	for _, arg := range cmd.Flag.Args() {
+		if isLink(arg) {
+			links = append(links, arg)
+			continue
+		}
+		l, err := readLinks(arg)
+		if err != nil {
+			base.Fatalf("failed to read links: %s", err)
+		}
+		links = append(links, l...)
+	}
+
+	outbounds := make([]json.RawMessage, 0, len(links))
+	for _, link := range links {
+		l, err := sharelink.Parse(link)
+		if err != nil {
+			base.Fatalf("failed to parse link: %s", err)
+		}
+		outbound, err := l.Outbound()
+		if err != nil {
+			base.Fatalf("failed to convert link %s: %s", link, err)
+		}
+		outbounds = append(outbounds, outbound)
+	}
+
+	b, err := json.MarshalIndent(map[string]interface{}{"outbounds": outbounds}, "", "  ")
+	if err != nil {
+		base.Fatalf("failed to marshal outbounds: %s", err)
+	}
+	fmt.Println(string(b))
+}
+
+func executeToLink(cmd *base.Command, args []string) {
+	var address string
+	cmd.Flag.StringVar(&address, "a", "", "")
+	cmd.Flag.StringVar(&address, "address", "", "")
+	cmd.Flag.Parse(args)
+	if cmd.Flag.NArg() < 1 {
+		base.Fatalf("empty input list")
+	}
+
+	for _, arg := range cmd.Flag.Args() {
+		reader, err := confloader.LoadConfig(arg)
+		if err != nil {
+			base.Fatalf("failed to load config: %s", err)
+		}
+		config, err := serial.DecodeJSONConfig(reader)
+		if err != nil {
+			base.Fatalf("failed to decode config: %s", err)
+		}
+
+		for _, inbound := range config.InboundConfigs {
+			if !isLinkProtocol(inbound.Protocol) {
+				continue
+			}
+			host := address
+			if listen := inbound.ListenOn; host == "" && listen != nil {
+				if listen.Family().IsDomain() {
+					host = listen.Domain()
+				} else if !listen.IP().IsUnspecified() {
+					host = listen.IP().String()
+				}
+			}
+			if host == "" {
+				base.Fatalf("no address for inbound %s, please specify -address", inbound.Tag)
+			}
+			links, err := sharelink.FromInbound(&inbound, host)
+			if err != nil {
+				base.Fatalf("failed to convert inbound %s: %s", inbound.Tag, err)
+			}
+			for _, l := range links {
+				fmt.Println(l.String())
+			}
+		}
+
+		for _, outbound := range config.OutboundConfigs {
+			if !isLinkProtocol(outbound.Protocol) {
+				continue
+			}
+			l, err := sharelink.FromOutbound(&outbound)
+			if err != nil {
+				base.Fatalf("failed to convert outbound %s: %s", outbound.Tag, err)
+			}
+			fmt.Println(l.String())
+		}
+	}
+}
+
+// isLinkProtocol returns whether inbounds and outbounds of the protocol have share links.
+func isLinkProtocol(protocol string) bool {
+	switch strings.ToLower(protocol) {
+	case "vless", "vmess", "trojan", "shadowsocks", "hysteria":
+		return true
+	}
+	return false
+}
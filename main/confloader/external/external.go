@@ -2,8 +2,6 @@ package external
 
 import (
 	"bytes"
-	"context"
-	"net"
 	"io"
 	"net/http"
 	"net/url"
@@ -13,6 +11,7 @@ import (
 
 	"github.com/xtls/xray-core/common/buf"
 	"github.com/xtls/xray-core/common/errors"
+	http_proto "github.com/xtls/xray-core/common/protocol/http"
 	"github.com/xtls/xray-core/main/confloader"
 )
 
@@ -76,41 +75,14 @@ func FetchHTTPContent(target string) ([]byte, error) {
 
 // Format: http+unix:///path/to/socket.sock/api/endpoint
 func FetchUnixSocketHTTPContent(target string) ([]byte, error) {
-	path := strings.TrimPrefix(target, "http+unix://")
-	
-	if !strings.HasPrefix(path, "/") {
-		return nil, errors.New("unix socket path must be absolute")
-	}
-	
-	var socketPath, httpPath string
-	
-	sockIdx := strings.Index(path, ".sock")
-	if sockIdx != -1 {
-		socketPath = path[:sockIdx+5]
-		httpPath = path[sockIdx+5:]
-		if httpPath == "" {
-			httpPath = "/"
-		}
-	} else {
-		return nil, errors.New("cannot determine socket path, socket file should have .sock extension")
-	}
-	
-	if _, err := os.Stat(socketPath); err != nil {
-		return nil, errors.New("socket file not found: ", socketPath).Base(err)
-	}
-	
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-		Transport: &http.Transport{
-			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
-				var d net.Dialer
-				return d.DialContext(ctx, "unix", socketPath)
-			},
-		},
+	socketPath, endpoint, err := http_proto.ParseUnixURL(target)
+	if err != nil {
+		return nil, err
 	}
+	client := http_proto.NewUnixClient(socketPath, 30*time.Second)
 	defer client.CloseIdleConnections()
 	
-	resp, err := client.Get("http://localhost" + httpPath)
+	resp, err := client.Get(endpoint)
 	if err != nil {
 		return nil, errors.New("failed to fetch from unix socket: ", socketPath).Base(err)
 	}
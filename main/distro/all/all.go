@@ -9,15 +9,21 @@ import (
 	_ "github.com/xtls/xray-core/app/proxyman/outbound"
 
 	// Default commander and all its services. This is an optional feature.
+	_ "github.com/xtls/xray-core/app/ban/command"
 	_ "github.com/xtls/xray-core/app/commander"
+	_ "github.com/xtls/xray-core/app/dispatcher/command"
+	_ "github.com/xtls/xray-core/app/dns/fakedns/command"
 	_ "github.com/xtls/xray-core/app/log/command"
+	_ "github.com/xtls/xray-core/app/policy/command"
 	_ "github.com/xtls/xray-core/app/proxyman/command"
+	_ "github.com/xtls/xray-core/app/reload/command"
 	_ "github.com/xtls/xray-core/app/stats/command"
 
 	// Developer preview services
 	_ "github.com/xtls/xray-core/app/observatory/command"
 
 	// Other optional features.
+	_ "github.com/xtls/xray-core/app/ban"
 	_ "github.com/xtls/xray-core/app/dns"
 	_ "github.com/xtls/xray-core/app/dns/fakedns"
 	_ "github.com/xtls/xray-core/app/log"
@@ -26,6 +32,7 @@ import (
 	_ "github.com/xtls/xray-core/app/reverse"
 	_ "github.com/xtls/xray-core/app/router"
 	_ "github.com/xtls/xray-core/app/stats"
+	_ "github.com/xtls/xray-core/app/subscription"
 
 	// Fix dependency cycle caused by core import in internet package
 	_ "github.com/xtls/xray-core/transport/internet/tagged/taggedimpl"
@@ -69,6 +76,9 @@ import (
 	_ "github.com/xtls/xray-core/main/toml"
 	_ "github.com/xtls/xray-core/main/yaml"
 
+	// Share links in subscriptions
+	_ "github.com/xtls/xray-core/infra/conf/sharelink"
+
 	// Load config from file or http(s)
 	_ "github.com/xtls/xray-core/main/confloader/external"
 
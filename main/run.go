@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -40,6 +41,10 @@ The -test flag tells Xray to test config files only,
 without launching the server.
 
 The -dump flag tells Xray to print the merged config.
+
+Sending SIGHUP to the running process reloads the config files,
+and applies the changes to routing, DNS, policy, log and the tagged
+inbounds and outbounds in place, without restarting.
 	`,
 }
 
@@ -105,8 +110,15 @@ func executeRun(cmd *base.Command, args []string) {
 
 	{
 		osSignals := make(chan os.Signal, 1)
-		signal.Notify(osSignals, os.Interrupt, syscall.SIGTERM)
-		<-osSignals
+		signal.Notify(osSignals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+		for sig := range osSignals {
+			if sig != syscall.SIGHUP {
+				break
+			}
+			if err := server.ReloadConfig(); err != nil {
+				errors.LogErrorInner(context.Background(), err, "failed to reload config")
+			}
+		}
 	}
 }
 
@@ -148,7 +160,7 @@ func getRegepxByFormat() string {
 	}
 }
 
-func readConfDir(dirPath string) {
+func readConfDir(dirPath string, files *cmdarg.Arg) {
 	confs, err := os.ReadDir(dirPath)
 	if err != nil {
 		log.Fatalln(err)
@@ -159,26 +171,28 @@ func readConfDir(dirPath string) {
 			log.Fatalln(err)
 		}
 		if matched {
-			configFiles.Set(path.Join(dirPath, f.Name()))
+			files.Set(path.Join(dirPath, f.Name()))
 		}
 	}
 }
 
 func getConfigFilePath(verbose bool) cmdarg.Arg {
+	// Copy the files given by flags, so that the confdir is read again on every call, e.g. when reloading.
+	files := append(cmdarg.Arg{}, configFiles...)
 	if dirExists(configDir) {
 		if verbose {
 			log.Println("Using confdir from arg:", configDir)
 		}
-		readConfDir(configDir)
+		readConfDir(configDir, &files)
 	} else if envConfDir := platform.GetConfDirPath(); dirExists(envConfDir) {
 		if verbose {
 			log.Println("Using confdir from env:", envConfDir)
 		}
-		readConfDir(envConfDir)
+		readConfDir(envConfDir, &files)
 	}
 
-	if len(configFiles) > 0 {
-		return configFiles
+	if len(files) > 0 {
+		return files
 	}
 
 	if workingDir, err := os.Getwd(); err == nil {
@@ -215,8 +229,8 @@ func getConfigFormat() string {
 	return f
 }
 
-func startXray() (core.Server, error) {
-	configFiles := getConfigFilePath(true)
+func loadConfig(verbose bool) (*core.Config, error) {
+	configFiles := getConfigFilePath(verbose)
 
 	// config, err := core.LoadConfig(getConfigFormat(), configFiles[0], configFiles)
 
@@ -224,11 +238,25 @@ func startXray() (core.Server, error) {
 	if err != nil {
 		return nil, errors.New("failed to load config files: [", configFiles.String(), "]").Base(err)
 	}
+	return c, nil
+}
+
+func startXray() (*core.Instance, error) {
+	c, err := loadConfig(true)
+	if err != nil {
+		return nil, err
+	}
 
 	server, err := core.New(c)
 	if err != nil {
 		return nil, errors.New("failed to create server").Base(err)
 	}
+	server.SetConfigProvider(func() (*core.Config, error) {
+		if files := getConfigFilePath(false); len(files) == 1 && files[0] == "stdin:" {
+			return nil, errors.New("config read from stdin can't be reloaded")
+		}
+		return loadConfig(false)
+	})
 
 	return server, nil
 }
@@ -8,6 +8,7 @@ package http
 
 import (
 	protocol "github.com/xtls/xray-core/common/protocol"
+	webhook "github.com/xtls/xray-core/proxy/webhook"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -80,8 +81,10 @@ type ServerConfig struct {
 	Accounts         map[string]string      `protobuf:"bytes,2,rep,name=accounts,proto3" json:"accounts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	AllowTransparent bool                   `protobuf:"varint,3,opt,name=allow_transparent,json=allowTransparent,proto3" json:"allow_transparent,omitempty"`
 	UserLevel        uint32                 `protobuf:"varint,4,opt,name=user_level,json=userLevel,proto3" json:"user_level,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	// Webhook validates the accounts not in accounts.
+	Webhook       *webhook.Config `protobuf:"bytes,5,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ServerConfig) Reset() {
@@ -135,6 +138,13 @@ func (x *ServerConfig) GetUserLevel() uint32 {
 	return 0
 }
 
+func (x *ServerConfig) GetWebhook() *webhook.Config {
+	if x != nil {
+		return x.Webhook
+	}
+	return nil
+}
+
 type Header struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -245,15 +255,16 @@ var File_proxy_http_config_proto protoreflect.FileDescriptor
 
 const file_proxy_http_config_proto_rawDesc = "" +
 	"\n" +
-	"\x17proxy/http/config.proto\x12\x0fxray.proxy.http\x1a!common/protocol/server_spec.proto\"A\n" +
+	"\x17proxy/http/config.proto\x12\x0fxray.proxy.http\x1a!common/protocol/server_spec.proto\x1a\x1aproxy/webhook/config.proto\"A\n" +
 	"\aAccount\x12\x1a\n" +
 	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"\xe0\x01\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"\x96\x02\n" +
 	"\fServerConfig\x12G\n" +
 	"\baccounts\x18\x02 \x03(\v2+.xray.proxy.http.ServerConfig.AccountsEntryR\baccounts\x12+\n" +
 	"\x11allow_transparent\x18\x03 \x01(\bR\x10allowTransparent\x12\x1d\n" +
 	"\n" +
-	"user_level\x18\x04 \x01(\rR\tuserLevel\x1a;\n" +
+	"user_level\x18\x04 \x01(\rR\tuserLevel\x124\n" +
+	"\awebhook\x18\x05 \x01(\v2\x1a.xray.proxy.webhook.ConfigR\awebhook\x1a;\n" +
 	"\rAccountsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"0\n" +
@@ -284,17 +295,19 @@ var file_proxy_http_config_proto_goTypes = []any{
 	(*Header)(nil),                  // 2: xray.proxy.http.Header
 	(*ClientConfig)(nil),            // 3: xray.proxy.http.ClientConfig
 	nil,                             // 4: xray.proxy.http.ServerConfig.AccountsEntry
-	(*protocol.ServerEndpoint)(nil), // 5: xray.common.protocol.ServerEndpoint
+	(*webhook.Config)(nil),          // 5: xray.proxy.webhook.Config
+	(*protocol.ServerEndpoint)(nil), // 6: xray.common.protocol.ServerEndpoint
 }
 var file_proxy_http_config_proto_depIdxs = []int32{
 	4, // 0: xray.proxy.http.ServerConfig.accounts:type_name -> xray.proxy.http.ServerConfig.AccountsEntry
-	5, // 1: xray.proxy.http.ClientConfig.server:type_name -> xray.common.protocol.ServerEndpoint
-	2, // 2: xray.proxy.http.ClientConfig.header:type_name -> xray.proxy.http.Header
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	5, // 1: xray.proxy.http.ServerConfig.webhook:type_name -> xray.proxy.webhook.Config
+	6, // 2: xray.proxy.http.ClientConfig.server:type_name -> xray.common.protocol.ServerEndpoint
+	2, // 3: xray.proxy.http.ClientConfig.header:type_name -> xray.proxy.http.Header
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_proxy_http_config_proto_init() }
@@ -23,6 +23,7 @@ import (
 	"github.com/xtls/xray-core/features/policy"
 	"github.com/xtls/xray-core/features/routing"
 	"github.com/xtls/xray-core/proxy"
+	"github.com/xtls/xray-core/proxy/webhook"
 	"github.com/xtls/xray-core/transport"
 	"github.com/xtls/xray-core/transport/internet/stat"
 )
@@ -31,6 +32,7 @@ import (
 type Server struct {
 	config        *ServerConfig
 	policyManager policy.Manager
+	webhook       *webhook.Authenticator
 }
 
 // NewServer creates a new HTTP inbound handler.
@@ -40,10 +42,42 @@ func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
 		config:        config,
 		policyManager: v.GetFeature(policy.ManagerType()).(policy.Manager),
 	}
+	if config.Webhook != nil {
+		w, err := webhook.New(config.Webhook)
+		if err != nil {
+			return nil, err
+		}
+		s.webhook = w
+	}
 
 	return s, nil
 }
 
+// Authenticate returns the user of the username and password sent by a client of the protocol,
+// or nil if they are invalid. The SOCKS server authenticates its clients with it too.
+func (s *Server) Authenticate(ctx context.Context, protocolName, username, password string) *protocol.MemoryUser {
+	if s.config.HasAccount(username, password) {
+		return &protocol.MemoryUser{Email: username, Level: s.config.UserLevel}
+	}
+	if s.webhook == nil {
+		return nil
+	}
+	r := s.webhook.Authenticate(ctx, webhook.Credential{
+		Protocol: protocolName,
+		Username: username,
+		Password: password,
+	})
+	if r == nil {
+		return nil
+	}
+	// The result is shared by the cache of the webhook, so it is not modified.
+	email := r.Email
+	if email == "" {
+		email = username
+	}
+	return &protocol.MemoryUser{Email: email, Level: r.Level}
+}
+
 func (s *Server) policy() policy.Session {
 	config := s.config
 	p := s.policyManager.ForLevel(config.UserLevel)
@@ -122,13 +156,17 @@ Start:
 		return trace
 	}
 
-	if len(s.config.Accounts) > 0 {
-		user, pass, ok := parseBasicAuth(request.Header.Get("Proxy-Authorization"))
-		if !ok || !s.config.HasAccount(user, pass) {
+	if len(s.config.Accounts) > 0 || s.webhook != nil {
+		var user *protocol.MemoryUser
+		if username, password, ok := parseBasicAuth(request.Header.Get("Proxy-Authorization")); ok {
+			user = s.Authenticate(ctx, "http", username, password)
+		}
+		if user == nil {
 			return common.Error2(conn.Write([]byte("HTTP/1.1 407 Proxy Authentication Required\r\nProxy-Authenticate: Basic realm=\"proxy\"\r\n\r\n")))
 		}
 		if inbound != nil {
-			inbound.User.Email = user
+			inbound.User.Email = user.Email
+			inbound.User.Level = user.Level
 		}
 	}
 
@@ -93,6 +93,11 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn stat.Con
 			userlevel = inbound.User.Level
 		}
 	}
+	release, err := policy.AcquireUserConnection(s.policyManager, inbound.User, inbound.Source.Address, conn.RemoteAddr(), "")
+	if err != nil {
+		return err
+	}
+	defer release()
 
 	iConn := stat.TryUnwrapStatsConn(conn)
 	if _, ok := iConn.(*hysteria.InterUdpConn); ok {
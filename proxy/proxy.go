@@ -90,6 +90,18 @@ type UserManager interface {
 	GetUsersCount(context.Context) int64
 }
 
+// AccountCreator is implemented by UserManagers whose users can be loaded from users files.
+type AccountCreator interface {
+	// CreateAccount creates the account of a user from its ID or password, and flow.
+	CreateAccount(id, password, flow string) (protocol.Account, error)
+}
+
+// GetUsersFile is implemented by inbound handlers whose users may be loaded from a users file.
+type GetUsersFile interface {
+	// GetUsersFile returns the path of the users file, or empty if the users are not loaded from one.
+	GetUsersFile() string
+}
+
 type GetInbound interface {
 	GetInbound() Inbound
 }
@@ -2,6 +2,7 @@ package shadowsocks
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/xtls/xray-core/common"
@@ -15,6 +16,7 @@ import (
 	"github.com/xtls/xray-core/common/signal"
 	"github.com/xtls/xray-core/common/task"
 	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/ban"
 	"github.com/xtls/xray-core/features/policy"
 	"github.com/xtls/xray-core/features/routing"
 	"github.com/xtls/xray-core/transport/internet/stat"
@@ -151,9 +153,20 @@ func (s *Server) handleUDPPayload(ctx context.Context, conn stat.Connection, dis
 			} else {
 				request, data, err = DecodeUDPPacket(s.validator, payload)
 				if err == nil {
+					// The packets from the same source are of the same user, so they count as one connection.
+					release, err := policy.AcquireUserConnection(s.policyManager, request.User, inbound.Source.Address, inbound.Source, request.Destination())
+					if err != nil {
+						errors.LogInfoInner(ctx, err, "dropping UDP packet from: ", inbound.Source)
+						payload.Release()
+						continue
+					}
+					defer release()
 					inbound.User = request.User
 				}
 			}
+			if err == nil {
+				err = request.User.CheckQuota()
+			}
 
 			if err != nil {
 				if inbound.Source.IsValid() {
@@ -212,8 +225,16 @@ func (s *Server) handleConnection(ctx context.Context, conn stat.Connection, dis
 			Status: log.AccessRejected,
 			Reason: err,
 		})
+		if errors.Cause(err) != io.EOF {
+			ban.RecordFailure(ctx)
+		}
 		return errors.New("failed to create request from: ", conn.RemoteAddr()).Base(err)
 	}
+	release, err := policy.AcquireUserConnection(s.policyManager, request.User, session.InboundFromContext(ctx).Source.Address, conn.RemoteAddr(), request.Destination())
+	if err != nil {
+		return err
+	}
+	defer release()
 	conn.SetReadDeadline(time.Time{})
 
 	inbound := session.InboundFromContext(ctx)
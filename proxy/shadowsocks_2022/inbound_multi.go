@@ -3,6 +3,7 @@ package shadowsocks_2022
 import (
 	"context"
 	"encoding/base64"
+	goerrors "errors"
 	"strconv"
 	"strings"
 	"sync"
@@ -24,6 +25,9 @@ import (
 	"github.com/xtls/xray-core/common/session"
 	"github.com/xtls/xray-core/common/singbridge"
 	"github.com/xtls/xray-core/common/uuid"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/ban"
+	"github.com/xtls/xray-core/features/policy"
 	"github.com/xtls/xray-core/features/routing"
 	"github.com/xtls/xray-core/transport/internet/stat"
 )
@@ -39,6 +43,8 @@ type MultiUserInbound struct {
 	networks []net.Network
 	users    []*protocol.MemoryUser
 	service  *shadowaead_2022.MultiService[int]
+
+	policyManager policy.Manager
 }
 
 func NewMultiServer(ctx context.Context, config *MultiUserServerConfig) (*MultiUserInbound, error) {
@@ -63,8 +69,9 @@ func NewMultiServer(ctx context.Context, config *MultiUserServerConfig) (*MultiU
 	}
 
 	inbound := &MultiUserInbound{
-		networks: networks,
-		users:    memUsers,
+		networks:      networks,
+		users:         memUsers,
+		policyManager: core.MustFromContext(ctx).GetFeature(policy.ManagerType()).(policy.Manager),
 	}
 	if config.Key == "" {
 		return nil, errors.New("missing key")
@@ -89,6 +96,17 @@ func NewMultiServer(ctx context.Context, config *MultiUserServerConfig) (*MultiU
 	return inbound, nil
 }
 
+// CreateAccount implements proxy.AccountCreator.
+func (*MultiUserInbound) CreateAccount(id, password, flow string) (protocol.Account, error) {
+	if _, err := base64.StdEncoding.DecodeString(password); err != nil || password == "" {
+		return nil, errors.New("invalid Shadowsocks 2022 key")
+	}
+	if flow != "" {
+		return nil, errors.New("Shadowsocks 2022 doesn't support flow")
+	}
+	return (&Account{Key: password}).AsAccount()
+}
+
 // AddUser implements proxy.UserManager.AddUser().
 func (i *MultiUserInbound) AddUser(ctx context.Context, u *protocol.MemoryUser) error {
 	i.Lock()
@@ -200,7 +218,12 @@ func (i *MultiUserInbound) Process(ctx context.Context, network net.Network, con
 	ctx = session.ContextWithDispatcher(ctx, dispatcher)
 
 	if network == net.Network_TCP {
-		return singbridge.ReturnError(i.service.NewConnection(ctx, connection, metadata))
+		err := i.service.NewConnection(ctx, connection, metadata)
+		if goerrors.Is(err, shadowaead_2022.ErrInvalidRequest) {
+			// No user has the identity of the request.
+			ban.RecordFailure(ctx)
+		}
+		return singbridge.ReturnError(err)
 	} else {
 		reader := buf.NewReader(connection)
 		pc := &natPacketConn{connection}
@@ -228,6 +251,11 @@ func (i *MultiUserInbound) NewConnection(ctx context.Context, conn net.Conn, met
 	inbound := session.InboundFromContext(ctx)
 	userInt, _ := A.UserFromContext[int](ctx)
 	user := i.users[userInt]
+	release, err := policy.AcquireUserConnection(i.policyManager, user, inbound.Source.Address, metadata.Source, metadata.Destination)
+	if err != nil {
+		return err
+	}
+	defer release()
 	inbound.User = user
 	ctx = log.ContextWithAccessMessage(ctx, &log.AccessMessage{
 		From:   metadata.Source,
@@ -253,6 +281,11 @@ func (i *MultiUserInbound) NewPacketConnection(ctx context.Context, conn N.Packe
 	inbound := session.InboundFromContext(ctx)
 	userInt, _ := A.UserFromContext[int](ctx)
 	user := i.users[userInt]
+	release, err := policy.AcquireUserConnection(i.policyManager, user, inbound.Source.Address, metadata.Source, metadata.Destination)
+	if err != nil {
+		return err
+	}
+	defer release()
 	inbound.User = user
 	ctx = log.ContextWithAccessMessage(ctx, &log.AccessMessage{
 		From:   metadata.Source,
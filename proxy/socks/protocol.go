@@ -44,6 +44,8 @@ type ServerSession struct {
 	address      net.Address
 	port         net.Port
 	localAddress net.Address
+	// authenticate returns the user of the username and password, or nil if they are invalid.
+	authenticate func(username, password string) *protocol.MemoryUser
 }
 
 func (s *ServerSession) handshake4(cmd byte, reader io.Reader, writer io.Writer) (*protocol.RequestHeader, error) {
@@ -95,12 +97,12 @@ func (s *ServerSession) handshake4(cmd byte, reader io.Reader, writer io.Writer)
 	}
 }
 
-func (s *ServerSession) auth5(nMethod byte, reader io.Reader, writer io.Writer) (username string, err error) {
+func (s *ServerSession) auth5(nMethod byte, reader io.Reader, writer io.Writer) (user *protocol.MemoryUser, err error) {
 	buffer := buf.StackNew()
 	defer buffer.Release()
 
 	if _, err = buffer.ReadFullFrom(reader, int32(nMethod)); err != nil {
-		return "", errors.New("failed to read auth methods").Base(err)
+		return nil, errors.New("failed to read auth methods").Base(err)
 	}
 
 	var expectedAuth byte = authNotRequired
@@ -110,39 +112,41 @@ func (s *ServerSession) auth5(nMethod byte, reader io.Reader, writer io.Writer)
 
 	if !hasAuthMethod(expectedAuth, buffer.BytesRange(0, int32(nMethod))) {
 		writeSocks5AuthenticationResponse(writer, socks5Version, authNoMatchingMethod)
-		return "", errors.New("no matching auth method")
+		return nil, errors.New("no matching auth method")
 	}
 
 	if err := writeSocks5AuthenticationResponse(writer, socks5Version, expectedAuth); err != nil {
-		return "", errors.New("failed to write auth response").Base(err)
+		return nil, errors.New("failed to write auth response").Base(err)
 	}
 
 	if expectedAuth == authPassword {
 		username, password, err := ReadUsernamePassword(reader)
 		if err != nil {
-			return "", errors.New("failed to read username and password for authentication").Base(err)
+			return nil, errors.New("failed to read username and password for authentication").Base(err)
 		}
 
-		if !s.config.HasAccount(username, password) {
+		if s.authenticate != nil {
+			user = s.authenticate(username, password)
+		} else if s.config.HasAccount(username, password) {
+			user = &protocol.MemoryUser{Email: username, Level: s.config.UserLevel}
+		}
+		if user == nil {
 			writeSocks5AuthenticationResponse(writer, 0x01, 0xFF)
-			return "", errors.New("invalid username or password")
+			return nil, errors.New("invalid username or password")
 		}
 
 		if err := writeSocks5AuthenticationResponse(writer, 0x01, 0x00); err != nil {
-			return "", errors.New("failed to write auth response").Base(err)
+			return nil, errors.New("failed to write auth response").Base(err)
 		}
-		return username, nil
+		return user, nil
 	}
 
-	return "", nil
+	return nil, nil
 }
 
 func (s *ServerSession) handshake5(nMethod byte, reader io.Reader, writer io.Writer) (*protocol.RequestHeader, error) {
-	var (
-		username string
-		err      error
-	)
-	if username, err = s.auth5(nMethod, reader, writer); err != nil {
+	user, err := s.auth5(nMethod, reader, writer)
+	if err != nil {
 		return nil, err
 	}
 
@@ -157,9 +161,8 @@ func (s *ServerSession) handshake5(nMethod byte, reader io.Reader, writer io.Wri
 		buffer.Release()
 	}
 
-	request := new(protocol.RequestHeader)
-	if username != "" {
-		request.User = &protocol.MemoryUser{Email: username}
+	request := &protocol.RequestHeader{
+		User: user,
 	}
 	switch cmd {
 	case cmdTCPConnect, cmdTorResolve, cmdTorResolvePTR:
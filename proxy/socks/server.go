@@ -46,12 +46,23 @@ func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
 	}
 	if config.AuthType == AuthType_PASSWORD {
 		httpConfig.Accounts = config.Accounts
+		httpConfig.Webhook = config.Webhook
 		s.udpFilter = new(UDPFilter) // We only use this when auth is enabled
 	}
-	s.httpServer, _ = http.NewServer(ctx, httpConfig)
+	httpServer, err := http.NewServer(ctx, httpConfig)
+	if err != nil {
+		return nil, err
+	}
+	s.httpServer = httpServer
 	return s, nil
 }
 
+// authenticate returns the user of the username and password, or nil if they are invalid.
+func (s *Server) authenticate(ctx context.Context, username, password string) *protocol.MemoryUser {
+	// The HTTP server has the same accounts and webhook, so one webhook cache serves both.
+	return s.httpServer.Authenticate(ctx, "socks", username, password)
+}
+
 func (s *Server) policy() policy.Session {
 	config := s.config
 	p := s.policyManager.ForLevel(config.UserLevel)
@@ -117,6 +128,9 @@ func (s *Server) processTCP(ctx context.Context, conn stat.Connection, dispatche
 		address:      inbound.Gateway.Address,
 		port:         inbound.Gateway.Port,
 		localAddress: net.IPAddress(conn.LocalAddr().(*net.TCPAddr).IP),
+		authenticate: func(username, password string) *protocol.MemoryUser {
+			return s.authenticate(ctx, username, password)
+		},
 	}
 
 	// Firstbyte is for forwarded conn from SOCKS inbound
@@ -140,6 +154,7 @@ func (s *Server) processTCP(ctx context.Context, conn stat.Connection, dispatche
 	}
 	if request.User != nil {
 		inbound.User.Email = request.User.Email
+		inbound.User.Level = request.User.Level
 	}
 
 	if err := conn.SetReadDeadline(time.Time{}); err != nil {
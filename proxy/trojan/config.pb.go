@@ -8,6 +8,7 @@ package trojan
 
 import (
 	protocol "github.com/xtls/xray-core/common/protocol"
+	webhook "github.com/xtls/xray-core/proxy/webhook"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -195,9 +196,11 @@ func (x *ClientConfig) GetServer() *protocol.ServerEndpoint {
 }
 
 type ServerConfig struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Users         []*protocol.User       `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
-	Fallbacks     []*Fallback            `protobuf:"bytes,2,rep,name=fallbacks,proto3" json:"fallbacks,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Users     []*protocol.User       `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	Fallbacks []*Fallback            `protobuf:"bytes,2,rep,name=fallbacks,proto3" json:"fallbacks,omitempty"`
+	// Webhook validates the passwords not of users.
+	Webhook       *webhook.Config `protobuf:"bytes,3,opt,name=webhook,proto3" json:"webhook,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -246,11 +249,18 @@ func (x *ServerConfig) GetFallbacks() []*Fallback {
 	return nil
 }
 
+func (x *ServerConfig) GetWebhook() *webhook.Config {
+	if x != nil {
+		return x.Webhook
+	}
+	return nil
+}
+
 var File_proxy_trojan_config_proto protoreflect.FileDescriptor
 
 const file_proxy_trojan_config_proto_rawDesc = "" +
 	"\n" +
-	"\x19proxy/trojan/config.proto\x12\x11xray.proxy.trojan\x1a\x1acommon/protocol/user.proto\x1a!common/protocol/server_spec.proto\"%\n" +
+	"\x19proxy/trojan/config.proto\x12\x11xray.proxy.trojan\x1a\x1acommon/protocol/user.proto\x1a!common/protocol/server_spec.proto\x1a\x1aproxy/webhook/config.proto\"%\n" +
 	"\aAccount\x12\x1a\n" +
 	"\bpassword\x18\x01 \x01(\tR\bpassword\"\x82\x01\n" +
 	"\bFallback\x12\x12\n" +
@@ -261,10 +271,11 @@ const file_proxy_trojan_config_proto_rawDesc = "" +
 	"\x04dest\x18\x05 \x01(\tR\x04dest\x12\x12\n" +
 	"\x04xver\x18\x06 \x01(\x04R\x04xver\"L\n" +
 	"\fClientConfig\x12<\n" +
-	"\x06server\x18\x01 \x01(\v2$.xray.common.protocol.ServerEndpointR\x06server\"{\n" +
+	"\x06server\x18\x01 \x01(\v2$.xray.common.protocol.ServerEndpointR\x06server\"\xb1\x01\n" +
 	"\fServerConfig\x120\n" +
 	"\x05users\x18\x01 \x03(\v2\x1a.xray.common.protocol.UserR\x05users\x129\n" +
-	"\tfallbacks\x18\x02 \x03(\v2\x1b.xray.proxy.trojan.FallbackR\tfallbacksBU\n" +
+	"\tfallbacks\x18\x02 \x03(\v2\x1b.xray.proxy.trojan.FallbackR\tfallbacks\x124\n" +
+	"\awebhook\x18\x03 \x01(\v2\x1a.xray.proxy.webhook.ConfigR\awebhookBU\n" +
 	"\x15com.xray.proxy.trojanP\x01Z&github.com/xtls/xray-core/proxy/trojan\xaa\x02\x11Xray.Proxy.Trojanb\x06proto3"
 
 var (
@@ -287,16 +298,18 @@ var file_proxy_trojan_config_proto_goTypes = []any{
 	(*ServerConfig)(nil),            // 3: xray.proxy.trojan.ServerConfig
 	(*protocol.ServerEndpoint)(nil), // 4: xray.common.protocol.ServerEndpoint
 	(*protocol.User)(nil),           // 5: xray.common.protocol.User
+	(*webhook.Config)(nil),          // 6: xray.proxy.webhook.Config
 }
 var file_proxy_trojan_config_proto_depIdxs = []int32{
 	4, // 0: xray.proxy.trojan.ClientConfig.server:type_name -> xray.common.protocol.ServerEndpoint
 	5, // 1: xray.proxy.trojan.ServerConfig.users:type_name -> xray.common.protocol.User
 	1, // 2: xray.proxy.trojan.ServerConfig.fallbacks:type_name -> xray.proxy.trojan.Fallback
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	6, // 3: xray.proxy.trojan.ServerConfig.webhook:type_name -> xray.proxy.webhook.Config
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_proxy_trojan_config_proto_init() }
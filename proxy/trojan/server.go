@@ -1,7 +1,9 @@
 package trojan
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
 	"io"
 	"strconv"
 	"strings"
@@ -19,8 +21,10 @@ import (
 	"github.com/xtls/xray-core/common/signal"
 	"github.com/xtls/xray-core/common/task"
 	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/ban"
 	"github.com/xtls/xray-core/features/policy"
 	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/proxy/webhook"
 	"github.com/xtls/xray-core/transport/internet/reality"
 	"github.com/xtls/xray-core/transport/internet/stat"
 	"github.com/xtls/xray-core/transport/internet/tls"
@@ -39,6 +43,7 @@ type Server struct {
 	validator     *Validator
 	fallbacks     map[string]map[string]map[string]*Fallback // or nil
 	cone          bool
+	webhook       *webhook.Authenticator
 }
 
 // NewServer creates a new trojan inbound handler.
@@ -62,6 +67,14 @@ func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
 		cone:          ctx.Value("cone").(bool),
 	}
 
+	if config.Webhook != nil {
+		w, err := webhook.New(config.Webhook)
+		if err != nil {
+			return nil, err
+		}
+		server.webhook = w
+	}
+
 	if config.Fallbacks != nil {
 		server.fallbacks = make(map[string]map[string]map[string]*Fallback)
 		for _, fb := range config.Fallbacks {
@@ -115,6 +128,17 @@ func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
 	return server, nil
 }
 
+// CreateAccount implements proxy.AccountCreator.
+func (*Server) CreateAccount(id, password, flow string) (protocol.Account, error) {
+	if password == "" {
+		return nil, errors.New("Trojan password is not specified")
+	}
+	if flow != "" {
+		return nil, errors.New("Trojan doesn't support flow")
+	}
+	return (&Account{Password: password}).AsAccount()
+}
+
 // AddUser implements proxy.UserManager.AddUser().
 func (s *Server) AddUser(ctx context.Context, u *protocol.MemoryUser) error {
 	return s.validator.Add(u)
@@ -145,6 +169,27 @@ func (s *Server) Network() []net.Network {
 	return []net.Network{net.Network_TCP, net.Network_UNIX}
 }
 
+// authenticate returns the user of the key, the hex SHA224 of the password, with the webhook.
+func (s *Server) authenticate(ctx context.Context, key []byte) *protocol.MemoryUser {
+	// Fallback traffic is not sent to the webhook.
+	var sum [28]byte
+	if _, err := hex.Decode(sum[:], key); err != nil {
+		return nil
+	}
+	r := s.webhook.Authenticate(ctx, webhook.Credential{
+		Protocol: "trojan",
+		Hash:     string(key),
+	})
+	if r == nil {
+		return nil
+	}
+	return &protocol.MemoryUser{
+		Account: &MemoryAccount{Key: bytes.Clone(key)},
+		Email:   r.Email,
+		Level:   r.Level,
+	}
+}
+
 // Process implements proxy.Inbound.Process().
 func (s *Server) Process(ctx context.Context, network net.Network, conn stat.Connection, dispatcher routing.Dispatcher) error {
 	iConn := stat.TryUnwrapStatsConn(conn)
@@ -186,6 +231,9 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn stat.Con
 		shouldFallback = true
 	} else {
 		user = s.validator.Get(hexString(first.BytesTo(56)))
+		if user == nil && s.webhook != nil {
+			user = s.authenticate(ctx, first.BytesTo(56))
+		}
 		if user == nil {
 			// invalid user, let's fallback
 			err = errors.New("not a valid user")
@@ -195,6 +243,7 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn stat.Con
 				Status: log.AccessRejected,
 				Reason: err,
 			})
+			ban.RecordFailure(ctx)
 
 			shouldFallback = true
 		}
@@ -218,6 +267,12 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn stat.Con
 	}
 
 	destination := clientReader.Target
+	release, err := policy.AcquireUserConnection(s.policyManager, user, session.InboundFromContext(ctx).Source.Address, conn.RemoteAddr(), destination)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	if err := conn.SetReadDeadline(time.Time{}); err != nil {
 		return errors.New("unable to set read deadline").Base(err).AtWarning()
 	}
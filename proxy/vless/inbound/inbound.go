@@ -27,6 +27,7 @@ import (
 	"github.com/xtls/xray-core/common/signal"
 	"github.com/xtls/xray-core/common/task"
 	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/ban"
 	"github.com/xtls/xray-core/features/dns"
 	feature_inbound "github.com/xtls/xray-core/features/inbound"
 	"github.com/xtls/xray-core/features/outbound"
@@ -37,6 +38,7 @@ import (
 	"github.com/xtls/xray-core/proxy/vless"
 	"github.com/xtls/xray-core/proxy/vless/encoding"
 	"github.com/xtls/xray-core/proxy/vless/encryption"
+	"github.com/xtls/xray-core/proxy/webhook"
 	"github.com/xtls/xray-core/transport"
 	"github.com/xtls/xray-core/transport/internet/reality"
 	"github.com/xtls/xray-core/transport/internet/stat"
@@ -81,6 +83,7 @@ type Handler struct {
 	defaultDispatcher      routing.Dispatcher
 	ctx                    context.Context
 	fallbacks              map[string]map[string]map[string]*Fallback // or nil
+	webhook                *webhook.Authenticator
 	// regexps               map[string]*regexp.Regexp       // or nil
 }
 
@@ -97,6 +100,14 @@ func New(ctx context.Context, config *Config, dc dns.Client, validator vless.Val
 		ctx:                    ctx,
 	}
 
+	if config.Webhook != nil {
+		w, err := webhook.New(config.Webhook)
+		if err != nil {
+			return nil, err
+		}
+		handler.webhook = w
+	}
+
 	if config.Decryption != "" && config.Decryption != "none" {
 		s := strings.Split(config.Decryption, ".")
 		var nfsSKeysBytes [][]byte
@@ -232,6 +243,16 @@ func (h *Handler) Close() error {
 	return errors.Combine(common.Close(h.validator))
 }
 
+// CreateAccount implements proxy.AccountCreator.
+func (*Handler) CreateAccount(id, password, flow string) (protocol.Account, error) {
+	switch flow {
+	case "", vless.XRV:
+	default:
+		return nil, errors.New(`VLESS doesn't support flow "`, flow, `"`)
+	}
+	return (&vless.Account{Id: id, Flow: flow}).AsAccount()
+}
+
 // AddUser implements proxy.UserManager.AddUser().
 func (h *Handler) AddUser(ctx context.Context, u *protocol.MemoryUser) error {
 	return h.validator.Add(u)
@@ -303,7 +324,11 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection s
 	if isfb && firstLen < 18 {
 		err = errors.New("fallback directly")
 	} else {
-		userSentID, request, requestAddons, isfb, err = encoding.DecodeRequestHeader(isfb, first, reader, h.validator)
+		var validator vless.Validator = h.validator
+		if h.webhook != nil {
+			validator = &webhookValidator{Validator: h.validator, ctx: ctx, webhook: h.webhook}
+		}
+		userSentID, request, requestAddons, isfb, err = encoding.DecodeRequestHeader(isfb, first, reader, validator)
 	}
 
 	if err != nil {
@@ -515,11 +540,18 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection s
 				Status: log.AccessRejected,
 				Reason: err,
 			})
+			ban.RecordFailure(ctx)
 			err = errors.New("invalid request from ", connection.RemoteAddr()).Base(err).AtInfo()
 		}
 		return err
 	}
 
+	release, err := policy.AcquireUserConnection(h.policyManager, request.User, session.InboundFromContext(ctx).Source.Address, connection.RemoteAddr(), request.Destination())
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	if err := connection.SetReadDeadline(time.Time{}); err != nil {
 		errors.LogWarningInner(ctx, err, "unable to set back read deadline")
 	}
@@ -0,0 +1,39 @@
+package inbound
+
+import (
+	"context"
+
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/common/uuid"
+	"github.com/xtls/xray-core/proxy/vless"
+	"github.com/xtls/xray-core/proxy/webhook"
+)
+
+// webhookValidator looks up the IDs not of the users with the webhook, for a connection.
+type webhookValidator struct {
+	vless.Validator
+	ctx     context.Context
+	webhook *webhook.Authenticator
+}
+
+// Get implements vless.Validator.
+func (v *webhookValidator) Get(id uuid.UUID) *protocol.MemoryUser {
+	if u := v.Validator.Get(id); u != nil {
+		return u
+	}
+	r := v.webhook.Authenticate(v.ctx, webhook.Credential{
+		Protocol: "vless",
+		ID:       id.String(),
+	})
+	if r == nil {
+		return nil
+	}
+	return &protocol.MemoryUser{
+		Account: &vless.MemoryAccount{
+			ID:   protocol.NewID(id),
+			Flow: r.Flow,
+		},
+		Email: r.Email,
+		Level: r.Level,
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/xtls/xray-core/common/task"
 	"github.com/xtls/xray-core/common/uuid"
 	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/ban"
 	feature_inbound "github.com/xtls/xray-core/features/inbound"
 	"github.com/xtls/xray-core/features/policy"
 	"github.com/xtls/xray-core/features/routing"
@@ -166,6 +167,14 @@ func (h *Handler) GetUsersCount(context.Context) int64 {
 	return h.clients.GetCount()
 }
 
+// CreateAccount implements proxy.AccountCreator.
+func (*Handler) CreateAccount(id, password, flow string) (protocol.Account, error) {
+	if flow != "" {
+		return nil, errors.New("VMess doesn't support flow")
+	}
+	return (&vmess.Account{Id: id}).AsAccount()
+}
+
 func (h *Handler) AddUser(ctx context.Context, user *protocol.MemoryUser) error {
 	if len(user.Email) > 0 && !h.usersByEmail.Add(user) {
 		return errors.New("User ", user.Email, " already exists.")
@@ -246,11 +255,18 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection s
 				Status: log.AccessRejected,
 				Reason: err,
 			})
+			ban.RecordFailure(ctx)
 			err = errors.New("invalid request from ", connection.RemoteAddr()).Base(err).AtInfo()
 		}
 		return err
 	}
 
+	release, err := policy.AcquireUserConnection(h.policyManager, request.User, session.InboundFromContext(ctx).Source.Address, connection.RemoteAddr(), request.Destination())
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	if request.Command != protocol.RequestCommandMux {
 		ctx = log.ContextWithAccessMessage(ctx, &log.AccessMessage{
 			From:   connection.RemoteAddr(),
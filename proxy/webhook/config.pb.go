@@ -0,0 +1,155 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.5
+// source: proxy/webhook/config.proto
+
+package webhook
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Config of an endpoint validating credentials unknown to an inbound.
+type Config struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// URL of the endpoint, in http://, https://, or http+unix:///path/to/socket.sock/path.
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// Timeout of calling the endpoint in nanoseconds. Default 5 seconds.
+	Timeout int64 `protobuf:"varint,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// Time in nanoseconds to cache accepted credentials. Default 5 minutes.
+	CacheTtl int64 `protobuf:"varint,3,opt,name=cache_ttl,json=cacheTtl,proto3" json:"cache_ttl,omitempty"`
+	// Time in nanoseconds to cache rejected credentials. Default 1 minute.
+	NegativeCacheTtl int64 `protobuf:"varint,4,opt,name=negative_cache_ttl,json=negativeCacheTtl,proto3" json:"negative_cache_ttl,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	mi := &file_proxy_webhook_config_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_proxy_webhook_config_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_proxy_webhook_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Config) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Config) GetTimeout() int64 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *Config) GetCacheTtl() int64 {
+	if x != nil {
+		return x.CacheTtl
+	}
+	return 0
+}
+
+func (x *Config) GetNegativeCacheTtl() int64 {
+	if x != nil {
+		return x.NegativeCacheTtl
+	}
+	return 0
+}
+
+var File_proxy_webhook_config_proto protoreflect.FileDescriptor
+
+const file_proxy_webhook_config_proto_rawDesc = "" +
+	"\n" +
+	"\x1aproxy/webhook/config.proto\x12\x12xray.proxy.webhook\"\x7f\n" +
+	"\x06Config\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x18\n" +
+	"\atimeout\x18\x02 \x01(\x03R\atimeout\x12\x1b\n" +
+	"\tcache_ttl\x18\x03 \x01(\x03R\bcacheTtl\x12,\n" +
+	"\x12negative_cache_ttl\x18\x04 \x01(\x03R\x10negativeCacheTtlBX\n" +
+	"\x16com.xray.proxy.webhookP\x01Z'github.com/xtls/xray-core/proxy/webhook\xaa\x02\x12Xray.Proxy.Webhookb\x06proto3"
+
+var (
+	file_proxy_webhook_config_proto_rawDescOnce sync.Once
+	file_proxy_webhook_config_proto_rawDescData []byte
+)
+
+func file_proxy_webhook_config_proto_rawDescGZIP() []byte {
+	file_proxy_webhook_config_proto_rawDescOnce.Do(func() {
+		file_proxy_webhook_config_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proxy_webhook_config_proto_rawDesc), len(file_proxy_webhook_config_proto_rawDesc)))
+	})
+	return file_proxy_webhook_config_proto_rawDescData
+}
+
+var file_proxy_webhook_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_proxy_webhook_config_proto_goTypes = []any{
+	(*Config)(nil), // 0: xray.proxy.webhook.Config
+}
+var file_proxy_webhook_config_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_proxy_webhook_config_proto_init() }
+func file_proxy_webhook_config_proto_init() {
+	if File_proxy_webhook_config_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proxy_webhook_config_proto_rawDesc), len(file_proxy_webhook_config_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_proxy_webhook_config_proto_goTypes,
+		DependencyIndexes: file_proxy_webhook_config_proto_depIdxs,
+		MessageInfos:      file_proxy_webhook_config_proto_msgTypes,
+	}.Build()
+	File_proxy_webhook_config_proto = out.File
+	file_proxy_webhook_config_proto_goTypes = nil
+	file_proxy_webhook_config_proto_depIdxs = nil
+}
@@ -0,0 +1,194 @@
+// Package webhook validates credentials unknown to inbounds by calling an external HTTP endpoint.
+//
+// The credential is posted to the endpoint in JSON. The endpoint accepts it by responding 200 with
+// the user in JSON, like {"email": "alice@example.com", "level": 1}, and rejects it by responding
+// 401, 403 or 404. Both results are cached, so the endpoint is not called for every connection.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common/cache"
+	"github.com/xtls/xray-core/common/errors"
+	http_proto "github.com/xtls/xray-core/common/protocol/http"
+)
+
+const (
+	// maxCacheSize is the number of credentials cached, beyond which the least recently used are removed.
+	maxCacheSize = 65536
+	// maxResponseSize is the limit of the size of responses of the endpoint.
+	maxResponseSize = 64 * 1024
+)
+
+// Credential is a credential sent by a client.
+type Credential struct {
+	Protocol string `json:"protocol"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// ID is the UUID of VLESS users.
+	ID string `json:"id,omitempty"`
+	// Hash is the hex SHA224 of the password of Trojan users.
+	Hash string `json:"hash,omitempty"`
+}
+
+// Result is the user of an accepted credential.
+type Result struct {
+	Email string `json:"email"`
+	Level uint32 `json:"level"`
+	// Flow is the flow of VLESS users.
+	Flow string `json:"flow"`
+}
+
+type entry struct {
+	// result is nil if the credential is rejected.
+	result *Result
+	expire time.Time
+}
+
+// call is an in-flight request to the endpoint, shared by the clients sending the same credential.
+type call struct {
+	done   chan struct{}
+	result *Result
+	err    error
+}
+
+// Authenticator validates credentials with the endpoint.
+type Authenticator struct {
+	url              string
+	client           *http.Client
+	cacheTTL         time.Duration
+	negativeCacheTTL time.Duration
+
+	access sync.Mutex
+	// cache keeps the *entry of credentials.
+	cache cache.Lru
+	calls map[Credential]*call
+
+	// now is replaced in tests.
+	now func() time.Time
+}
+
+// New creates an Authenticator.
+func New(config *Config) (*Authenticator, error) {
+	a := &Authenticator{
+		url:              config.Url,
+		cacheTTL:         5 * time.Minute,
+		negativeCacheTTL: time.Minute,
+		cache:            cache.NewLru(maxCacheSize),
+		calls:            make(map[Credential]*call),
+		now:              time.Now,
+	}
+	if config.CacheTtl > 0 {
+		a.cacheTTL = time.Duration(config.CacheTtl)
+	}
+	if config.NegativeCacheTtl > 0 {
+		a.negativeCacheTTL = time.Duration(config.NegativeCacheTtl)
+	}
+	timeout := 5 * time.Second
+	if config.Timeout > 0 {
+		timeout = time.Duration(config.Timeout)
+	}
+	a.client = &http.Client{Timeout: timeout}
+
+	switch {
+	case strings.HasPrefix(config.Url, "http+unix://"):
+		socketPath, url, err := http_proto.ParseUnixURL(config.Url)
+		if err != nil {
+			return nil, errors.New("invalid URL of webhook: ", config.Url).Base(err)
+		}
+		a.url = url
+		a.client = http_proto.NewUnixClient(socketPath, timeout)
+	case strings.HasPrefix(config.Url, "http://"), strings.HasPrefix(config.Url, "https://"):
+	default:
+		return nil, errors.New("invalid URL of webhook: ", config.Url)
+	}
+	return a, nil
+}
+
+// Authenticate returns the user of the credential, or nil if it is rejected or the endpoint fails.
+func (a *Authenticator) Authenticate(ctx context.Context, credential Credential) *Result {
+	a.access.Lock()
+	if v, found := a.cache.Get(credential); found {
+		if e := v.(*entry); a.now().Before(e.expire) {
+			a.access.Unlock()
+			return e.result
+		}
+	}
+	c, found := a.calls[credential]
+	if !found {
+		c = &call{done: make(chan struct{})}
+		a.calls[credential] = c
+	}
+	a.access.Unlock()
+
+	if found {
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return nil
+		}
+	} else {
+		c.result, c.err = a.request(credential)
+		a.access.Lock()
+		delete(a.calls, credential)
+		if c.err == nil {
+			a.store(credential, c.result)
+		}
+		a.access.Unlock()
+		close(c.done)
+	}
+
+	if c.err != nil {
+		errors.LogWarningInner(ctx, c.err, "failed to authenticate ", credential.Protocol, " user with webhook")
+		return nil
+	}
+	return c.result
+}
+
+// store caches the result of the credential. Must be called with a.access locked.
+func (a *Authenticator) store(credential Credential, result *Result) {
+	ttl := a.cacheTTL
+	if result == nil {
+		ttl = a.negativeCacheTTL
+	}
+	// The entry is replaced instead of updated, as the cache also indexes its values.
+	a.cache.Delete(credential)
+	a.cache.Put(credential, &entry{
+		result: result,
+		expire: a.now().Add(ttl),
+	})
+}
+
+// request calls the endpoint. It returns nil result without error if the credential is rejected.
+func (a *Authenticator) request(credential Credential) (*Result, error) {
+	body, err := json.Marshal(credential)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.client.Post(a.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.New("failed to call webhook").Base(err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, errors.New("unexpected HTTP status code of webhook: ", resp.StatusCode)
+	}
+
+	result := new(Result)
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(result); err != nil && err != io.EOF {
+		return nil, errors.New("invalid response of webhook").Base(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+)
+
+func newEndpoint(calls *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		var c Credential
+		if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch {
+		case c.Username == "alice" && c.Password == "secret":
+			w.Write([]byte(`{"email": "alice@example.com", "level": 1}`))
+		case c.Username == "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+}
+
+func TestAuthenticator(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(newEndpoint(&calls))
+	defer server.Close()
+
+	a, err := New(&Config{
+		Url:              server.URL,
+		CacheTtl:         int64(time.Minute),
+		NegativeCacheTtl: int64(10 * time.Second),
+	})
+	common.Must(err)
+	now := time.Unix(1700000000, 0)
+	a.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	alice := Credential{Protocol: "socks", Username: "alice", Password: "secret"}
+	r := a.Authenticate(ctx, alice)
+	if r == nil || r.Email != "alice@example.com" || r.Level != 1 {
+		t.Fatal("unexpected result ", r)
+	}
+	a.Authenticate(ctx, alice)
+	if calls.Load() != 1 {
+		t.Error("expect accepted credential cached, but endpoint called ", calls.Load(), " times")
+	}
+
+	wrong := Credential{Protocol: "socks", Username: "alice", Password: "wrong"}
+	if a.Authenticate(ctx, wrong) != nil || a.Authenticate(ctx, wrong) != nil {
+		t.Error("expect rejected")
+	}
+	if calls.Load() != 2 {
+		t.Error("expect rejected credential cached, but endpoint called ", calls.Load(), " times")
+	}
+
+	broken := Credential{Protocol: "socks", Username: "broken"}
+	if a.Authenticate(ctx, broken) != nil || a.Authenticate(ctx, broken) != nil {
+		t.Error("expect rejected on endpoint failure")
+	}
+	if calls.Load() != 4 {
+		t.Error("expect failures not cached, but endpoint called ", calls.Load(), " times")
+	}
+
+	now = now.Add(30 * time.Second)
+	a.Authenticate(ctx, wrong)
+	a.Authenticate(ctx, alice)
+	if calls.Load() != 5 {
+		t.Error("expect only rejected credential expired, but endpoint called ", calls.Load(), " times")
+	}
+}
+
+func TestAuthenticatorUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auth.sock")
+	l, err := net.Listen("unix", path)
+	common.Must(err)
+	var calls atomic.Int32
+	server := &http.Server{Handler: newEndpoint(&calls)}
+	go server.Serve(l)
+	defer server.Close()
+
+	a, err := New(&Config{Url: "http+unix://" + path + "/auth"})
+	common.Must(err)
+	if r := a.Authenticate(context.Background(), Credential{Protocol: "http", Username: "alice", Password: "secret"}); r == nil {
+		t.Error("expect accepted through unix socket")
+	}
+
+	if _, err := New(&Config{Url: "http+unix://relative.sock/auth"}); err == nil {
+		t.Error("expect error for relative socket path")
+	}
+	if _, err := New(&Config{Url: "http+unix://" + filepath.Join(t.TempDir(), "missing.sock") + "/auth"}); err == nil {
+		t.Error("expect error for missing socket")
+	}
+	if _, err := New(&Config{Url: "ftp://example.com"}); err == nil {
+		t.Error("expect error for invalid scheme")
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/peer"
 )
 
 type Listener struct {
@@ -29,18 +30,28 @@ type Listener struct {
 
 func (l Listener) Tun(server encoding.GRPCService_TunServer) error {
 	tunCtx, cancel := context.WithCancel(l.ctx)
-	l.handler(encoding.NewHunkConn(server, cancel))
+	l.handler(withClientCert(server.Context(), encoding.NewHunkConn(server, cancel)))
 	<-tunCtx.Done()
 	return nil
 }
 
 func (l Listener) TunMulti(server encoding.GRPCService_TunMultiServer) error {
 	tunCtx, cancel := context.WithCancel(l.ctx)
-	l.handler(encoding.NewMultiHunkConn(server, cancel))
+	l.handler(withClientCert(server.Context(), encoding.NewMultiHunkConn(server, cancel)))
 	<-tunCtx.Done()
 	return nil
 }
 
+// withClientCert names conn after the verified TLS client certificate of the stream of ctx, if any.
+func withClientCert(ctx context.Context, conn net.Conn) net.Conn {
+	if pr, ok := peer.FromContext(ctx); ok {
+		if info, ok := pr.AuthInfo.(credentials.TLSInfo); ok {
+			return tls.WithClientCertSubject(conn, &info.State)
+		}
+	}
+	return conn
+}
+
 func (l Listener) Close() error {
 	l.s.Stop()
 	return nil
@@ -99,7 +99,13 @@ func (s *server) upgrade(conn net.Conn) (stat.Connection, error) {
 		}
 	}
 
-	return stat.Connection(newConnection(conn, remoteAddr)), nil
+	var state *tls.ConnectionState
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		// The handshake is done before the request is read.
+		s := tlsConn.ConnectionState()
+		state = &s
+	}
+	return v2tls.WithClientCertSubject(newConnection(conn, remoteAddr), state), nil
 }
 
 func (s *server) keepAccepting() {
@@ -380,7 +380,7 @@ func (h *requestHandler) ServeHTTP(writer http.ResponseWriter, request *http.Req
 			conn.reader = currentSession.uploadQueue
 		}
 
-		h.ln.addConn(stat.Connection(&conn))
+		h.ln.addConn(stat.Connection(tls.WithClientCertSubject(&conn, request.TLS)))
 
 		// "A ResponseWriter may not be used after [Handler.ServeHTTP] has returned."
 		select {
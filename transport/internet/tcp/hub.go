@@ -16,6 +16,8 @@ import (
 	"github.com/xtls/xray-core/transport/internet/tls"
 )
 
+const tlsHandshakeTimeout = 8 * time.Second
+
 // Listener is an internet.Listener that listens for TCP connections.
 type Listener struct {
 	listener      net.Listener
@@ -111,6 +113,17 @@ func (v *Listener) keepAccepting() {
 		go func() {
 			if v.tlsConfig != nil {
 				conn = tls.Server(conn, v.tlsConfig)
+				if v.tlsConfig.ClientAuth != gotls.NoClientCert {
+					// Handshake now to tell the inbound who the client is.
+					ctx, cancel := context.WithTimeout(context.Background(), tlsHandshakeTimeout)
+					err := conn.(*tls.Conn).HandshakeContext(ctx)
+					cancel()
+					if err != nil {
+						errors.LogInfoInner(context.Background(), err, "TLS handshake failed with ", conn.RemoteAddr())
+						conn.Close()
+						return
+					}
+				}
 			} else if v.realityConfig != nil {
 				if conn, err = reality.Server(conn, v.realityConfig); err != nil {
 					errors.LogInfo(context.Background(), err.Error())
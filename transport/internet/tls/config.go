@@ -420,6 +420,28 @@ func (c *Config) GetTLSConfig(opts ...Option) *tls.Config {
 		config.CurvePreferences = ParseCurveName(c.CurvePreferences)
 	}
 
+	if c.ClientAuth != Config_NO_CLIENT_CERT {
+		config.ClientCAs = x509.NewCertPool()
+		for _, ca := range c.ClientCa {
+			if !config.ClientCAs.AppendCertsFromPEM(ca) {
+				errors.LogError(context.Background(), "failed to load client CA certificate")
+			}
+		}
+		if c.ClientAuth == Config_REQUIRE_AND_VERIFY {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		} else {
+			config.ClientAuth = tls.VerifyClientCertIfGiven
+			// Clients are allowed to connect without certificate.
+			verifyPeerCert := config.VerifyPeerCertificate
+			config.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+				if len(rawCerts) == 0 {
+					return nil
+				}
+				return verifyPeerCert(rawCerts, verifiedChains)
+			}
+		}
+	}
+
 	if len(config.NextProtos) == 0 {
 		config.NextProtos = []string{"h2", "http/1.1"}
 	}
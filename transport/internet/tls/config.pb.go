@@ -71,6 +71,58 @@ func (Certificate_Usage) EnumDescriptor() ([]byte, []int) {
 	return file_transport_internet_tls_config_proto_rawDescGZIP(), []int{0, 0}
 }
 
+type Config_ClientAuth int32
+
+const (
+	// Client certificates are not requested.
+	Config_NO_CLIENT_CERT Config_ClientAuth = 0
+	// Client certificates are verified if the client sends one.
+	Config_VERIFY_IF_GIVEN Config_ClientAuth = 1
+	// Clients must send a valid certificate.
+	Config_REQUIRE_AND_VERIFY Config_ClientAuth = 2
+)
+
+// Enum value maps for Config_ClientAuth.
+var (
+	Config_ClientAuth_name = map[int32]string{
+		0: "NO_CLIENT_CERT",
+		1: "VERIFY_IF_GIVEN",
+		2: "REQUIRE_AND_VERIFY",
+	}
+	Config_ClientAuth_value = map[string]int32{
+		"NO_CLIENT_CERT":     0,
+		"VERIFY_IF_GIVEN":    1,
+		"REQUIRE_AND_VERIFY": 2,
+	}
+)
+
+func (x Config_ClientAuth) Enum() *Config_ClientAuth {
+	p := new(Config_ClientAuth)
+	*p = x
+	return p
+}
+
+func (x Config_ClientAuth) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Config_ClientAuth) Descriptor() protoreflect.EnumDescriptor {
+	return file_transport_internet_tls_config_proto_enumTypes[1].Descriptor()
+}
+
+func (Config_ClientAuth) Type() protoreflect.EnumType {
+	return &file_transport_internet_tls_config_proto_enumTypes[1]
+}
+
+func (x Config_ClientAuth) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Config_ClientAuth.Descriptor instead.
+func (Config_ClientAuth) EnumDescriptor() ([]byte, []int) {
+	return file_transport_internet_tls_config_proto_rawDescGZIP(), []int{1, 0}
+}
+
 type Certificate struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// TLS certificate in x509 format.
@@ -208,8 +260,12 @@ type Config struct {
 	EchForceQuery        string                 `protobuf:"bytes,20,opt,name=ech_force_query,json=echForceQuery,proto3" json:"ech_force_query,omitempty"`
 	EchSocketSettings    *internet.SocketConfig `protobuf:"bytes,21,opt,name=ech_socket_settings,json=echSocketSettings,proto3" json:"ech_socket_settings,omitempty"`
 	PinnedPeerCertSha256 [][]byte               `protobuf:"bytes,22,rep,name=pinned_peer_cert_sha256,json=pinnedPeerCertSha256,proto3" json:"pinned_peer_cert_sha256,omitempty"`
-	unknownFields        protoimpl.UnknownFields
-	sizeCache            protoimpl.SizeCache
+	// How the server verifies client certificates.
+	ClientAuth Config_ClientAuth `protobuf:"varint,23,opt,name=client_auth,json=clientAuth,proto3,enum=xray.transport.internet.tls.Config_ClientAuth" json:"client_auth,omitempty"`
+	// CA certificates in PEM format to verify client certificates with.
+	ClientCa      [][]byte `protobuf:"bytes,24,rep,name=client_ca,json=clientCa,proto3" json:"client_ca,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Config) Reset() {
@@ -375,6 +431,20 @@ func (x *Config) GetPinnedPeerCertSha256() [][]byte {
 	return nil
 }
 
+func (x *Config) GetClientAuth() Config_ClientAuth {
+	if x != nil {
+		return x.ClientAuth
+	}
+	return Config_NO_CLIENT_CERT
+}
+
+func (x *Config) GetClientCa() [][]byte {
+	if x != nil {
+		return x.ClientCa
+	}
+	return nil
+}
+
 var File_transport_internet_tls_config_proto protoreflect.FileDescriptor
 
 const file_transport_internet_tls_config_proto_rawDesc = "" +
@@ -393,7 +463,7 @@ const file_transport_internet_tls_config_proto_rawDesc = "" +
 	"\x05Usage\x12\x10\n" +
 	"\fENCIPHERMENT\x10\x00\x12\x14\n" +
 	"\x10AUTHORITY_VERIFY\x10\x01\x12\x13\n" +
-	"\x0fAUTHORITY_ISSUE\x10\x02\"\xf5\x06\n" +
+	"\x0fAUTHORITY_ISSUE\x10\x02\"\xb2\b\n" +
 	"\x06Config\x12%\n" +
 	"\x0eallow_insecure\x18\x01 \x01(\bR\rallowInsecure\x12J\n" +
 	"\vcertificate\x18\x02 \x03(\v2(.xray.transport.internet.tls.CertificateR\vcertificate\x12\x1f\n" +
@@ -416,7 +486,15 @@ const file_transport_internet_tls_config_proto_rawDesc = "" +
 	"\x0fech_config_list\x18\x13 \x01(\tR\rechConfigList\x12&\n" +
 	"\x0fech_force_query\x18\x14 \x01(\tR\rechForceQuery\x12U\n" +
 	"\x13ech_socket_settings\x18\x15 \x01(\v2%.xray.transport.internet.SocketConfigR\x11echSocketSettings\x125\n" +
-	"\x17pinned_peer_cert_sha256\x18\x16 \x03(\fR\x14pinnedPeerCertSha256Bs\n" +
+	"\x17pinned_peer_cert_sha256\x18\x16 \x03(\fR\x14pinnedPeerCertSha256\x12O\n" +
+	"\vclient_auth\x18\x17 \x01(\x0e2..xray.transport.internet.tls.Config.ClientAuthR\n" +
+	"clientAuth\x12\x1b\n" +
+	"\tclient_ca\x18\x18 \x03(\fR\bclientCa\"M\n" +
+	"\n" +
+	"ClientAuth\x12\x12\n" +
+	"\x0eNO_CLIENT_CERT\x10\x00\x12\x13\n" +
+	"\x0fVERIFY_IF_GIVEN\x10\x01\x12\x16\n" +
+	"\x12REQUIRE_AND_VERIFY\x10\x02Bs\n" +
 	"\x1fcom.xray.transport.internet.tlsP\x01Z0github.com/xtls/xray-core/transport/internet/tls\xaa\x02\x1bXray.Transport.Internet.Tlsb\x06proto3"
 
 var (
@@ -431,23 +509,25 @@ func file_transport_internet_tls_config_proto_rawDescGZIP() []byte {
 	return file_transport_internet_tls_config_proto_rawDescData
 }
 
-var file_transport_internet_tls_config_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_transport_internet_tls_config_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
 var file_transport_internet_tls_config_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_transport_internet_tls_config_proto_goTypes = []any{
 	(Certificate_Usage)(0),        // 0: xray.transport.internet.tls.Certificate.Usage
-	(*Certificate)(nil),           // 1: xray.transport.internet.tls.Certificate
-	(*Config)(nil),                // 2: xray.transport.internet.tls.Config
-	(*internet.SocketConfig)(nil), // 3: xray.transport.internet.SocketConfig
+	(Config_ClientAuth)(0),        // 1: xray.transport.internet.tls.Config.ClientAuth
+	(*Certificate)(nil),           // 2: xray.transport.internet.tls.Certificate
+	(*Config)(nil),                // 3: xray.transport.internet.tls.Config
+	(*internet.SocketConfig)(nil), // 4: xray.transport.internet.SocketConfig
 }
 var file_transport_internet_tls_config_proto_depIdxs = []int32{
 	0, // 0: xray.transport.internet.tls.Certificate.usage:type_name -> xray.transport.internet.tls.Certificate.Usage
-	1, // 1: xray.transport.internet.tls.Config.certificate:type_name -> xray.transport.internet.tls.Certificate
-	3, // 2: xray.transport.internet.tls.Config.ech_socket_settings:type_name -> xray.transport.internet.SocketConfig
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	2, // 1: xray.transport.internet.tls.Config.certificate:type_name -> xray.transport.internet.tls.Certificate
+	4, // 2: xray.transport.internet.tls.Config.ech_socket_settings:type_name -> xray.transport.internet.SocketConfig
+	1, // 3: xray.transport.internet.tls.Config.client_auth:type_name -> xray.transport.internet.tls.Config.ClientAuth
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_transport_internet_tls_config_proto_init() }
@@ -460,7 +540,7 @@ func file_transport_internet_tls_config_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_transport_internet_tls_config_proto_rawDesc), len(file_transport_internet_tls_config_proto_rawDesc)),
-			NumEnums:      1,
+			NumEnums:      2,
 			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
@@ -3,6 +3,7 @@ package tls_test
 import (
 	gotls "crypto/tls"
 	"crypto/x509"
+	"net"
 	"testing"
 	"time"
 
@@ -73,6 +74,77 @@ func TestInsecureCertificates(t *testing.T) {
 	}
 }
 
+func handshake(server *Config, client *gotls.Config) (net.Conn, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer listener.Close()
+
+	go func() {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		gotls.Client(conn, client).Handshake()
+	}()
+
+	rawConn, err := listener.Accept()
+	common.Must(err)
+	defer rawConn.Close()
+	conn := gotls.Server(rawConn, server.GetTLSConfig())
+	conn.SetDeadline(time.Now().Add(time.Second * 5))
+	return conn, conn.Handshake()
+}
+
+func TestClientAuth(t *testing.T) {
+	ca, _ := cert.MustGenerate(nil, cert.Authority(true), cert.KeyUsage(x509.KeyUsageCertSign),
+		cert.ExtKeyUsage(x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth))
+	caPEM, _ := ca.ToPEM()
+	serverCert, _ := cert.MustGenerate(ca, cert.DNSNames("www.example.com"))
+	clientCert, _ := cert.MustGenerate(ca, cert.CommonName("alice"), cert.ExtKeyUsage(x509.ExtKeyUsageClientAuth))
+	clientCertPEM, clientKeyPEM := clientCert.ToPEM()
+	clientCertificate, err := gotls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	common.Must(err)
+
+	server := &Config{
+		Certificate: []*Certificate{ParseCertificate(serverCert)},
+		ClientAuth:  Config_REQUIRE_AND_VERIFY,
+		ClientCa:    [][]byte{caPEM},
+	}
+
+	conn, err := handshake(server, &gotls.Config{
+		InsecureSkipVerify: true,
+		Certificates:       []gotls.Certificate{clientCertificate},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject := ClientCertSubject(conn); subject != "alice" {
+		t.Error("unexpected client subject: ", subject)
+	}
+	state := conn.(*gotls.Conn).ConnectionState()
+	inner, _ := net.Pipe()
+	if subject := ClientCertSubject(WithClientCertSubject(inner, &state)); subject != "alice" {
+		t.Error("unexpected client subject of inner connection: ", subject)
+	}
+	if WithClientCertSubject(inner, nil) != inner {
+		t.Error("expect connection without TLS to be returned as is")
+	}
+
+	if _, err := handshake(server, &gotls.Config{InsecureSkipVerify: true}); err == nil {
+		t.Error("expect handshake failure without client certificate")
+	}
+
+	server.ClientAuth = Config_VERIFY_IF_GIVEN
+	conn, err = handshake(server, &gotls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject := ClientCertSubject(conn); subject != "" {
+		t.Error("unexpected client subject: ", subject)
+	}
+}
+
 func BenchmarkCertificateIssuing(b *testing.B) {
 	ct, _ := cert.MustGenerate(nil, cert.Authority(true), cert.KeyUsage(x509.KeyUsageCertSign))
 	certificate := ParseCertificate(ct)
@@ -56,6 +56,51 @@ func (c *Conn) NegotiatedProtocol() string {
 	return state.NegotiatedProtocol
 }
 
+// ClientCertSubject returns the name of the verified client certificate of a server side
+// connection after handshake, which is the common name of the certificate, or its whole
+// subject if there is no common name. Returns empty string if the client isn't verified.
+// Connections of transports terminating TLS in an HTTP or gRPC server are named with
+// WithClientCertSubject.
+func ClientCertSubject(conn net.Conn) string {
+	if c, ok := conn.(*clientCertConn); ok {
+		return c.subject
+	}
+	c, ok := conn.(interface{ ConnectionState() tls.ConnectionState })
+	if !ok {
+		return ""
+	}
+	state := c.ConnectionState()
+	return clientCertSubjectOf(&state)
+}
+
+func clientCertSubjectOf(state *tls.ConnectionState) string {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	subject := state.VerifiedChains[0][0].Subject
+	if subject.CommonName != "" {
+		return subject.CommonName
+	}
+	return subject.String()
+}
+
+// clientCertConn is a connection carried over a TLS connection, named after its verified client certificate.
+type clientCertConn struct {
+	net.Conn
+	subject string
+}
+
+// WithClientCertSubject names conn after the verified client certificate in state, the state of the
+// TLS connection which carries conn, so that ClientCertSubject returns it. state may be nil.
+// conn is returned as is if the client isn't verified.
+func WithClientCertSubject(conn net.Conn, state *tls.ConnectionState) net.Conn {
+	subject := clientCertSubjectOf(state)
+	if subject == "" {
+		return conn
+	}
+	return &clientCertConn{Conn: conn, subject: subject}
+}
+
 // Client initiates a TLS client handshake on the given connection.
 func Client(c net.Conn, config *tls.Config) net.Conn {
 	tlsConn := tls.Client(c, config)
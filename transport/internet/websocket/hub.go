@@ -84,7 +84,7 @@ func (h *requestHandler) ServeHTTP(writer http.ResponseWriter, request *http.Req
 		}
 	}
 
-	h.ln.addConn(NewConnection(conn, remoteAddr, extraReader, h.ln.config.HeartbeatPeriod))
+	h.ln.addConn(v2tls.WithClientCertSubject(NewConnection(conn, remoteAddr, extraReader, h.ln.config.HeartbeatPeriod), request.TLS))
 }
 
 type Listener struct {
@@ -39,3 +39,8 @@ func (r *Reader) Recover() (err error) {
 	}
 	return
 }
+
+// Done returns a channel that is closed when the pipe is closed or interrupted.
+func (r *Reader) Done() <-chan struct{} {
+	return r.pipe.done.Wait()
+}